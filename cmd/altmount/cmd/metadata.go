@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/javi11/altmount/internal/config"
+	"github.com/javi11/altmount/internal/metadata"
+	"github.com/spf13/cobra"
+)
+
+var (
+	metadataExportOutput      string
+	metadataImportTarget      string
+	metadataImportOnCollision string
+)
+
+func init() {
+	metadataCmd := &cobra.Command{
+		Use:   "metadata",
+		Short: "Export or import the metadata of a library subtree",
+	}
+
+	exportCmd := &cobra.Command{
+		Use:   "export <virtual-path>",
+		Short: "Export the metadata under a virtual path to an archive",
+		Long: `Write the metadata of every file under a virtual path, e.g. /movies, to a gzip compressed tar archive.
+The archive can be imported into another AltMount instance without importing the NZBs again.`,
+		Args:          cobra.ExactArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE:          runMetadataExport,
+	}
+	exportCmd.Flags().StringVarP(&metadataExportOutput, "output", "o", "", "archive file to write (default: standard output)")
+
+	importCmd := &cobra.Command{
+		Use:   "import <archive>",
+		Short: "Import a metadata archive created by metadata export",
+		Long: `Write the files of a metadata archive under the virtual path it was exported from, or under --target.
+Files that already exist are skipped, overwritten or imported under a new name depending on --on-collision.
+Stop the server first, or restart it afterwards, so it picks up the imported files.`,
+		Args:          cobra.ExactArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE:          runMetadataImport,
+	}
+	importCmd.Flags().StringVar(&metadataImportTarget, "target", "", "virtual path to import under (default: the exported path)")
+	importCmd.Flags().StringVar(&metadataImportOnCollision, "on-collision", string(metadata.CollisionSkip), "what to do with existing files: skip, overwrite or rename")
+
+	metadataCmd.AddCommand(exportCmd, importCmd)
+	rootCmd.AddCommand(metadataCmd)
+}
+
+// newCLIMetadataService returns the metadata service of the configured metadata directory
+func newCLIMetadataService(cmd *cobra.Command) (*metadata.MetadataService, error) {
+	cfg, err := config.ReadConfig(configFile)
+	if err != nil {
+		cmd.PrintErrln(err)
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return metadata.NewMetadataService(cfg.Metadata.RootPath), nil
+}
+
+func runMetadataExport(cmd *cobra.Command, args []string) error {
+	ms, err := newCLIMetadataService(cmd)
+	if err != nil {
+		return err
+	}
+
+	if metadataExportOutput == "" {
+		_, err := ms.ExportSubtree(args[0], cmd.OutOrStdout())
+		if err != nil {
+			cmd.PrintErrln(err)
+		}
+		return err
+	}
+
+	file, err := os.Create(metadataExportOutput)
+	if err != nil {
+		cmd.PrintErrln(err)
+		return err
+	}
+
+	files, err := ms.ExportSubtree(args[0], file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		cmd.PrintErrln(err)
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Exported %d files from %s to %s\n", files, args[0], metadataExportOutput)
+	return nil
+}
+
+func runMetadataImport(cmd *cobra.Command, args []string) error {
+	ms, err := newCLIMetadataService(cmd)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(args[0])
+	if err != nil {
+		cmd.PrintErrln(err)
+		return err
+	}
+	defer file.Close()
+
+	result, err := ms.ImportSubtree(file, metadataImportTarget, metadata.CollisionPolicy(metadataImportOnCollision))
+	if result != nil {
+		fmt.Fprintf(cmd.OutOrStdout(), "Imported %d files under %s (%d skipped, %d renamed)\n",
+			result.Imported, result.Root, result.Skipped, result.Renamed)
+	}
+	if err != nil {
+		cmd.PrintErrln(err)
+		return err
+	}
+
+	return nil
+}
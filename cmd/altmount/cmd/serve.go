@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"net/http"
 	_ "net/http/pprof"
@@ -17,6 +18,8 @@ import (
 	"github.com/javi11/altmount/internal/arrs"
 	"github.com/javi11/altmount/internal/config"
 	"github.com/javi11/altmount/internal/health"
+	"github.com/javi11/altmount/internal/maintenance"
+	"github.com/javi11/altmount/internal/metrics"
 	"github.com/javi11/altmount/internal/pool"
 	"github.com/javi11/altmount/internal/progress"
 	"github.com/javi11/altmount/internal/rclone"
@@ -103,7 +106,10 @@ func runServe(cmd *cobra.Command, args []string) error {
 	progressBroadcaster := progress.NewProgressBroadcaster()
 	defer progressBroadcaster.Close()
 
-	importerService, err := initializeImporter(ctx, cfg, metadataService, db, poolManager, rcloneRCClient, configManager.GetConfigGetter(), progressBroadcaster, repos.UserRepo)
+	// Maintenance mode toggled through the API freezes background activity
+	maintenanceState := maintenance.NewState()
+
+	importerService, err := initializeImporter(ctx, cfg, metadataService, db, poolManager, rcloneRCClient, configManager.GetConfigGetter(), progressBroadcaster, repos.UserRepo, maintenanceState)
 	if err != nil {
 		return err
 	}
@@ -114,23 +120,42 @@ func runServe(cmd *cobra.Command, args []string) error {
 		}
 	}()
 
-	fs := initializeFilesystem(ctx, metadataService, repos.HealthRepo, poolManager, configManager.GetConfigGetter())
+	segmentCache := setupSegmentCache(ctx, cfg, configManager)
+	probeCache := setupProbeCache(ctx, cfg, configManager)
+	fs := initializeFilesystem(ctx, metadataService, repos.HealthRepo, poolManager, configManager.GetConfigGetter(), segmentCache, probeCache)
 
 	// 6. Setup web services
-	app, debugMode := createFiberApp(ctx, cfg)
+	app := createFiberApp(ctx)
 	authService := setupAuthService(ctx, repos.UserRepo)
 
 	arrsService := arrs.NewService(configManager.GetConfigGetter(), configManager)
+	// Syncs each instance on its sync interval, surfacing typos in instance URLs or keys early
+	// instead of on the first failed repair
+	go arrsService.RunInstanceSync(ctx)
 
 	apiServer := setupAPIServer(app, repos, authService, configManager, metadataReader, fs, poolManager, importerService, arrsService, mountService, progressBroadcaster)
+	apiServer.SetSchemaStatus(db.SchemaStatus())
+	apiServer.SetMaintenanceState(maintenanceState)
 
 	webdavHandler, err := setupWebDAV(cfg, fs, authService, repos.UserRepo, configManager)
 	if err != nil {
 		return err
 	}
+	webdavHandler.SetMaintenanceState(maintenanceState)
 
 	// Create stream handler for file streaming
-	streamHandler := setupStreamHandler(fs, repos.UserRepo)
+	streamHandler := setupStreamHandler(fs, repos.UserRepo, configManager.GetConfigGetter())
+
+	// Export internal stats to Prometheus if enabled
+	var metricsCollector *metrics.Collector
+	var metricsHandler http.Handler
+	if cfg.MetricsEnabled {
+		metricsCollector = metrics.NewCollector(poolManager, repos.MainRepo, streamHandler)
+		metricsHandler, err = metrics.NewHandler(metricsCollector)
+		if err != nil {
+			return fmt.Errorf("failed to create metrics handler: %w", err)
+		}
+	}
 
 	// Setup SPA routes
 	setupSPARoutes(app)
@@ -138,14 +163,25 @@ func runServe(cmd *cobra.Command, args []string) error {
 	// 7. Register config change handlers
 	pool.RegisterConfigHandlers(ctx, configManager, poolManager)
 	webdav.RegisterConfigHandlers(ctx, configManager, webdavHandler)
-	api.RegisterLogLevelHandler(ctx, configManager, debugMode)
+	mountService.RegisterConfigHandlers(ctx)
+	api.RegisterLogLevelHandler(ctx, configManager)
+
+	if cfg.WatchConfig {
+		if err := configManager.WatchConfig(ctx); err != nil {
+			logger.WarnContext(ctx, "Config file watching disabled", "err", err)
+		}
+	}
 
-	healthWorker, librarySyncWorker, err := startHealthWorker(ctx, cfg, repos.HealthRepo, poolManager, configManager, rcloneRCClient, arrsService)
+	healthWorker, librarySyncWorker, err := startHealthWorker(ctx, cfg, repos.HealthRepo, poolManager, configManager, rcloneRCClient, arrsService, maintenanceState)
 	if err != nil {
 		logger.Warn("Health worker initialization failed", "err", err)
 	}
 	if healthWorker != nil {
 		apiServer.SetHealthWorker(healthWorker)
+		fs.SetReadFailureHandler(healthWorker.ReportReadFailure)
+		if metricsCollector != nil {
+			metricsCollector.SetHealthWorker(healthWorker)
+		}
 	}
 	if librarySyncWorker != nil {
 		apiServer.SetLibrarySyncWorker(librarySyncWorker)
@@ -157,6 +193,8 @@ func runServe(cmd *cobra.Command, args []string) error {
 		healthController.RegisterConfigChangeHandler(configManager)
 	}
 
+	startDatabaseMaintenance(ctx, db, configManager.GetConfigGetter(), importerService, healthWorker, librarySyncWorker)
+
 	// ARRs service status logging
 	if cfg.Arrs.Enabled != nil && *cfg.Arrs.Enabled {
 		logger.InfoContext(ctx, "Arrs service ready for health monitoring and repair")
@@ -165,17 +203,17 @@ func runServe(cmd *cobra.Command, args []string) error {
 	}
 
 	// 9. Create HTTP server
-	customServer := createHTTPServer(app, webdavHandler, streamHandler, cfg.WebDAV.Port, cfg.ProfilerEnabled)
+	customServer := createHTTPServer(app, webdavHandler, streamHandler, metricsHandler, cfg.WebDAV.Port, cfg.WebDAV.Prefix, cfg.ProfilerEnabled)
 
 	logger.Info("AltMount server started",
 		"port", cfg.WebDAV.Port,
-		"webdav_path", "/webdav",
+		"webdav_path", cfg.WebDAV.Prefix,
 		"api_path", "/api",
 		"providers", len(cfg.Providers),
 		"download_workers", cfg.Streaming.MaxDownloadWorkers,
 		"processor_workers", cfg.Import.MaxProcessorWorkers)
 
-	// Set up signal handling for graceful shutdown
+	// Set up signal handling for graceful shutdown and config reload
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT, syscall.SIGHUP)
 
@@ -199,16 +237,31 @@ func runServe(cmd *cobra.Command, args []string) error {
 		}
 	}()
 
-	// Wait for shutdown signal or server error
-	select {
-	case sig := <-sigChan:
-		logger.InfoContext(ctx, "Received shutdown signal", "signal", sig.String())
-		cancel() // Cancel context to signal all services to stop
-	case err := <-serverErr:
-		logger.ErrorContext(ctx, "Server error, shutting down", "error", err)
-		cancel()
-	case <-ctx.Done():
-		logger.InfoContext(ctx, "Context cancelled, shutting down")
+	// Wait for shutdown signal or server error, reloading configuration on SIGHUP
+waitLoop:
+	for {
+		select {
+		case sig := <-sigChan:
+			if sig == syscall.SIGHUP {
+				logger.InfoContext(ctx, "Received SIGHUP, reloading configuration")
+				if err := configManager.ReloadConfig(); err != nil {
+					logger.ErrorContext(ctx, "Failed to reload configuration", "error", err)
+				} else {
+					logger.InfoContext(ctx, "Configuration reloaded")
+				}
+				continue
+			}
+
+			logger.InfoContext(ctx, "Received shutdown signal", "signal", sig.String())
+			cancel() // Cancel context to signal all services to stop
+		case err := <-serverErr:
+			logger.ErrorContext(ctx, "Server error, shutting down", "error", err)
+			cancel()
+		case <-ctx.Done():
+			logger.InfoContext(ctx, "Context cancelled, shutting down")
+		}
+
+		break waitLoop
 	}
 
 	// Start graceful shutdown sequence
@@ -261,6 +314,26 @@ func handleFiberHealth(c *fiber.Ctx) error {
 	return c.JSON(response)
 }
 
+// handleFiberReady returns a readiness check endpoint that fails while the rclone mount is down
+func handleFiberReady(mountService *rclone.MountService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		mountHealth := mountService.Health(c.Context())
+
+		status := "ok"
+		code := fiber.StatusOK
+		if !mountHealth.Healthy {
+			status = "unavailable"
+			code = fiber.StatusServiceUnavailable
+		}
+
+		return c.Status(code).JSON(map[string]any{
+			"status":    status,
+			"mount":     mountHealth,
+			"timestamp": time.Now().UTC().Format(time.RFC3339),
+		})
+	}
+}
+
 // setupSPARoutes configures Fiber SPA routing for the frontend
 func setupSPARoutes(app *fiber.App) {
 	// Determine frontend build path
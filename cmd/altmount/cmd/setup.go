@@ -19,11 +19,14 @@ import (
 	"github.com/javi11/altmount/internal/database"
 	"github.com/javi11/altmount/internal/health"
 	"github.com/javi11/altmount/internal/importer"
+	"github.com/javi11/altmount/internal/maintenance"
 	"github.com/javi11/altmount/internal/metadata"
 	"github.com/javi11/altmount/internal/nzbfilesystem"
 	"github.com/javi11/altmount/internal/pool"
 	"github.com/javi11/altmount/internal/progress"
 	"github.com/javi11/altmount/internal/rclone"
+	"github.com/javi11/altmount/internal/slogutil"
+	"github.com/javi11/altmount/internal/usenet"
 	"github.com/javi11/altmount/internal/webdav"
 	"github.com/javi11/altmount/pkg/rclonecli"
 )
@@ -40,6 +43,9 @@ type repositorySet struct {
 func initializeDatabase(ctx context.Context, cfg *config.Config) (*database.DB, error) {
 	dbConfig := database.Config{
 		DatabasePath: cfg.Database.Path,
+		JournalMode:  cfg.Database.GetJournalMode(),
+		BusyTimeout:  cfg.Database.GetBusyTimeout(),
+		Synchronous:  cfg.Database.GetSynchronous(),
 	}
 
 	db, err := database.NewDB(dbConfig)
@@ -48,6 +54,21 @@ func initializeDatabase(ctx context.Context, cfg *config.Config) (*database.DB,
 		return nil, err
 	}
 
+	if journalMode := db.JournalMode(); journalMode != dbConfig.JournalMode {
+		slog.WarnContext(ctx, "Database journal mode is not supported, using the current mode instead",
+			"requested", dbConfig.JournalMode,
+			"active", journalMode)
+	}
+
+	schema := db.SchemaStatus()
+	if schema.Migrated {
+		slog.InfoContext(ctx, "Database schema migrated",
+			"from_version", schema.PreviousVersion,
+			"to_version", schema.Version)
+	} else {
+		slog.InfoContext(ctx, "Database schema is up to date", "version", schema.Version)
+	}
+
 	return db, nil
 }
 
@@ -69,6 +90,7 @@ func initializeImporter(
 	configGetter config.ConfigGetter,
 	broadcaster *progress.ProgressBroadcaster,
 	userRepo *database.UserRepository,
+	maintenanceState *maintenance.State,
 ) (*importer.Service, error) {
 	// Set defaults for workers if not configured
 	maxProcessorWorkers := cfg.Import.MaxProcessorWorkers
@@ -77,7 +99,8 @@ func initializeImporter(
 	}
 
 	serviceConfig := importer.ServiceConfig{
-		Workers: maxProcessorWorkers,
+		Workers:             maxProcessorWorkers,
+		HighPriorityWorkers: cfg.Import.HighPriorityWorkers,
 	}
 
 	importerService, err := importer.NewService(serviceConfig, metadataService, db, poolManager, rcloneClient, configGetter, broadcaster, userRepo)
@@ -85,6 +108,7 @@ func initializeImporter(
 		slog.ErrorContext(ctx, "failed to create importer service", "err", err)
 		return nil, err
 	}
+	importerService.SetMaintenanceState(maintenanceState)
 
 	// Start importer service
 	if err := importerService.Start(ctx); err != nil {
@@ -102,6 +126,8 @@ func initializeFilesystem(
 	healthRepo *database.HealthRepository,
 	poolManager pool.Manager,
 	configGetter config.ConfigGetter,
+	segmentCache *usenet.SegmentCache,
+	probeCache *nzbfilesystem.ProbeCache,
 ) *nzbfilesystem.NzbFilesystem {
 	// Reset all in-progress file health checks on start up
 	if err := healthRepo.ResetFileAllChecking(ctx); err != nil {
@@ -115,14 +141,61 @@ func initializeFilesystem(
 		poolManager,
 		configGetter,
 	)
+	if segmentCache != nil {
+		metadataRemoteFile.SetSegmentCache(segmentCache)
+	}
+	metadataRemoteFile.SetProbeCache(probeCache)
 
 	// Create filesystem backed by metadata
 	return nzbfilesystem.NewNzbFilesystem(metadataRemoteFile)
 }
 
+// setupSegmentCache opens the on-disk segment cache if it is enabled. Streaming keeps
+// working without it, so failures are only logged.
+func setupSegmentCache(ctx context.Context, cfg *config.Config, configManager *config.Manager) *usenet.SegmentCache {
+	if cfg.Streaming.DiskCacheDir == "" {
+		return nil
+	}
+
+	segmentCache, err := usenet.NewSegmentCache(cfg.Streaming.DiskCacheDir, cfg.Streaming.DiskCacheMaxSizeMB)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to open segment disk cache, continuing without it", "err", err)
+		return nil
+	}
+
+	// The size limit can change at runtime, the directory requires a restart
+	configManager.OnConfigChange(func(oldConfig, newConfig *config.Config) {
+		if newConfig.Streaming.DiskCacheMaxSizeMB != oldConfig.Streaming.DiskCacheMaxSizeMB {
+			segmentCache.SetMaxSize(newConfig.Streaming.DiskCacheMaxSizeMB)
+			slog.InfoContext(ctx, "Segment disk cache size updated",
+				"max_size_mb", newConfig.Streaming.DiskCacheMaxSizeMB)
+		}
+	})
+
+	return segmentCache
+}
+
+// setupProbeCache creates the in-memory cache of file starts and ends, whose size can
+// change at runtime
+func setupProbeCache(ctx context.Context, cfg *config.Config, configManager *config.Manager) *nzbfilesystem.ProbeCache {
+	probeCache := nzbfilesystem.NewProbeCache(cfg.Streaming.ProbeCacheSizeMB)
+
+	configManager.OnConfigChange(func(oldConfig, newConfig *config.Config) {
+		if newConfig.Streaming.ProbeCacheSizeMB != oldConfig.Streaming.ProbeCacheSizeMB {
+			probeCache.SetMaxSize(newConfig.Streaming.ProbeCacheSizeMB)
+			slog.InfoContext(ctx, "Probe cache size updated",
+				"max_size_mb", newConfig.Streaming.ProbeCacheSizeMB)
+		}
+	})
+
+	return probeCache
+}
+
 // setupNNTPPool initializes the NNTP connection pool
 func setupNNTPPool(ctx context.Context, cfg *config.Config, poolManager pool.Manager) error {
 	if len(cfg.Providers) > 0 {
+		poolManager.SetRetryPolicy(pool.RetryPolicyFromConfig(cfg))
+		poolManager.SetSpeedLimits(pool.SpeedLimitsFromConfig(cfg))
 		providers := cfg.ToNNTPProviders()
 		if err := poolManager.SetProviders(providers); err != nil {
 			slog.ErrorContext(ctx, "failed to create initial NNTP pool", "err", err)
@@ -156,7 +229,7 @@ func setupRCloneClient(ctx context.Context, cfg *config.Config, configManager *c
 }
 
 // createFiberApp creates and configures the Fiber application
-func createFiberApp(ctx context.Context, cfg *config.Config) (*fiber.App, *bool) {
+func createFiberApp(ctx context.Context) *fiber.App {
 	app := fiber.New(fiber.Config{
 		RequestMethods: append(
 			fiber.DefaultMethods, "PROPFIND", "PROPPATCH", "MKCOL", "COPY", "MOVE", "LOCK", "UNLOCK",
@@ -173,19 +246,16 @@ func createFiberApp(ctx context.Context, cfg *config.Config) (*fiber.App, *bool)
 		},
 	})
 
-	// Conditional Fiber request logging - only in debug mode
-	debugMode := cfg.Log.Level == "debug"
-
-	// Create the logger middleware but wrap it to check debug mode
+	// Conditional Fiber request logging - only while the log level is debug
 	fiberLogger := fLogger.New()
 	app.Use(func(c *fiber.Ctx) error {
-		if debugMode {
+		if slogutil.Level() <= slog.LevelDebug {
 			return fiberLogger(c)
 		}
 		return c.Next()
 	})
 
-	return app, &debugMode
+	return app
 }
 
 // setupRepositories creates all database repositories
@@ -227,8 +297,9 @@ func setupAuthService(ctx context.Context, userRepo *database.UserRepository) *a
 func setupStreamHandler(
 	nzbFilesystem *nzbfilesystem.NzbFilesystem,
 	userRepo *database.UserRepository,
+	configGetter config.ConfigGetter,
 ) *api.StreamHandler {
-	return api.NewStreamHandler(nzbFilesystem, userRepo)
+	return api.NewStreamHandler(nzbFilesystem, userRepo, configGetter)
 }
 
 // setupAPIServer creates and configures the API server
@@ -275,6 +346,9 @@ func setupAPIServer(
 	// Add simple liveness endpoint for Docker health checks
 	app.Get("/live", handleFiberHealth)
 
+	// Add readiness endpoint that also checks the rclone mount
+	app.Get("/ready", handleFiberReady(mountService))
+
 	return apiServer
 }
 
@@ -296,10 +370,12 @@ func setupWebDAV(
 	}
 
 	webdavHandler, err := webdav.NewHandler(&webdav.Config{
-		Port:   cfg.WebDAV.Port,
-		User:   cfg.WebDAV.User,
-		Pass:   cfg.WebDAV.Password,
-		Prefix: "/webdav",
+		Port:             cfg.WebDAV.Port,
+		User:             cfg.WebDAV.User,
+		Pass:             cfg.WebDAV.Password,
+		Prefix:           cfg.WebDAV.Prefix,
+		ReadOnly:         cfg.WebDAV.ReadOnly,
+		MaxPropfindDepth: cfg.WebDAV.MaxPropfindDepth,
 	}, fs, tokenService, webdavUserRepo, configManager.GetConfigGetter())
 
 	if err != nil {
@@ -318,6 +394,7 @@ func startHealthWorker(
 	configManager *config.Manager,
 	rcloneClient rclonecli.RcloneRcClient,
 	arrsService *arrs.Service,
+	maintenanceState *maintenance.State,
 ) (*health.HealthWorker, *health.LibrarySyncWorker, error) {
 	// Create metadata service for health worker
 	metadataService := metadata.NewMetadataService(cfg.Metadata.RootPath)
@@ -349,6 +426,9 @@ func startHealthWorker(
 		rcloneClient,
 	)
 
+	healthWorker.SetMaintenanceState(maintenanceState)
+	librarySyncWorker.SetMaintenanceState(maintenanceState)
+
 	// Only start health system if enabled
 	if cfg.Health.Enabled != nil && *cfg.Health.Enabled {
 		// Start health worker with the main context
@@ -368,24 +448,54 @@ func startHealthWorker(
 	return healthWorker, librarySyncWorker, nil
 }
 
-// startMountService starts the RClone mount service if enabled
-func startMountService(ctx context.Context, cfg *config.Config, mountService *rclone.MountService, logger *slog.Logger) error {
-	if cfg.RClone.MountEnabled == nil || !*cfg.RClone.MountEnabled {
-		slog.InfoContext(ctx, "RClone mount service is disabled in configuration")
-		return nil
+// startDatabaseMaintenance starts the worker that periodically optimizes and vacuums the
+// database. It runs while the server is up and does nothing while
+// database.maintenance_interval_hours is 0, so enabling it later needs no restart.
+func startDatabaseMaintenance(
+	ctx context.Context,
+	db *database.DB,
+	configGetter config.ConfigGetter,
+	importerService *importer.Service,
+	healthWorker *health.HealthWorker,
+	librarySyncWorker *health.LibrarySyncWorker,
+) {
+	interval := func() time.Duration {
+		return time.Duration(configGetter().Database.MaintenanceIntervalHours) * time.Hour
+	}
+
+	// VACUUM blocks writers, so it waits until imports, health checks and library syncs
+	// are done
+	busy := func() bool {
+		if importerService != nil && importerService.IsBusy() {
+			return true
+		}
+		if healthWorker != nil && healthWorker.IsBusy() {
+			return true
+		}
+		return librarySyncWorker != nil && librarySyncWorker.GetStatus().IsRunning
 	}
 
+	database.NewMaintenanceWorker(db, interval, busy).Start(slogutil.With(ctx, slogutil.ComponentKey, "database"))
+}
+
+// startMountService starts the RClone mount service. Its monitor always runs, so a mount
+// started later through the API is watched too.
+func startMountService(ctx context.Context, cfg *config.Config, mountService *rclone.MountService, logger *slog.Logger) error {
 	if err := mountService.Start(ctx); err != nil {
-		slog.ErrorContext(ctx, "Failed to start mount service", "error", err)
+		slog.ErrorContext(ctx, "Failed to start mount service, retrying in the background", "error", err)
 		return err
 	}
 
+	if cfg.RClone.MountEnabled == nil || !*cfg.RClone.MountEnabled {
+		return nil
+	}
+
 	slog.InfoContext(ctx, "RClone mount service started", "mount_point", cfg.MountPath)
 	return nil
 }
 
-// createHTTPServer creates the HTTP server with routing
-func createHTTPServer(app *fiber.App, webdavHandler *webdav.Handler, streamHandler *api.StreamHandler, port int, profilerEnabled bool) *http.Server {
+// createHTTPServer creates the HTTP server with routing, metricsHandler serves /metrics when it is not nil
+func createHTTPServer(app *fiber.App, webdavHandler *webdav.Handler, streamHandler *api.StreamHandler, metricsHandler http.Handler, port int, webdavPrefix string, profilerEnabled bool) *http.Server {
 	// Mount WebDAV handler directly (no Fiber adapter needed)
 	webdavHTTPHandler := webdavHandler.GetHTTPHandler()
 
@@ -405,6 +515,12 @@ func createHTTPServer(app *fiber.App, webdavHandler *webdav.Handler, streamHandl
 			return
 		}
 
+		// Route Prometheus scrapes if metrics are enabled
+		if metricsHandler != nil && path == "/metrics" {
+			metricsHandler.ServeHTTP(w, r)
+			return
+		}
+
 		// Route stream requests directly to stream handler
 		if strings.HasPrefix(path, "/api/files/stream") {
 			streamHTTPHandler.ServeHTTP(w, r)
@@ -412,7 +528,7 @@ func createHTTPServer(app *fiber.App, webdavHandler *webdav.Handler, streamHandl
 		}
 
 		// Route WebDAV requests directly to WebDAV handler
-		if len(path) >= 7 && path[:7] == "/webdav" {
+		if path == webdavPrefix || strings.HasPrefix(path, webdavPrefix+"/") {
 			webdavHTTPHandler.ServeHTTP(w, r)
 			return
 		}
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/javi11/altmount/internal/config"
+	"github.com/javi11/nntppool/v2/pkg/nntpcli"
+	"github.com/spf13/cobra"
+)
+
+var testProvidersTimeout time.Duration
+
+func init() {
+	testProvidersCmd := &cobra.Command{
+		Use:   "test-providers",
+		Short: "Test the connection to every enabled NNTP provider",
+		Long: `Connect and authenticate to every enabled NNTP provider in the configuration file, using its TLS settings.
+Prints the result, the time taken to connect and authenticate and whether the provider allows posting.
+Exits with a non-zero status when any provider fails.`,
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE:          runTestProviders,
+	}
+
+	testProvidersCmd.Flags().DurationVar(&testProvidersTimeout, "timeout", 30*time.Second, "timeout for testing each provider")
+
+	rootCmd.AddCommand(testProvidersCmd)
+}
+
+// providerTestResult is the outcome of testing a single provider
+type providerTestResult struct {
+	provider     config.ProviderConfig
+	latency      time.Duration
+	capabilities []string
+	err          error
+}
+
+func runTestProviders(cmd *cobra.Command, args []string) error {
+	cfg, err := config.ReadConfig(configFile)
+	if err != nil {
+		cmd.PrintErrln(err)
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	var results []providerTestResult
+	for _, provider := range cfg.Providers {
+		if provider.Enabled == nil || !*provider.Enabled {
+			continue
+		}
+		results = append(results, testProvider(cmd.Context(), provider, testProvidersTimeout))
+	}
+
+	if len(results) == 0 {
+		err := fmt.Errorf("no enabled providers in %s", configFile)
+		cmd.PrintErrln(err)
+		return err
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROVIDER\tHOST\tTLS\tSTATUS\tLATENCY\tPOSTING\tERROR")
+
+	failed := 0
+	for _, result := range results {
+		p := result.provider
+
+		tls := "no"
+		if p.TLS && p.InsecureTLS {
+			tls = "insecure"
+		} else if p.TLS {
+			tls = "yes"
+		}
+
+		status, latency, posting, errMsg := "ok", result.latency.Round(time.Millisecond).String(), "-", ""
+		if result.err != nil {
+			failed++
+			status, latency, errMsg = "failed", "-", result.err.Error()
+		} else if result.capabilities != nil {
+			posting = "no"
+			if slices.Contains(result.capabilities, "POST") {
+				posting = "yes"
+			}
+		}
+
+		fmt.Fprintf(w, "%s\t%s:%d\t%s\t%s\t%s\t%s\t%s\n", p.ID, p.Host, p.Port, tls, status, latency, posting, errMsg)
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		err := fmt.Errorf("%d of %d providers failed", failed, len(results))
+		cmd.PrintErrln(err)
+		return err
+	}
+
+	return nil
+}
+
+// testProvider connects and authenticates to provider, then reads its capabilities.
+// Capabilities are left nil when the provider does not support the CAPABILITIES command,
+// the connection itself is still considered working.
+func testProvider(ctx context.Context, provider config.ProviderConfig, timeout time.Duration) providerTestResult {
+	result := providerTestResult{provider: provider}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	client := nntpcli.New()
+	dialConfig := nntpcli.DialConfig{DialTimeout: timeout}
+
+	start := time.Now()
+
+	var (
+		conn nntpcli.Connection
+		err  error
+	)
+	if provider.TLS {
+		conn, err = client.DialTLS(ctx, provider.Host, provider.Port, provider.InsecureTLS, dialConfig)
+	} else {
+		conn, err = client.Dial(ctx, provider.Host, provider.Port, dialConfig)
+	}
+	if err != nil {
+		result.err = fmt.Errorf("failed to connect: %w", err)
+		return result
+	}
+	defer conn.Close()
+
+	if provider.Username != "" && provider.Password != "" {
+		if err := conn.Authenticate(provider.Username, provider.Password); err != nil {
+			result.err = fmt.Errorf("authentication failed: %w", err)
+			return result
+		}
+	}
+
+	result.latency = time.Since(start)
+
+	if caps, err := conn.Capabilities(); err == nil {
+		result.capabilities = make([]string, 0, len(caps))
+		for _, c := range caps {
+			// Capability lines may carry arguments, e.g. "AUTHINFO USER"
+			if fields := strings.Fields(c); len(fields) > 0 {
+				result.capabilities = append(result.capabilities, strings.ToUpper(fields[0]))
+			}
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/javi11/altmount/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	validateCmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate the configuration file without starting the server",
+		Long: `Load the configuration file, check every setting and that the configured directories exist or can be created.
+Exits with a non-zero status listing the problems found. Nothing is created or modified, neither the configuration file nor the directories.`,
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE:          runValidate,
+	}
+
+	rootCmd.AddCommand(validateCmd)
+}
+
+func runValidate(cmd *cobra.Command, args []string) error {
+	cfg, err := config.ReadConfig(configFile)
+	if err != nil {
+		var verrs config.ValidationErrors
+		if errors.As(err, &verrs) {
+			cmd.PrintErrf("%s is invalid:\n", configFile)
+			for _, verr := range verrs {
+				cmd.PrintErrf("  %s: %s\n", verr.Field, verr.Message)
+			}
+		} else {
+			cmd.PrintErrln(err)
+		}
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	if err := cfg.CheckDirectories(); err != nil {
+		cmd.PrintErrf("%s is invalid:\n  %s\n", configFile, err)
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "%s is valid\n", configFile)
+	return nil
+}
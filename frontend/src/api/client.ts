@@ -1,6 +1,8 @@
 import type {
+	APIKey,
 	APIResponse,
 	AuthResponse,
+	CreateAPIKeyRequest,
 	FileHealth,
 	FileMetadata,
 	HealthCheckRequest,
@@ -11,6 +13,7 @@ import type {
 	LibrarySyncStatus,
 	ManualScanRequest,
 	PoolMetrics,
+	PoolStats,
 	QueueItem,
 	QueueStats,
 	SABnzbdAddResponse,
@@ -376,6 +379,10 @@ export class APIClient {
 		return this.request<PoolMetrics>("/system/pool/metrics");
 	}
 
+	async getPoolStats() {
+		return this.request<PoolStats>("/system/pool/stats");
+	}
+
 	async directHealthCheck(id: number) {
 		return this.request<{
 			message: string;
@@ -481,6 +488,21 @@ export class APIClient {
 		});
 	}
 
+	async getAPIKeys() {
+		return this.request<APIKey[]>("/user/api-keys");
+	}
+
+	async createAPIKey(data: CreateAPIKeyRequest) {
+		return this.request<APIKey>("/user/api-keys", {
+			method: "POST",
+			body: JSON.stringify(data),
+		});
+	}
+
+	async deleteAPIKey(id: number) {
+		return this.request<void>(`/user/api-keys/${id}`, { method: "DELETE" });
+	}
+
 	async getUsers(params?: { limit?: number; offset?: number }) {
 		const searchParams = new URLSearchParams();
 		if (params?.limit) searchParams.set("limit", params.limit.toString());
@@ -566,6 +588,22 @@ export class APIClient {
 		});
 	}
 
+	async getMaintenance() {
+		return this.request<{ enabled: boolean; reason?: string; since?: string }>(
+			"/system/maintenance",
+		);
+	}
+
+	async setMaintenance(enabled: boolean, reason?: string) {
+		return this.request<{ enabled: boolean; reason?: string; since?: string }>(
+			"/system/maintenance",
+			{
+				method: "POST",
+				body: JSON.stringify({ enabled, reason }),
+			},
+		);
+	}
+
 	// Provider endpoints
 	async testProvider(data: ProviderTestRequest) {
 		return this.request<ProviderTestResponse>("/providers/test", {
@@ -284,6 +284,14 @@ export const usePoolMetrics = () => {
 	});
 };
 
+export const usePoolStats = () => {
+	return useQuery({
+		queryKey: ["system", "pool", "stats"],
+		queryFn: () => apiClient.getPoolStats(),
+		refetchInterval: 5000,
+	});
+};
+
 export const useDirectHealthCheck = () => {
 	const queryClient = useQueryClient();
 
@@ -4,7 +4,6 @@ import { webdavClient } from "../services/webdavClient";
 import type { WebDAVFile } from "../types/webdav";
 import {
 	createBlobUrl,
-	encodeWebDAVPath,
 	getFileTypeInfo,
 	isAudioFile,
 	isTextFile,
@@ -56,7 +55,7 @@ export function useFilePreview() {
 
 			// For video and audio files, use direct streaming URL with proper encoding
 			if (isVideoFile(file.basename, file.mime) || isAudioFile(file.basename, file.mime)) {
-				const streamUrl = `/webdav${encodeWebDAVPath(path)}`;
+				const streamUrl = webdavClient.getFileUrl(path);
 				return { content: null, shouldGetAsText: false, fileInfo, streamUrl };
 			}
 
@@ -4,7 +4,7 @@ import { apiClient } from "../api/client";
 import { useToast } from "../contexts/ToastContext";
 import { webdavClient } from "../services/webdavClient";
 import type { WebDAVDirectory } from "../types/webdav";
-import { encodeWebDAVPath } from "../utils/fileUtils";
+import { configKeys } from "./useConfig";
 
 export function useWebDAVConnection() {
 	const [isConnected, setIsConnected] = useState(false);
@@ -13,7 +13,12 @@ export function useWebDAVConnection() {
 
 	const connect = useMutation({
 		mutationFn: async () => {
-			webdavClient.connect(); // Connect using cookie authentication
+			// The WebDAV server is served under the configured prefix
+			const config = await queryClient.ensureQueryData({
+				queryKey: configKeys.current(),
+				queryFn: () => apiClient.getConfig(),
+			});
+			webdavClient.connect(config.webdav.prefix || "/webdav"); // Connect using cookie authentication
 			const success = await webdavClient.testConnection();
 			if (!success) {
 				throw new Error("Failed to connect to WebDAV server - authentication required");
@@ -102,7 +107,7 @@ export function useWebDAVFileOperations() {
 	const downloadFile = useMutation({
 		mutationFn: async ({ path, filename }: { path: string; filename: string }) => {
 			// Use direct WebDAV URL for download with proper encoding
-			const downloadUrl = `/webdav${encodeWebDAVPath(path)}`;
+			const downloadUrl = webdavClient.getFileUrl(path);
 			let downloadMethod = "window";
 
 			try {
@@ -49,6 +49,7 @@ export function HealthFilters({
 							<option value="healthy">Healthy</option>
 							<option value="corrupted">Corrupted</option>
 							<option value="repair_triggered">Repair Triggered</option>
+							<option value="unmanaged">Unmanaged</option>
 						</select>
 					</fieldset>
 				</div>
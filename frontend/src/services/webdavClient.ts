@@ -1,8 +1,10 @@
 import { AuthType, createClient, type FileStat } from "webdav";
 import type { WebDAVDirectory, WebDAVFile } from "../types/webdav";
+import { encodeWebDAVPath } from "../utils/fileUtils";
 
 export class WebDAVClient {
 	private client: ReturnType<typeof createClient> | null = null;
+	private prefix = "/webdav";
 
 	// Parse and enhance error messages for better handling
 	private parseError(error: unknown, operation: string, path?: string): Error {
@@ -60,8 +62,8 @@ export class WebDAVClient {
 		return new Error(`Unknown error during ${operation}${pathInfo}`);
 	}
 
-	connect() {
-		// Use relative /webdav path and let browser handle authentication via cookies
+	connect(prefix: string) {
+		// Use the configured WebDAV prefix and let browser handle authentication via cookies
 		// No credentials needed since we use cookie-based authentication
 		const clientOptions = {
 			authType: AuthType.Auto,
@@ -74,13 +76,19 @@ export class WebDAVClient {
 			},
 		};
 
-		this.client = createClient("/webdav", clientOptions);
+		this.prefix = prefix;
+		this.client = createClient(prefix, clientOptions);
 	}
 
 	isConnected(): boolean {
 		return this.client !== null;
 	}
 
+	// Direct URL of a file under the WebDAV prefix, for downloads and streaming
+	getFileUrl(path: string): string {
+		return `${this.prefix}${encodeWebDAVPath(path)}`;
+	}
+
 	async listDirectory(path = "/", showCorrupted = false): Promise<WebDAVDirectory> {
 		if (!this.client) {
 			throw new Error("WebDAV client not connected");
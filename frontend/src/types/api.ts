@@ -56,6 +56,16 @@ export interface QueueStats {
 	total_failed: number;
 	avg_processing_time_ms: number;
 	last_updated: string;
+	fallback?: FallbackStatus;
+	depth?: number; // Pending and processing items
+	max_depth?: number; // 0 = unlimited
+}
+
+export interface FallbackStatus {
+	state: "closed" | "open" | "half_open";
+	consecutive_failures: number;
+	opened_at?: string;
+	retry_at?: string;
 }
 
 // Manual Scan types
@@ -88,6 +98,7 @@ export const HealthStatus = {
 	HEALTHY: "healthy",
 	CORRUPTED: "corrupted",
 	REPAIR_TRIGGERED: "repair_triggered",
+	UNMANAGED: "unmanaged",
 } as const;
 
 export type HealthStatus = (typeof HealthStatus)[keyof typeof HealthStatus];
@@ -113,8 +124,12 @@ export interface FileHealth {
 export interface HealthStats {
 	total: number;
 	pending: number;
+	checking?: number;
 	healthy: number;
+	repair_triggered?: number;
 	corrupted: number;
+	skipped?: number;
+	unmanaged?: number;
 }
 
 export interface HealthRetryRequest {
@@ -213,6 +228,23 @@ export interface User {
 	api_key?: string;
 	is_admin: boolean;
 	last_login?: string;
+	base_path?: string;
+}
+
+// Named API key of a user, key and download_key are only returned on creation
+export interface APIKey {
+	id: number;
+	name: string;
+	key?: string;
+	download_key?: string;
+	created_at: string;
+	expires_at?: string;
+	expired: boolean;
+}
+
+export interface CreateAPIKeyRequest {
+	name: string;
+	expires_at?: string;
 }
 
 export interface AuthResponse {
@@ -229,6 +261,10 @@ export interface UserAdminUpdateRequest {
 	is_admin: boolean;
 }
 
+export interface UserBasePathUpdateRequest {
+	base_path: string;
+}
+
 // Health Worker types
 export interface HealthCheckRequest {
 	file_path: string;
@@ -298,6 +334,43 @@ export interface PoolMetrics {
 	providers: ProviderStatus[];
 }
 
+export interface ProviderStats {
+	id: string;
+	host: string;
+	username: string;
+	is_backup: boolean;
+	state: string;
+	active_connections: number;
+	idle_connections: number;
+	total_connections: number;
+	max_connections: number;
+	errors: number;
+	failure_reason?: string;
+	articles: ProviderArticleStats;
+}
+
+export interface ArticleCounts {
+	found: number;
+	missing: number; // Article not found, e.g. past retention or taken down
+	miss_rate: number; // 0-1
+}
+
+export interface ProviderArticleStats {
+	total: ArticleCounts; // Since the server started
+	window: ArticleCounts; // Over the last window_hours hours
+	window_hours: number;
+}
+
+export interface PoolStats {
+	providers: ProviderStats[];
+	active_connections: number;
+	idle_connections: number;
+	total_connections: number;
+	max_connections: number;
+	total_errors: number;
+	timestamp: string;
+}
+
 // SABnzbd API response types
 export interface SABnzbdAddResponse {
 	status: boolean;
@@ -14,6 +14,7 @@ export interface ConfigResponse {
 	log: LogConfig;
 	sabnzbd: SABnzbdConfig;
 	arrs: ArrsConfig;
+	pool: PoolConfig;
 	providers: ProviderConfig[];
 	mount_path: string;
 	api_key?: string;
@@ -24,6 +25,9 @@ export interface WebDAVConfig {
 	port: number;
 	user: string;
 	password: string;
+	read_only?: boolean;
+	prefix?: string;
+	max_propfind_depth?: number;
 }
 
 // API server configuration
@@ -34,11 +38,17 @@ export interface APIConfig {
 // Authentication configuration
 export interface AuthConfig {
 	login_required: boolean;
+	allowed_networks?: string[];
+	trusted_proxies?: string[];
 }
 
 // Database configuration
 export interface DatabaseConfig {
 	path: string;
+	journal_mode?: string;
+	busy_timeout_ms?: number;
+	synchronous?: string;
+	maintenance_interval_hours?: number;
 }
 
 // Metadata configuration
@@ -51,6 +61,12 @@ export interface MetadataConfig {
 export interface StreamingConfig {
 	max_download_workers: number;
 	max_cache_size_mb: number;
+	read_ahead_mb?: number;
+	max_concurrent_streams_per_key?: number;
+	disk_cache_dir?: string;
+	disk_cache_max_size_mb?: number;
+	probe_window_kb?: number;
+	probe_cache_size_mb?: number;
 }
 
 // Health configuration
@@ -61,8 +77,16 @@ export interface HealthConfig {
 	check_interval_seconds?: number; // Interval in seconds (optional)
 	max_connections_for_health_checks?: number;
 	segment_sample_percentage?: number; // Percentage of segments to check (1-100)
+	sample_tiers?: HealthSampleTier[]; // Overrides segment_sample_percentage by file age
 	library_sync_interval_minutes?: number; // Library sync interval in minutes (optional)
 	check_all_segments?: boolean; // Whether to check all segments or use sampling
+	corruption_webhook_url?: string; // Notified when a file is permanently marked corrupted (empty = disabled)
+}
+
+// Sample percentage for files released at most max_age_days ago
+export interface HealthSampleTier {
+	max_age_days: number;
+	sample_percentage: number;
 }
 
 // Library sync types
@@ -111,6 +135,7 @@ export interface RCloneConfig {
 	// Mount Configuration
 	mount_enabled: boolean;
 	mount_options: Record<string, string>;
+	strict_options?: boolean;
 
 	// Mount-Specific Settings
 	allow_other: boolean;
@@ -152,26 +177,52 @@ export interface RCloneConfig {
 // Import strategy type
 export type ImportStrategy = "NONE" | "SYMLINK" | "STRM";
 
+// Segment sampling strategy type
+export type SampleStrategy = "PERCENTAGE" | "HEAD_TAIL" | "DISTRIBUTED";
+
 // Import configuration
 export interface ImportConfig {
 	max_processor_workers: number;
+	high_priority_workers?: number; // Workers reserved for high priority items, out of max_processor_workers
 	queue_processing_interval_seconds: number; // Interval in seconds for queue processing
 	allowed_file_extensions: string[];
 	max_import_connections: number;
 	import_cache_size_mb: number;
 	segment_sample_percentage: number; // Percentage of segments to check (1-100)
+	sample_strategy?: SampleStrategy; // Which segments are checked when sampling
 	import_strategy: ImportStrategy;
 	import_dir?: string;
+	min_file_size_mb?: number; // Files smaller than this are skipped (0 = no limit)
+	max_queue_depth?: number; // New NZBs are rejected beyond this many pending and processing items (0 = unlimited)
+	extension_rules?: Record<string, ExtensionRule>;
+	post_import_webhook_url?: string; // Notified after every successful import (empty = disabled)
+	post_import_webhook_secret?: string; // Write-only, used to sign webhook payloads
+	post_import_webhook_secret_set?: boolean; // For display purposes only
+}
+
+// Per-extension import rule
+export interface ExtensionRule {
+	allowed?: boolean; // Unset keeps allowed_file_extensions as is for this extension
+	strategy?: ImportStrategy;
+	min_file_size_mb?: number;
 }
 
 // Log configuration
 export interface LogConfig {
 	file: string;
 	level: string;
+	format?: string;
 	max_size: number;
 	max_age: number;
 	max_backups: number;
 	compress: boolean;
+	component_levels?: Record<string, string>;
+}
+
+// NNTP connection pool configuration, shared by all providers
+export interface PoolConfig {
+	retry_attempts?: number; // Retries for transient provider errors (0 = pool default)
+	retry_backoff_seconds?: number; // Base exponential backoff between retries (0 = no backoff)
 }
 
 // NNTP Provider configuration (sanitized)
@@ -186,6 +237,8 @@ export interface ProviderConfig {
 	password_set: boolean;
 	enabled: boolean;
 	is_backup_provider: boolean;
+	speed_limit_kbps?: number;
+	priority?: number; // Lower priorities are used first
 }
 
 // SABnzbd configuration
@@ -204,6 +257,8 @@ export interface SABnzbdCategory {
 	order: number;
 	priority: number;
 	dir: string;
+	import_strategy?: ImportStrategy;
+	import_dir?: string;
 }
 
 // Configuration update request types
@@ -240,11 +295,17 @@ export interface APIUpdateRequest {
 // Auth update request
 export interface AuthUpdateRequest {
 	login_required?: boolean;
+	allowed_networks?: string[];
+	trusted_proxies?: string[];
 }
 
 // Database update request
 export interface DatabaseUpdateRequest {
 	path?: string;
+	journal_mode?: string;
+	busy_timeout_ms?: number;
+	synchronous?: string;
+	maintenance_interval_hours?: number;
 }
 
 // Metadata update request
@@ -257,6 +318,12 @@ export interface MetadataUpdateRequest {
 export interface StreamingUpdateRequest {
 	max_download_workers?: number;
 	max_cache_size_mb?: number;
+	read_ahead_mb?: number;
+	max_concurrent_streams_per_key?: number;
+	disk_cache_dir?: string;
+	disk_cache_max_size_mb?: number;
+	probe_window_kb?: number;
+	probe_cache_size_mb?: number;
 }
 
 // Health update request
@@ -280,6 +347,7 @@ export interface RCloneUpdateRequest {
 	rc_options?: Record<string, string>;
 	mount_enabled?: boolean;
 	mount_options?: Record<string, string>;
+	strict_options?: boolean;
 
 	// Mount-Specific Settings
 	allow_other?: boolean;
@@ -321,6 +389,7 @@ export interface RCloneUpdateRequest {
 // Import update request
 export interface ImportUpdateRequest {
 	max_processor_workers?: number;
+	high_priority_workers?: number;
 	queue_processing_interval_seconds?: number; // Interval in seconds for queue processing
 	allowed_file_extensions?: string[];
 	import_strategy?: ImportStrategy;
@@ -349,6 +418,8 @@ export interface ProviderUpdateRequest {
 	insecure_tls?: boolean;
 	enabled?: boolean;
 	is_backup_provider?: boolean;
+	speed_limit_kbps?: number;
+	priority?: number;
 }
 
 // SABnzbd update request
@@ -523,6 +594,30 @@ export interface MountStatus {
 	mount_point: string;
 	error?: string;
 	started_at?: string;
+	remount_count?: number;
+	last_remount_at?: string;
+}
+
+export interface MountHealth {
+	healthy: boolean;
+	enabled: boolean;
+	mounted: boolean;
+	rc_reachable: boolean;
+	mount_point?: string;
+	checked_at: string;
+	vfs?: {
+		inUse: number;
+		diskCache?: {
+			bytesUsed: number;
+			files: number;
+			erroredFiles: number;
+			outOfSpace: boolean;
+			uploadsInProgress: number;
+			uploadsQueued: number;
+			path: string;
+		};
+	};
+	error?: string;
 }
 
 export interface ProviderFormData {
@@ -555,7 +650,7 @@ export interface SABnzbdFormData {
 }
 
 // Arrs configuration types
-export type ArrsType = "radarr" | "sonarr";
+export type ArrsType = "radarr" | "sonarr" | "lidarr" | "readarr";
 
 // Sync status types
 export type SyncStatus = "idle" | "running" | "cancelling" | "completed" | "failed";
@@ -570,7 +665,8 @@ export interface ArrsInstanceConfig {
 	url: string;
 	api_key: string;
 	enabled: boolean;
-	sync_interval_hours: number;
+	sync_interval_hours?: number; // Overrides default_sync_interval_hours when set
+	tags?: string[];
 }
 
 // Database-backed arrs instance (includes real ID from database)
@@ -581,7 +677,7 @@ export interface ArrsInstance {
 	url: string;
 	api_key: string;
 	enabled: boolean;
-	sync_interval_hours: number;
+	sync_interval_hours: number; // Effective interval, the instance override or the global default
 	last_sync_at?: string;
 	created_at: string;
 	updated_at: string;
@@ -590,8 +686,11 @@ export interface ArrsInstance {
 export interface ArrsConfig {
 	enabled: boolean;
 	max_workers: number;
+	default_sync_interval_hours?: number;
 	radarr_instances: ArrsInstanceConfig[];
 	sonarr_instances: ArrsInstanceConfig[];
+	lidarr_instances?: ArrsInstanceConfig[];
+	readarr_instances?: ArrsInstanceConfig[];
 }
 
 // Sync status and progress types
@@ -625,8 +724,11 @@ export interface SyncResult {
 export interface ArrsFormData {
 	enabled: boolean;
 	max_workers: number;
+	default_sync_interval_hours?: number;
 	radarr_instances: ArrsInstanceConfig[];
 	sonarr_instances: ArrsInstanceConfig[];
+	lidarr_instances?: ArrsInstanceConfig[];
+	readarr_instances?: ArrsInstanceConfig[];
 }
 
 // Helper type for configuration sections
@@ -18,10 +18,11 @@ type ArrsInstanceRequest struct {
 
 // ArrsInstanceResponse represents an arrs instance in API responses
 type ArrsInstanceResponse struct {
-	Name    string `json:"name"`
-	Type    string `json:"type"`
-	URL     string `json:"url"`
-	Enabled bool   `json:"enabled"`
+	Name              string `json:"name"`
+	Type              string `json:"type"`
+	URL               string `json:"url"`
+	Enabled           bool   `json:"enabled"`
+	SyncIntervalHours int    `json:"sync_interval_hours"`
 }
 
 // ArrsStatsResponse represents arrs statistics
@@ -32,6 +33,10 @@ type ArrsStatsResponse struct {
 	EnabledRadarr    int     `json:"enabled_radarr"`
 	TotalSonarr      int     `json:"total_sonarr"`
 	EnabledSonarr    int     `json:"enabled_sonarr"`
+	TotalLidarr      int     `json:"total_lidarr"`
+	EnabledLidarr    int     `json:"enabled_lidarr"`
+	TotalReadarr     int     `json:"total_readarr"`
+	EnabledReadarr   int     `json:"enabled_readarr"`
 	DueForSync       int     `json:"due_for_sync"`
 	LastSync         *string `json:"last_sync"`
 }
@@ -94,10 +99,11 @@ func (s *Server) handleListArrsInstances(c *fiber.Ctx) error {
 	response := make([]*ArrsInstanceResponse, len(instances))
 	for i, instance := range instances {
 		response[i] = &ArrsInstanceResponse{
-			Name:    instance.Name,
-			Type:    instance.Type,
-			URL:     instance.URL,
-			Enabled: instance.Enabled,
+			Name:              instance.Name,
+			Type:              instance.Type,
+			URL:               instance.URL,
+			Enabled:           instance.Enabled,
+			SyncIntervalHours: instance.SyncIntervalHours,
 		}
 	}
 
@@ -138,10 +144,11 @@ func (s *Server) handleGetArrsInstance(c *fiber.Ctx) error {
 	}
 
 	response := &ArrsInstanceResponse{
-		Name:    instance.Name,
-		Type:    instance.Type,
-		URL:     instance.URL,
-		Enabled: instance.Enabled,
+		Name:              instance.Name,
+		Type:              instance.Type,
+		URL:               instance.URL,
+		Enabled:           instance.Enabled,
+		SyncIntervalHours: instance.SyncIntervalHours,
 	}
 
 	return c.Status(200).JSON(fiber.Map{
@@ -202,6 +209,7 @@ func (s *Server) handleGetArrsStats(c *fiber.Ctx) error {
 
 	// Calculate stats from instances
 	var totalRadarr, enabledRadarr, totalSonarr, enabledSonarr int
+	var totalLidarr, enabledLidarr, totalReadarr, enabledReadarr int
 	for _, instance := range instances {
 		switch instance.Type {
 		case "radarr":
@@ -214,16 +222,30 @@ func (s *Server) handleGetArrsStats(c *fiber.Ctx) error {
 			if instance.Enabled {
 				enabledSonarr++
 			}
+		case "lidarr":
+			totalLidarr++
+			if instance.Enabled {
+				enabledLidarr++
+			}
+		case "readarr":
+			totalReadarr++
+			if instance.Enabled {
+				enabledReadarr++
+			}
 		}
 	}
 
 	response := &ArrsStatsResponse{
-		TotalInstances:   totalRadarr + totalSonarr,
-		EnabledInstances: enabledRadarr + enabledSonarr,
+		TotalInstances:   totalRadarr + totalSonarr + totalLidarr + totalReadarr,
+		EnabledInstances: enabledRadarr + enabledSonarr + enabledLidarr + enabledReadarr,
 		TotalRadarr:      totalRadarr,
 		EnabledRadarr:    enabledRadarr,
 		TotalSonarr:      totalSonarr,
 		EnabledSonarr:    enabledSonarr,
+		TotalLidarr:      totalLidarr,
+		EnabledLidarr:    enabledLidarr,
+		TotalReadarr:     totalReadarr,
+		EnabledReadarr:   enabledReadarr,
 		DueForSync:       0, // Not applicable with config-first approach
 	}
 
@@ -1,7 +1,10 @@
 package api
 
 import (
+	"errors"
 	"log/slog"
+	"path"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -27,6 +30,25 @@ type UserResponse struct {
 	APIKey    string `json:"api_key,omitempty"`
 	IsAdmin   bool   `json:"is_admin"`
 	LastLogin string `json:"last_login,omitempty"`
+	BasePath  string `json:"base_path,omitempty"`
+}
+
+// APIKeyResponse represents a named API key. The key itself is only returned when it is
+// created.
+type APIKeyResponse struct {
+	ID          int64      `json:"id"`
+	Name        string     `json:"name"`
+	Key         string     `json:"key,omitempty"`
+	DownloadKey string     `json:"download_key,omitempty"` // Hash of the key accepted by the stream endpoint
+	CreatedAt   time.Time  `json:"created_at"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	Expired     bool       `json:"expired"`
+}
+
+// CreateAPIKeyRequest represents a request to create a named API key
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // RFC 3339, omitted for a key that never expires
 }
 
 // LoginRequest represents direct authentication login request
@@ -378,8 +400,62 @@ func (s *Server) handleUpdateUserAdmin(c *fiber.Ctx) error {
 	})
 }
 
-// handleRegenerateAPIKey regenerates API key for the authenticated user
-func (s *Server) handleRegenerateAPIKey(c *fiber.Ctx) error {
+// handleUpdateUserBasePath restricts a user's WebDAV view and streams to a subtree (admin only)
+func (s *Server) handleUpdateUserBasePath(c *fiber.Ctx) error {
+	user := auth.GetUserFromContext(c)
+	if user == nil || !user.IsAdmin {
+		return c.Status(403).JSON(fiber.Map{
+			"success": false,
+			"message": "Admin privileges required",
+		})
+	}
+
+	userID := c.Params("user_id")
+	if userID == "" {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "User ID is required",
+		})
+	}
+
+	// Parse request body
+	var req struct {
+		BasePath string `json:"base_path"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Invalid request body",
+			"details": err.Error(),
+		})
+	}
+
+	// An empty path or the root removes the restriction
+	var basePath *string
+	if cleaned := path.Clean("/" + strings.TrimSpace(req.BasePath)); cleaned != "/" {
+		basePath = &cleaned
+	}
+
+	if err := s.userRepo.SetBasePath(c.Context(), userID, basePath); err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Failed to update user base path",
+			"details": err.Error(),
+		})
+	}
+
+	response := AuthResponse{
+		Message: "User base path updated successfully",
+	}
+	return c.Status(200).JSON(fiber.Map{
+		"success": true,
+		"data":    response,
+	})
+}
+
+// apiKeyOwner returns the user whose API keys a request manages: the authenticated user,
+// or the first user when authentication is disabled
+func (s *Server) apiKeyOwner(c *fiber.Ctx) *database.User {
 	// Try to get user from context (auth enabled case)
 	user := auth.GetUserFromContext(c)
 
@@ -391,6 +467,13 @@ func (s *Server) handleRegenerateAPIKey(c *fiber.Ctx) error {
 		}
 	}
 
+	return user
+}
+
+// handleRegenerateAPIKey regenerates API key for the authenticated user
+func (s *Server) handleRegenerateAPIKey(c *fiber.Ctx) error {
+	user := s.apiKeyOwner(c)
+
 	// If still no user, return error
 	if user == nil {
 		return c.Status(401).JSON(fiber.Map{
@@ -419,6 +502,149 @@ func (s *Server) handleRegenerateAPIKey(c *fiber.Ctx) error {
 	})
 }
 
+// handleListAPIKeys returns the named API keys of the authenticated user
+func (s *Server) handleListAPIKeys(c *fiber.Ctx) error {
+	user := s.apiKeyOwner(c)
+	if user == nil {
+		return c.Status(401).JSON(fiber.Map{
+			"success": false,
+			"message": "No user found",
+		})
+	}
+
+	keys, err := s.userRepo.ListAPIKeys(c.Context(), user.UserID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Failed to list API keys",
+			"details": err.Error(),
+		})
+	}
+
+	now := time.Now()
+	response := make([]APIKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		response = append(response, mapAPIKeyToResponse(key, now))
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"success": true,
+		"data":    response,
+	})
+}
+
+// handleCreateAPIKey creates a named API key for the authenticated user
+func (s *Server) handleCreateAPIKey(c *fiber.Ctx) error {
+	user := s.apiKeyOwner(c)
+	if user == nil {
+		return c.Status(401).JSON(fiber.Map{
+			"success": false,
+			"message": "No user found",
+		})
+	}
+
+	var req CreateAPIKeyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Invalid request body",
+			"details": err.Error(),
+		})
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "API key name is required",
+		})
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "API key expiry must be in the future",
+		})
+	}
+
+	key, err := s.userRepo.CreateAPIKey(c.Context(), user.UserID, req.Name, req.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, database.ErrAPIKeyNameTaken) {
+			return c.Status(409).JSON(fiber.Map{
+				"success": false,
+				"message": "An API key with this name already exists",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Failed to create API key",
+			"details": err.Error(),
+		})
+	}
+
+	slog.InfoContext(c.Context(), "API key created", "user_id", user.UserID, "key_name", key.Name)
+
+	// The key is only shown once, it cannot be listed afterwards
+	response := mapAPIKeyToResponse(key, time.Now())
+	response.Key = key.Key
+	response.DownloadKey = hashAPIKey(key.Key)
+
+	return c.Status(201).JSON(fiber.Map{
+		"success": true,
+		"data":    response,
+	})
+}
+
+// handleDeleteAPIKey revokes a named API key of the authenticated user
+func (s *Server) handleDeleteAPIKey(c *fiber.Ctx) error {
+	user := s.apiKeyOwner(c)
+	if user == nil {
+		return c.Status(401).JSON(fiber.Map{
+			"success": false,
+			"message": "No user found",
+		})
+	}
+
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Invalid API key ID",
+		})
+	}
+
+	if err := s.userRepo.DeleteAPIKey(c.Context(), user.UserID, int64(id)); err != nil {
+		if errors.Is(err, database.ErrAPIKeyNotFound) {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "API key not found",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Failed to revoke API key",
+			"details": err.Error(),
+		})
+	}
+
+	slog.InfoContext(c.Context(), "API key revoked", "user_id", user.UserID, "key_id", id)
+
+	return c.Status(200).JSON(fiber.Map{
+		"success": true,
+		"message": "API key revoked successfully",
+	})
+}
+
+// mapAPIKeyToResponse converts a database APIKey to an APIKeyResponse without the key itself
+func mapAPIKeyToResponse(key *database.APIKey, now time.Time) APIKeyResponse {
+	return APIKeyResponse{
+		ID:        key.ID,
+		Name:      key.Name,
+		CreatedAt: key.CreatedAt,
+		ExpiresAt: key.ExpiresAt,
+		Expired:   key.IsExpired(now),
+	}
+}
+
 // mapUserToResponse converts database User to API UserResponse
 func (s *Server) mapUserToResponse(user *database.User) *UserResponse {
 	// Use username as display name if no name is set
@@ -450,6 +676,10 @@ func (s *Server) mapUserToResponse(user *database.User) *UserResponse {
 		response.APIKey = *user.APIKey
 	}
 
+	if user.BasePath != nil {
+		response.BasePath = *user.BasePath
+	}
+
 	return response
 }
 
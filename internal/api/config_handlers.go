@@ -12,6 +12,7 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/javi11/altmount/internal/auth"
 	"github.com/javi11/altmount/internal/config"
+	"github.com/javi11/altmount/internal/slogutil"
 	"github.com/javi11/nntppool/v2"
 )
 
@@ -29,27 +30,18 @@ type ConfigManager interface {
 	ClearLibrarySyncFlag()
 }
 
-// parseLogLevel converts string log level to slog.Level
-func parseLogLevel(level string) slog.Level {
-	switch strings.ToLower(level) {
-	case "debug":
-		return slog.LevelDebug
-	case "warn":
-		return slog.LevelWarn
-	case "error":
-		return slog.LevelError
-	default:
-		return slog.LevelInfo
-	}
-}
-
 // ApplyLogLevel applies the log level to the global logger
 func ApplyLogLevel(level string) {
 	if level != "" {
-		slog.SetLogLoggerLevel(parseLogLevel(level))
+		slogutil.SetLevel(level)
 	}
 }
 
+// activeLogLevel returns the level the global logger is running at
+func activeLogLevel() string {
+	return strings.ToLower(slogutil.Level().String())
+}
+
 // getEffectiveLogLevel returns the effective log level, preferring new config over legacy
 func getEffectiveLogLevel(newLevel, legacyLevel string) string {
 	if newLevel != "" {
@@ -62,7 +54,7 @@ func getEffectiveLogLevel(newLevel, legacyLevel string) string {
 }
 
 // RegisterLogLevelHandler registers handler for log level configuration changes
-func RegisterLogLevelHandler(ctx context.Context, configManager *config.Manager, debugMode *bool) {
+func RegisterLogLevelHandler(ctx context.Context, configManager *config.Manager) {
 	configManager.OnConfigChange(func(oldConfig, newConfig *config.Config) {
 		// Determine old and new log levels
 		oldLevel := getEffectiveLogLevel(oldConfig.Log.Level, oldConfig.Log.Level)
@@ -71,16 +63,61 @@ func RegisterLogLevelHandler(ctx context.Context, configManager *config.Manager,
 		// Apply log level change if it changed
 		if oldLevel != newLevel {
 			ApplyLogLevel(newLevel)
-			// Update Fiber logger debug mode
-			*debugMode = newLevel == "debug"
 			slog.InfoContext(ctx, "Log level updated dynamically",
 				"old_level", oldLevel,
-				"new_level", newLevel,
-				"fiber_logging", *debugMode)
+				"new_level", newLevel)
 		}
 	})
 }
 
+// handleGetLogLevel handles GET /api/log/level
+func (s *Server) handleGetLogLevel(c *fiber.Ctx) error {
+	response := LogLevelResponse{
+		Level: activeLogLevel(),
+	}
+	if s.configManager != nil {
+		response.ConfigLevel = getEffectiveLogLevel(s.configManager.GetConfig().Log.Level, "")
+		response.Overridden = response.Level != response.ConfigLevel
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"success": true,
+		"data":    response,
+	})
+}
+
+// handleSetLogLevel handles POST /api/log/level. The level is only changed in memory, it
+// is reset to the configured level on restart or when log.level is changed.
+func (s *Server) handleSetLogLevel(c *fiber.Ctx) error {
+	var req LogLevelRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Invalid request body",
+			"details": err.Error(),
+		})
+	}
+
+	level := strings.ToLower(strings.TrimSpace(req.Level))
+	switch level {
+	case "debug", "info", "warn", "error":
+	default:
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Invalid log level",
+			"details": "level must be one of: debug, info, warn, error",
+		})
+	}
+
+	oldLevel := activeLogLevel()
+	ApplyLogLevel(level)
+	slog.InfoContext(c.Context(), "Log level changed at runtime",
+		"old_level", oldLevel,
+		"new_level", level)
+
+	return s.handleGetLogLevel(c)
+}
+
 // handleGetConfig returns the current configuration
 func (s *Server) handleGetConfig(c *fiber.Ctx) error {
 	if s.configManager == nil {
@@ -136,6 +173,7 @@ func (s *Server) handleUpdateConfig(c *fiber.Ctx) error {
 			"success": false,
 			"message": "Configuration validation failed",
 			"details": err.Error(),
+			"errors":  config.AsValidationErrors(err),
 		})
 	}
 
@@ -176,7 +214,7 @@ func (s *Server) handleUpdateConfig(c *fiber.Ctx) error {
 	})
 }
 
-// handlePatchConfigSection updates a specific configuration section
+// handlePatchConfigSection merges a partial configuration update, see config.MergePatch
 func (s *Server) handlePatchConfigSection(c *fiber.Ctx) error {
 	if s.configManager == nil {
 		return c.Status(500).JSON(fiber.Map{
@@ -206,27 +244,29 @@ func (s *Server) handlePatchConfigSection(c *fiber.Ctx) error {
 		})
 	}
 
-	// Create a copy and decode partial updates directly
-	newConfig := *currentConfig // Start with current config
-	if err := c.BodyParser(&newConfig); err != nil {
+	// Merge the partial update onto a copy of the current config, settings the client
+	// did not send keep their current value
+	newConfig, err := currentConfig.MergePatch(c.Body())
+	if err != nil {
 		return c.Status(422).JSON(fiber.Map{
 			"success": false,
-			"message": "Invalid JSON in request body",
+			"message": "Invalid configuration patch",
 			"details": err.Error(),
 		})
 	}
 
 	// Validate the new configuration with API restrictions
-	if err := s.configManager.ValidateConfigUpdate(&newConfig); err != nil {
+	if err := s.configManager.ValidateConfigUpdate(newConfig); err != nil {
 		return c.Status(422).JSON(fiber.Map{
 			"success": false,
 			"message": "Configuration validation failed",
 			"details": err.Error(),
+			"errors":  config.AsValidationErrors(err),
 		})
 	}
 
 	// Update the configuration
-	if err := s.configManager.UpdateConfig(&newConfig); err != nil {
+	if err := s.configManager.UpdateConfig(newConfig); err != nil {
 		return c.Status(500).JSON(fiber.Map{
 			"success": false,
 			"message": "Failed to update configuration",
@@ -236,7 +276,7 @@ func (s *Server) handlePatchConfigSection(c *fiber.Ctx) error {
 
 	// Ensure SABnzbd category directories exist if SABnzbd section was updated
 	if section == "sabnzbd" || section == "" {
-		if err := s.ensureSABnzbdCategoryDirectories(&newConfig); err != nil {
+		if err := s.ensureSABnzbdCategoryDirectories(newConfig); err != nil {
 			// Log the error but don't fail the update
 			slog.WarnContext(c.Context(), "Failed to create SABnzbd category directories", "error", err)
 		}
@@ -259,7 +299,7 @@ func (s *Server) handlePatchConfigSection(c *fiber.Ctx) error {
 	// Get API key for response
 	apiKey := s.getAPIKeyForConfig(c)
 
-	response := ToConfigAPIResponse(&newConfig, apiKey)
+	response := ToConfigAPIResponse(newConfig, apiKey)
 	return c.Status(200).JSON(fiber.Map{
 		"success": true,
 		"data":    response,
@@ -320,25 +360,11 @@ func (s *Server) handleValidateConfig(c *fiber.Ctx) error {
 	validationErr := s.configManager.ValidateConfig(&cfg)
 
 	response := struct {
-		Valid  bool `json:"valid"`
-		Errors []struct {
-			Field   string `json:"field"`
-			Message string `json:"message"`
-		} `json:"errors,omitempty"`
+		Valid  bool                    `json:"valid"`
+		Errors config.ValidationErrors `json:"errors,omitempty"`
 	}{
-		Valid: validationErr == nil,
-	}
-
-	if validationErr != nil {
-		response.Errors = []struct {
-			Field   string `json:"field"`
-			Message string `json:"message"`
-		}{
-			{
-				Field:   "config",
-				Message: validationErr.Error(),
-			},
-		}
+		Valid:  validationErr == nil,
+		Errors: config.AsValidationErrors(validationErr),
 	}
 
 	return c.Status(200).JSON(fiber.Map{
@@ -455,6 +481,11 @@ func (s *Server) handleCreateProvider(c *fiber.Ctx) error {
 		InsecureTLS      bool   `json:"insecure_tls"`
 		Enabled          bool   `json:"enabled"`
 		IsBackupProvider bool   `json:"is_backup_provider"`
+
+		MaxConnectionIdleTimeSeconds int `json:"max_connection_idle_time_seconds"`
+		MaxConnectionTTLSeconds      int `json:"max_connection_ttl_seconds"`
+		SpeedLimitKBps               int `json:"speed_limit_kbps"`
+		Priority                     int `json:"priority"`
 	}
 
 	if err := c.BodyParser(&createReq); err != nil {
@@ -506,6 +537,11 @@ func (s *Server) handleCreateProvider(c *fiber.Ctx) error {
 		InsecureTLS:      createReq.InsecureTLS,
 		Enabled:          &createReq.Enabled,
 		IsBackupProvider: &createReq.IsBackupProvider,
+
+		MaxConnectionIdleTimeSeconds: createReq.MaxConnectionIdleTimeSeconds,
+		MaxConnectionTTLSeconds:      createReq.MaxConnectionTTLSeconds,
+		SpeedLimitKBps:               createReq.SpeedLimitKBps,
+		Priority:                     createReq.Priority,
 	}
 
 	// Add to config
@@ -518,6 +554,7 @@ func (s *Server) handleCreateProvider(c *fiber.Ctx) error {
 			"success": false,
 			"message": "Configuration validation failed",
 			"details": err.Error(),
+			"errors":  config.AsValidationErrors(err),
 		})
 	}
 
@@ -549,6 +586,11 @@ func (s *Server) handleCreateProvider(c *fiber.Ctx) error {
 		PasswordSet:      newProvider.Password != "",
 		Enabled:          newProvider.Enabled != nil && *newProvider.Enabled,
 		IsBackupProvider: newProvider.IsBackupProvider != nil && *newProvider.IsBackupProvider,
+
+		MaxConnectionIdleTimeSeconds: newProvider.MaxConnectionIdleTimeSeconds,
+		MaxConnectionTTLSeconds:      newProvider.MaxConnectionTTLSeconds,
+		SpeedLimitKBps:               newProvider.SpeedLimitKBps,
+		Priority:                     newProvider.Priority,
 	}
 
 	return c.Status(200).JSON(fiber.Map{
@@ -615,6 +657,11 @@ func (s *Server) handleUpdateProvider(c *fiber.Ctx) error {
 		InsecureTLS      *bool   `json:"insecure_tls,omitempty"`
 		Enabled          *bool   `json:"enabled,omitempty"`
 		IsBackupProvider *bool   `json:"is_backup_provider,omitempty"`
+
+		MaxConnectionIdleTimeSeconds *int `json:"max_connection_idle_time_seconds,omitempty"`
+		MaxConnectionTTLSeconds      *int `json:"max_connection_ttl_seconds,omitempty"`
+		SpeedLimitKBps               *int `json:"speed_limit_kbps,omitempty"`
+		Priority                     *int `json:"priority,omitempty"`
 	}
 
 	if err := c.BodyParser(&updateReq); err != nil {
@@ -687,6 +734,18 @@ func (s *Server) handleUpdateProvider(c *fiber.Ctx) error {
 	if updateReq.IsBackupProvider != nil {
 		provider.IsBackupProvider = updateReq.IsBackupProvider
 	}
+	if updateReq.MaxConnectionIdleTimeSeconds != nil {
+		provider.MaxConnectionIdleTimeSeconds = *updateReq.MaxConnectionIdleTimeSeconds
+	}
+	if updateReq.MaxConnectionTTLSeconds != nil {
+		provider.MaxConnectionTTLSeconds = *updateReq.MaxConnectionTTLSeconds
+	}
+	if updateReq.SpeedLimitKBps != nil {
+		provider.SpeedLimitKBps = *updateReq.SpeedLimitKBps
+	}
+	if updateReq.Priority != nil {
+		provider.Priority = *updateReq.Priority
+	}
 
 	// Assign the updated provider back to the slice
 	newConfig.Providers[providerIndex] = provider
@@ -697,6 +756,7 @@ func (s *Server) handleUpdateProvider(c *fiber.Ctx) error {
 			"success": false,
 			"message": "Configuration validation failed",
 			"details": err.Error(),
+			"errors":  config.AsValidationErrors(err),
 		})
 	}
 
@@ -728,6 +788,11 @@ func (s *Server) handleUpdateProvider(c *fiber.Ctx) error {
 		PasswordSet:      provider.Password != "",
 		Enabled:          provider.Enabled != nil && *provider.Enabled,
 		IsBackupProvider: provider.IsBackupProvider != nil && *provider.IsBackupProvider,
+
+		MaxConnectionIdleTimeSeconds: provider.MaxConnectionIdleTimeSeconds,
+		MaxConnectionTTLSeconds:      provider.MaxConnectionTTLSeconds,
+		SpeedLimitKBps:               provider.SpeedLimitKBps,
+		Priority:                     provider.Priority,
 	}
 
 	return c.Status(200).JSON(fiber.Map{
@@ -794,6 +859,7 @@ func (s *Server) handleDeleteProvider(c *fiber.Ctx) error {
 			"success": false,
 			"message": "Configuration validation failed",
 			"details": err.Error(),
+			"errors":  config.AsValidationErrors(err),
 		})
 	}
 
@@ -904,6 +970,7 @@ func (s *Server) handleReorderProviders(c *fiber.Ctx) error {
 			"success": false,
 			"message": "Configuration validation failed",
 			"details": err.Error(),
+			"errors":  config.AsValidationErrors(err),
 		})
 	}
 
@@ -937,6 +1004,11 @@ func (s *Server) handleReorderProviders(c *fiber.Ctx) error {
 			PasswordSet:      p.Password != "",
 			Enabled:          p.Enabled != nil && *p.Enabled,
 			IsBackupProvider: p.IsBackupProvider != nil && *p.IsBackupProvider,
+
+			MaxConnectionIdleTimeSeconds: p.MaxConnectionIdleTimeSeconds,
+			MaxConnectionTTLSeconds:      p.MaxConnectionTTLSeconds,
+			SpeedLimitKBps:               p.SpeedLimitKBps,
+			Priority:                     p.Priority,
 		}
 	}
 
@@ -0,0 +1,134 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	defaultLogTailLines = 200
+	maxLogTailLines     = 5000
+	maxLogTailBytes     = 4 << 20 // Never read more than the last 4 MiB of the log file
+)
+
+// handleGetDiagnostics handles GET /api/diagnostics
+func (s *Server) handleGetDiagnostics(c *fiber.Ctx) error {
+	if s.configManager == nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Configuration management not available",
+			"details": "CONFIG_UNAVAILABLE",
+		})
+	}
+
+	cfg := s.configManager.GetConfig()
+	if cfg == nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Configuration not available",
+			"details": "CONFIG_NOT_FOUND",
+		})
+	}
+
+	lines := defaultLogTailLines
+	if linesStr := c.Query("log_lines"); linesStr != "" {
+		if n, err := strconv.Atoi(linesStr); err == nil && n >= 0 && n <= maxLogTailLines {
+			lines = n
+		}
+	}
+
+	// Secrets are redacted before anything is added to the response
+	response := DiagnosticsResponse{
+		GeneratedAt: time.Now(),
+		System:      s.getSystemInfo(),
+		Config:      cfg.Redacted(),
+		LogTail:     []string{},
+		Errors:      make(map[string]string),
+	}
+
+	if s.poolManager != nil && s.poolManager.HasPool() {
+		if stats, err := s.poolManager.Stats(); err != nil {
+			response.Errors["pool"] = err.Error()
+		} else {
+			response.Pool = &stats
+		}
+	}
+
+	if s.mountService != nil {
+		mountHealth := s.mountService.Health(c.Context())
+		response.Mount = &mountHealth
+	}
+
+	if s.healthWorker != nil {
+		stats := s.healthWorker.GetStats()
+		response.HealthWorker = &stats
+	}
+
+	if s.queueRepo != nil {
+		if stats, err := s.queueRepo.GetQueueStats(c.Context()); err != nil {
+			response.Errors["queue"] = err.Error()
+		} else {
+			response.Queue = ToQueueStatsResponse(stats)
+		}
+	}
+
+	if cfg.Log.File != "" && lines > 0 {
+		tail, err := tailFile(cfg.Log.File, lines)
+		if err != nil {
+			response.Errors["log_tail"] = err.Error()
+		}
+		for _, line := range tail {
+			response.LogTail = append(response.LogTail, cfg.RedactText(line))
+		}
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"success": true,
+		"data":    response,
+	})
+}
+
+// tailFile returns up to the last n lines of the file at path
+func tailFile(path string, n int) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log file: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat log file: %w", err)
+	}
+
+	offset := max(info.Size()-maxLogTailBytes, 0)
+	data, err := io.ReadAll(io.NewSectionReader(f, offset, info.Size()-offset))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read log file: %w", err)
+	}
+
+	// Drop the first line when reading from the middle of the file, it is likely cut short
+	if offset > 0 {
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			data = data[i+1:]
+		}
+	}
+
+	text := strings.TrimRight(string(data), "\r\n")
+	if text == "" {
+		return nil, nil
+	}
+
+	lines := strings.Split(text, "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines, nil
+}
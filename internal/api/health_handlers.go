@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
@@ -11,6 +12,7 @@ import (
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/javi11/altmount/internal/arrs"
 	"github.com/javi11/altmount/internal/database"
 )
 
@@ -46,7 +48,7 @@ func (s *Server) handleListHealth(c *fiber.Ctx) error {
 		status := database.HealthStatus(statusStr)
 		// Validate status
 		switch status {
-		case database.HealthStatusPending, database.HealthStatusChecking, database.HealthStatusCorrupted, database.HealthStatusRepairTriggered:
+		case database.HealthStatusPending, database.HealthStatusChecking, database.HealthStatusCorrupted, database.HealthStatusRepairTriggered, database.HealthStatusUnmanaged:
 			statusFilter = &status
 		default:
 			return c.Status(400).JSON(fiber.Map{
@@ -54,7 +56,7 @@ func (s *Server) handleListHealth(c *fiber.Ctx) error {
 				"error": fiber.Map{
 					"code":    "VALIDATION_ERROR",
 					"message": "Invalid status filter",
-					"details": "Valid values: pending, checking, corrupted, repair_triggered",
+					"details": "Valid values: pending, checking, corrupted, repair_triggered, unmanaged",
 				},
 			})
 		}
@@ -425,7 +427,14 @@ func (s *Server) handleRepairHealth(c *fiber.Ctx) error {
 			return c.Status(404).JSON(fiber.Map{
 				"success": false,
 				"message": "File not managed by any ARR instance",
-				"details": "This file is not found in any of the configured Radarr or Sonarr instances. Please ensure the file is in your media library and the ARR instances are properly configured.",
+				"details": "This file is not found in any of the configured Radarr, Sonarr, Lidarr or Readarr instances. Please ensure the file is in your media library and the ARR instances are properly configured.",
+			})
+		}
+		if errors.Is(err, arrs.ErrItemNotTagged) {
+			return c.Status(409).JSON(fiber.Map{
+				"success": false,
+				"message": "File not managed by AltMount in its ARR instance",
+				"details": "The ARR item for this file does not have any of the tags configured for the instance, so no rescan was triggered.",
 			})
 		}
 		// Handle other errors as internal server errors
@@ -520,7 +529,7 @@ func (s *Server) handleListCorrupted(c *fiber.Ctx) error {
 
 // handleGetHealthStats handles GET /api/health/stats
 func (s *Server) handleGetHealthStats(c *fiber.Ctx) error {
-	stats, err := s.healthRepo.GetHealthStats(c.Context())
+	stats, err := s.healthRepo.CountByStatus(c.Context())
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{
 			"success": false,
@@ -568,13 +577,13 @@ func (s *Server) handleCleanupHealth(c *fiber.Ctx) error {
 		if statusStr := c.Query("status"); statusStr != "" {
 			status := database.HealthStatus(statusStr)
 			switch status {
-			case database.HealthStatusPending, database.HealthStatusChecking, database.HealthStatusCorrupted, database.HealthStatusRepairTriggered:
+			case database.HealthStatusPending, database.HealthStatusChecking, database.HealthStatusCorrupted, database.HealthStatusRepairTriggered, database.HealthStatusUnmanaged:
 				req.Status = &status
 			default:
 				return c.Status(422).JSON(fiber.Map{
 					"success": false,
 					"message": "Invalid status filter",
-					"details": "Valid values: pending, checking, corrupted, repair_triggered",
+					"details": "Valid values: pending, checking, corrupted, repair_triggered, unmanaged",
 				})
 			}
 		}
@@ -784,6 +793,17 @@ func (s *Server) handleGetHealthWorkerStatus(c *fiber.Ctx) error {
 		PendingManualChecks:    stats.PendingManualChecks,
 		LastError:              stats.LastError,
 		ErrorCount:             stats.ErrorCount,
+		ActiveChecks:           make([]HealthActiveCheckResponse, len(stats.ActiveChecks)),
+	}
+
+	for i, check := range stats.ActiveChecks {
+		response.ActiveChecks[i] = HealthActiveCheckResponse{
+			FilePath:        check.FilePath,
+			StartedAt:       check.StartedAt,
+			Waiting:         check.Waiting,
+			SegmentsChecked: check.SegmentsChecked,
+			TotalSegments:   check.TotalSegments,
+		}
 	}
 
 	return c.Status(200).JSON(fiber.Map{
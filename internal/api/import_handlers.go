@@ -1,13 +1,12 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
-	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/javi11/altmount/internal/database"
 	"github.com/javi11/altmount/internal/importer"
 )
 
@@ -197,21 +196,25 @@ func (s *Server) handleManualImportFile(c *fiber.Ctx) error {
 		})
 	}
 
-	// Add the file to the processing queue
-	item := &database.ImportQueueItem{
-		NzbPath:      req.FilePath,
-		Priority:     database.QueuePriorityNormal,
-		Status:       database.QueueStatusPending,
-		RetryCount:   0,
-		MaxRetries:   3,
-		CreatedAt:    time.Now(),
-		RelativePath: req.RelativePath,
+	if s.importerService == nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Importer service not available",
+		})
 	}
 
 	slog.DebugContext(c.Context(), "Adding file to queue", "file", req.FilePath, "relative_path", req.RelativePath)
 
-	err = s.queueRepo.AddToQueue(c.Context(),item)
+	// Add the file to the processing queue using the centralized method, which enforces max_queue_depth
+	item, err := s.importerService.AddToQueue(req.FilePath, req.RelativePath, nil, nil)
 	if err != nil {
+		if errors.Is(err, importer.ErrQueueFull) {
+			return c.Status(503).JSON(fiber.Map{
+				"success": false,
+				"message": "Import queue is full",
+				"details": err.Error(),
+			})
+		}
 		return c.Status(500).JSON(fiber.Map{
 			"success": false,
 			"message": "Failed to add file to queue",
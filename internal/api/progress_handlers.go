@@ -26,8 +26,8 @@ func (s *Server) handleProgressStream(c *fiber.Ctx) error {
 	defer cancel()
 
 	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
-		// Subscribe to progress updates
-		subID, updateCh := s.progressBroadcaster.Subscribe()
+		// Subscribe to progress updates, getting the recent updates of running imports
+		subID, updateCh, replay := s.progressBroadcaster.SubscribeWithReplay()
 		defer s.progressBroadcaster.Unsubscribe(subID)
 
 		// Send initial progress state
@@ -43,6 +43,20 @@ func (s *Server) handleProgressStream(c *fiber.Ctx) error {
 
 		// Send initial state
 		fmt.Fprintf(w, "data: %s\n\n", initialData)
+
+		// Replay recent updates so a client joining mid-import sees its progress right away
+		for _, update := range replay {
+			updateData, err := json.Marshal(fiber.Map{
+				"type": "update",
+				"data": update,
+			})
+			if err != nil {
+				slog.ErrorContext(c.Context(), "failed to marshal progress update", "error", err, "queue_id", update.QueueID)
+				continue
+			}
+			fmt.Fprintf(w, "data: %s\n\n", updateData)
+		}
+
 		if err := w.Flush(); err != nil {
 			return
 		}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/javi11/altmount/internal/database"
+	"github.com/javi11/altmount/internal/importer"
 )
 
 // transformQueueError transforms specific errors to user-friendly messages
@@ -499,6 +501,14 @@ func (s *Server) handleGetQueueStats(c *fiber.Ctx) error {
 	}
 
 	response := ToQueueStatsResponse(stats)
+	if s.configManager != nil {
+		response.MaxDepth = s.configManager.GetConfig().Import.MaxQueueDepth
+	}
+	if s.importerService != nil && s.configManager != nil && s.configManager.GetConfig().SABnzbd.FallbackHost != "" {
+		fallback := s.importerService.GetFallbackStatus()
+		response.Fallback = &fallback
+	}
+
 	return c.Status(200).JSON(fiber.Map{
 		"success": true,
 		"data":    response,
@@ -764,6 +774,16 @@ func (s *Server) handleUploadToQueue(c *fiber.Ctx) error {
 	if err != nil {
 		// Clean up temp file on error
 		os.Remove(tempFile)
+		if errors.Is(err, importer.ErrQueueFull) {
+			return c.Status(503).JSON(fiber.Map{
+				"success": false,
+				"error": fiber.Map{
+					"code":    "QUEUE_FULL",
+					"message": "Import queue is full",
+					"details": err.Error(),
+				},
+			})
+		}
 		return c.Status(500).JSON(fiber.Map{
 			"success": false,
 			"error": fiber.Map{
@@ -35,6 +35,14 @@ func (h *RCloneHandlers) GetMountStatus(c *fiber.Ctx) error {
 	})
 }
 
+// GetMountHealth reports whether the mount is functional
+func (h *RCloneHandlers) GetMountHealth(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    h.mountService.Health(c.Context()),
+	})
+}
+
 // StartMount starts the rclone mount
 func (h *RCloneHandlers) StartMount(c *fiber.Ctx) error {
 	if err := h.mountService.Mount(c.Context()); err != nil {
@@ -162,6 +170,7 @@ func RegisterRCloneRoutes(apiGroup fiber.Router, handlers *RCloneHandlers) {
 	// Mount management
 	mountGroup := rcloneGroup.Group("/mount")
 	mountGroup.Get("/status", handlers.GetMountStatus)
+	mountGroup.Get("/health", handlers.GetMountHealth)
 	mountGroup.Post("/start", handlers.StartMount)
 	mountGroup.Post("/stop", handlers.StopMount)
 	mountGroup.Delete("/", handlers.StopMount) // Alias for stop
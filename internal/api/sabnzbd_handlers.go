@@ -19,6 +19,7 @@ import (
 	"github.com/javi11/altmount/internal/arrs"
 	"github.com/javi11/altmount/internal/config"
 	"github.com/javi11/altmount/internal/database"
+	"github.com/javi11/altmount/internal/importer"
 )
 
 var defaultCategory = config.SABnzbdCategory{
@@ -89,6 +90,10 @@ func (s *Server) handleSABnzbd(c *fiber.Ctx) error {
 		return s.handleSABnzbdGetConfig(c)
 	case "version":
 		return s.handleSABnzbdVersion(c)
+	case "pause":
+		return s.handleSABnzbdPause(c)
+	case "resume":
+		return s.handleSABnzbdResume(c)
 	default:
 		return s.writeSABnzbdErrorFiber(c, fmt.Sprintf("Unknown mode: %s", mode))
 	}
@@ -234,9 +239,13 @@ func (s *Server) handleSABnzbdAddFile(c *fiber.Ctx) error {
 
 	// Add the file to the processing queue using centralized method
 	// Pass completeDir as the base path (not tempDir) so files are placed in the correct location
-	priority := s.parseSABnzbdPriority(c.FormValue("priority"))
+	priority := s.parseSABnzbdPriority(c.FormValue("priority"), validatedCategory)
 	item, err := s.importerService.AddToQueue(tempFile, &completeDir, &validatedCategory, &priority)
 	if err != nil {
+		if errors.Is(err, importer.ErrQueueFull) {
+			_ = os.Remove(tempFile)
+			return s.writeSABnzbdQueueFullFiber(c)
+		}
 		return s.writeSABnzbdErrorFiber(c, "Failed to add to queue")
 	}
 
@@ -325,9 +334,13 @@ func (s *Server) handleSABnzbdAddUrl(c *fiber.Ctx) error {
 
 	// Add the file to the processing queue using centralized method
 	// Pass completeDir as the base path (not tempDir) so files are placed in the correct location
-	priority := s.parseSABnzbdPriority(c.Query("priority"))
+	priority := s.parseSABnzbdPriority(c.Query("priority"), validatedCategory)
 	item, err := s.importerService.AddToQueue(tempFile, &completeDir, &validatedCategory, &priority)
 	if err != nil {
+		if errors.Is(err, importer.ErrQueueFull) {
+			_ = os.Remove(tempFile)
+			return s.writeSABnzbdQueueFullFiber(c)
+		}
 		return s.writeSABnzbdErrorFiber(c, "Failed to add to queue")
 	}
 
@@ -371,12 +384,33 @@ func (s *Server) handleSABnzbdQueue(c *fiber.Ctx) error {
 		slots = append(slots, ToSABnzbdQueueSlot(item, i, s.progressBroadcaster))
 	}
 
+	queue := SABnzbdQueueObject{
+		Paused:   s.importerService.IsPaused(),
+		Mb:       formatSizeMB(0),
+		Mbleft:   formatSizeMB(0),
+		Speed:    formatSABnzbdSpeed(0),
+		KbPerSec: "0.00",
+		Timeleft: formatSABnzbdTimeLeft(0),
+		Slots:    slots,
+	}
+
+	// Aggregate figures cover the whole queue regardless of the category filter
+	summary, err := s.importerService.GetQueueSummary(c.Context())
+	if err != nil {
+		slog.WarnContext(c.Context(), "Failed to get queue summary", "error", err)
+	} else {
+		queue.Paused = summary.Paused
+		queue.NoOfSlots = summary.Slots
+		queue.Mb = formatSizeMB(summary.TotalBytes)
+		queue.Mbleft = formatSizeMB(summary.RemainingBytes)
+		queue.Speed = formatSABnzbdSpeed(summary.BytesPerSecond)
+		queue.KbPerSec = fmt.Sprintf("%.2f", summary.BytesPerSecond/1024)
+		queue.Timeleft = formatSABnzbdTimeLeft(summary.TimeLeft)
+	}
+
 	response := SABnzbdQueueResponse{
 		Status: true,
-		Queue: SABnzbdQueueObject{
-			Paused: false,
-			Slots:  slots,
-		},
+		Queue:  queue,
 	}
 
 	return s.writeSABnzbdResponseFiber(c, response)
@@ -448,13 +482,13 @@ func (s *Server) handleSABnzbdHistory(c *fiber.Ctx) error {
 
 	for _, item := range completed {
 		// Calculate category-specific base path for this item
-		itemBasePath := s.calculateItemBasePath()
+		itemBasePath := s.calculateItemBasePath(item.Category)
 		slots = append(slots, ToSABnzbdHistorySlot(item, index, itemBasePath))
 		index++
 	}
 	for _, item := range failed {
 		// Calculate category-specific base path for this item
-		itemBasePath := s.calculateItemBasePath()
+		itemBasePath := s.calculateItemBasePath(item.Category)
 		slots = append(slots, ToSABnzbdHistorySlot(item, index, itemBasePath))
 		index++
 	}
@@ -526,6 +560,8 @@ func (s *Server) handleSABnzbdStatus(c *fiber.Ctx) error {
 		}
 	}
 
+	paused := s.importerService != nil && s.importerService.IsPaused()
+
 	response := SABnzbdStatusResponse{
 		Status:          true,
 		Version:         "4.5.0",
@@ -536,7 +572,7 @@ func (s *Server) handleSABnzbdStatus(c *fiber.Ctx) error {
 		Pid:             os.Getpid(),
 		NewRelURL:       "",
 		ActiveDownload:  len(slots) > 0,
-		Paused:          false,
+		Paused:          paused,
 		PauseInt:        0,
 		Remaining:       "0 B",
 		MbLeft:          0,
@@ -652,16 +688,64 @@ func (s *Server) handleSABnzbdVersion(c *fiber.Ctx) error {
 	return s.writeSABnzbdResponseFiber(c, response)
 }
 
-// parseSABnzbdPriority converts SABnzbd priority string to AltMount priority
-func (s *Server) parseSABnzbdPriority(priority string) database.QueuePriority {
+// handleSABnzbdPause pauses the import queue. Items already being imported finish normally.
+func (s *Server) handleSABnzbdPause(c *fiber.Ctx) error {
+	if s.importerService == nil {
+		return s.writeSABnzbdErrorFiber(c, "Importer service not available")
+	}
+
+	s.importerService.Pause()
+
+	return s.writeSABnzbdResponseFiber(c, SABnzbdPauseResponse{Status: true})
+}
+
+// handleSABnzbdResume resumes the import queue after a pause
+func (s *Server) handleSABnzbdResume(c *fiber.Ctx) error {
+	if s.importerService == nil {
+		return s.writeSABnzbdErrorFiber(c, "Importer service not available")
+	}
+
+	s.importerService.Resume()
+
+	return s.writeSABnzbdResponseFiber(c, SABnzbdPauseResponse{Status: true})
+}
+
+// parseSABnzbdPriority converts SABnzbd priority string to AltMount priority.
+// SABnzbd uses -1 for low, 0 for normal, 1 for high and 2 for force; force is treated as high.
+// When no priority is given, or the SABnzbd default (-100), the category's priority is used.
+func (s *Server) parseSABnzbdPriority(priority string, category string) database.QueuePriority {
 	switch strings.ToLower(priority) {
-	case "high", "2":
+	case "force", "high", "2", "1":
 		return database.QueuePriorityHigh
-	case "low", "0":
+	case "normal", "0":
+		return database.QueuePriorityNormal
+	case "low", "-1":
 		return database.QueuePriorityLow
 	default:
+		return s.categoryPriority(category)
+	}
+}
+
+// categoryPriority returns the queue priority configured for a category, normal if it has none
+func (s *Server) categoryPriority(category string) database.QueuePriority {
+	if s.configManager == nil {
 		return database.QueuePriorityNormal
 	}
+
+	for _, configCategory := range s.configManager.GetConfig().SABnzbd.Categories {
+		if configCategory.Name != category {
+			continue
+		}
+		switch {
+		case configCategory.Priority > 0:
+			return database.QueuePriorityHigh
+		case configCategory.Priority < 0:
+			return database.QueuePriorityLow
+		}
+		break
+	}
+
+	return database.QueuePriorityNormal
 }
 
 // buildCategoryPath builds the directory path for a category
@@ -744,6 +828,19 @@ func (s *Server) writeSABnzbdErrorFiber(c *fiber.Ctx, message string) error {
 	return c.Status(200).JSON(response) // SABnzbd returns 200 even for errors
 }
 
+// writeSABnzbdQueueFullFiber rejects an addition because the import queue is full. Unlike other
+// errors it is sent as 503 so the *arr apps treat the client as temporarily unavailable and
+// retry the grab later instead of marking the release as failed.
+func (s *Server) writeSABnzbdQueueFullFiber(c *fiber.Ctx) error {
+	message := "Import queue is full, try again later"
+	response := SABnzbdResponse{
+		Status: false,
+		Error:  &message,
+	}
+	c.Set("Retry-After", "60")
+	return c.Status(fiber.StatusServiceUnavailable).JSON(response)
+}
+
 // ensureCategoryDirectories creates directories for a category in both temp and mount paths
 func (s *Server) ensureCategoryDirectories(category string) error {
 	if s.configManager == nil {
@@ -773,20 +870,26 @@ func (s *Server) ensureCategoryDirectories(category string) error {
 	return nil
 }
 
-// calculateItemBasePath calculates the base path for an item based on the import strategy configuration
-func (s *Server) calculateItemBasePath() string {
+// calculateItemBasePath calculates the base path for an item based on the import strategy
+// configuration, honouring per-category overrides
+func (s *Server) calculateItemBasePath(category *string) string {
 	if s.configManager == nil {
 		return ""
 	}
 
 	cfg := s.configManager.GetConfig()
 
+	categoryName := ""
+	if category != nil {
+		categoryName = *category
+	}
+	strategy, importDir := cfg.ImportSettingsForCategory(categoryName)
+
 	// Determine if we should use import directory or mount path
 	var basePath string
-	if cfg.Import.ImportStrategy != config.ImportStrategyNone &&
-		cfg.Import.ImportDir != nil && *cfg.Import.ImportDir != "" {
+	if strategy != config.ImportStrategyNone && importDir != nil && *importDir != "" {
 		// Use import directory as base when import strategy is enabled
-		basePath = *cfg.Import.ImportDir
+		basePath = *importDir
 	} else {
 		// Fall back to mount path
 		basePath = cfg.MountPath
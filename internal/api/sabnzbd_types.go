@@ -3,6 +3,7 @@ package api
 import (
 	"fmt"
 	"path/filepath"
+	"time"
 
 	"github.com/javi11/altmount/internal/database"
 	"github.com/javi11/altmount/internal/progress"
@@ -23,8 +24,14 @@ type SABnzbdResponse struct {
 
 // SABnzbdQueueObject represents the nested queue object in the response
 type SABnzbdQueueObject struct {
-	Paused bool               `json:"paused"`
-	Slots  []SABnzbdQueueSlot `json:"slots"`
+	Paused    bool               `json:"paused"`
+	NoOfSlots int                `json:"noofslots"`
+	Mb        string             `json:"mb"`
+	Mbleft    string             `json:"mbleft"`
+	Speed     string             `json:"speed"`
+	KbPerSec  string             `json:"kbpersec"`
+	Timeleft  string             `json:"timeleft"`
+	Slots     []SABnzbdQueueSlot `json:"slots"`
 }
 
 // SABnzbdQueueResponse represents the queue response structure
@@ -196,6 +203,11 @@ type SABnzbdAddResponse struct {
 	Error  *string  `json:"error,omitempty"`
 }
 
+// SABnzbdPauseResponse represents the response from pausing or resuming the queue
+type SABnzbdPauseResponse struct {
+	Status bool `json:"status"`
+}
+
 // SABnzbdDeleteResponse represents the response from deleting an item
 type SABnzbdDeleteResponse struct {
 	Status bool    `json:"status"`
@@ -231,6 +243,29 @@ func formatSizeMB(bytes int64) string {
 	return fmt.Sprintf("%.2f", megabytes)
 }
 
+// formatSABnzbdSpeed formats a transfer rate the way SABnzbd does, e.g. "1.5 M" or "512 K"
+func formatSABnzbdSpeed(bytesPerSecond float64) string {
+	switch {
+	case bytesPerSecond >= 1024*1024*1024:
+		return fmt.Sprintf("%.1f G", bytesPerSecond/(1024*1024*1024))
+	case bytesPerSecond >= 1024*1024:
+		return fmt.Sprintf("%.1f M", bytesPerSecond/(1024*1024))
+	case bytesPerSecond >= 1024:
+		return fmt.Sprintf("%.0f K", bytesPerSecond/1024)
+	default:
+		return fmt.Sprintf("%.0f ", bytesPerSecond)
+	}
+}
+
+// formatSABnzbdTimeLeft formats a duration as h:mm:ss like the SABnzbd queue
+func formatSABnzbdTimeLeft(d time.Duration) string {
+	total := int64(d.Round(time.Second) / time.Second)
+	if total < 0 {
+		total = 0
+	}
+	return fmt.Sprintf("%d:%02d:%02d", total/3600, total/60%60, total%60)
+}
+
 // ToSABnzbdQueueSlot converts an AltMount ImportQueueItem to SABnzbd format
 func ToSABnzbdQueueSlot(item *database.ImportQueueItem, index int, progressBroadcaster *progress.ProgressBroadcaster) SABnzbdQueueSlot {
 	if item == nil {
@@ -13,6 +13,7 @@ import (
 	"github.com/javi11/altmount/internal/database"
 	"github.com/javi11/altmount/internal/health"
 	"github.com/javi11/altmount/internal/importer"
+	"github.com/javi11/altmount/internal/maintenance"
 	"github.com/javi11/altmount/internal/metadata"
 	"github.com/javi11/altmount/internal/nzbfilesystem"
 	"github.com/javi11/altmount/internal/pool"
@@ -53,6 +54,8 @@ type Server struct {
 	mountService        *rclone.MountService
 	startTime           time.Time
 	progressBroadcaster *progress.ProgressBroadcaster
+	schemaStatus        *database.SchemaStatus
+	maintenance         *maintenance.State
 }
 
 // NewServer creates a new API server that can optionally register routes on the provided mux (for backwards compatibility)
@@ -107,6 +110,16 @@ func (s *Server) SetLibrarySyncWorker(librarySyncWorker *health.LibrarySyncWorke
 	s.librarySyncWorker = librarySyncWorker
 }
 
+// SetMaintenanceState sets the maintenance mode toggled through the API
+func (s *Server) SetMaintenanceState(state *maintenance.State) {
+	s.maintenance = state
+}
+
+// SetSchemaStatus sets the database schema status reported by the server
+func (s *Server) SetSchemaStatus(status database.SchemaStatus) {
+	s.schemaStatus = &status
+}
+
 // SetRcloneClient sets the rclone client reference for the server
 func (s *Server) SetRcloneClient(rcloneClient rclonecli.RcloneRcClient) {
 	s.rcloneClient = rcloneClient
@@ -203,9 +216,18 @@ func (s *Server) SetupRoutes(app *fiber.App) {
 	// System endpoints
 	api.Get("/system/stats", s.handleGetSystemStats)
 	api.Get("/system/health", s.handleGetSystemHealth)
+	api.Get("/system/database", s.handleGetDatabaseStatus)
+	api.Get("/system/streams", s.handleGetStreamThroughput)
 	api.Get("/system/pool/metrics", s.handleGetPoolMetrics)
+	api.Get("/system/pool/stats", s.handleGetPoolStats)
 	api.Post("/system/cleanup", s.handleSystemCleanup)
 	api.Post("/system/restart", s.handleSystemRestart)
+	api.Get("/system/maintenance", s.handleGetMaintenance)
+	api.Post("/system/maintenance", s.handleSetMaintenance)
+	// Runtime log level, not persisted to the configuration
+	api.Get("/log/level", s.handleGetLogLevel)
+	api.Post("/log/level", s.handleSetLogLevel)
+	api.Get("/diagnostics", s.handleGetDiagnostics)
 
 	api.Get("/config", s.handleGetConfig)
 	api.Put("/config", s.handleUpdateConfig)
@@ -237,10 +259,14 @@ func (s *Server) SetupRoutes(app *fiber.App) {
 	api.Post("/user/refresh", s.handleAuthRefresh)
 	api.Post("/user/logout", s.handleAuthLogout)
 	api.Post("/user/api-key/regenerate", s.handleRegenerateAPIKey)
+	api.Get("/user/api-keys", s.handleListAPIKeys)
+	api.Post("/user/api-keys", s.handleCreateAPIKey)
+	api.Delete("/user/api-keys/:id", s.handleDeleteAPIKey)
 
 	// Admin endpoints (admin check is done inside handlers)
 	api.Get("/users", s.handleListUsers)
 	api.Put("/users/:user_id/admin", s.handleUpdateUserAdmin)
+	api.Put("/users/:user_id/base-path", s.handleUpdateUserBasePath)
 }
 
 // getSystemInfo returns current system information
@@ -274,7 +300,7 @@ func (s *Server) checkSystemHealth(ctx context.Context) SystemHealthResponse {
 	}
 
 	// Check health repository
-	if _, err := s.healthRepo.GetHealthStats(ctx); err != nil {
+	if _, err := s.healthRepo.CountByStatus(ctx); err != nil {
 		components["health_repository"] = ComponentHealth{
 			Status:  "unhealthy",
 			Message: "Health repository failed",
@@ -3,15 +3,26 @@ package api
 import (
 	"context"
 	"crypto/sha256"
+	"crypto/subtle"
 	"encoding/hex"
+	"fmt"
+	"io"
 	"log/slog"
 	"mime"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/javi11/altmount/internal/auth"
+	"github.com/javi11/altmount/internal/config"
 	"github.com/javi11/altmount/internal/database"
 	"github.com/javi11/altmount/internal/nzbfilesystem"
+	"github.com/javi11/altmount/internal/slogutil"
 	"github.com/javi11/altmount/internal/utils"
 )
 
@@ -21,28 +32,49 @@ import (
 type StreamHandler struct {
 	nzbFilesystem *nzbfilesystem.NzbFilesystem
 	userRepo      *database.UserRepository
+	configGetter  config.ConfigGetter
+	networks      *auth.NetworkGuard // Rejects clients outside auth.allowed_networks, nil allows all
+
+	// Open streams per hashed API key, used to enforce max_concurrent_streams_per_key
+	activeStreams   map[string]int
+	activeStreamsMu sync.Mutex
+
+	// Total bytes written to stream responses, exported as a metric
+	bytesServed atomic.Int64
 }
 
 // NewStreamHandler creates a new stream handler with the provided filesystem and user repository
-func NewStreamHandler(fs *nzbfilesystem.NzbFilesystem, userRepo *database.UserRepository) *StreamHandler {
-	return &StreamHandler{
+func NewStreamHandler(fs *nzbfilesystem.NzbFilesystem, userRepo *database.UserRepository, configGetter config.ConfigGetter) *StreamHandler {
+	h := &StreamHandler{
 		nzbFilesystem: fs,
 		userRepo:      userRepo,
+		configGetter:  configGetter,
+		activeStreams: make(map[string]int),
+	}
+	if configGetter != nil {
+		h.networks = auth.NewNetworkGuard(func() ([]string, []string) {
+			cfg := configGetter()
+			return cfg.Auth.AllowedNetworks, cfg.Auth.TrustedProxies
+		})
 	}
+	return h
 }
 
-// authenticate validates the download_key parameter against user API keys
-// Returns true if the download_key matches a hashed API key from any user
-func (h *StreamHandler) authenticate(r *http.Request) bool {
+// authenticate validates the request credentials against user API keys and the named
+// API keys that have not expired.
+// An "Authorization: Bearer" header is preferred over the download_key query parameter,
+// which is still accepted for compatibility. The bearer token may be either the raw API
+// key or its download key hash. Returns the matching user, their hashed API key and
+// whether the credentials matched any user.
+func (h *StreamHandler) authenticate(r *http.Request) (*database.User, string, bool) {
 	ctx := r.Context()
 
-	// Extract download_key from query parameter
-	downloadKey := r.URL.Query().Get("download_key")
+	downloadKey, method := streamCredentials(r)
 	if downloadKey == "" {
-		slog.WarnContext(ctx, "Stream access attempt without download_key",
+		slog.WarnContext(ctx, "Stream access attempt without credentials",
 			"path", r.URL.Query().Get("path"),
 			"remote_addr", r.RemoteAddr)
-		return false
+		return nil, "", false
 	}
 
 	// Get all users with API keys
@@ -50,28 +82,190 @@ func (h *StreamHandler) authenticate(r *http.Request) bool {
 	if err != nil {
 		slog.ErrorContext(ctx, "Failed to get users for authentication",
 			"error", err)
-		return false
+		return nil, "", false
+	}
+
+	// A bearer token holding the raw API key is hashed the same way as the stored keys
+	candidates := []string{downloadKey}
+	if method == "authorization_header" {
+		candidates = append(candidates, hashAPIKey(downloadKey))
+	}
+
+	// matches reports whether the credentials are the hash of apiKey, returning the hash
+	matches := func(apiKey string) (string, bool) {
+		// Hash the API key with SHA256
+		hashedKey := hashAPIKey(apiKey)
+
+		// Compare with provided credentials (constant-time comparison for security)
+		for _, candidate := range candidates {
+			if subtle.ConstantTimeCompare([]byte(hashedKey), []byte(candidate)) == 1 {
+				return hashedKey, true
+			}
+		}
+		return "", false
 	}
 
-	// Check download_key against hashed API keys
+	// Check credentials against hashed API keys
 	for _, user := range users {
 		if user.APIKey == nil || *user.APIKey == "" {
 			continue
 		}
 
-		// Hash the user's API key with SHA256
-		hashedKey := hashAPIKey(*user.APIKey)
+		if hashedKey, ok := matches(*user.APIKey); ok {
+			slog.DebugContext(ctx, "Stream request authenticated", "method", method, "user_id", user.UserID)
+			return user, hashedKey, true
+		}
+	}
+
+	// Then against the named API keys that have not expired
+	keys, err := h.userRepo.GetActiveAPIKeys(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get API keys for authentication",
+			"error", err)
+		return nil, "", false
+	}
+
+	for _, key := range keys {
+		hashedKey, ok := matches(key.Key)
+		if !ok {
+			continue
+		}
 
-		// Compare with provided download_key (constant-time comparison for security)
-		if hashedKey == downloadKey {
-			return true
+		user, err := h.userRepo.GetUserByID(ctx, key.UserID)
+		if err != nil || user == nil {
+			slog.ErrorContext(ctx, "Failed to get owner of API key",
+				"key_name", key.Name,
+				"user_id", key.UserID,
+				"error", err)
+			return nil, "", false
 		}
+
+		slog.DebugContext(ctx, "Stream request authenticated", "method", method, "user_id", user.UserID, "key_name", key.Name)
+		return user, hashedKey, true
 	}
 
-	slog.WarnContext(ctx, "Stream authentication failed - invalid download_key",
+	slog.WarnContext(ctx, "Stream authentication failed - invalid credentials",
+		"method", method,
 		"path", r.URL.Query().Get("path"),
 		"remote_addr", r.RemoteAddr)
-	return false
+	return nil, "", false
+}
+
+// acquireStream reserves a stream slot for key. It returns false when the key already has
+// max_concurrent_streams_per_key open streams; zero means unlimited.
+func (h *StreamHandler) acquireStream(key string) bool {
+	limit := 0
+	if h.configGetter != nil {
+		limit = h.configGetter().Streaming.MaxConcurrentStreamsPerKey
+	}
+
+	h.activeStreamsMu.Lock()
+	defer h.activeStreamsMu.Unlock()
+
+	if limit > 0 && h.activeStreams[key] >= limit {
+		return false
+	}
+
+	h.activeStreams[key]++
+	return true
+}
+
+// releaseStream frees the stream slot reserved by acquireStream
+func (h *StreamHandler) releaseStream(key string) {
+	h.activeStreamsMu.Lock()
+	defer h.activeStreamsMu.Unlock()
+
+	h.activeStreams[key]--
+	if h.activeStreams[key] <= 0 {
+		delete(h.activeStreams, key)
+	}
+}
+
+// ActiveStreams returns the number of streams currently being served
+func (h *StreamHandler) ActiveStreams() int {
+	h.activeStreamsMu.Lock()
+	defer h.activeStreamsMu.Unlock()
+
+	total := 0
+	for _, n := range h.activeStreams {
+		total += n
+	}
+	return total
+}
+
+// BytesServed returns the total number of bytes sent in stream responses
+func (h *StreamHandler) BytesServed() int64 {
+	return h.bytesServed.Load()
+}
+
+// streamResponseWriter records the status and size of a stream response for the access
+// log, and adds the bytes written to the handler's total
+type streamResponseWriter struct {
+	http.ResponseWriter
+	bytesServed *atomic.Int64
+	status      int
+	written     int64
+}
+
+func (w *streamResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *streamResponseWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(p)
+	w.written += int64(n)
+	w.bytesServed.Add(int64(n))
+	return n, err
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (w *streamResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// logAccess writes the access log line of a served stream request. The client details are
+// only included in debug mode to keep the line short.
+func (h *StreamHandler) logAccess(r *http.Request, w *streamResponseWriter, start time.Time) {
+	status := w.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	attrs := []any{
+		"path", r.URL.Query().Get("path"),
+		"method", r.Method,
+		"status", status,
+		"bytes", w.written,
+		"range", r.Header.Get("Range"),
+		"duration_ms", time.Since(start).Milliseconds(),
+	}
+
+	if slogutil.Level() <= slog.LevelDebug {
+		attrs = append(attrs,
+			"remote_addr", r.RemoteAddr,
+			"user_agent", r.UserAgent(),
+			"if_range", r.Header.Get("If-Range"),
+			"referer", r.Referer())
+	}
+
+	slog.InfoContext(r.Context(), "Stream served", attrs...)
+}
+
+// streamCredentials returns the key sent with a stream request and how it was sent
+func streamCredentials(r *http.Request) (key, method string) {
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		if token, ok := strings.CutPrefix(auth, "Bearer "); ok && strings.TrimSpace(token) != "" {
+			return strings.TrimSpace(token), "authorization_header"
+		}
+	}
+
+	return r.URL.Query().Get("download_key"), "download_key"
 }
 
 // hashAPIKey generates a SHA256 hash of the API key for secure comparison
@@ -82,27 +276,63 @@ func hashAPIKey(apiKey string) string {
 
 // GetHTTPHandler returns an http.Handler that serves files from NzbFilesystem
 // This handler:
-// - Requires authentication via download_key parameter
+// - Requires authentication via a Bearer token or the download_key parameter
 // - Preserves context for logging and health tracking
 // - Uses http.ServeContent for automatic Range request handling
 // - Supports ETag and Last-Modified for caching
 // - Provides proper Content-Type detection
 func (h *StreamHandler) GetHTTPHandler() http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Authenticate using download_key
-		if !h.authenticate(r) {
+		// Reject clients outside auth.allowed_networks before looking at their credentials
+		if !h.networks.Allow(r) {
+			http.Error(w, "Forbidden: client network not allowed", http.StatusForbidden)
+			return
+		}
+
+		// Authenticate using a bearer token or download_key
+		user, key, ok := h.authenticate(r)
+		if !ok {
 			w.Header().Set("WWW-Authenticate", `Bearer realm="Stream API"`)
-			http.Error(w, "Unauthorized: valid download_key required", http.StatusUnauthorized)
+			http.Error(w, "Unauthorized: valid bearer token or download_key required", http.StatusUnauthorized)
 			return
 		}
 
-		// Serve the file
-		h.serveFile(w, r)
+		// Attribute all further logs of this request to the authenticated user
+		r = r.WithContext(slogutil.With(r.Context(), "user_id", user.UserID))
+
+		// Restrict users with a base path to their subtree of the library, like WebDAV
+		path := streamPath(user, r.URL.Query().Get("path"))
+
+		// Limit concurrent streams per key so a leaked key cannot exhaust the NNTP connections
+		if !h.acquireStream(key) {
+			slog.WarnContext(r.Context(), "Too many concurrent streams for key",
+				"path", r.URL.Query().Get("path"),
+				"remote_addr", r.RemoteAddr)
+			http.Error(w, "Too many concurrent streams", http.StatusTooManyRequests)
+			return
+		}
+		defer h.releaseStream(key)
+
+		// Serve the file, logging the outcome once the response is written
+		start := time.Now()
+		sw := &streamResponseWriter{ResponseWriter: w, bytesServed: &h.bytesServed}
+		h.serveFile(sw, r, path)
+		h.logAccess(r, sw, start)
 	})
 }
 
-// serveFile handles the actual file streaming after authentication
-func (h *StreamHandler) serveFile(w http.ResponseWriter, r *http.Request) {
+// streamPath returns the filesystem path of the requested path for user. Users with a base
+// path can only reach files below it. An empty requested path stays empty.
+func streamPath(user *database.User, requested string) string {
+	if requested == "" || user == nil || user.BasePath == nil {
+		return requested
+	}
+
+	return auth.ScopedPath(*user.BasePath, requested)
+}
+
+// serveFile handles the actual file streaming of path after authentication
+func (h *StreamHandler) serveFile(w http.ResponseWriter, r *http.Request, path string) {
 	ctx := r.Context()
 
 	// Enrich context with request metadata (similar to WebDAV adapter)
@@ -111,13 +341,22 @@ func (h *StreamHandler) serveFile(w http.ResponseWriter, r *http.Request) {
 	ctx = context.WithValue(ctx, utils.Origin, r.RequestURI)
 	ctx = context.WithValue(ctx, utils.ShowCorrupted, r.Header.Get("X-Show-Corrupted") == "true")
 
-	// Get path from query parameter
-	path := r.URL.Query().Get("path")
 	if path == "" {
 		http.Error(w, "Path parameter required", http.StatusBadRequest)
 		return
 	}
 
+	// Revalidations of an unchanged file are answered from metadata, without opening it
+	if utils.HasRevalidationHeaders(r) && h.serveNotModified(ctx, w, r, path) {
+		return
+	}
+
+	// HEAD requests are answered from metadata without opening the file
+	if r.Method == http.MethodHead {
+		h.serveHead(ctx, w, path)
+		return
+	}
+
 	// Open file via NzbFilesystem (handles encryption, health tracking, etc.)
 	file, err := h.nzbFilesystem.OpenFile(ctx, path, os.O_RDONLY, 0)
 	if err != nil {
@@ -137,12 +376,107 @@ func (h *StreamHandler) serveFile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	slog.DebugContext(ctx, "Streaming file",
+		"path", path,
+		"range", r.Header.Get("Range"),
+		"remote_addr", r.RemoteAddr)
+
 	// Check if it's a directory
 	if stat.IsDir() {
 		http.Error(w, "Cannot stream directory", http.StatusBadRequest)
 		return
 	}
 
+	setStreamHeaders(ctx, w, path, stat)
+
+	// Our files only honour the first range of the Range header, so requests for several
+	// ranges are answered explicitly with one file handle per range
+	if rangeHeader := r.Header.Get("Range"); strings.Contains(rangeHeader, ",") &&
+		ifRangeMatches(r, w.Header().Get("ETag"), stat.ModTime()) {
+		ranges, err := parseByteRanges(rangeHeader, stat.Size())
+		if err != nil {
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes */%d", stat.Size()))
+			http.Error(w, err.Error(), http.StatusRequestedRangeNotSatisfiable)
+			return
+		}
+
+		// Like http.ServeContent, send the whole file when the ranges add up to more than it
+		if len(ranges) > 0 && sumRangesSize(ranges) <= stat.Size() {
+			contentType := w.Header().Get("Content-Type")
+			if contentType == "" {
+				contentType = "application/octet-stream"
+			}
+
+			serveByteRanges(w, r, stat.Size(), contentType, ranges, func(ra byteRange) (io.ReadCloser, error) {
+				return h.openRange(ctx, path, ra)
+			})
+			return
+		}
+
+		r.Header.Del("Range")
+	}
+
+	// http.ServeContent will handle:
+	// - Range requests automatically (HTTP 206 Partial Content)
+	// - Content-Type detection from filename (already set above)
+	// - Last-Modified header from file modtime
+	// - If-Modified-Since conditional requests
+	// - If-None-Match and If-Range with the ETag set above
+	// - Accept-Ranges: bytes header (already set above)
+	//
+	// The file must implement io.ReadSeeker (which afero.File does)
+	http.ServeContent(w, r, filepath.Base(path), stat.ModTime(), file)
+}
+
+// serveNotModified answers 304 Not Modified when the conditional headers of r match the
+// metadata of path. It reports false, without writing anything, when the file has to be served.
+func (h *StreamHandler) serveNotModified(ctx context.Context, w http.ResponseWriter, r *http.Request, path string) bool {
+	stat, err := h.nzbFilesystem.Stat(ctx, path)
+	if err != nil || stat.IsDir() {
+		return false
+	}
+
+	etag := statETag(ctx, stat)
+	if !utils.NotModified(r, etag, stat.ModTime()) {
+		return false
+	}
+
+	utils.WriteNotModified(w, etag, stat.ModTime())
+	return true
+}
+
+// serveHead answers a HEAD request with the size, type and caching headers of a file,
+// read from its metadata so probing clients do not cause the file to be opened
+func (h *StreamHandler) serveHead(ctx context.Context, w http.ResponseWriter, path string) {
+	stat, err := h.nzbFilesystem.Stat(ctx, path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			http.Error(w, "File not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to get file information", http.StatusInternalServerError)
+		return
+	}
+
+	if stat.IsDir() {
+		http.Error(w, "Cannot stream directory", http.StatusBadRequest)
+		return
+	}
+
+	setStreamHeaders(ctx, w, path, stat)
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "application/octet-stream")
+	}
+	if !stat.ModTime().IsZero() {
+		w.Header().Set("Last-Modified", stat.ModTime().UTC().Format(http.TimeFormat))
+	}
+	w.Header().Set("Content-Length", strconv.FormatInt(stat.Size(), 10))
+	w.WriteHeader(http.StatusOK)
+}
+
+// setStreamHeaders sets the Content-Type, Accept-Ranges, ETag and Content-Disposition
+// headers for a streamed file
+func setStreamHeaders(ctx context.Context, w http.ResponseWriter, path string, stat os.FileInfo) {
 	// Set MIME type based on file extension (prevents internal seeks)
 	// This follows the same pattern as the WebDAV adapter
 	ext := filepath.Ext(path)
@@ -158,18 +492,45 @@ func (h *StreamHandler) serveFile(w http.ResponseWriter, r *http.Request) {
 	// Indicate support for range requests
 	w.Header().Set("Accept-Ranges", "bytes")
 
+	// Set an ETag derived from the file metadata so http.ServeContent can answer
+	// If-None-Match and If-Range. Corrupted files have none.
+	if etag := statETag(ctx, stat); etag != "" {
+		w.Header().Set("ETag", etag)
+	}
+
 	// Set Content-Disposition to inline for browser viewing
-	filename := filepath.Base(path)
-	w.Header().Set("Content-Disposition", `inline; filename="`+filename+`"`)
+	w.Header().Set("Content-Disposition", `inline; filename="`+filepath.Base(path)+`"`)
+}
 
-	// http.ServeContent will handle:
-	// - Range requests automatically (HTTP 206 Partial Content)
-	// - Content-Type detection from filename (already set above)
-	// - Last-Modified header from file modtime
-	// - If-Modified-Since conditional requests
-	// - If-None-Match with ETag (if we add it)
-	// - Accept-Ranges: bytes header (already set above)
-	//
-	// The file must implement io.ReadSeeker (which afero.File does)
-	http.ServeContent(w, r, filename, stat.ModTime(), file)
+// statETag returns the metadata-based ETag of a file, or an empty string when it has none
+func statETag(ctx context.Context, stat os.FileInfo) string {
+	etager, ok := stat.(interface {
+		ETag(ctx context.Context) (string, error)
+	})
+	if !ok {
+		return ""
+	}
+
+	etag, err := etager.ETag(ctx)
+	if err != nil {
+		return ""
+	}
+	return etag
+}
+
+// openRange opens path for reading a single range, positioned at the start of the range
+func (h *StreamHandler) openRange(ctx context.Context, path string, ra byteRange) (io.ReadCloser, error) {
+	ctx = context.WithValue(ctx, utils.RangeKey, fmt.Sprintf("bytes=%d-%d", ra.start, ra.start+ra.length-1))
+
+	file, err := h.nzbFilesystem.OpenFile(ctx, path, os.O_RDONLY, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := file.Seek(ra.start, io.SeekStart); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to seek to range start: %w", err)
+	}
+
+	return file, nil
 }
@@ -0,0 +1,35 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/javi11/altmount/internal/database"
+)
+
+func TestStreamPath(t *testing.T) {
+	basePath := "/movies"
+	restricted := &database.User{UserID: "restricted", BasePath: &basePath}
+	unrestricted := &database.User{UserID: "admin"}
+
+	tests := []struct {
+		name      string
+		user      *database.User
+		requested string
+		want      string
+	}{
+		{name: "unrestricted user", user: unrestricted, requested: "/tv/show.mkv", want: "/tv/show.mkv"},
+		{name: "missing path", user: restricted, requested: "", want: ""},
+		{name: "file below base path", user: restricted, requested: "/film/film.mkv", want: "/movies/film/film.mkv"},
+		{name: "relative path", user: restricted, requested: "film.mkv", want: "/movies/film.mkv"},
+		{name: "parent traversal", user: restricted, requested: "/../tv/show.mkv", want: "/movies/tv/show.mkv"},
+		{name: "nested traversal", user: restricted, requested: "/film/../../../etc/passwd", want: "/movies/etc/passwd"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := streamPath(tt.user, tt.requested); got != tt.want {
+				t.Errorf("streamPath(%q) = %q, want %q", tt.requested, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// errNoOverlap is returned when none of the requested ranges overlap the file
+var errNoOverlap = errors.New("invalid range: failed to overlap")
+
+// byteRange is a single satisfiable range of a Range header
+type byteRange struct {
+	start, length int64
+}
+
+// contentRange returns the Content-Range header value for the range
+func (r byteRange) contentRange(size int64) string {
+	return fmt.Sprintf("bytes %d-%d/%d", r.start, r.start+r.length-1, size)
+}
+
+// mimeHeader returns the part header for the range in a multipart/byteranges body
+func (r byteRange) mimeHeader(contentType string, size int64) textproto.MIMEHeader {
+	return textproto.MIMEHeader{
+		"Content-Range": {r.contentRange(size)},
+		"Content-Type":  {contentType},
+	}
+}
+
+// parseByteRanges parses a Range header such as "bytes=0-99,200-299" against a file
+// of the given size. Ranges starting past the end of the file are dropped; if none
+// remain errNoOverlap is returned.
+func parseByteRanges(s string, size int64) ([]byteRange, error) {
+	const preamble = "bytes="
+	if !strings.HasPrefix(s, preamble) {
+		return nil, errors.New("invalid range")
+	}
+
+	var ranges []byteRange
+	noOverlap := false
+	for _, spec := range strings.Split(s[len(preamble):], ",") {
+		spec = textproto.TrimString(spec)
+		if spec == "" {
+			continue
+		}
+
+		startStr, endStr, ok := strings.Cut(spec, "-")
+		if !ok {
+			return nil, errors.New("invalid range")
+		}
+		startStr, endStr = textproto.TrimString(startStr), textproto.TrimString(endStr)
+
+		var r byteRange
+		if startStr == "" {
+			// Suffix range: the last N bytes of the file
+			if endStr == "" || endStr[0] == '-' {
+				return nil, errors.New("invalid range")
+			}
+			n, err := strconv.ParseInt(endStr, 10, 64)
+			if err != nil || n < 0 {
+				return nil, errors.New("invalid range")
+			}
+			if n == 0 {
+				noOverlap = true
+				continue
+			}
+			n = min(n, size)
+			r.start = size - n
+			r.length = n
+		} else {
+			start, err := strconv.ParseInt(startStr, 10, 64)
+			if err != nil || start < 0 {
+				return nil, errors.New("invalid range")
+			}
+			if start >= size {
+				noOverlap = true
+				continue
+			}
+			r.start = start
+
+			if endStr == "" {
+				r.length = size - start
+			} else {
+				end, err := strconv.ParseInt(endStr, 10, 64)
+				if err != nil || start > end {
+					return nil, errors.New("invalid range")
+				}
+				end = min(end, size-1)
+				r.length = end - start + 1
+			}
+		}
+		ranges = append(ranges, r)
+	}
+
+	if noOverlap && len(ranges) == 0 {
+		return nil, errNoOverlap
+	}
+
+	return ranges, nil
+}
+
+// sumRangesSize returns the number of bytes covered by ranges, counting overlaps twice
+func sumRangesSize(ranges []byteRange) int64 {
+	var size int64
+	for _, r := range ranges {
+		size += r.length
+	}
+	return size
+}
+
+// ifRangeMatches reports whether the If-Range precondition allows serving a partial
+// response. A request without If-Range always matches.
+func ifRangeMatches(r *http.Request, etag string, modTime time.Time) bool {
+	ifRange := r.Header.Get("If-Range")
+	if ifRange == "" {
+		return true
+	}
+
+	// Entity tags must match strongly
+	if strings.HasPrefix(ifRange, `"`) || strings.HasPrefix(ifRange, "W/") {
+		return etag != "" && !strings.HasPrefix(etag, "W/") && ifRange == etag
+	}
+
+	t, err := http.ParseTime(ifRange)
+	if err != nil || modTime.IsZero() {
+		return false
+	}
+	return modTime.Truncate(time.Second).Equal(t)
+}
+
+// countingWriter counts the bytes written to it
+type countingWriter int64
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	*w += countingWriter(len(p))
+	return len(p), nil
+}
+
+// multipartRangesSize returns the encoded size of a multipart/byteranges body
+func multipartRangesSize(ranges []byteRange, contentType string, size int64, boundary string) int64 {
+	var w countingWriter
+	mw := multipart.NewWriter(&w)
+	_ = mw.SetBoundary(boundary)
+
+	var encSize int64
+	for _, ra := range ranges {
+		_, _ = mw.CreatePart(ra.mimeHeader(contentType, size))
+		encSize += ra.length
+	}
+	_ = mw.Close()
+
+	return encSize + int64(w)
+}
+
+// serveByteRanges writes a 206 response for ranges of a file of the given size. A single
+// range is sent as a plain body with Content-Range, several ranges as multipart/byteranges.
+// openRange must return a reader positioned at the start of the range.
+func serveByteRanges(
+	w http.ResponseWriter,
+	r *http.Request,
+	size int64,
+	contentType string,
+	ranges []byteRange,
+	openRange func(byteRange) (io.ReadCloser, error),
+) {
+	ctx := r.Context()
+
+	if len(ranges) == 1 {
+		ra := ranges[0]
+		rc, err := openRange(ra)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to open file range", "range", ra.contentRange(size), "error", err)
+			http.Error(w, "Failed to open file", http.StatusInternalServerError)
+			return
+		}
+		defer rc.Close()
+
+		w.Header().Set("Content-Range", ra.contentRange(size))
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Length", strconv.FormatInt(ra.length, 10))
+		w.WriteHeader(http.StatusPartialContent)
+
+		if r.Method != http.MethodHead {
+			if _, err := io.CopyN(w, rc, ra.length); err != nil {
+				slog.ErrorContext(ctx, "Failed to stream file range", "range", ra.contentRange(size), "error", err)
+			}
+		}
+		return
+	}
+
+	mw := multipart.NewWriter(w)
+	w.Header().Set("Content-Type", "multipart/byteranges; boundary="+mw.Boundary())
+	w.Header().Set("Content-Length", strconv.FormatInt(multipartRangesSize(ranges, contentType, size, mw.Boundary()), 10))
+	w.WriteHeader(http.StatusPartialContent)
+
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	for _, ra := range ranges {
+		part, err := mw.CreatePart(ra.mimeHeader(contentType, size))
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to write multipart range header", "error", err)
+			return
+		}
+
+		rc, err := openRange(ra)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to open file range", "range", ra.contentRange(size), "error", err)
+			return
+		}
+		_, err = io.CopyN(part, rc, ra.length)
+		rc.Close()
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to stream file range", "range", ra.contentRange(size), "error", err)
+			return
+		}
+	}
+
+	if err := mw.Close(); err != nil {
+		slog.ErrorContext(ctx, "Failed to finish multipart response", "error", err)
+	}
+}
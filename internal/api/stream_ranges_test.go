@@ -0,0 +1,153 @@
+package api
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// testRangeOpener serves ranges from an in-memory file
+func testRangeOpener(data []byte) func(byteRange) (io.ReadCloser, error) {
+	return func(ra byteRange) (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data[ra.start : ra.start+ra.length])), nil
+	}
+}
+
+func testFileData(size int) []byte {
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	return data
+}
+
+func TestParseByteRanges(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		size    int64
+		want    []byteRange
+		wantErr bool
+	}{
+		{name: "single", header: "bytes=0-99", size: 1000, want: []byteRange{{0, 100}}},
+		{name: "multiple", header: "bytes=0-99,200-299", size: 1000, want: []byteRange{{0, 100}, {200, 100}}},
+		{name: "open ended", header: "bytes=900-", size: 1000, want: []byteRange{{900, 100}}},
+		{name: "suffix", header: "bytes=-50", size: 1000, want: []byteRange{{950, 50}}},
+		{name: "end clamped", header: "bytes=990-2000", size: 1000, want: []byteRange{{990, 10}}},
+		{name: "past end dropped", header: "bytes=0-9,5000-6000", size: 1000, want: []byteRange{{0, 10}}},
+		{name: "no overlap", header: "bytes=5000-6000", size: 1000, wantErr: true},
+		{name: "bad unit", header: "items=0-9", size: 1000, wantErr: true},
+		{name: "start after end", header: "bytes=20-10", size: 1000, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseByteRanges(tt.header, tt.size)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseByteRanges(%q) expected error, got %v", tt.header, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseByteRanges(%q) unexpected error: %v", tt.header, err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("parseByteRanges(%q) = %v, want %v", tt.header, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("parseByteRanges(%q)[%d] = %v, want %v", tt.header, i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestServeByteRanges_Multipart(t *testing.T) {
+	data := testFileData(1000)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/files/stream?path=/movie.mp4", nil)
+	req.Header.Set("Range", "bytes=0-99,200-299")
+
+	ranges, err := parseByteRanges(req.Header.Get("Range"), int64(len(data)))
+	if err != nil {
+		t.Fatalf("parseByteRanges() unexpected error: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	serveByteRanges(rec, req, int64(len(data)), "video/mp4", ranges, testRangeOpener(data))
+
+	if rec.Code != http.StatusPartialContent {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusPartialContent)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(rec.Header().Get("Content-Type"))
+	if err != nil {
+		t.Fatalf("invalid Content-Type %q: %v", rec.Header().Get("Content-Type"), err)
+	}
+	if mediaType != "multipart/byteranges" {
+		t.Fatalf("Content-Type = %q, want multipart/byteranges", mediaType)
+	}
+
+	body := rec.Body.Bytes()
+	if got := rec.Header().Get("Content-Length"); got != strconv.Itoa(len(body)) {
+		t.Errorf("Content-Length = %s, want %d", got, len(body))
+	}
+
+	wantParts := []struct {
+		contentRange string
+		data         []byte
+	}{
+		{"bytes 0-99/1000", data[0:100]},
+		{"bytes 200-299/1000", data[200:300]},
+	}
+
+	mr := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	for i, want := range wantParts {
+		part, err := mr.NextPart()
+		if err != nil {
+			t.Fatalf("part %d: %v", i, err)
+		}
+		if got := part.Header.Get("Content-Range"); got != want.contentRange {
+			t.Errorf("part %d Content-Range = %q, want %q", i, got, want.contentRange)
+		}
+		if got := part.Header.Get("Content-Type"); got != "video/mp4" {
+			t.Errorf("part %d Content-Type = %q, want video/mp4", i, got)
+		}
+		got, err := io.ReadAll(part)
+		if err != nil {
+			t.Fatalf("part %d: read body: %v", i, err)
+		}
+		if !bytes.Equal(got, want.data) {
+			t.Errorf("part %d body does not match the requested range", i)
+		}
+	}
+
+	if _, err := mr.NextPart(); err != io.EOF {
+		t.Errorf("expected exactly %d parts, got extra part or error: %v", len(wantParts), err)
+	}
+}
+
+func TestServeByteRanges_SingleRange(t *testing.T) {
+	data := testFileData(1000)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/files/stream?path=/movie.mp4", nil)
+	rec := httptest.NewRecorder()
+	serveByteRanges(rec, req, int64(len(data)), "video/mp4", []byteRange{{start: 500, length: 10}}, testRangeOpener(data))
+
+	if rec.Code != http.StatusPartialContent {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusPartialContent)
+	}
+	if got := rec.Header().Get("Content-Range"); got != "bytes 500-509/1000" {
+		t.Errorf("Content-Range = %q, want %q", got, "bytes 500-509/1000")
+	}
+	if !bytes.Equal(rec.Body.Bytes(), data[500:510]) {
+		t.Errorf("body does not match the requested range")
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/javi11/altmount/internal/pool"
 )
 
 // handleGetSystemStats handles GET /api/system/stats
@@ -24,7 +25,7 @@ func (s *Server) handleGetSystemStats(c *fiber.Ctx) error {
 	}
 
 	// Get health statistics
-	healthStatsMap, err := s.healthRepo.GetHealthStats(c.Context())
+	healthStatsMap, err := s.healthRepo.CountByStatus(c.Context())
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{
 			"success": false,
@@ -79,6 +80,36 @@ func (s *Server) handleGetSystemHealth(c *fiber.Ctx) error {
 	})
 }
 
+// handleGetDatabaseStatus handles GET /api/system/database
+func (s *Server) handleGetDatabaseStatus(c *fiber.Ctx) error {
+	if s.schemaStatus == nil {
+		return c.Status(503).JSON(fiber.Map{
+			"success": false,
+			"message": "Database status not available",
+		})
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"success": true,
+		"data":    s.schemaStatus,
+	})
+}
+
+// handleGetStreamThroughput handles GET /api/system/streams
+func (s *Server) handleGetStreamThroughput(c *fiber.Ctx) error {
+	if s.nzbFilesystem == nil {
+		return c.Status(503).JSON(fiber.Map{
+			"success": false,
+			"message": "Filesystem not available",
+		})
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"success": true,
+		"data":    s.nzbFilesystem.Throughput(),
+	})
+}
+
 // handleSystemCleanup handles POST /api/system/cleanup
 func (s *Server) handleSystemCleanup(c *fiber.Ctx) error {
 	// Parse request body
@@ -178,6 +209,52 @@ func (s *Server) handleSystemCleanup(c *fiber.Ctx) error {
 	})
 }
 
+// handleGetMaintenance handles GET /api/system/maintenance
+func (s *Server) handleGetMaintenance(c *fiber.Ctx) error {
+	if s.maintenance == nil {
+		return c.Status(503).JSON(fiber.Map{
+			"success": false,
+			"message": "Maintenance mode not available",
+		})
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"success": true,
+		"data":    s.maintenance.Status(),
+	})
+}
+
+// handleSetMaintenance handles POST /api/system/maintenance. While maintenance mode is on
+// the importer and health workers stop picking up new work and WebDAV rejects writes,
+// streaming keeps working. Work already in progress is allowed to finish.
+func (s *Server) handleSetMaintenance(c *fiber.Ctx) error {
+	if s.maintenance == nil {
+		return c.Status(503).JSON(fiber.Map{
+			"success": false,
+			"message": "Maintenance mode not available",
+		})
+	}
+
+	var req MaintenanceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Invalid request body",
+			"details": err.Error(),
+		})
+	}
+
+	if req.Enabled {
+		if s.maintenance.Enable(req.Reason) {
+			slog.WarnContext(c.Context(), "Maintenance mode enabled, background activity is paused", "reason", req.Reason)
+		}
+	} else if s.maintenance.Disable() {
+		slog.InfoContext(c.Context(), "Maintenance mode disabled, background activity resumed")
+	}
+
+	return s.handleGetMaintenance(c)
+}
+
 // handleSystemRestart handles POST /api/system/restart
 func (s *Server) handleSystemRestart(c *fiber.Ctx) error {
 	// Parse request body if present
@@ -348,3 +425,40 @@ func (s *Server) handleGetPoolMetrics(c *fiber.Ctx) error {
 		"data":    response,
 	})
 }
+
+// handleGetPoolStats handles GET /api/system/pool/stats
+func (s *Server) handleGetPoolStats(c *fiber.Ctx) error {
+	// Check if pool manager is available
+	if s.poolManager == nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Pool manager not available",
+			"details": "NNTP pool manager not configured",
+		})
+	}
+
+	// Report empty stats when no providers are configured
+	if !s.poolManager.HasPool() {
+		return c.Status(200).JSON(fiber.Map{
+			"success": true,
+			"data": pool.PoolStats{
+				Providers: []pool.ProviderStats{},
+				Timestamp: time.Now(),
+			},
+		})
+	}
+
+	stats, err := s.poolManager.Stats()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Failed to get NNTP pool stats",
+			"details": err.Error(),
+		})
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"success": true,
+		"data":    stats,
+	})
+}
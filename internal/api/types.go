@@ -6,6 +6,10 @@ import (
 
 	"github.com/javi11/altmount/internal/config"
 	"github.com/javi11/altmount/internal/database"
+	"github.com/javi11/altmount/internal/health"
+	"github.com/javi11/altmount/internal/pool"
+	"github.com/javi11/altmount/internal/rclone"
+	"github.com/javi11/altmount/internal/sabnzbd"
 )
 
 // API Response Wrappers for sensitive data masking
@@ -35,8 +39,9 @@ type RCloneAPIResponse struct {
 	RCOptions map[string]string `json:"rc_options"`
 
 	// Mount Configuration
-	MountEnabled bool              `json:"mount_enabled"`
-	MountOptions map[string]string `json:"mount_options"`
+	MountEnabled  bool              `json:"mount_enabled"`
+	MountOptions  map[string]string `json:"mount_options"`
+	StrictOptions bool              `json:"strict_options"`
 
 	// Mount-Specific Settings
 	AllowOther    bool   `json:"allow_other"`
@@ -87,18 +92,30 @@ type ProviderAPIResponse struct {
 	PasswordSet      bool   `json:"password_set"`
 	Enabled          bool   `json:"enabled"`
 	IsBackupProvider bool   `json:"is_backup_provider"`
+
+	MaxConnectionIdleTimeSeconds int `json:"max_connection_idle_time_seconds,omitempty"`
+	MaxConnectionTTLSeconds      int `json:"max_connection_ttl_seconds,omitempty"`
+	SpeedLimitKBps               int `json:"speed_limit_kbps,omitempty"`
+	Priority                     int `json:"priority,omitempty"`
 }
 
 // ImportAPIResponse handles Import config for API responses
 type ImportAPIResponse struct {
-	MaxProcessorWorkers            int                   `json:"max_processor_workers"`
-	QueueProcessingIntervalSeconds int                   `json:"queue_processing_interval_seconds"` // Interval in seconds
-	AllowedFileExtensions          []string              `json:"allowed_file_extensions"`
-	MaxImportConnections           int                   `json:"max_import_connections"`
-	ImportCacheSizeMB              int                   `json:"import_cache_size_mb"`
-	SegmentSamplePercentage        int                   `json:"segment_sample_percentage"` // Percentage of segments to check (1-100)
-	ImportStrategy                 config.ImportStrategy `json:"import_strategy"`
-	ImportDir                      *string               `json:"import_dir,omitempty"`
+	MaxProcessorWorkers            int                             `json:"max_processor_workers"`
+	HighPriorityWorkers            int                             `json:"high_priority_workers"`
+	QueueProcessingIntervalSeconds int                             `json:"queue_processing_interval_seconds"` // Interval in seconds
+	AllowedFileExtensions          []string                        `json:"allowed_file_extensions"`
+	MaxImportConnections           int                             `json:"max_import_connections"`
+	ImportCacheSizeMB              int                             `json:"import_cache_size_mb"`
+	SegmentSamplePercentage        int                             `json:"segment_sample_percentage"` // Percentage of segments to check (1-100)
+	SampleStrategy                 config.SampleStrategy           `json:"sample_strategy"`
+	ImportStrategy                 config.ImportStrategy           `json:"import_strategy"`
+	ImportDir                      *string                         `json:"import_dir,omitempty"`
+	MinFileSizeMB                  int                             `json:"min_file_size_mb"`
+	MaxQueueDepth                  int                             `json:"max_queue_depth"`
+	ExtensionRules                 map[string]config.ExtensionRule `json:"extension_rules,omitempty"`
+	PostImportWebhookURL           string                          `json:"post_import_webhook_url"`
+	PostImportWebhookSecretSet     bool                            `json:"post_import_webhook_secret_set"` // The secret itself is never returned
 }
 
 // SABnzbdAPIResponse sanitizes SABnzbd config for API responses
@@ -133,6 +150,11 @@ func ToConfigAPIResponse(cfg *config.Config, apiKey string) *ConfigAPIResponse {
 			PasswordSet:      p.Password != "",
 			Enabled:          p.Enabled != nil && *p.Enabled,
 			IsBackupProvider: p.IsBackupProvider != nil && *p.IsBackupProvider,
+
+			MaxConnectionIdleTimeSeconds: p.MaxConnectionIdleTimeSeconds,
+			MaxConnectionTTLSeconds:      p.MaxConnectionTTLSeconds,
+			SpeedLimitKBps:               p.SpeedLimitKBps,
+			Priority:                     p.Priority,
 		}
 	}
 
@@ -149,6 +171,8 @@ func ToConfigAPIResponse(cfg *config.Config, apiKey string) *ConfigAPIResponse {
 		MountEnabled: cfg.RClone.MountEnabled != nil && *cfg.RClone.MountEnabled,
 		MountOptions: cfg.RClone.MountOptions,
 
+		StrictOptions: cfg.RClone.StrictOptions,
+
 		// Mount-Specific Settings
 		AllowOther:    cfg.RClone.AllowOther,
 		AllowNonEmpty: cfg.RClone.AllowNonEmpty,
@@ -214,13 +238,20 @@ func ToConfigAPIResponse(cfg *config.Config, apiKey string) *ConfigAPIResponse {
 func ToImportAPIResponse(importConfig config.ImportConfig) ImportAPIResponse {
 	return ImportAPIResponse{
 		MaxProcessorWorkers:            importConfig.MaxProcessorWorkers,
+		HighPriorityWorkers:            importConfig.HighPriorityWorkers,
 		QueueProcessingIntervalSeconds: importConfig.QueueProcessingIntervalSeconds,
 		AllowedFileExtensions:          importConfig.AllowedFileExtensions,
 		MaxImportConnections:           importConfig.MaxImportConnections,
 		ImportCacheSizeMB:              importConfig.ImportCacheSizeMB,
 		SegmentSamplePercentage:        importConfig.SegmentSamplePercentage,
+		SampleStrategy:                 importConfig.SampleStrategy,
 		ImportStrategy:                 importConfig.ImportStrategy,
 		ImportDir:                      importConfig.ImportDir,
+		MinFileSizeMB:                  importConfig.MinFileSizeMB,
+		MaxQueueDepth:                  importConfig.MaxQueueDepth,
+		ExtensionRules:                 importConfig.ExtensionRules,
+		PostImportWebhookURL:           importConfig.PostImportWebhookURL,
+		PostImportWebhookSecretSet:     importConfig.PostImportWebhookSecret != "",
 	}
 }
 
@@ -295,12 +326,15 @@ type QueueListRequest struct {
 
 // QueueStatsResponse represents queue statistics in API responses
 type QueueStatsResponse struct {
-	TotalQueued         int       `json:"total_queued"`
-	TotalProcessing     int       `json:"total_processing"`
-	TotalCompleted      int       `json:"total_completed"`
-	TotalFailed         int       `json:"total_failed"`
-	AvgProcessingTimeMs *int      `json:"avg_processing_time_ms"`
-	LastUpdated         time.Time `json:"last_updated"`
+	TotalQueued         int                    `json:"total_queued"`
+	TotalProcessing     int                    `json:"total_processing"`
+	TotalCompleted      int                    `json:"total_completed"`
+	TotalFailed         int                    `json:"total_failed"`
+	AvgProcessingTimeMs *int                   `json:"avg_processing_time_ms"`
+	LastUpdated         time.Time              `json:"last_updated"`
+	Fallback            *sabnzbd.BreakerStatus `json:"fallback,omitempty"` // Set when a SABnzbd fallback host is configured
+	Depth               int                    `json:"depth"`              // Pending and processing items, counted against max_queue_depth
+	MaxDepth            int                    `json:"max_depth"`          // 0 = unlimited
 }
 
 // Health API Types
@@ -333,9 +367,14 @@ type HealthListRequest struct {
 
 // HealthStatsResponse represents health statistics in API responses
 type HealthStatsResponse struct {
-	Total     int `json:"total"`
-	Pending   int `json:"pending"`
-	Corrupted int `json:"corrupted"`
+	Total           int64 `json:"total"`
+	Pending         int64 `json:"pending"`
+	Checking        int64 `json:"checking"`
+	Healthy         int64 `json:"healthy"`
+	RepairTriggered int64 `json:"repair_triggered"`
+	Corrupted       int64 `json:"corrupted"`
+	Skipped         int64 `json:"skipped"`
+	Unmanaged       int64 `json:"unmanaged"`
 }
 
 // HealthRetryRequest represents request to retry a corrupted file
@@ -377,6 +416,17 @@ type HealthWorkerStatusResponse struct {
 	PendingManualChecks    int        `json:"pending_manual_checks"`
 	LastError              *string    `json:"last_error,omitempty"`
 	ErrorCount             int64      `json:"error_count"`
+
+	ActiveChecks []HealthActiveCheckResponse `json:"active_checks"`
+}
+
+// HealthActiveCheckResponse represents a health check that is currently running
+type HealthActiveCheckResponse struct {
+	FilePath        string    `json:"file_path"`
+	StartedAt       time.Time `json:"started_at"`
+	Waiting         bool      `json:"waiting"`
+	SegmentsChecked int       `json:"segments_checked"`
+	TotalSegments   int       `json:"total_segments"`
 }
 
 // System API Types
@@ -424,6 +474,24 @@ type SystemCleanupResponse struct {
 	DryRun               bool `json:"dry_run"`
 }
 
+// LogLevelRequest represents a request to change the log level at runtime
+type LogLevelRequest struct {
+	Level string `json:"level"`
+}
+
+// LogLevelResponse represents the active log level
+type LogLevelResponse struct {
+	Level       string `json:"level"`        // Level the logger is running at
+	ConfigLevel string `json:"config_level"` // Level set in the configuration
+	Overridden  bool   `json:"overridden"`   // Level was changed at runtime and differs from the configuration
+}
+
+// MaintenanceRequest represents a request to turn maintenance mode on or off
+type MaintenanceRequest struct {
+	Enabled bool   `json:"enabled"`
+	Reason  string `json:"reason,omitempty"` // Shown in the status, e.g. "backup running"
+}
+
 // SystemRestartRequest represents request for system restart
 type SystemRestartRequest struct {
 	Force bool `json:"force,omitempty"` // Force restart even if unsafe
@@ -435,6 +503,20 @@ type SystemRestartResponse struct {
 	Timestamp time.Time `json:"timestamp"`
 }
 
+// DiagnosticsResponse bundles everything needed to triage a bug report. The config is
+// redacted and secrets are scrubbed from the log tail before it is returned.
+type DiagnosticsResponse struct {
+	GeneratedAt  time.Time           `json:"generated_at"`
+	System       SystemInfoResponse  `json:"system"`
+	Config       *config.Config      `json:"config"`
+	Pool         *pool.PoolStats     `json:"pool,omitempty"`
+	Mount        *rclone.MountHealth `json:"mount,omitempty"` // Includes the VFS cache stats
+	HealthWorker *health.WorkerStats `json:"health_worker,omitempty"`
+	Queue        *QueueStatsResponse `json:"queue,omitempty"`
+	LogTail      []string            `json:"log_tail"`
+	Errors       map[string]string   `json:"errors,omitempty"` // Sections that could not be collected
+}
+
 // Configuration API Types - Now using core config types directly with minimal wrappers above
 
 // Converter functions
@@ -486,6 +568,7 @@ func ToQueueStatsResponse(stats *database.QueueStats) *QueueStatsResponse {
 		TotalFailed:         stats.TotalFailed,
 		AvgProcessingTimeMs: stats.AvgProcessingTimeMs,
 		LastUpdated:         stats.LastUpdated,
+		Depth:               stats.TotalQueued + stats.TotalProcessing,
 	}
 }
 
@@ -514,20 +597,22 @@ func ToHealthItemResponse(item *database.FileHealth) *HealthItemResponse {
 }
 
 // ToHealthStatsResponse converts health stats map to HealthStatsResponse
-func ToHealthStatsResponse(stats map[database.HealthStatus]int) *HealthStatsResponse {
-	pending := stats[database.HealthStatusPending]
-	corrupted := stats[database.HealthStatusCorrupted]
-
+func ToHealthStatsResponse(stats map[database.HealthStatus]int64) *HealthStatsResponse {
 	// Calculate total from all tracked statuses
-	total := 0
+	var total int64
 	for _, count := range stats {
 		total += count
 	}
 
 	return &HealthStatsResponse{
-		Total:     total,
-		Pending:   pending,
-		Corrupted: corrupted,
+		Total:           total,
+		Pending:         stats[database.HealthStatusPending],
+		Checking:        stats[database.HealthStatusChecking],
+		Healthy:         stats[database.HealthStatusHealthy],
+		RepairTriggered: stats[database.HealthStatusRepairTriggered],
+		Corrupted:       stats[database.HealthStatusCorrupted],
+		Skipped:         stats[database.HealthStatusSkipped],
+		Unmanaged:       stats[database.HealthStatusUnmanaged],
 	}
 }
 
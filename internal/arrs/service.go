@@ -2,25 +2,41 @@ package arrs
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/url"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/javi11/altmount/internal/config"
 	"golift.io/starr"
+	"golift.io/starr/lidarr"
 	"golift.io/starr/radarr"
+	"golift.io/starr/readarr"
 	"golift.io/starr/sonarr"
 )
 
+// ErrItemNotTagged is returned when a rescan is skipped because the ARR item does not carry
+// any of the tags configured for the instance
+var ErrItemNotTagged = errors.New("item does not have any of the instance's configured tags")
+
 // ConfigInstance represents an arrs instance from configuration
 type ConfigInstance struct {
-	Name    string `json:"name"`
-	Type    string `json:"type"` // "radarr" or "sonarr"
-	URL     string `json:"url"`
-	APIKey  string `json:"api_key"`
-	Enabled bool   `json:"enabled"`
+	Name    string   `json:"name"`
+	Type    string   `json:"type"` // "radarr", "sonarr", "lidarr" or "readarr"
+	URL     string   `json:"url"`
+	APIKey  string   `json:"api_key"`
+	Enabled bool     `json:"enabled"`
+	Tags    []string `json:"tags,omitempty"` // Only items with one of these tags are rescanned
+	// SyncIntervalHours is the instance override or, when unset, the global default
+	SyncIntervalHours int `json:"sync_interval_hours"`
+}
+
+// SyncInterval returns how often the instance should be synced
+func (i *ConfigInstance) SyncInterval() time.Duration {
+	return time.Duration(i.SyncIntervalHours) * time.Hour
 }
 
 // ConfigManager interface defines methods needed for configuration management
@@ -30,22 +46,26 @@ type ConfigManager interface {
 	SaveConfig() error
 }
 
-// Service manages Radarr and Sonarr instances for health monitoring and file repair
+// Service manages Radarr, Sonarr, Lidarr and Readarr instances for health monitoring and file repair
 type Service struct {
-	configGetter  config.ConfigGetter
-	configManager ConfigManager
-	mu            sync.RWMutex
-	radarrClients map[string]*radarr.Radarr // key: instance name
-	sonarrClients map[string]*sonarr.Sonarr // key: instance name
+	configGetter   config.ConfigGetter
+	configManager  ConfigManager
+	mu             sync.RWMutex
+	radarrClients  map[string]*radarr.Radarr   // key: instance name
+	sonarrClients  map[string]*sonarr.Sonarr   // key: instance name
+	lidarrClients  map[string]*lidarr.Lidarr   // key: instance name
+	readarrClients map[string]*readarr.Readarr // key: instance name
 }
 
 // NewService creates a new arrs service for health monitoring and file repair
 func NewService(configGetter config.ConfigGetter, configManager ConfigManager) *Service {
 	return &Service{
-		configGetter:  configGetter,
-		configManager: configManager,
-		radarrClients: make(map[string]*radarr.Radarr),
-		sonarrClients: make(map[string]*sonarr.Sonarr),
+		configGetter:   configGetter,
+		configManager:  configManager,
+		radarrClients:  make(map[string]*radarr.Radarr),
+		sonarrClients:  make(map[string]*sonarr.Sonarr),
+		lidarrClients:  make(map[string]*lidarr.Lidarr),
+		readarrClients: make(map[string]*readarr.Readarr),
 	}
 }
 
@@ -58,11 +78,13 @@ func (s *Service) getConfigInstances() []*ConfigInstance {
 	if len(cfg.Arrs.RadarrInstances) > 0 {
 		for _, radarrConfig := range cfg.Arrs.RadarrInstances {
 			instance := &ConfigInstance{
-				Name:    radarrConfig.Name,
-				Type:    "radarr",
-				URL:     radarrConfig.URL,
-				APIKey:  radarrConfig.APIKey,
-				Enabled: radarrConfig.Enabled != nil && *radarrConfig.Enabled,
+				Name:              radarrConfig.Name,
+				Type:              "radarr",
+				URL:               radarrConfig.URL,
+				APIKey:            radarrConfig.APIKey,
+				Enabled:           radarrConfig.Enabled != nil && *radarrConfig.Enabled,
+				Tags:              radarrConfig.Tags,
+				SyncIntervalHours: cfg.Arrs.EffectiveSyncIntervalHours(radarrConfig),
 			}
 			instances = append(instances, instance)
 		}
@@ -72,16 +94,44 @@ func (s *Service) getConfigInstances() []*ConfigInstance {
 	if len(cfg.Arrs.SonarrInstances) > 0 {
 		for _, sonarrConfig := range cfg.Arrs.SonarrInstances {
 			instance := &ConfigInstance{
-				Name:    sonarrConfig.Name,
-				Type:    "sonarr",
-				URL:     sonarrConfig.URL,
-				APIKey:  sonarrConfig.APIKey,
-				Enabled: sonarrConfig.Enabled != nil && *sonarrConfig.Enabled,
+				Name:              sonarrConfig.Name,
+				Type:              "sonarr",
+				URL:               sonarrConfig.URL,
+				APIKey:            sonarrConfig.APIKey,
+				Enabled:           sonarrConfig.Enabled != nil && *sonarrConfig.Enabled,
+				Tags:              sonarrConfig.Tags,
+				SyncIntervalHours: cfg.Arrs.EffectiveSyncIntervalHours(sonarrConfig),
 			}
 			instances = append(instances, instance)
 		}
 	}
 
+	// Convert Lidarr instances
+	for _, lidarrConfig := range cfg.Arrs.LidarrInstances {
+		instances = append(instances, &ConfigInstance{
+			Name:              lidarrConfig.Name,
+			Type:              "lidarr",
+			URL:               lidarrConfig.URL,
+			APIKey:            lidarrConfig.APIKey,
+			Enabled:           lidarrConfig.Enabled != nil && *lidarrConfig.Enabled,
+			Tags:              lidarrConfig.Tags,
+			SyncIntervalHours: cfg.Arrs.EffectiveSyncIntervalHours(lidarrConfig),
+		})
+	}
+
+	// Convert Readarr instances
+	for _, readarrConfig := range cfg.Arrs.ReadarrInstances {
+		instances = append(instances, &ConfigInstance{
+			Name:              readarrConfig.Name,
+			Type:              "readarr",
+			URL:               readarrConfig.URL,
+			APIKey:            readarrConfig.APIKey,
+			Enabled:           readarrConfig.Enabled != nil && *readarrConfig.Enabled,
+			Tags:              readarrConfig.Tags,
+			SyncIntervalHours: cfg.Arrs.EffectiveSyncIntervalHours(readarrConfig),
+		})
+	}
+
 	return instances
 }
 
@@ -125,6 +175,34 @@ func (s *Service) getOrCreateSonarrClient(instanceName, url, apiKey string) (*so
 	return client, nil
 }
 
+// getOrCreateLidarrClient gets or creates a Lidarr client for an instance
+func (s *Service) getOrCreateLidarrClient(instanceName, url, apiKey string) (*lidarr.Lidarr, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if client, exists := s.lidarrClients[instanceName]; exists {
+		return client, nil
+	}
+
+	client := lidarr.New(&starr.Config{URL: url, APIKey: apiKey})
+	s.lidarrClients[instanceName] = client
+	return client, nil
+}
+
+// getOrCreateReadarrClient gets or creates a Readarr client for an instance
+func (s *Service) getOrCreateReadarrClient(instanceName, url, apiKey string) (*readarr.Readarr, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if client, exists := s.readarrClients[instanceName]; exists {
+		return client, nil
+	}
+
+	client := readarr.New(&starr.Config{URL: url, APIKey: apiKey})
+	s.readarrClients[instanceName] = client
+	return client, nil
+}
+
 // findInstanceForFilePath finds which ARR instance manages the given file path
 func (s *Service) findInstanceForFilePath(ctx context.Context, filePath string) (instanceType string, instanceName string, err error) {
 	slog.DebugContext(ctx, "Finding instance for file path", "file_path", filePath)
@@ -158,6 +236,24 @@ func (s *Service) findInstanceForFilePath(ctx context.Context, filePath string)
 			if s.sonarrManagesFile(ctx, client, filePath) {
 				return "sonarr", instance.Name, nil
 			}
+
+		case "lidarr":
+			client, err := s.getOrCreateLidarrClient(instance.Name, instance.URL, instance.APIKey)
+			if err != nil {
+				continue
+			}
+			if s.lidarrManagesFile(ctx, client, filePath) {
+				return "lidarr", instance.Name, nil
+			}
+
+		case "readarr":
+			client, err := s.getOrCreateReadarrClient(instance.Name, instance.URL, instance.APIKey)
+			if err != nil {
+				continue
+			}
+			if s.readarrManagesFile(ctx, client, filePath) {
+				return "readarr", instance.Name, nil
+			}
 		}
 	}
 
@@ -168,23 +264,35 @@ func (s *Service) findInstanceForFilePath(ctx context.Context, filePath string)
 // The pathForRescan should be the library path (symlink or .strm file) if available,
 // otherwise the mount path. It's the caller's responsibility to find the appropriate path.
 func (s *Service) TriggerFileRescan(ctx context.Context, pathForRescan string) error {
-	slog.InfoContext(ctx, "Triggering ARR rescan", "path", pathForRescan)
+	return s.TriggerFilesRescan(ctx, []string{pathForRescan})[pathForRescan]
+}
+
+// TriggerFilesRescan triggers a rescan for files managed by the same ARR instance, such as the
+// episodes of a season, searching for the files of each series or movie with a single command.
+// The instance is looked up from the first path. The returned map holds the error of every
+// path that could not be rescanned and is empty when all of them were.
+func (s *Service) TriggerFilesRescan(ctx context.Context, pathsForRescan []string) map[string]error {
+	if len(pathsForRescan) == 0 {
+		return nil
+	}
+
+	slog.InfoContext(ctx, "Triggering ARR rescan", "paths", pathsForRescan)
 
-	// Find which ARR instance manages this file path
-	instanceType, instanceName, err := s.findInstanceForFilePath(ctx, pathForRescan)
+	// Find which ARR instance manages the files
+	instanceType, instanceName, err := s.findInstanceForFilePath(ctx, pathsForRescan[0])
 	if err != nil {
-		return fmt.Errorf("failed to find ARR instance for file path %s: %w", pathForRescan, err)
+		return failAll(pathsForRescan, fmt.Errorf("failed to find ARR instance for file path %s: %w", pathsForRescan[0], err))
 	}
 
 	// Find the instance configuration
 	instanceConfig, err := s.findConfigInstance(instanceType, instanceName)
 	if err != nil {
-		return fmt.Errorf("failed to find instance config: %w", err)
+		return failAll(pathsForRescan, fmt.Errorf("failed to find instance config: %w", err))
 	}
 
 	// Check if instance is enabled
 	if !instanceConfig.Enabled {
-		return fmt.Errorf("instance %s/%s is disabled", instanceType, instanceName)
+		return failAll(pathsForRescan, fmt.Errorf("instance %s/%s is disabled", instanceType, instanceName))
 	}
 
 	// Trigger rescan based on instance type
@@ -192,20 +300,58 @@ func (s *Service) TriggerFileRescan(ctx context.Context, pathForRescan string) e
 	case "radarr":
 		client, err := s.getOrCreateRadarrClient(instanceName, instanceConfig.URL, instanceConfig.APIKey)
 		if err != nil {
-			return fmt.Errorf("failed to create Radarr client: %w", err)
+			return failAll(pathsForRescan, fmt.Errorf("failed to create Radarr client: %w", err))
 		}
-		return s.triggerRadarrRescanByPath(ctx, client, pathForRescan, instanceName)
+		return s.triggerRadarrRescanByPaths(ctx, client, pathsForRescan, instanceName, instanceConfig.Tags)
 
 	case "sonarr":
 		client, err := s.getOrCreateSonarrClient(instanceName, instanceConfig.URL, instanceConfig.APIKey)
 		if err != nil {
-			return fmt.Errorf("failed to create Sonarr client: %w", err)
+			return failAll(pathsForRescan, fmt.Errorf("failed to create Sonarr client: %w", err))
 		}
-		return s.triggerSonarrRescanByPath(ctx, client, pathForRescan, instanceName)
+		return s.triggerSonarrRescanByPaths(ctx, client, pathsForRescan, instanceName, instanceConfig.Tags)
+
+	case "lidarr":
+		client, err := s.getOrCreateLidarrClient(instanceName, instanceConfig.URL, instanceConfig.APIKey)
+		if err != nil {
+			return failAll(pathsForRescan, fmt.Errorf("failed to create Lidarr client: %w", err))
+		}
+		return rescanEach(pathsForRescan, func(path string) error {
+			return s.triggerLidarrRescanByPath(ctx, client, path, instanceName, instanceConfig.Tags)
+		})
+
+	case "readarr":
+		client, err := s.getOrCreateReadarrClient(instanceName, instanceConfig.URL, instanceConfig.APIKey)
+		if err != nil {
+			return failAll(pathsForRescan, fmt.Errorf("failed to create Readarr client: %w", err))
+		}
+		return rescanEach(pathsForRescan, func(path string) error {
+			return s.triggerReadarrRescanByPath(ctx, client, path, instanceName, instanceConfig.Tags)
+		})
 
 	default:
-		return fmt.Errorf("unsupported instance type: %s", instanceType)
+		return failAll(pathsForRescan, fmt.Errorf("unsupported instance type: %s", instanceType))
+	}
+}
+
+// failAll returns err as the error of every path
+func failAll(paths []string, err error) map[string]error {
+	failed := make(map[string]error, len(paths))
+	for _, path := range paths {
+		failed[path] = err
+	}
+	return failed
+}
+
+// rescanEach triggers the rescan of every path on its own, for ARR types searched per file
+func rescanEach(paths []string, rescan func(path string) error) map[string]error {
+	failed := make(map[string]error)
+	for _, path := range paths {
+		if err := rescan(path); err != nil {
+			failed[path] = err
+		}
 	}
+	return failed
 }
 
 // radarrManagesFile checks if Radarr manages the given file path using root folders (checkrr approach)
@@ -233,66 +379,88 @@ func (s *Service) radarrManagesFile(ctx context.Context, client *radarr.Radarr,
 	return false
 }
 
-// triggerRadarrRescanByPath triggers a rescan in Radarr for the given file path
-func (s *Service) triggerRadarrRescanByPath(ctx context.Context, client *radarr.Radarr, filePath, instanceName string) error {
-	slog.DebugContext(ctx, "Checking Radarr for file path",
+// triggerRadarrRescanByPaths triggers a rescan in Radarr for the movies of the given file paths
+func (s *Service) triggerRadarrRescanByPaths(ctx context.Context, client *radarr.Radarr, filePaths []string, instanceName string, tags []string) map[string]error {
+	slog.DebugContext(ctx, "Checking Radarr for file paths",
 		"instance", instanceName,
-		"file_path", filePath)
+		"file_paths", filePaths)
 
-	// Get all movies to find the one with matching file path
+	// Get all movies to find the ones with matching file paths
 	movies, err := client.GetMovieContext(ctx, &radarr.GetMovie{})
 	if err != nil {
-		return fmt.Errorf("failed to get movies from Radarr: %w", err)
+		return failAll(filePaths, fmt.Errorf("failed to get movies from Radarr: %w", err))
 	}
 
-	var targetMovie *radarr.Movie
+	moviesByFile := make(map[string]*radarr.Movie, len(movies))
 	for _, movie := range movies {
-		if movie.HasFile && movie.MovieFile != nil && movie.MovieFile.Path == filePath {
-			targetMovie = movie
-			break
+		if movie.HasFile && movie.MovieFile != nil {
+			moviesByFile[movie.MovieFile.Path] = movie
 		}
 	}
 
-	if targetMovie == nil {
-		slog.DebugContext(ctx, "No movie found with file path",
+	failed := make(map[string]error)
+	var rescanPaths []string
+	var movieIDs []int64
+	for _, filePath := range filePaths {
+		targetMovie := moviesByFile[filePath]
+		if targetMovie == nil {
+			slog.DebugContext(ctx, "No movie found with file path",
+				"instance", instanceName,
+				"file_path", filePath)
+
+			failed[filePath] = fmt.Errorf("no movie found with file path: %s. Check if the movie has any files", filePath)
+			continue
+		}
+
+		slog.DebugContext(ctx, "Found matching movie for file",
 			"instance", instanceName,
+			"movie_id", targetMovie.ID,
+			"movie_title", targetMovie.Title,
+			"movie_path", targetMovie.Path,
 			"file_path", filePath)
 
-		return fmt.Errorf("no movie found with file path: %s. Check if the movie has any files", filePath)
-	}
+		if err := checkItemTags(ctx, client.GetTagsContext, targetMovie.Tags, tags); err != nil {
+			failed[filePath] = fmt.Errorf("movie %s: %w", targetMovie.Title, err)
+			continue
+		}
 
-	slog.DebugContext(ctx, "Found matching movie for file",
-		"instance", instanceName,
-		"movie_id", targetMovie.ID,
-		"movie_title", targetMovie.Title,
-		"movie_path", targetMovie.Path,
-		"file_path", filePath)
+		// Delete the existing file
+		err = client.DeleteMovieFilesContext(ctx, targetMovie.MovieFile.ID)
+		if err != nil {
+			slog.WarnContext(ctx, "Failed to delete movie file, continuing with rescan",
+				"instance", instanceName,
+				"movie_id", targetMovie.ID,
+				"file_id", targetMovie.MovieFile.ID,
+				"error", err)
+		}
 
-	// Delete the existing file
-	err = client.DeleteMovieFilesContext(ctx, targetMovie.MovieFile.ID)
-	if err != nil {
-		slog.WarnContext(ctx, "Failed to delete movie file, continuing with rescan",
-			"instance", instanceName,
-			"movie_id", targetMovie.ID,
-			"file_id", targetMovie.MovieFile.ID,
-			"error", err)
+		rescanPaths = append(rescanPaths, filePath)
+		movieIDs = append(movieIDs, targetMovie.ID)
 	}
 
-	// Trigger rescan for the movie
+	if len(movieIDs) == 0 {
+		return failed
+	}
+
+	// Trigger one rescan for all the movies
 	response, err := client.SendCommandContext(ctx, &radarr.CommandRequest{
 		Name:     "RescanMovie",
-		MovieIDs: []int64{targetMovie.ID},
+		MovieIDs: movieIDs,
 	})
 	if err != nil {
-		return fmt.Errorf("failed to trigger Radarr rescan for movie ID %d: %w", targetMovie.ID, err)
+		err = fmt.Errorf("failed to trigger Radarr rescan for movie IDs %v: %w", movieIDs, err)
+		for _, filePath := range rescanPaths {
+			failed[filePath] = err
+		}
+		return failed
 	}
 
 	slog.DebugContext(ctx, "Successfully triggered Radarr rescan",
 		"instance", instanceName,
-		"movie_id", targetMovie.ID,
+		"movie_ids", movieIDs,
 		"command_id", response.ID)
 
-	return nil
+	return failed
 }
 
 // sonarrManagesFile checks if Sonarr manages the given file path using root folders (checkrr approach)
@@ -320,8 +488,9 @@ func (s *Service) sonarrManagesFile(ctx context.Context, client *sonarr.Sonarr,
 	return false
 }
 
-// triggerSonarrRescanByPath triggers a rescan in Sonarr for the given file path
-func (s *Service) triggerSonarrRescanByPath(ctx context.Context, client *sonarr.Sonarr, filePath, instanceName string) error {
+// triggerSonarrRescanByPaths triggers a rescan in Sonarr for the given file paths, searching
+// for the episodes of each series with a single command
+func (s *Service) triggerSonarrRescanByPaths(ctx context.Context, client *sonarr.Sonarr, filePaths []string, instanceName string, tags []string) map[string]error {
 	cfg := s.configGetter()
 
 	// Get library directory from health config
@@ -329,96 +498,130 @@ func (s *Service) triggerSonarrRescanByPath(ctx context.Context, client *sonarr.
 	if cfg.Health.LibraryDir != nil && *cfg.Health.LibraryDir != "" {
 		libraryDir = *cfg.Health.LibraryDir
 	} else {
-		return fmt.Errorf("Health.LibraryDir is not configured")
+		return failAll(filePaths, fmt.Errorf("Health.LibraryDir is not configured"))
 	}
 
 	slog.DebugContext(ctx, "Triggering Sonarr rescan/re-download by path",
 		"instance", instanceName,
-		"file_path", filePath,
+		"file_paths", filePaths,
 		"library_dir", libraryDir)
 
-	// Get all series to find the one that contains this file path
+	// Get all series to find the ones that contain the file paths
 	series, err := client.GetAllSeriesContext(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to get series from Sonarr: %w", err)
+		return failAll(filePaths, fmt.Errorf("failed to get series from Sonarr: %w", err))
 	}
 
-	// Find the series that contains this file path
-	var targetSeries *sonarr.Series
-	for _, show := range series {
-		if strings.Contains(filePath, show.Path) {
-			targetSeries = show
-			break
+	// Group the files by the series that contains them
+	failed := make(map[string]error)
+	var seriesOrder []*sonarr.Series
+	seriesFiles := make(map[int64][]string)
+	for _, filePath := range filePaths {
+		var targetSeries *sonarr.Series
+		for _, show := range series {
+			if strings.Contains(filePath, show.Path) {
+				targetSeries = show
+				break
+			}
+		}
+
+		if targetSeries == nil {
+			failed[filePath] = fmt.Errorf("no series found containing file path: %s", filePath)
+			continue
 		}
+
+		if _, ok := seriesFiles[targetSeries.ID]; !ok {
+			seriesOrder = append(seriesOrder, targetSeries)
+		}
+		seriesFiles[targetSeries.ID] = append(seriesFiles[targetSeries.ID], filePath)
 	}
 
-	if targetSeries == nil {
-		return fmt.Errorf("no series found containing file path: %s", filePath)
+	for _, targetSeries := range seriesOrder {
+		paths := seriesFiles[targetSeries.ID]
+		for filePath, err := range s.triggerSonarrSeriesRescan(ctx, client, targetSeries, paths, instanceName, tags) {
+			failed[filePath] = err
+		}
 	}
 
-	slog.DebugContext(ctx, "Found matching series for file",
+	return failed
+}
+
+// triggerSonarrSeriesRescan deletes the episode files of a series with the given paths and
+// searches for all their episodes at once
+func (s *Service) triggerSonarrSeriesRescan(ctx context.Context, client *sonarr.Sonarr, targetSeries *sonarr.Series, filePaths []string, instanceName string, tags []string) map[string]error {
+	slog.DebugContext(ctx, "Found matching series for files",
 		"series_title", targetSeries.Title,
 		"series_path", targetSeries.Path,
-		"file_path", filePath)
+		"file_paths", filePaths)
+
+	if err := checkItemTags(ctx, client.GetTagsContext, targetSeries.Tags, tags); err != nil {
+		return failAll(filePaths, fmt.Errorf("series %s: %w", targetSeries.Title, err))
+	}
 
 	// Get all episodes for this specific series
 	episodes, err := client.GetSeriesEpisodesContext(ctx, &sonarr.GetEpisode{
 		SeriesID: targetSeries.ID,
 	})
 	if err != nil {
-		return fmt.Errorf("failed to get episodes for series %s: %w", targetSeries.Title, err)
+		return failAll(filePaths, fmt.Errorf("failed to get episodes for series %s: %w", targetSeries.Title, err))
 	}
 
-	// Get episode files for this series to find the matching file
+	// Get episode files for this series to find the matching files
 	episodeFiles, err := client.GetSeriesEpisodeFilesContext(ctx, targetSeries.ID)
 	if err != nil {
-		return fmt.Errorf("failed to get episode files for series %s: %w", targetSeries.Title, err)
+		return failAll(filePaths, fmt.Errorf("failed to get episode files for series %s: %w", targetSeries.Title, err))
 	}
 
-	// Find the episode file with matching path
-	var targetEpisodeFile *sonarr.EpisodeFile
+	episodeFilesByPath := make(map[string]*sonarr.EpisodeFile, len(episodeFiles))
 	for _, episodeFile := range episodeFiles {
-		if episodeFile.Path == filePath {
-			targetEpisodeFile = episodeFile
-			break
-		}
+		episodeFilesByPath[episodeFile.Path] = episodeFile
 	}
 
-	if targetEpisodeFile == nil {
-		return fmt.Errorf("no episode file found with path: %s", filePath)
-	}
+	failed := make(map[string]error)
+	var searchPaths []string
+	var episodeIDs []int64
+	for _, filePath := range filePaths {
+		targetEpisodeFile := episodeFilesByPath[filePath]
+		if targetEpisodeFile == nil {
+			failed[filePath] = fmt.Errorf("no episode file found with path: %s", filePath)
+			continue
+		}
 
-	// Find episodes that use this episode file
-	var targetEpisodes []*sonarr.Episode
-	for _, episode := range episodes {
-		if episode.HasFile && episode.EpisodeFileID == targetEpisodeFile.ID {
-			targetEpisodes = append(targetEpisodes, episode)
+		// Find episodes that use this episode file
+		var fileEpisodeIDs []int64
+		for _, episode := range episodes {
+			if episode.HasFile && episode.EpisodeFileID == targetEpisodeFile.ID {
+				fileEpisodeIDs = append(fileEpisodeIDs, episode.ID)
+			}
 		}
-	}
 
-	if len(targetEpisodes) == 0 {
-		return fmt.Errorf("no episodes found with file path: %s", filePath)
-	}
+		if len(fileEpisodeIDs) == 0 {
+			failed[filePath] = fmt.Errorf("no episodes found with file path: %s", filePath)
+			continue
+		}
 
-	slog.DebugContext(ctx, "Found matching episodes",
-		"episode_count", len(targetEpisodes),
-		"episode_file_id", targetEpisodeFile.ID)
+		slog.DebugContext(ctx, "Found matching episodes",
+			"episode_count", len(fileEpisodeIDs),
+			"episode_file_id", targetEpisodeFile.ID)
 
-	// Delete the existing episode file
-	err = client.DeleteEpisodeFileContext(ctx, targetEpisodeFile.ID)
-	if err != nil {
-		slog.WarnContext(ctx, "Failed to delete episode file",
-			"instance", instanceName,
-			"episode_file_id", targetEpisodeFile.ID,
-			"error", err)
+		// Delete the existing episode file
+		err = client.DeleteEpisodeFileContext(ctx, targetEpisodeFile.ID)
+		if err != nil {
+			slog.WarnContext(ctx, "Failed to delete episode file",
+				"instance", instanceName,
+				"episode_file_id", targetEpisodeFile.ID,
+				"error", err)
+		}
+
+		searchPaths = append(searchPaths, filePath)
+		episodeIDs = append(episodeIDs, fileEpisodeIDs...)
 	}
 
-	// Trigger episode search for all episodes in this file
-	var episodeIDs []int64
-	for _, episode := range targetEpisodes {
-		episodeIDs = append(episodeIDs, episode.ID)
+	if len(episodeIDs) == 0 {
+		return failed
 	}
 
+	// Trigger one episode search for all episodes in these files
 	searchCmd := &sonarr.CommandRequest{
 		Name:       "EpisodeSearch",
 		EpisodeIDs: episodeIDs,
@@ -426,7 +629,11 @@ func (s *Service) triggerSonarrRescanByPath(ctx context.Context, client *sonarr.
 
 	response, err := client.SendCommandContext(ctx, searchCmd)
 	if err != nil {
-		return fmt.Errorf("failed to trigger episode search: %w", err)
+		err = fmt.Errorf("failed to trigger episode search: %w", err)
+		for _, filePath := range searchPaths {
+			failed[filePath] = err
+		}
+		return failed
 	}
 
 	slog.DebugContext(ctx, "Successfully triggered episode search for re-download",
@@ -435,9 +642,223 @@ func (s *Service) triggerSonarrRescanByPath(ctx context.Context, client *sonarr.
 		"episode_ids", episodeIDs,
 		"command_id", response.ID)
 
+	return failed
+}
+
+// lidarrManagesFile checks if Lidarr manages the given file path using root folders
+func (s *Service) lidarrManagesFile(ctx context.Context, client *lidarr.Lidarr, filePath string) bool {
+	rootFolders, err := client.GetRootFoldersContext(ctx)
+	if err != nil {
+		slog.DebugContext(ctx, "Failed to get root folders from Lidarr for file check", "error", err)
+		return false
+	}
+
+	for _, folder := range rootFolders {
+		if strings.HasPrefix(filePath, folder.Path) {
+			slog.DebugContext(ctx, "File matches Lidarr root folder", "folder_path", folder.Path)
+			return true
+		}
+	}
+
+	slog.DebugContext(ctx, "File does not match any Lidarr root folders")
+	return false
+}
+
+// triggerLidarrRescanByPath deletes the track file with the given path from Lidarr and
+// searches for its album again
+func (s *Service) triggerLidarrRescanByPath(ctx context.Context, client *lidarr.Lidarr, filePath, instanceName string, tags []string) error {
+	slog.DebugContext(ctx, "Triggering Lidarr re-download by path",
+		"instance", instanceName,
+		"file_path", filePath)
+
+	// Get all artists to find the one that contains this file path
+	artists, err := client.GetArtistContext(ctx, "")
+	if err != nil {
+		return fmt.Errorf("failed to get artists from Lidarr: %w", err)
+	}
+
+	var targetFile *lidarr.TrackFile
+	var targetArtist *lidarr.Artist
+	for _, artist := range artists {
+		if artist.Path == "" || !strings.HasPrefix(filePath, artist.Path) {
+			continue
+		}
+
+		trackFiles, err := client.GetTrackFilesForArtistContext(ctx, artist.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get track files for artist %s: %w", artist.ArtistName, err)
+		}
+
+		for _, trackFile := range trackFiles {
+			if trackFile.Path == filePath {
+				targetFile = trackFile
+				targetArtist = artist
+				break
+			}
+		}
+		if targetFile != nil {
+			break
+		}
+	}
+
+	if targetFile == nil {
+		return fmt.Errorf("no track file found with path: %s", filePath)
+	}
+
+	if err := checkItemTags(ctx, client.GetTagsContext, targetArtist.Tags, tags); err != nil {
+		return fmt.Errorf("artist %s: %w", targetArtist.ArtistName, err)
+	}
+
+	// Delete the existing track file
+	if err := client.DeleteTrackFileContext(ctx, targetFile.ID); err != nil {
+		slog.WarnContext(ctx, "Failed to delete track file",
+			"instance", instanceName,
+			"track_file_id", targetFile.ID,
+			"error", err)
+	}
+
+	response, err := client.SendCommandContext(ctx, &lidarr.CommandRequest{
+		Name:     "AlbumSearch",
+		AlbumIDs: []int64{targetFile.AlbumID},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to trigger Lidarr album search for album ID %d: %w", targetFile.AlbumID, err)
+	}
+
+	slog.DebugContext(ctx, "Successfully triggered Lidarr album search for re-download",
+		"instance", instanceName,
+		"album_id", targetFile.AlbumID,
+		"command_id", response.ID)
+
 	return nil
 }
 
+// readarrManagesFile checks if Readarr manages the given file path using root folders
+func (s *Service) readarrManagesFile(ctx context.Context, client *readarr.Readarr, filePath string) bool {
+	rootFolders, err := client.GetRootFoldersContext(ctx)
+	if err != nil {
+		slog.DebugContext(ctx, "Failed to get root folders from Readarr for file check", "error", err)
+		return false
+	}
+
+	for _, folder := range rootFolders {
+		if strings.HasPrefix(filePath, folder.Path) {
+			slog.DebugContext(ctx, "File matches Readarr root folder", "folder_path", folder.Path)
+			return true
+		}
+	}
+
+	slog.DebugContext(ctx, "File does not match any Readarr root folders")
+	return false
+}
+
+// triggerReadarrRescanByPath deletes the book file with the given path from Readarr and
+// searches for the book again
+func (s *Service) triggerReadarrRescanByPath(ctx context.Context, client *readarr.Readarr, filePath, instanceName string, tags []string) error {
+	slog.DebugContext(ctx, "Triggering Readarr re-download by path",
+		"instance", instanceName,
+		"file_path", filePath)
+
+	// Readarr has no endpoint listing authors, so collect them from the books
+	books, err := client.GetBookContext(ctx, "")
+	if err != nil {
+		return fmt.Errorf("failed to get books from Readarr: %w", err)
+	}
+
+	checkedAuthors := make(map[int64]bool)
+	var targetFile *readarr.BookFile
+	var targetAuthor *readarr.Author
+	for _, book := range books {
+		if book.Author == nil || checkedAuthors[book.AuthorID] {
+			continue
+		}
+		checkedAuthors[book.AuthorID] = true
+
+		if book.Author.Path == "" || !strings.HasPrefix(filePath, book.Author.Path) {
+			continue
+		}
+
+		bookFiles, err := client.GetBookFilesForAuthorContext(ctx, book.AuthorID)
+		if err != nil {
+			return fmt.Errorf("failed to get book files for author %s: %w", book.Author.AuthorName, err)
+		}
+
+		for _, bookFile := range bookFiles {
+			if bookFile.Path == filePath {
+				targetFile = bookFile
+				targetAuthor = book.Author
+				break
+			}
+		}
+		if targetFile != nil {
+			break
+		}
+	}
+
+	if targetFile == nil {
+		return fmt.Errorf("no book file found with path: %s", filePath)
+	}
+
+	if err := checkItemTags(ctx, client.GetTagsContext, targetAuthor.Tags, tags); err != nil {
+		return fmt.Errorf("author %s: %w", targetAuthor.AuthorName, err)
+	}
+
+	// Delete the existing book file
+	if err := client.DeleteBookFileContext(ctx, targetFile.ID); err != nil {
+		slog.WarnContext(ctx, "Failed to delete book file",
+			"instance", instanceName,
+			"book_file_id", targetFile.ID,
+			"error", err)
+	}
+
+	response, err := client.SendCommandContext(ctx, &readarr.CommandRequest{
+		Name:    "BookSearch",
+		BookIDs: []int64{targetFile.BookID},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to trigger Readarr book search for book ID %d: %w", targetFile.BookID, err)
+	}
+
+	slog.DebugContext(ctx, "Successfully triggered Readarr book search for re-download",
+		"instance", instanceName,
+		"book_id", targetFile.BookID,
+		"command_id", response.ID)
+
+	return nil
+}
+
+// checkItemTags returns ErrItemNotTagged unless the item carries one of the wanted tag labels.
+// Tag labels are compared case-insensitively; an empty wanted list accepts every item.
+func checkItemTags(ctx context.Context, getTags func(context.Context) ([]*starr.Tag, error), itemTags []int, wanted []string) error {
+	if len(wanted) == 0 {
+		return nil
+	}
+
+	if len(itemTags) > 0 {
+		allTags, err := getTags(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get tags: %w", err)
+		}
+
+		labels := make(map[int]string, len(allTags))
+		for _, tag := range allTags {
+			labels[tag.ID] = tag.Label
+		}
+
+		for _, id := range itemTags {
+			for _, want := range wanted {
+				if strings.EqualFold(labels[id], want) {
+					return nil
+				}
+			}
+		}
+	}
+
+	slog.InfoContext(ctx, "Skipping ARR rescan for item without a managed tag", "required_tags", wanted)
+
+	return ErrItemNotTagged
+}
+
 // GetAllInstances returns all arrs instances from configuration
 func (s *Service) GetAllInstances() []*ConfigInstance {
 	return s.getConfigInstances()
@@ -488,8 +909,8 @@ func (s *Service) instanceExistsByURL(checkURL string) bool {
 	return false
 }
 
-// detectARRType attempts to detect if a URL points to Radarr or Sonarr
-// Returns "radarr", "sonarr", or an error if neither can be determined
+// detectARRType attempts to detect if a URL points to Radarr, Sonarr, Lidarr or Readarr
+// Returns "radarr", "sonarr", "lidarr", "readarr", or an error if none can be determined
 func (s *Service) detectARRType(ctx context.Context, arrURL, apiKey string) (string, error) {
 	slog.DebugContext(ctx, "Detecting ARR type", "url", arrURL)
 
@@ -527,7 +948,22 @@ func (s *Service) detectARRType(ctx context.Context, arrURL, apiKey string) (str
 		}
 	}
 
-	return "", fmt.Errorf("unable to detect ARR type for URL %s - neither Radarr nor Sonarr responded successfully", arrURL)
+	// Lidarr and Readarr serve the v1 API, so the clients above cannot reach them
+	lidarrClient := lidarr.New(&starr.Config{URL: arrURL, APIKey: apiKey})
+	if lidarrStatus, err := lidarrClient.GetSystemStatusContext(ctx); err == nil {
+		switch lidarrStatus.AppName {
+		case "Lidarr":
+			slog.DebugContext(ctx, "Detected Lidarr instance", "url", arrURL)
+			return "lidarr", nil
+		case "Readarr":
+			slog.DebugContext(ctx, "Detected Readarr instance", "url", arrURL)
+			return "readarr", nil
+		default:
+			slog.DebugContext(ctx, "Unknown AppName from Lidarr client", "app_name", lidarrStatus.AppName, "url", arrURL)
+		}
+	}
+
+	return "", fmt.Errorf("unable to detect ARR type for URL %s - no Radarr, Sonarr, Lidarr or Readarr instance responded successfully", arrURL)
 }
 
 // RegisterInstance attempts to automatically register an ARR instance
@@ -535,6 +971,8 @@ func (s *Service) detectARRType(ctx context.Context, arrURL, apiKey string) (str
 // Also creates the appropriate category in SABnzbd configuration based on ARR type:
 // - Radarr instances get "movies" category
 // - Sonarr instances get "tv" category
+// - Lidarr instances get "music" category
+// - Readarr instances get "books" category
 func (s *Service) RegisterInstance(ctx context.Context, arrURL, apiKey string) error {
 	if s.configManager == nil {
 		return fmt.Errorf("config manager not available")
@@ -561,6 +999,10 @@ func (s *Service) RegisterInstance(ctx context.Context, arrURL, apiKey string) e
 		category = "movies"
 	case "sonarr":
 		category = "tv"
+	case "lidarr":
+		category = "music"
+	case "readarr":
+		category = "books"
 	default:
 		return fmt.Errorf("unsupported ARR type: %s", arrType)
 	}
@@ -596,6 +1038,10 @@ func (s *Service) RegisterInstance(ctx context.Context, arrURL, apiKey string) e
 		newConfig.Arrs.RadarrInstances = append(newConfig.Arrs.RadarrInstances, newInstance)
 	case "sonarr":
 		newConfig.Arrs.SonarrInstances = append(newConfig.Arrs.SonarrInstances, newInstance)
+	case "lidarr":
+		newConfig.Arrs.LidarrInstances = append(newConfig.Arrs.LidarrInstances, newInstance)
+	case "readarr":
+		newConfig.Arrs.ReadarrInstances = append(newConfig.Arrs.ReadarrInstances, newInstance)
 	}
 
 	// Create category for this ARR type
@@ -669,6 +1115,91 @@ func (s *Service) GetInstance(instanceType, instanceName string) *ConfigInstance
 	return nil
 }
 
+// instanceCheckTimeout bounds each reachability check made when an instance is synced
+const instanceCheckTimeout = 10 * time.Second
+
+// instanceSyncCheckInterval is how often RunInstanceSync looks for instances that are due
+const instanceSyncCheckInterval = time.Minute
+
+// RunInstanceSync syncs every enabled instance when it starts and then each time the
+// instance's sync interval elapses, until ctx is done. Nothing is synced while arrs is
+// disabled.
+func (s *Service) RunInstanceSync(ctx context.Context) {
+	lastSync := make(map[string]time.Time)
+
+	ticker := time.NewTicker(instanceSyncCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		s.syncDueInstances(ctx, lastSync, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// syncDueInstances syncs the enabled instances whose last sync in lastSync is at least
+// their sync interval before now, recording now as their last sync
+func (s *Service) syncDueInstances(ctx context.Context, lastSync map[string]time.Time, now time.Time) {
+	cfg := s.configGetter()
+	if cfg.Arrs.Enabled == nil || !*cfg.Arrs.Enabled {
+		return
+	}
+
+	for _, instance := range s.getConfigInstances() {
+		if !instance.Enabled {
+			continue
+		}
+
+		key := instance.Type + "/" + instance.Name
+		if last, ok := lastSync[key]; ok && now.Sub(last) < instance.SyncInterval() {
+			continue
+		}
+
+		lastSync[key] = now
+		s.syncInstance(ctx, instance)
+	}
+}
+
+// syncInstance drops the cached client of an instance, so the next repair uses its current
+// URL and API key, and logs a warning when the instance cannot be reached. It only reports
+// problems; unreachable instances stay configured.
+func (s *Service) syncInstance(ctx context.Context, instance *ConfigInstance) {
+	s.mu.Lock()
+	switch instance.Type {
+	case "radarr":
+		delete(s.radarrClients, instance.Name)
+	case "sonarr":
+		delete(s.sonarrClients, instance.Name)
+	case "lidarr":
+		delete(s.lidarrClients, instance.Name)
+	case "readarr":
+		delete(s.readarrClients, instance.Name)
+	}
+	s.mu.Unlock()
+
+	checkCtx, cancel := context.WithTimeout(ctx, instanceCheckTimeout)
+	err := s.TestConnection(checkCtx, instance.Type, instance.URL, instance.APIKey)
+	cancel()
+
+	if err != nil {
+		slog.WarnContext(ctx, "ARR instance is not reachable",
+			"instance", instance.Name,
+			"type", instance.Type,
+			"url", instance.URL,
+			"error", err)
+		return
+	}
+
+	slog.DebugContext(ctx, "ARR instance synced",
+		"instance", instance.Name,
+		"type", instance.Type,
+		"next_sync", instance.SyncInterval())
+}
+
 // TestConnection tests the connection to an arrs instance
 func (s *Service) TestConnection(ctx context.Context, instanceType, url, apiKey string) error {
 	switch instanceType {
@@ -682,12 +1213,28 @@ func (s *Service) TestConnection(ctx context.Context, instanceType, url, apiKey
 
 	case "sonarr":
 		client := sonarr.New(&starr.Config{URL: url, APIKey: apiKey})
-		_, err := client.GetSystemStatus()
+		_, err := client.GetSystemStatusContext(ctx)
 		if err != nil {
 			return fmt.Errorf("failed to connect to Sonarr: %w", err)
 		}
 		return nil
 
+	case "lidarr":
+		client := lidarr.New(&starr.Config{URL: url, APIKey: apiKey})
+		_, err := client.GetSystemStatusContext(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to connect to Lidarr: %w", err)
+		}
+		return nil
+
+	case "readarr":
+		client := readarr.New(&starr.Config{URL: url, APIKey: apiKey})
+		_, err := client.GetSystemStatusContext(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to connect to Readarr: %w", err)
+		}
+		return nil
+
 	default:
 		return fmt.Errorf("unsupported instance type: %s", instanceType)
 	}
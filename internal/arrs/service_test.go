@@ -0,0 +1,152 @@
+package arrs
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/javi11/altmount/internal/config"
+)
+
+func TestSyncDueInstances(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	enabled := true
+	hourly := 1
+	cfg := config.DefaultConfig(t.TempDir())
+	cfg.Arrs.Enabled = &enabled
+	cfg.Arrs.DefaultSyncIntervalHours = 24
+	cfg.Arrs.RadarrInstances = []config.ArrsInstanceConfig{
+		{Name: "hourly", URL: server.URL, APIKey: "key", Enabled: &enabled, SyncIntervalHours: &hourly},
+		{Name: "daily", URL: server.URL, APIKey: "key", Enabled: &enabled},
+	}
+	s := NewService(func() *config.Config { return cfg }, nil)
+
+	start := time.Now()
+	lastSync := make(map[string]time.Time)
+
+	steps := []struct {
+		after time.Duration
+		want  int32 // Total instance syncs so far
+	}{
+		{after: 0, want: 2},
+		{after: 30 * time.Minute, want: 2},
+		{after: time.Hour, want: 3},
+		{after: 90 * time.Minute, want: 3},
+		{after: 24 * time.Hour, want: 5},
+	}
+
+	for _, step := range steps {
+		s.syncDueInstances(context.Background(), lastSync, start.Add(step.after))
+		if got := requests.Load(); got != step.want {
+			t.Errorf("after %v: got %d syncs, want %d", step.after, got, step.want)
+		}
+	}
+
+	// Nothing is synced while arrs is disabled
+	disabled := false
+	cfg.Arrs.Enabled = &disabled
+	s.syncDueInstances(context.Background(), lastSync, start.Add(48*time.Hour))
+	if got := requests.Load(); got != 5 {
+		t.Errorf("arrs disabled: got %d syncs, want 5", got)
+	}
+}
+
+func TestSyncInstanceDropsCachedClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	s := NewService(func() *config.Config { return config.DefaultConfig(t.TempDir()) }, nil)
+	old, _ := s.getOrCreateRadarrClient("radarr", "http://old.invalid", "old")
+
+	s.syncInstance(context.Background(), &ConfigInstance{Name: "radarr", Type: "radarr", URL: server.URL, APIKey: "new"})
+
+	client, _ := s.getOrCreateRadarrClient("radarr", server.URL, "new")
+	if client == old {
+		t.Error("the client cached before the sync is still used")
+	}
+}
+
+func TestTriggerFilesRescanSearchesSeriesOnce(t *testing.T) {
+	var deleted []string
+	var searches [][]int64
+	mux := http.NewServeMux()
+	reply := func(w http.ResponseWriter, v any) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(v)
+	}
+	mux.HandleFunc("GET /api/v3/rootFolder", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, []map[string]any{{"id": 1, "path": "/tv"}})
+	})
+	mux.HandleFunc("GET /api/v3/series", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, []map[string]any{{"id": 1, "title": "Show", "path": "/tv/Show"}})
+	})
+	mux.HandleFunc("GET /api/v3/episode", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, []map[string]any{
+			{"id": 11, "hasFile": true, "episodeFileId": 101},
+			{"id": 12, "hasFile": true, "episodeFileId": 102},
+			{"id": 13, "hasFile": true, "episodeFileId": 103},
+		})
+	})
+	mux.HandleFunc("GET /api/v3/episodeFile", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, []map[string]any{
+			{"id": 101, "path": "/tv/Show/Season 1/S01E01.mkv"},
+			{"id": 102, "path": "/tv/Show/Season 1/S01E02.mkv"},
+			{"id": 103, "path": "/tv/Show/Season 1/S01E03.mkv"},
+		})
+	})
+	mux.HandleFunc("DELETE /api/v3/episodeFile/{id}", func(w http.ResponseWriter, r *http.Request) {
+		deleted = append(deleted, r.PathValue("id"))
+	})
+	mux.HandleFunc("POST /api/v3/command", func(w http.ResponseWriter, r *http.Request) {
+		var cmd struct {
+			Name       string  `json:"name"`
+			EpisodeIDs []int64 `json:"episodeIds"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&cmd)
+		if cmd.Name == "EpisodeSearch" {
+			searches = append(searches, cmd.EpisodeIDs)
+		}
+		reply(w, map[string]any{"id": 1})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	enabled := true
+	libraryDir := "/tv"
+	cfg := config.DefaultConfig(t.TempDir())
+	cfg.Arrs.Enabled = &enabled
+	cfg.Health.LibraryDir = &libraryDir
+	cfg.Arrs.SonarrInstances = []config.ArrsInstanceConfig{
+		{Name: "sonarr", URL: server.URL, APIKey: "key", Enabled: &enabled},
+	}
+	s := NewService(func() *config.Config { return cfg }, nil)
+
+	failed := s.TriggerFilesRescan(context.Background(), []string{
+		"/tv/Show/Season 1/S01E01.mkv",
+		"/tv/Show/Season 1/S01E03.mkv",
+		"/tv/Show/Season 1/S01E04.mkv",
+	})
+
+	if len(failed) != 1 || failed["/tv/Show/Season 1/S01E04.mkv"] == nil {
+		t.Errorf("failed paths: got %v, want only the file unknown to Sonarr", failed)
+	}
+	if len(deleted) != 2 {
+		t.Errorf("deleted episode files: got %v, want 2", deleted)
+	}
+	if len(searches) != 1 || len(searches[0]) != 2 || searches[0][0] != 11 || searches[0][1] != 13 {
+		t.Errorf("episode searches: got %v, want one search for episodes [11 13]", searches)
+	}
+}
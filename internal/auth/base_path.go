@@ -0,0 +1,14 @@
+package auth
+
+import "path"
+
+// ScopedPath maps a client path into basePath, the subtree of the library a user is
+// restricted to. The name is cleaned as an absolute path first, so it can never resolve
+// outside basePath. An empty basePath leaves name unchanged.
+func ScopedPath(basePath, name string) string {
+	if basePath == "" {
+		return name
+	}
+
+	return path.Join(basePath, path.Clean("/"+name))
+}
@@ -0,0 +1,207 @@
+package auth
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// IPFilter restricts access to clients from a list of allowed networks. Behind a reverse
+// proxy the client address is taken from X-Forwarded-For, but only when the request comes
+// from a trusted proxy, since any client can send the header. Loopback clients are allowed
+// so the rclone mount and other services of this host keep working, unless the request
+// carries a forwarding header: a reverse proxy on the same host connects from loopback too.
+type IPFilter struct {
+	allowed []netip.Prefix
+	trusted []netip.Prefix
+}
+
+// NewIPFilter creates a filter allowing the given networks. Networks are CIDRs such as
+// 192.168.1.0/24 or fd00::/8, or single IPv4 or IPv6 addresses. trustedProxies lists the
+// proxies whose X-Forwarded-For header is used. It returns nil when allowed is empty,
+// which allows every client.
+func NewIPFilter(allowed, trustedProxies []string) (*IPFilter, error) {
+	if len(allowed) == 0 {
+		return nil, nil
+	}
+
+	allowedPrefixes, err := ParseNetworks(allowed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid allowed network: %w", err)
+	}
+	trustedPrefixes, err := ParseNetworks(trustedProxies)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trusted proxy: %w", err)
+	}
+
+	return &IPFilter{allowed: allowedPrefixes, trusted: trustedPrefixes}, nil
+}
+
+// ParseNetworks parses CIDRs and single IP addresses into prefixes
+func ParseNetworks(networks []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(networks))
+	for _, network := range networks {
+		network = strings.TrimSpace(network)
+
+		if strings.Contains(network, "/") {
+			prefix, err := netip.ParsePrefix(network)
+			if err != nil {
+				return nil, err
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+
+		addr, err := netip.ParseAddr(network)
+		if err != nil {
+			return nil, err
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+
+	return prefixes, nil
+}
+
+// Allow reports whether the client of r is in an allowed network, or on the loopback
+// interface and not forwarded by a proxy, and returns its address. A nil filter allows
+// every client.
+func (f *IPFilter) Allow(r *http.Request) (netip.Addr, bool) {
+	client := f.ClientIP(r)
+	if f == nil {
+		return client, true
+	}
+	if !client.IsValid() {
+		return client, false
+	}
+
+	return client, (client.IsLoopback() && !forwarded(r)) || containsAddr(f.allowed, client)
+}
+
+// forwarded reports whether r was relayed by a proxy, which sets one of these headers
+func forwarded(r *http.Request) bool {
+	for _, header := range []string{"X-Forwarded-For", "Forwarded", "X-Real-Ip"} {
+		if r.Header.Get(header) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP returns the address of the client that sent r. When the connection comes from
+// a trusted proxy, X-Forwarded-For is read from right to left and the first address that
+// is not a trusted proxy is the client.
+func (f *IPFilter) ClientIP(r *http.Request) netip.Addr {
+	remote := parseRemoteAddr(r.RemoteAddr)
+	if f == nil || !remote.IsValid() || !containsAddr(f.trusted, remote) {
+		return remote
+	}
+
+	client := remote
+	forwarded := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(forwarded[i])
+		if hop == "" {
+			continue
+		}
+
+		addr, err := netip.ParseAddr(hop)
+		if err != nil {
+			// A malformed entry cannot be trusted, stop at the last valid hop
+			break
+		}
+		client = addr.Unmap()
+		if !containsAddr(f.trusted, client) {
+			break
+		}
+	}
+
+	return client
+}
+
+// parseRemoteAddr returns the address of an http.Request RemoteAddr ("ip:port")
+func parseRemoteAddr(remoteAddr string) netip.Addr {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}
+	}
+	return addr.Unmap().WithZone("")
+}
+
+func containsAddr(prefixes []netip.Prefix, addr netip.Addr) bool {
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// NetworkGuard rejects clients outside the allowed networks of the current config. The
+// networks are parsed again only when they change, not on every request.
+type NetworkGuard struct {
+	settings func() (allowed, trustedProxies []string)
+
+	mu      sync.Mutex
+	parsed  bool
+	allowed []string
+	trusted []string
+	filter  *IPFilter
+	err     error
+}
+
+// NewNetworkGuard creates a guard reading the allowed networks and trusted proxies from
+// settings on every request
+func NewNetworkGuard(settings func() (allowed, trustedProxies []string)) *NetworkGuard {
+	return &NetworkGuard{settings: settings}
+}
+
+// Allow reports whether the client of r may proceed, logging rejected clients. An invalid
+// allow-list rejects every client, a nil guard allows every client.
+func (g *NetworkGuard) Allow(r *http.Request) bool {
+	if g == nil {
+		return true
+	}
+
+	filter, err := g.currentFilter()
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Invalid network allow-list, rejecting request", "error", err)
+		return false
+	}
+
+	client, ok := filter.Allow(r)
+	if !ok {
+		slog.WarnContext(r.Context(), "Request from a network that is not allowed",
+			"client_ip", client.String(),
+			"remote_addr", r.RemoteAddr,
+			"method", r.Method,
+			"path", r.URL.Path)
+	}
+	return ok
+}
+
+// currentFilter returns the filter of the current settings, rebuilding it when they changed
+func (g *NetworkGuard) currentFilter() (*IPFilter, error) {
+	allowed, trusted := g.settings()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if !g.parsed || !slices.Equal(allowed, g.allowed) || !slices.Equal(trusted, g.trusted) {
+		g.filter, g.err = NewIPFilter(allowed, trusted)
+		g.allowed, g.trusted = slices.Clone(allowed), slices.Clone(trusted)
+		g.parsed = true
+	}
+
+	return g.filter, g.err
+}
@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIPFilterAllow(t *testing.T) {
+	filter, err := NewIPFilter([]string{"10.0.0.0/8", "fd00::/8"}, []string{"192.0.2.1"})
+	if err != nil {
+		t.Fatalf("NewIPFilter: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor string
+		want         bool
+	}{
+		{name: "allowed IPv4", remoteAddr: "10.1.2.3:5000", want: true},
+		{name: "allowed IPv6", remoteAddr: "[fd00::1]:5000", want: true},
+		{name: "IPv4-mapped IPv6", remoteAddr: "[::ffff:10.1.2.3]:5000", want: true},
+		{name: "outside the allow-list", remoteAddr: "203.0.113.5:5000", want: false},
+		{name: "loopback IPv4", remoteAddr: "127.0.0.1:5000", want: true},
+		{name: "loopback IPv6", remoteAddr: "[::1]:5000", want: true},
+		{name: "client behind trusted proxy", remoteAddr: "192.0.2.1:5000", forwardedFor: "10.0.0.5", want: true},
+		{name: "forwarded header from untrusted client", remoteAddr: "203.0.113.5:5000", forwardedFor: "10.0.0.5", want: false},
+		{name: "forwarded loopback from untrusted client", remoteAddr: "203.0.113.5:5000", forwardedFor: "127.0.0.1", want: false},
+		{name: "loopback proxy without trusted proxies", remoteAddr: "127.0.0.1:5000", forwardedFor: "203.0.113.5", want: false},
+		{name: "loopback proxy forwarding an allowed client", remoteAddr: "[::1]:5000", forwardedFor: "10.0.0.5", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.forwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			}
+
+			if _, got := filter.Allow(req); got != tt.want {
+				t.Errorf("Allow: got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNetworkGuardFollowsSettings(t *testing.T) {
+	allowed := []string{"10.0.0.0/8"}
+	guard := NewNetworkGuard(func() ([]string, []string) { return allowed, nil })
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "192.168.1.5:5000"
+
+	if guard.Allow(req) {
+		t.Fatal("client outside the allow-list was allowed")
+	}
+
+	allowed = []string{"192.168.1.0/24"}
+	if !guard.Allow(req) {
+		t.Error("client not allowed after the allow-list changed")
+	}
+
+	allowed = []string{"not-a-network"}
+	if guard.Allow(req) {
+		t.Error("invalid allow-list allowed a client")
+	}
+
+	allowed = nil
+	if !guard.Allow(req) {
+		t.Error("empty allow-list rejected a client")
+	}
+}
+
+func TestIPFilterLoopbackBehindProxy(t *testing.T) {
+	filter, err := NewIPFilter([]string{"10.0.0.0/8"}, []string{"127.0.0.1"})
+	if err != nil {
+		t.Fatalf("NewIPFilter: %v", err)
+	}
+
+	for forwardedFor, want := range map[string]bool{
+		"10.0.0.5":    true,  // Allowed client behind the trusted local proxy
+		"203.0.113.5": false, // Client outside the allow-list behind the trusted local proxy
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = "127.0.0.1:5000"
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+
+		if _, got := filter.Allow(req); got != want {
+			t.Errorf("Allow(%s): got %v, want %v", forwardedFor, got, want)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "127.0.0.1:5000"
+	req.Header.Set("Forwarded", "for=203.0.113.5")
+	if _, got := filter.Allow(req); got {
+		t.Error("Allow: loopback request with a Forwarded header was allowed")
+	}
+}
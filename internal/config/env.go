@@ -0,0 +1,129 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// envReferencePattern matches the ${ENV:VAR_NAME} interpolation syntax
+var envReferencePattern = regexp.MustCompile(`^\$\{ENV:([A-Za-z_][A-Za-z0-9_]*)\}$`)
+
+// secretRef remembers the value a secret had in the config file before it was
+// resolved from the environment, so that saving the config does not write the
+// resolved plaintext back to disk.
+type secretRef struct {
+	raw      string
+	resolved string
+}
+
+// secretField is a secret config value that can be supplied through the environment
+type secretField struct {
+	key    string  // Stable key used to track the original file value
+	envVar string  // Environment variable that overrides the value
+	value  *string // Pointer to the value inside the config
+	file   *string // Optional path of a file holding the value
+}
+
+// secretFields returns the secret values that can be overridden from the environment.
+// Provider passwords are keyed by provider ID when available so reordering providers
+// does not mix up their original file values, and their variables are named after the ID.
+func (c *Config) secretFields() []secretField {
+	fields := []secretField{
+		{key: "webdav.password", envVar: "ALTMOUNT_WEBDAV_PASSWORD", value: &c.WebDAV.Password},
+		{key: "rclone.password", envVar: "ALTMOUNT_RCLONE_PASSWORD", value: &c.RClone.Password, file: &c.RClone.PasswordFile},
+		{key: "rclone.salt", envVar: "ALTMOUNT_RCLONE_SALT", value: &c.RClone.Salt, file: &c.RClone.SaltFile},
+		{key: "rclone.rc_pass", envVar: "ALTMOUNT_RCLONE_RC_PASS", value: &c.RClone.RCPass, file: &c.RClone.RCPassFile},
+		{key: "sabnzbd.fallback_api_key", envVar: "ALTMOUNT_SABNZBD_FALLBACK_API_KEY", value: &c.SABnzbd.FallbackAPIKey},
+		{key: "import.post_import_webhook_secret", envVar: "ALTMOUNT_IMPORT_WEBHOOK_SECRET", value: &c.Import.PostImportWebhookSecret},
+	}
+
+	for i := range c.Providers {
+		key := "providers." + strconv.Itoa(i) + ".password"
+		if c.Providers[i].ID != "" {
+			key = "providers.id:" + c.Providers[i].ID + ".password"
+		}
+		fields = append(fields, secretField{
+			key:    key,
+			envVar: providerPasswordEnvVar(c.Providers[i]),
+			value:  &c.Providers[i].Password,
+		})
+	}
+
+	return fields
+}
+
+// providerPasswordEnvVar returns the variable overriding the password of provider, e.g.
+// ALTMOUNT_PROVIDER_PRIMARY_PASSWORD for the provider with ID "primary". Providers without an
+// ID use the ID generated from their host, port and username, as the API assigns it.
+func providerPasswordEnvVar(provider ProviderConfig) string {
+	id := provider.ID
+	if id == "" {
+		id = GenerateProviderID(provider.Host, provider.Port, provider.Username)
+	}
+
+	name := strings.Map(func(r rune) rune {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, strings.ToUpper(id))
+
+	return "ALTMOUNT_PROVIDER_" + name + "_PASSWORD"
+}
+
+// expandEnvSecrets resolves secrets from the environment. An ALTMOUNT_* variable takes
+// precedence over the file value, followed by the contents of a *_file path. Otherwise a
+// ${ENV:VAR} reference is replaced with the variable's value. A reference to a variable
+// that is not set or a secret file that cannot be read is an error.
+func (c *Config) expandEnvSecrets() error {
+	refs := make(map[string]secretRef)
+
+	for _, field := range c.secretFields() {
+		raw := *field.value
+
+		if override, ok := os.LookupEnv(field.envVar); ok {
+			*field.value = override
+		} else if field.file != nil && *field.file != "" {
+			data, err := os.ReadFile(*field.file)
+			if err != nil {
+				return fmt.Errorf("%s: failed to read secret file: %w", field.key, err)
+			}
+			// Secret files usually end with a newline that is not part of the value
+			*field.value = strings.TrimRight(string(data), "\r\n")
+		} else if match := envReferencePattern.FindStringSubmatch(strings.TrimSpace(raw)); match != nil {
+			value, ok := os.LookupEnv(match[1])
+			if !ok {
+				return fmt.Errorf("%s references environment variable %s which is not set", field.key, match[1])
+			}
+			*field.value = value
+		} else {
+			continue
+		}
+
+		refs[field.key] = secretRef{raw: raw, resolved: *field.value}
+	}
+
+	if len(refs) > 0 {
+		c.secretRefs = refs
+	}
+
+	return nil
+}
+
+// restoreSecretRefs puts back the original file values of secrets that were resolved
+// from the environment and have not been changed since.
+func (c *Config) restoreSecretRefs() {
+	if len(c.secretRefs) == 0 {
+		return
+	}
+
+	for _, field := range c.secretFields() {
+		ref, ok := c.secretRefs[field.key]
+		if ok && *field.value == ref.resolved {
+			*field.value = ref.raw
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProviderPasswordOverrideFollowsProviderID(t *testing.T) {
+	cfg := DefaultConfig(t.TempDir())
+	cfg.Providers = []ProviderConfig{
+		{ID: "backup-news", Host: "backup.example.com", Port: 563, Username: "user", Password: "backup"},
+		{ID: "provider_1", Host: "news.example.com", Port: 563, Username: "user", Password: "primary"},
+		{Host: "other.example.com", Port: 563, Username: "user", Password: "other"},
+	}
+	generatedID := GenerateProviderID("other.example.com", 563, "user")
+
+	t.Setenv("ALTMOUNT_PROVIDER_PROVIDER_1_PASSWORD", "primary-env")
+	t.Setenv("ALTMOUNT_PROVIDER_BACKUP_NEWS_PASSWORD", "backup-env")
+	t.Setenv("ALTMOUNT_PROVIDER_"+strings.ToUpper(generatedID)+"_PASSWORD", "other-env")
+	// Variables keyed by position no longer apply
+	t.Setenv("ALTMOUNT_PROVIDER_0_PASSWORD", "index-env")
+
+	if err := cfg.expandEnvSecrets(); err != nil {
+		t.Fatalf("expandEnvSecrets: %v", err)
+	}
+
+	tests := []struct {
+		id   string
+		want string
+	}{
+		{id: "backup-news", want: "backup-env"},
+		{id: "provider_1", want: "primary-env"},
+		{id: generatedID, want: "other-env"},
+	}
+	for i, tt := range tests {
+		if got := cfg.Providers[i].Password; got != tt.want {
+			t.Errorf("provider %s: got password %q, want %q", tt.id, got, tt.want)
+		}
+	}
+
+	// Saving puts back the file values
+	cfg.restoreSecretRefs()
+	if cfg.Providers[0].Password != "backup" || cfg.Providers[1].Password != "primary" || cfg.Providers[2].Password != "other" {
+		t.Errorf("restored passwords: got %q, %q, %q", cfg.Providers[0].Password, cfg.Providers[1].Password, cfg.Providers[2].Password)
+	}
+}
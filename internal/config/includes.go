@@ -0,0 +1,405 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// applyIncludes merges the YAML fragments listed under includes into settings, the
+// settings read from the main config file. Fragments are merged in the order they are
+// listed, files matched by a glob pattern in lexical order:
+//   - mappings are merged key by key
+//   - scalars from a fragment override the value set by the main file or earlier fragments
+//   - list items from a fragment are appended; an item with the same id or name as an
+//     existing item (or the same value for plain lists) replaces it instead
+//
+// Relative paths are resolved against the directory of configFile. It returns every value
+// that came from a fragment, so saving the config writes back the values of the main file
+// instead of copying fragment values into it.
+func applyIncludes(settings map[string]any, configFile string) (*includeOrigins, error) {
+	patterns, err := includePatterns(settings["includes"])
+	if err != nil {
+		return nil, err
+	}
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+
+	baseDir := filepath.Dir(configFile)
+	origins := &includeOrigins{
+		scalars: make(map[string]includedScalar),
+		items:   make(map[string][]includedItem),
+		lengths: make(map[string]int),
+	}
+
+	for _, pattern := range patterns {
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(baseDir, pattern)
+		}
+
+		files := []string{pattern}
+		if strings.ContainsAny(pattern, "*?[") {
+			// A pattern that matches nothing is fine, e.g. an empty providers.d directory
+			files, err = filepath.Glob(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid include pattern %s: %w", pattern, err)
+			}
+			slices.Sort(files)
+		}
+
+		for _, file := range files {
+			fragment, err := readIncludeFile(file)
+			if err != nil {
+				return nil, err
+			}
+			origins.merge(settings, fragment, "")
+		}
+	}
+
+	return origins, nil
+}
+
+// includeOrigins records the values merged from included fragments. It is never modified
+// after the config is loaded.
+type includeOrigins struct {
+	scalars map[string]includedScalar // By key path
+	items   map[string][]includedItem // By list path
+	lengths map[string]int            // Length of each merged list, by list path
+}
+
+// includedScalar is a value set by a fragment, with the value the main file had for it
+type includedScalar struct {
+	original any
+	inMain   bool
+}
+
+// includedItem is a list item added by a fragment
+type includedItem struct {
+	index    int // Position in the merged list
+	value    any // Item as read from the fragment
+	original any // Item of the main file it replaced, nil when it was appended
+}
+
+// includePatterns returns the include patterns set in the main config file
+func includePatterns(value any) ([]string, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	items, ok := value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("includes must be a list of file paths")
+	}
+
+	patterns := make([]string, 0, len(items))
+	for _, item := range items {
+		pattern, ok := item.(string)
+		if !ok || strings.TrimSpace(pattern) == "" {
+			return nil, fmt.Errorf("includes must be a list of file paths")
+		}
+		patterns = append(patterns, pattern)
+	}
+
+	return patterns, nil
+}
+
+// readIncludeFile reads a config fragment
+func readIncludeFile(file string) (map[string]any, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read included config %s: %w", file, err)
+	}
+
+	fragment := make(map[string]any)
+	if err := yaml.Unmarshal(data, &fragment); err != nil {
+		return nil, fmt.Errorf("failed to parse included config %s: %w", file, err)
+	}
+
+	if _, ok := fragment["includes"]; ok {
+		return nil, fmt.Errorf("included config %s: includes can only be set in the main config file", file)
+	}
+
+	return fragment, nil
+}
+
+// merge merges src into dst, recording every value taken from src under its key path
+func (o *includeOrigins) merge(dst, src map[string]any, path string) {
+	for key, value := range src {
+		// Viper lowercases keys, fragments must match them
+		key = strings.ToLower(key)
+		keyPath := key
+		if path != "" {
+			keyPath = path + "." + key
+		}
+
+		switch v := value.(type) {
+		case map[string]any:
+			existing, ok := dst[key].(map[string]any)
+			if !ok {
+				// Merge into an empty mapping, so each value is recorded on its own
+				existing = make(map[string]any)
+				dst[key] = existing
+			}
+			o.merge(existing, v, keyPath)
+		case []any:
+			// Copy the list, replacing items must not modify the settings it was read from
+			existing, _ := dst[key].([]any)
+			dst[key] = o.appendItems(slices.Clone(existing), v, keyPath)
+		default:
+			// Keep the main file value, not the one of an earlier fragment
+			if _, ok := o.scalars[keyPath]; !ok {
+				original, inMain := dst[key]
+				o.scalars[keyPath] = includedScalar{original: original, inMain: inMain}
+			}
+			dst[key] = v
+		}
+	}
+}
+
+// appendItems appends items to list, replacing existing items with the same identity
+func (o *includeOrigins) appendItems(list, items []any, path string) []any {
+	for _, item := range items {
+		id := includeItemIdentity(item)
+		i := -1
+		if id != "" {
+			i = slices.IndexFunc(list, func(existing any) bool {
+				return includeItemIdentity(existing) == id
+			})
+		}
+
+		if i < 0 {
+			list = append(list, item)
+			o.items[path] = append(o.items[path], includedItem{index: len(list) - 1, value: item})
+			continue
+		}
+
+		// An item replacing one from an earlier fragment keeps the main file item it replaced
+		j := slices.IndexFunc(o.items[path], func(e includedItem) bool { return e.index == i })
+		if j >= 0 {
+			o.items[path][j].value = item
+		} else {
+			o.items[path] = append(o.items[path], includedItem{index: i, value: item, original: list[i]})
+		}
+		list[i] = item
+	}
+
+	o.lengths[path] = len(list)
+	return list
+}
+
+// includeItemIdentity returns the id or name of a list item, or the value of a plain item.
+// Items without an identity cannot be matched and return an empty string.
+func includeItemIdentity(item any) string {
+	switch v := item.(type) {
+	case map[string]any:
+		for _, key := range []string{"id", "name"} {
+			if id, ok := v[key]; ok && id != nil && fmt.Sprint(id) != "" {
+				return key + ":" + fmt.Sprint(id)
+			}
+		}
+		return ""
+	case nil:
+		return ""
+	default:
+		return "value:" + fmt.Sprint(v)
+	}
+}
+
+// strip replaces the values merged from fragments in the encoded config with the values
+// of the main file, so saving does not copy fragment values into the main config file.
+// List items are matched by identity or content. An item edited since loading is matched
+// by position, as long as no item was removed from its list.
+func (o *includeOrigins) strip(node *yaml.Node) error {
+	if o == nil {
+		return nil
+	}
+
+	for path, items := range o.items {
+		list := yamlNodeAtPath(node, strings.Split(path, "."))
+		if list == nil || list.Kind != yaml.SequenceNode {
+			continue
+		}
+
+		matched := make(map[int]*yaml.Node, len(items))
+		for _, item := range items {
+			i := matchIncludedItem(list.Content, item, matched)
+			if i < 0 && len(list.Content) >= o.lengths[path] {
+				if _, ok := matched[item.index]; !ok {
+					i = item.index
+				}
+			}
+			if i < 0 {
+				continue
+			}
+
+			// A nil replacement drops the item
+			var original *yaml.Node
+			if item.original != nil {
+				original = &yaml.Node{}
+				if err := original.Encode(item.original); err != nil {
+					return fmt.Errorf("failed to encode %s item: %w", path, err)
+				}
+			}
+			matched[i] = original
+		}
+
+		content := make([]*yaml.Node, 0, len(list.Content))
+		for i, item := range list.Content {
+			replacement, ok := matched[i]
+			switch {
+			case !ok:
+				content = append(content, item)
+			case replacement != nil:
+				content = append(content, replacement)
+			}
+		}
+		list.Content = content
+	}
+
+	for path, scalar := range o.scalars {
+		keys := strings.Split(path, ".")
+		parent := yamlNodeAtPath(node, keys[:len(keys)-1])
+		if parent == nil || parent.Kind != yaml.MappingNode {
+			continue
+		}
+
+		for i := 0; i+1 < len(parent.Content); i += 2 {
+			if !strings.EqualFold(parent.Content[i].Value, keys[len(keys)-1]) {
+				continue
+			}
+
+			if !scalar.inMain {
+				parent.Content = slices.Delete(parent.Content, i, i+2)
+				break
+			}
+
+			var original yaml.Node
+			if err := original.Encode(scalar.original); err != nil {
+				return fmt.Errorf("failed to encode %s: %w", path, err)
+			}
+			parent.Content[i+1] = &original
+			break
+		}
+	}
+
+	return nil
+}
+
+// matchIncludedItem returns the position of the encoded list item holding item, preferring
+// its recorded position, or -1. Positions in matched are already taken.
+func matchIncludedItem(list []*yaml.Node, item includedItem, matched map[int]*yaml.Node) int {
+	id := includeItemIdentity(item.value)
+	matches := func(i int) bool {
+		if _, ok := matched[i]; ok {
+			return false
+		}
+		if id != "" && yamlNodeIdentity(list[i]) == id {
+			return true
+		}
+
+		var decoded any
+		if err := list[i].Decode(&decoded); err != nil {
+			return false
+		}
+		return includeValueMatches(item.value, decoded)
+	}
+
+	if item.index < len(list) && matches(item.index) {
+		return item.index
+	}
+	for i := range list {
+		if matches(i) {
+			return i
+		}
+	}
+	return -1
+}
+
+// includeValueMatches reports whether got holds every value set in want. The encoded
+// config has every field, while a fragment only sets some of them.
+func includeValueMatches(want, got any) bool {
+	switch w := want.(type) {
+	case nil:
+		return true
+	case map[string]any:
+		g, ok := got.(map[string]any)
+		if !ok {
+			return false
+		}
+		for key, value := range w {
+			found := false
+			for gotKey, gotValue := range g {
+				if strings.EqualFold(key, gotKey) {
+					found = includeValueMatches(value, gotValue)
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+		return true
+	case []any:
+		g, ok := got.([]any)
+		if !ok || len(g) != len(w) {
+			return false
+		}
+		for i := range w {
+			if !includeValueMatches(w[i], g[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return fmt.Sprint(want) == fmt.Sprint(got)
+	}
+}
+
+// yamlNodeAtPath returns the value at the given keys of a mapping node
+func yamlNodeAtPath(node *yaml.Node, keys []string) *yaml.Node {
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+
+	for _, key := range keys {
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+
+		var next *yaml.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if strings.EqualFold(node.Content[i].Value, key) {
+				next = node.Content[i+1]
+				break
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		node = next
+	}
+
+	return node
+}
+
+// yamlNodeIdentity is includeItemIdentity for an encoded list item
+func yamlNodeIdentity(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for _, key := range []string{"id", "name"} {
+			if value := yamlNodeAtPath(node, []string{key}); value != nil && value.Kind == yaml.ScalarNode && value.Value != "" {
+				return key + ":" + value.Value
+			}
+		}
+		return ""
+	case yaml.ScalarNode:
+		return "value:" + node.Value
+	default:
+		return ""
+	}
+}
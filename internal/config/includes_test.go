@@ -0,0 +1,207 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeIncludeTestConfig saves a valid main config using the providers.d fragments, and
+// writes the given fragments
+func writeIncludeTestConfig(t *testing.T, main *Config, fragments map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "providers.d"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range fragments {
+		if err := os.WriteFile(filepath.Join(dir, "providers.d", name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	main.Includes = []string{"providers.d/*.yaml"}
+	file := filepath.Join(dir, "config.yaml")
+	if err := SaveToFile(main, file); err != nil {
+		t.Fatalf("SaveToFile: %v", err)
+	}
+	return file
+}
+
+func newIncludeTestConfig(t *testing.T) *Config {
+	t.Helper()
+
+	cfg := DefaultConfig(t.TempDir())
+	cfg.Providers = []ProviderConfig{{
+		Host:           "main.example.com",
+		Port:           563,
+		Username:       "main",
+		Password:       "main-secret",
+		MaxConnections: 10,
+		TLS:            true,
+	}}
+	return cfg
+}
+
+// saveAndReload saves cfg to file and loads it again
+func saveAndReload(t *testing.T, cfg *Config, file string) *Config {
+	t.Helper()
+
+	if err := SaveToFile(cfg, file); err != nil {
+		t.Fatalf("SaveToFile: %v", err)
+	}
+	reloaded, err := ReadConfig(file)
+	if err != nil {
+		t.Fatalf("reload after save: %v", err)
+	}
+	return reloaded
+}
+
+func TestIncludesSaveDoesNotCopyFragmentValues(t *testing.T) {
+	file := writeIncludeTestConfig(t, newIncludeTestConfig(t), map[string]string{
+		// Providers without id or name, and scalar overrides of the main file
+		"10-backup.yaml": `
+providers:
+  - host: backup.example.com
+    port: 119
+    username: backup
+    password: backup-secret
+    max_connections: 5
+webdav:
+  password: fragment-secret
+log:
+  level: debug
+`,
+	})
+
+	cfg, err := ReadConfig(file)
+	if err != nil {
+		t.Fatalf("ReadConfig: %v", err)
+	}
+	if len(cfg.Providers) != 2 || cfg.WebDAV.Password != "fragment-secret" || cfg.Log.Level != "debug" {
+		t.Fatalf("fragment not merged: providers=%d webdav.password=%q log.level=%q",
+			len(cfg.Providers), cfg.WebDAV.Password, cfg.Log.Level)
+	}
+
+	reloaded := saveAndReload(t, cfg, file)
+	if len(reloaded.Providers) != 2 {
+		t.Errorf("got %d providers after reload, want 2", len(reloaded.Providers))
+	}
+	if reloaded.WebDAV.Password != "fragment-secret" || reloaded.Log.Level != "debug" {
+		t.Errorf("fragment overrides lost: webdav.password=%q log.level=%q", reloaded.WebDAV.Password, reloaded.Log.Level)
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, value := range []string{"backup.example.com", "backup-secret", "fragment-secret", "level: debug"} {
+		if strings.Contains(string(data), value) {
+			t.Errorf("main config contains fragment value %q:\n%s", value, data)
+		}
+	}
+	if !strings.Contains(string(data), "main-secret") {
+		t.Errorf("main config lost its own provider:\n%s", data)
+	}
+
+	// Saving twice must be stable
+	again := saveAndReload(t, reloaded, file)
+	if len(again.Providers) != 2 {
+		t.Errorf("got %d providers after second reload, want 2", len(again.Providers))
+	}
+}
+
+func TestIncludesSaveKeepsMainValuesReplacedByFragments(t *testing.T) {
+	main := newIncludeTestConfig(t)
+	main.Providers[0].ID = "primary"
+	main.WebDAV.User = "main-user"
+	file := writeIncludeTestConfig(t, main, map[string]string{
+		"10-primary.yaml": `
+providers:
+  - id: primary
+    host: replaced.example.com
+    port: 563
+    username: replaced
+    password: replaced-secret
+    max_connections: 20
+webdav:
+  user: fragment-user
+`,
+	})
+
+	cfg, err := ReadConfig(file)
+	if err != nil {
+		t.Fatalf("ReadConfig: %v", err)
+	}
+	if len(cfg.Providers) != 1 || cfg.Providers[0].Host != "replaced.example.com" {
+		t.Fatalf("fragment did not replace the provider: %+v", cfg.Providers)
+	}
+
+	reloaded := saveAndReload(t, cfg, file)
+	if len(reloaded.Providers) != 1 || reloaded.Providers[0].Host != "replaced.example.com" {
+		t.Errorf("unexpected providers after reload: %+v", reloaded.Providers)
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, value := range []string{"main.example.com", "main-secret", "main-user"} {
+		if !strings.Contains(string(data), value) {
+			t.Errorf("main config lost its value %q:\n%s", value, data)
+		}
+	}
+	for _, value := range []string{"replaced.example.com", "replaced-secret", "fragment-user"} {
+		if strings.Contains(string(data), value) {
+			t.Errorf("main config contains fragment value %q:\n%s", value, data)
+		}
+	}
+}
+
+func TestIncludesSaveAfterEditingFragmentItem(t *testing.T) {
+	file := writeIncludeTestConfig(t, newIncludeTestConfig(t), map[string]string{
+		"10-backup.yaml": `
+providers:
+  - host: backup.example.com
+    port: 119
+    username: backup
+    password: backup-secret
+    max_connections: 5
+`,
+	})
+
+	cfg, err := ReadConfig(file)
+	if err != nil {
+		t.Fatalf("ReadConfig: %v", err)
+	}
+
+	// An item edited in place no longer matches the fragment and is found by position
+	cfg.Providers[1].MaxConnections = 8
+	cfg.Providers[1].Password = "changed-secret"
+	// A provider added after loading belongs to the main file
+	cfg.Providers = append(cfg.Providers, ProviderConfig{
+		Host:           "new.example.com",
+		Port:           563,
+		Username:       "new",
+		Password:       "new-secret",
+		MaxConnections: 4,
+	})
+
+	reloaded := saveAndReload(t, cfg, file)
+	if len(reloaded.Providers) != 3 {
+		t.Fatalf("got %d providers after reload, want 3", len(reloaded.Providers))
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "backup.example.com") {
+		t.Errorf("main config contains the fragment provider:\n%s", data)
+	}
+	if !strings.Contains(string(data), "new.example.com") {
+		t.Errorf("main config is missing the added provider:\n%s", data)
+	}
+}
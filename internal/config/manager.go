@@ -1,22 +1,32 @@
 package config
 
 import (
+	"cmp"
 	"crypto/sha256"
 	"fmt"
+	"log/slog"
+	"maps"
+	"net/netip"
+	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/javi11/nntppool/v2"
 	"github.com/spf13/viper"
-	"gopkg.in/yaml.v3"
 )
 
 const MountProvider = "altmount"
 
+// DefaultWebDAVPrefix is the URL path the WebDAV server is mounted at unless configured
+const DefaultWebDAVPrefix = "/webdav"
+
 // Config represents the complete application configuration
 type Config struct {
+	Version         int              `yaml:"version" mapstructure:"version" json:"version"` // Config schema version (see CurrentConfigVersion)
 	WebDAV          WebDAVConfig     `yaml:"webdav" mapstructure:"webdav" json:"webdav"`
 	API             APIConfig        `yaml:"api" mapstructure:"api" json:"api"`
 	Auth            AuthConfig       `yaml:"auth" mapstructure:"auth" json:"auth"`
@@ -29,9 +39,22 @@ type Config struct {
 	Log             LogConfig        `yaml:"log" mapstructure:"log" json:"log,omitempty"`
 	SABnzbd         SABnzbdConfig    `yaml:"sabnzbd" mapstructure:"sabnzbd" json:"sabnzbd"`
 	Arrs            ArrsConfig       `yaml:"arrs" mapstructure:"arrs" json:"arrs"`
+	Pool            PoolConfig       `yaml:"pool" mapstructure:"pool" json:"pool"`
 	Providers       []ProviderConfig `yaml:"providers" mapstructure:"providers" json:"providers"`
 	MountPath       string           `yaml:"mount_path" mapstructure:"mount_path" json:"mount_path"` // WebDAV mount path
 	ProfilerEnabled bool             `yaml:"profiler_enabled" mapstructure:"profiler_enabled" json:"profiler_enabled" default:"false"`
+	MetricsEnabled  bool             `yaml:"metrics_enabled" mapstructure:"metrics_enabled" json:"metrics_enabled"`
+	WatchConfig     bool             `yaml:"watch_config" mapstructure:"watch_config" json:"watch_config"` // Reload automatically when the config file changes
+
+	// Includes lists config fragments merged into this file, see applyIncludes
+	Includes []string `yaml:"includes,omitempty" mapstructure:"includes" json:"includes,omitempty"`
+
+	// secretRefs tracks secrets resolved from the environment (see expandEnvSecrets)
+	secretRefs map[string]secretRef
+	// pathRefs tracks paths resolved against the config directory (see resolveRelativePaths)
+	pathRefs map[string]pathRef
+	// included tracks the values merged from included fragments (see applyIncludes)
+	included *includeOrigins
 }
 
 // WebDAVConfig represents WebDAV server configuration
@@ -39,6 +62,10 @@ type WebDAVConfig struct {
 	Port     int    `yaml:"port" mapstructure:"port" json:"port"`
 	User     string `yaml:"user" mapstructure:"user" json:"user"`
 	Password string `yaml:"password" mapstructure:"password" json:"password"`
+	ReadOnly bool   `yaml:"read_only" mapstructure:"read_only" json:"read_only"`
+	Prefix   string `yaml:"prefix" mapstructure:"prefix" json:"prefix"`
+	// Deepest PROPFIND allowed, deeper requests are refused (0 = allow Depth: infinity)
+	MaxPropfindDepth int `yaml:"max_propfind_depth" mapstructure:"max_propfind_depth" json:"max_propfind_depth"`
 }
 
 // APIConfig represents REST API configuration
@@ -49,11 +76,57 @@ type APIConfig struct {
 // AuthConfig represents authentication configuration
 type AuthConfig struct {
 	LoginRequired *bool `yaml:"login_required" mapstructure:"login_required" json:"login_required"`
+	// Networks (CIDRs or single IPs) allowed to stream and use WebDAV, checked before the
+	// credentials. Loopback clients are allowed unless the request has a forwarding header
+	// such as X-Forwarded-For, list 127.0.0.1 and ::1 to allow a local reverse proxy. Empty
+	// allows every client.
+	AllowedNetworks []string `yaml:"allowed_networks" mapstructure:"allowed_networks" json:"allowed_networks,omitempty"`
+	// Reverse proxies whose X-Forwarded-For header identifies the client for allowed_networks
+	TrustedProxies []string `yaml:"trusted_proxies" mapstructure:"trusted_proxies" json:"trusted_proxies,omitempty"`
 }
 
 // DatabaseConfig represents database configuration
 type DatabaseConfig struct {
 	Path string `yaml:"path" mapstructure:"path" json:"path"`
+	// SQLite connection settings, applied to every connection when the database is opened
+	JournalMode   string `yaml:"journal_mode" mapstructure:"journal_mode" json:"journal_mode,omitempty"`          // DELETE, TRUNCATE, PERSIST, MEMORY, WAL or OFF (default: WAL)
+	BusyTimeoutMs int    `yaml:"busy_timeout_ms" mapstructure:"busy_timeout_ms" json:"busy_timeout_ms,omitempty"` // How long a query waits for a lock before failing (default: 30000)
+	Synchronous   string `yaml:"synchronous" mapstructure:"synchronous" json:"synchronous,omitempty"`             // OFF, NORMAL, FULL or EXTRA (default: NORMAL)
+	// Hours between runs of PRAGMA optimize and VACUUM, postponed while imports or health
+	// checks are running. 0 disables it.
+	MaintenanceIntervalHours int `yaml:"maintenance_interval_hours" mapstructure:"maintenance_interval_hours" json:"maintenance_interval_hours,omitempty"`
+}
+
+// SQLite journal modes and synchronous levels accepted in the database config
+var (
+	sqliteJournalModes      = []string{"DELETE", "TRUNCATE", "PERSIST", "MEMORY", "WAL", "OFF"}
+	sqliteSynchronousLevels = []string{"OFF", "NORMAL", "FULL", "EXTRA"}
+)
+
+// GetJournalMode returns the SQLite journal mode, defaulting to WAL so readers do not
+// block writers
+func (d *DatabaseConfig) GetJournalMode() string {
+	if d.JournalMode == "" {
+		return "WAL"
+	}
+	return strings.ToUpper(d.JournalMode)
+}
+
+// GetBusyTimeout returns how long SQLite waits for a lock, defaulting to 30 seconds
+func (d *DatabaseConfig) GetBusyTimeout() time.Duration {
+	if d.BusyTimeoutMs <= 0 {
+		return 30 * time.Second
+	}
+	return time.Duration(d.BusyTimeoutMs) * time.Millisecond
+}
+
+// GetSynchronous returns the SQLite synchronous level, defaulting to NORMAL, which is
+// safe in WAL mode
+func (d *DatabaseConfig) GetSynchronous() string {
+	if d.Synchronous == "" {
+		return "NORMAL"
+	}
+	return strings.ToUpper(d.Synchronous)
 }
 
 // MetadataConfig represents metadata filesystem configuration
@@ -64,8 +137,18 @@ type MetadataConfig struct {
 
 // StreamingConfig represents streaming and chunking configuration
 type StreamingConfig struct {
-	MaxDownloadWorkers int `yaml:"max_download_workers" mapstructure:"max_download_workers" json:"max_download_workers"`
-	MaxCacheSizeMB     int `yaml:"max_cache_size_mb" mapstructure:"max_cache_size_mb" json:"max_cache_size_mb"`
+	MaxDownloadWorkers         int `yaml:"max_download_workers" mapstructure:"max_download_workers" json:"max_download_workers"`
+	MaxCacheSizeMB             int `yaml:"max_cache_size_mb" mapstructure:"max_cache_size_mb" json:"max_cache_size_mb"`
+	ReadAheadMB                int `yaml:"read_ahead_mb" mapstructure:"read_ahead_mb" json:"read_ahead_mb"`
+	MaxConcurrentStreamsPerKey int `yaml:"max_concurrent_streams_per_key" mapstructure:"max_concurrent_streams_per_key" json:"max_concurrent_streams_per_key"`
+
+	// On-disk segment cache kept across restarts, disabled when DiskCacheDir is empty
+	DiskCacheDir       string `yaml:"disk_cache_dir" mapstructure:"disk_cache_dir" json:"disk_cache_dir"`
+	DiskCacheMaxSizeMB int    `yaml:"disk_cache_max_size_mb" mapstructure:"disk_cache_max_size_mb" json:"disk_cache_max_size_mb"`
+
+	// In-memory cache of the start and end of files, which media servers read to probe them
+	ProbeWindowKB    int `yaml:"probe_window_kb" mapstructure:"probe_window_kb" json:"probe_window_kb"`
+	ProbeCacheSizeMB int `yaml:"probe_cache_size_mb" mapstructure:"probe_cache_size_mb" json:"probe_cache_size_mb"`
 }
 
 // RCloneConfig represents rclone configuration
@@ -75,14 +158,18 @@ type RCloneConfig struct {
 	// Encryption
 	Password string `yaml:"password" mapstructure:"password" json:"-"`
 	Salt     string `yaml:"salt" mapstructure:"salt" json:"-"`
+	// Files to read the secrets from instead, e.g. Docker or Kubernetes secrets
+	PasswordFile string `yaml:"password_file,omitempty" mapstructure:"password_file" json:"-"`
+	SaltFile     string `yaml:"salt_file,omitempty" mapstructure:"salt_file" json:"-"`
 
 	// RC (Remote Control) Configuration
-	RCEnabled *bool             `yaml:"rc_enabled" mapstructure:"rc_enabled" json:"rc_enabled"`
-	RCUrl     string            `yaml:"rc_url" mapstructure:"rc_url" json:"rc_url"`
-	RCPort    int               `yaml:"rc_port" mapstructure:"rc_port" json:"rc_port"`
-	RCUser    string            `yaml:"rc_user" mapstructure:"rc_user" json:"rc_user"`
-	RCPass    string            `yaml:"rc_pass" mapstructure:"rc_pass" json:"-"`
-	RCOptions map[string]string `yaml:"rc_options" mapstructure:"rc_options" json:"rc_options"`
+	RCEnabled  *bool             `yaml:"rc_enabled" mapstructure:"rc_enabled" json:"rc_enabled"`
+	RCUrl      string            `yaml:"rc_url" mapstructure:"rc_url" json:"rc_url"`
+	RCPort     int               `yaml:"rc_port" mapstructure:"rc_port" json:"rc_port"`
+	RCUser     string            `yaml:"rc_user" mapstructure:"rc_user" json:"rc_user"`
+	RCPass     string            `yaml:"rc_pass" mapstructure:"rc_pass" json:"-"`
+	RCPassFile string            `yaml:"rc_pass_file,omitempty" mapstructure:"rc_pass_file" json:"-"`
+	RCOptions  map[string]string `yaml:"rc_options" mapstructure:"rc_options" json:"rc_options"`
 
 	// Mount Configuration
 	MountEnabled *bool             `yaml:"mount_enabled" mapstructure:"mount_enabled" json:"mount_enabled"`
@@ -123,6 +210,9 @@ type RCloneConfig struct {
 	AsyncRead          bool `yaml:"async_read" mapstructure:"async_read" json:"async_read"`
 	VFSFastFingerprint bool `yaml:"vfs_fast_fingerprint" mapstructure:"vfs_fast_fingerprint" json:"vfs_fast_fingerprint"`
 	UseMmap            bool `yaml:"use_mmap" mapstructure:"use_mmap" json:"use_mmap"`
+
+	// Reject unknown keys in rc_options and mount_options instead of only logging them
+	StrictOptions bool `yaml:"strict_options" mapstructure:"strict_options" json:"strict_options"`
 }
 
 // ImportStrategy represents the import strategy type
@@ -134,26 +224,171 @@ const (
 	ImportStrategySTRM    ImportStrategy = "STRM"
 )
 
+// SampleStrategy selects which segments are checked when segments are sampled
+type SampleStrategy string
+
+const (
+	SampleStrategyPercentage  SampleStrategy = "PERCENTAGE"  // First and last segments plus random ones from the middle
+	SampleStrategyHeadTail    SampleStrategy = "HEAD_TAIL"   // Contiguous runs at the start, middle and end of the file
+	SampleStrategyDistributed SampleStrategy = "DISTRIBUTED" // One random segment from each evenly sized slice of the file
+)
+
 // ImportConfig represents import processing configuration
 type ImportConfig struct {
 	MaxProcessorWorkers            int            `yaml:"max_processor_workers" mapstructure:"max_processor_workers" json:"max_processor_workers"`
+	HighPriorityWorkers            int            `yaml:"high_priority_workers" mapstructure:"high_priority_workers" json:"high_priority_workers"`
 	QueueProcessingIntervalSeconds int            `yaml:"queue_processing_interval_seconds" mapstructure:"queue_processing_interval_seconds" json:"queue_processing_interval_seconds"`
 	AllowedFileExtensions          []string       `yaml:"allowed_file_extensions" mapstructure:"allowed_file_extensions" json:"allowed_file_extensions"`
 	MaxImportConnections           int            `yaml:"max_import_connections" mapstructure:"max_import_connections" json:"max_import_connections"`
 	ImportCacheSizeMB              int            `yaml:"import_cache_size_mb" mapstructure:"import_cache_size_mb" json:"import_cache_size_mb"`
 	SegmentSamplePercentage        int            `yaml:"segment_sample_percentage" mapstructure:"segment_sample_percentage" json:"segment_sample_percentage"`
+	SampleStrategy                 SampleStrategy `yaml:"sample_strategy" mapstructure:"sample_strategy" json:"sample_strategy"`
 	ImportStrategy                 ImportStrategy `yaml:"import_strategy" mapstructure:"import_strategy" json:"import_strategy"`
 	ImportDir                      *string        `yaml:"import_dir" mapstructure:"import_dir" json:"import_dir,omitempty"`
+	MinFileSizeMB                  int            `yaml:"min_file_size_mb" mapstructure:"min_file_size_mb" json:"min_file_size_mb"` // Files smaller than this are skipped (0 = no limit)
+	MaxQueueDepth                  int            `yaml:"max_queue_depth" mapstructure:"max_queue_depth" json:"max_queue_depth"`    // Pending and processing items allowed before new additions are rejected (0 = unlimited)
+	// ExtensionRules overrides allowed_file_extensions and the import strategy per extension.
+	// Keys are extensions with or without the leading dot (e.g. "iso").
+	ExtensionRules map[string]ExtensionRule `yaml:"extension_rules,omitempty" mapstructure:"extension_rules" json:"extension_rules,omitempty"`
+
+	// PostImportWebhookURL receives a JSON notification after every successful import (empty = disabled).
+	// When PostImportWebhookSecret is set the body is signed with it using HMAC-SHA256.
+	PostImportWebhookURL    string `yaml:"post_import_webhook_url,omitempty" mapstructure:"post_import_webhook_url" json:"post_import_webhook_url,omitempty"`
+	PostImportWebhookSecret string `yaml:"post_import_webhook_secret,omitempty" mapstructure:"post_import_webhook_secret" json:"post_import_webhook_secret,omitempty"`
+}
+
+// ExtensionRule controls how files with a given extension are imported
+type ExtensionRule struct {
+	Allowed       *bool          `yaml:"allowed,omitempty" mapstructure:"allowed" json:"allowed,omitempty"`                            // Whether these files are imported, overriding allowed_file_extensions (unset = inherit)
+	Strategy      ImportStrategy `yaml:"strategy,omitempty" mapstructure:"strategy" json:"strategy,omitempty"`                         // Overrides the import strategy for these files (empty = inherit)
+	MinFileSizeMB *int           `yaml:"min_file_size_mb,omitempty" mapstructure:"min_file_size_mb" json:"min_file_size_mb,omitempty"` // Overrides min_file_size_mb for these files
+}
+
+// normalizeExtension lowercases an extension and ensures it has a leading dot
+func normalizeExtension(ext string) string {
+	ext = strings.ToLower(strings.TrimSpace(ext))
+	if ext == "" {
+		return ""
+	}
+	return "." + strings.TrimPrefix(ext, ".")
+}
+
+// ExtensionRule returns the rule for the extension of filename, if one is configured
+func (ic ImportConfig) ExtensionRule(filename string) (ExtensionRule, bool) {
+	ext := normalizeExtension(filepath.Ext(filename))
+	if ext == "" {
+		return ExtensionRule{}, false
+	}
+	for key, rule := range ic.ExtensionRules {
+		if normalizeExtension(key) == ext {
+			return rule, true
+		}
+	}
+	return ExtensionRule{}, false
+}
+
+// EffectiveAllowedExtensions returns allowed_file_extensions with extension rules applied:
+// allowed rules add their extension and disallowed rules remove it, rules that leave allowed
+// unset change nothing. An empty result still means every extension is allowed, so allowed
+// rules never narrow an empty list.
+func (ic ImportConfig) EffectiveAllowedExtensions() []string {
+	if len(ic.AllowedFileExtensions) == 0 {
+		return nil
+	}
+
+	allowed := make(map[string]bool, len(ic.AllowedFileExtensions)+len(ic.ExtensionRules))
+	var extensions []string
+	for _, ext := range ic.AllowedFileExtensions {
+		ext = normalizeExtension(ext)
+		if ext != "" && !allowed[ext] {
+			allowed[ext] = true
+			extensions = append(extensions, ext)
+		}
+	}
+
+	for key, rule := range ic.ExtensionRules {
+		ext := normalizeExtension(key)
+		if ext == "" || rule.Allowed == nil || allowed[ext] == *rule.Allowed {
+			continue
+		}
+		allowed[ext] = *rule.Allowed
+		if *rule.Allowed {
+			extensions = append(extensions, ext)
+		}
+	}
+
+	result := extensions[:0]
+	for _, ext := range extensions {
+		if allowed[ext] {
+			result = append(result, ext)
+		}
+	}
+	return result
+}
+
+// StrategyForFile returns the import strategy for filename, applying its extension rule
+// on top of the given strategy
+func (ic ImportConfig) StrategyForFile(filename string, strategy ImportStrategy) ImportStrategy {
+	if rule, ok := ic.ExtensionRule(filename); ok && rule.Strategy != "" {
+		return rule.Strategy
+	}
+	return strategy
+}
+
+// MinFileSizeBytes returns the size below which filename is skipped on import, using its
+// extension rule when it sets one and min_file_size_mb otherwise. 0 means no limit.
+func (ic ImportConfig) MinFileSizeBytes(filename string) int64 {
+	minSizeMB := ic.MinFileSizeMB
+	if rule, ok := ic.ExtensionRule(filename); ok && rule.MinFileSizeMB != nil {
+		minSizeMB = *rule.MinFileSizeMB
+	}
+	return int64(minSizeMB) * 1024 * 1024
+}
+
+// HasExtensionStrategy reports whether any extension rule selects the given strategy
+func (ic ImportConfig) HasExtensionStrategy(strategy ImportStrategy) bool {
+	for _, rule := range ic.ExtensionRules {
+		if rule.Strategy == strategy {
+			return true
+		}
+	}
+	return false
 }
 
 // LogConfig represents logging configuration with rotation support
 type LogConfig struct {
 	File       string `yaml:"file" mapstructure:"file" json:"file,omitempty"`                      // Log file path (empty = console only)
 	Level      string `yaml:"level" mapstructure:"level" json:"level,omitempty"`                   // Log level (debug, info, warn, error)
+	Format     string `yaml:"format" mapstructure:"format" json:"format,omitempty"`                // Log output format (text, json)
 	MaxSize    int    `yaml:"max_size" mapstructure:"max_size" json:"max_size,omitempty"`          // Max size in MB before rotation
 	MaxAge     int    `yaml:"max_age" mapstructure:"max_age" json:"max_age,omitempty"`             // Max age in days to keep files
 	MaxBackups int    `yaml:"max_backups" mapstructure:"max_backups" json:"max_backups,omitempty"` // Max number of old files to keep
 	Compress   bool   `yaml:"compress" mapstructure:"compress" json:"compress,omitempty"`          // Compress old log files
+
+	// Level per component, overriding Level for the logs of that component
+	ComponentLevels map[string]string `yaml:"component_levels" mapstructure:"component_levels" json:"component_levels,omitempty"`
+}
+
+// LogComponents are the components that can be given their own level in
+// log.component_levels, the values of the component attribute set by each subsystem
+var LogComponents = []string{
+	"7z-processor",
+	"config-watcher",
+	"database",
+	"health",
+	"importer-service",
+	"library-sync",
+	"metrics-tracker",
+	"nzb-parser",
+	"nzb-processor",
+	"pool",
+	"progress-broadcaster",
+	"rar-processor",
+	"rclone",
+	"strm-parser",
+	"usenet-reader",
+	"webdav",
+	"webdav-auth-updater",
 }
 
 // HealthConfig represents health checker configuration
@@ -166,6 +401,42 @@ type HealthConfig struct {
 	SegmentSamplePercentage       int     `yaml:"segment_sample_percentage" mapstructure:"segment_sample_percentage" json:"segment_sample_percentage,omitempty"`
 	LibrarySyncIntervalMinutes    int     `yaml:"library_sync_interval_minutes" mapstructure:"library_sync_interval_minutes" json:"library_sync_interval_minutes,omitempty"`
 	LibrarySyncConcurrency        int     `yaml:"library_sync_concurrency" mapstructure:"library_sync_concurrency" json:"library_sync_concurrency,omitempty"`
+	MaxConcurrentJobs             int     `yaml:"max_concurrent_jobs" mapstructure:"max_concurrent_jobs" json:"max_concurrent_jobs,omitempty"`
+	CheckTimeoutSeconds           int     `yaml:"check_timeout_seconds" mapstructure:"check_timeout_seconds" json:"check_timeout_seconds,omitempty"`
+	RetryBackoffBaseSeconds       int     `yaml:"retry_backoff_base_seconds" mapstructure:"retry_backoff_base_seconds" json:"retry_backoff_base_seconds,omitempty"`
+	RetryBackoffMaxSeconds        int     `yaml:"retry_backoff_max_seconds" mapstructure:"retry_backoff_max_seconds" json:"retry_backoff_max_seconds,omitempty"`
+	AutoRepairEnabled             *bool   `yaml:"auto_repair_enabled" mapstructure:"auto_repair_enabled" json:"auto_repair_enabled,omitempty"`
+	RepairCooldownSeconds         int     `yaml:"repair_cooldown_seconds" mapstructure:"repair_cooldown_seconds" json:"repair_cooldown_seconds,omitempty"`
+	DryRun                        *bool   `yaml:"dry_run" mapstructure:"dry_run" json:"dry_run,omitempty"`
+	CorruptionWebhookURL          string  `yaml:"corruption_webhook_url" mapstructure:"corruption_webhook_url" json:"corruption_webhook_url,omitempty"` // Notified when a file is permanently marked corrupted (empty = disabled)
+
+	// SampleTiers override segment_sample_percentage by file age, so recent releases that are
+	// more likely to be incomplete are checked more thoroughly than files that proved healthy.
+	SampleTiers []HealthSampleTier `yaml:"sample_tiers,omitempty" mapstructure:"sample_tiers" json:"sample_tiers,omitempty"`
+}
+
+// HealthSampleTier is the sample percentage used for files released at most MaxAgeDays ago
+type HealthSampleTier struct {
+	MaxAgeDays       int `yaml:"max_age_days" mapstructure:"max_age_days" json:"max_age_days"`
+	SamplePercentage int `yaml:"sample_percentage" mapstructure:"sample_percentage" json:"sample_percentage"`
+}
+
+// SamplePercentageForAge returns the percentage of segments to check for a file of the
+// given age: the one of the tier with the smallest max_age_days covering it, or
+// segment_sample_percentage when no tier does
+func (hc HealthConfig) SamplePercentageForAge(age time.Duration) int {
+	percentage := hc.SegmentSamplePercentage
+	maxAgeDays := 0
+	for _, tier := range hc.SampleTiers {
+		if age > time.Duration(tier.MaxAgeDays)*24*time.Hour {
+			continue
+		}
+		if maxAgeDays == 0 || tier.MaxAgeDays < maxAgeDays {
+			percentage = tier.SamplePercentage
+			maxAgeDays = tier.MaxAgeDays
+		}
+	}
+	return percentage
 }
 
 // GenerateProviderID creates a unique ID based on host, port, and username
@@ -227,8 +498,45 @@ func checkDirectoryWritable(path string) error {
 	return nil
 }
 
+// checkDirectoryUsable checks without touching the filesystem that path is a directory, or
+// that it does not exist yet and its nearest existing parent is a directory it can be created in
+func checkDirectoryUsable(path string) error {
+	if path == "" {
+		return fmt.Errorf("path cannot be empty")
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		absPath = path
+	}
+
+	for dir := absPath; ; dir = filepath.Dir(dir) {
+		info, err := os.Stat(dir)
+		if err == nil {
+			if !info.IsDir() {
+				if dir == absPath {
+					return fmt.Errorf("path %s exists but is not a directory", absPath)
+				}
+				return fmt.Errorf("directory %s cannot be created, %s is not a directory", absPath, dir)
+			}
+			return nil
+		}
+		if !os.IsNotExist(err) {
+			return fmt.Errorf("cannot access directory %s: %w", dir, err)
+		}
+		if parent := filepath.Dir(dir); parent == dir {
+			return fmt.Errorf("directory %s cannot be created", absPath)
+		}
+	}
+}
+
 // checkFileDirectoryWritable checks if the directory containing a file path is writable
 func checkFileDirectoryWritable(filePath string, fileType string) error {
+	return checkFileDirectory(filePath, fileType, checkDirectoryWritable)
+}
+
+// checkFileDirectory checks the directory containing a file path with check
+func checkFileDirectory(filePath string, fileType string, check func(path string) error) error {
 	if filePath == "" {
 		return nil // Empty path is valid for some config options (like log file)
 	}
@@ -239,13 +547,21 @@ func checkFileDirectoryWritable(filePath string, fileType string) error {
 		dir = "./" // current directory
 	}
 
-	if err := checkDirectoryWritable(dir); err != nil {
+	if err := check(dir); err != nil {
 		return fmt.Errorf("%s file directory check failed: %w", fileType, err)
 	}
 
 	return nil
 }
 
+// PoolConfig represents NNTP connection pool settings shared by all providers
+type PoolConfig struct {
+	// Retry settings for transient provider errors (0 = pool defaults). The pool retries an
+	// operation across all providers, so they cannot be set per provider.
+	RetryAttempts       int `yaml:"retry_attempts" mapstructure:"retry_attempts" json:"retry_attempts,omitempty"`
+	RetryBackoffSeconds int `yaml:"retry_backoff_seconds" mapstructure:"retry_backoff_seconds" json:"retry_backoff_seconds,omitempty"`
+}
+
 // ProviderConfig represents a single NNTP provider configuration
 type ProviderConfig struct {
 	ID               string `yaml:"id" mapstructure:"id" json:"id"`
@@ -258,6 +574,30 @@ type ProviderConfig struct {
 	InsecureTLS      bool   `yaml:"insecure_tls" mapstructure:"insecure_tls" json:"insecure_tls"`
 	Enabled          *bool  `yaml:"enabled" mapstructure:"enabled" json:"enabled,omitempty"`
 	IsBackupProvider *bool  `yaml:"is_backup_provider" mapstructure:"is_backup_provider" json:"is_backup_provider,omitempty"`
+	// Connection lifetime settings (0 = default of 60 seconds)
+	MaxConnectionIdleTimeSeconds int `yaml:"max_connection_idle_time_seconds" mapstructure:"max_connection_idle_time_seconds" json:"max_connection_idle_time_seconds,omitempty"`
+	MaxConnectionTTLSeconds      int `yaml:"max_connection_ttl_seconds" mapstructure:"max_connection_ttl_seconds" json:"max_connection_ttl_seconds,omitempty"`
+	// Download speed cap for this provider in KB/s (0 = unlimited)
+	SpeedLimitKBps int `yaml:"speed_limit_kbps" mapstructure:"speed_limit_kbps" json:"speed_limit_kbps,omitempty"`
+	// Failover order: providers with a lower priority are tried first. Providers of equal
+	// priority keep their configured order.
+	Priority int `yaml:"priority" mapstructure:"priority" json:"priority,omitempty"`
+}
+
+// GetMaxConnectionIdleTimeSeconds returns the idle timeout for pooled connections, defaulting to 60 seconds
+func (p *ProviderConfig) GetMaxConnectionIdleTimeSeconds() int {
+	if p.MaxConnectionIdleTimeSeconds <= 0 {
+		return 60 // Default idle timeout
+	}
+	return p.MaxConnectionIdleTimeSeconds
+}
+
+// GetMaxConnectionTTLSeconds returns the maximum lifetime of pooled connections, defaulting to 60 seconds
+func (p *ProviderConfig) GetMaxConnectionTTLSeconds() int {
+	if p.MaxConnectionTTLSeconds <= 0 {
+		return 60 // Default connection TTL
+	}
+	return p.MaxConnectionTTLSeconds
 }
 
 // SABnzbdConfig represents SABnzbd-compatible API configuration
@@ -276,14 +616,29 @@ type SABnzbdCategory struct {
 	Order    int    `yaml:"order" mapstructure:"order" json:"order"`
 	Priority int    `yaml:"priority" mapstructure:"priority" json:"priority"`
 	Dir      string `yaml:"dir" mapstructure:"dir" json:"dir"`
+	// Optional overrides of the global import strategy and directory for this category
+	ImportStrategy ImportStrategy `yaml:"import_strategy,omitempty" mapstructure:"import_strategy" json:"import_strategy,omitempty"`
+	ImportDir      *string        `yaml:"import_dir,omitempty" mapstructure:"import_dir" json:"import_dir,omitempty"`
 }
 
 // ArrsConfig represents arrs configuration
 type ArrsConfig struct {
-	Enabled         *bool                `yaml:"enabled" mapstructure:"enabled" json:"enabled"`
-	MaxWorkers      int                  `yaml:"max_workers" mapstructure:"max_workers" json:"max_workers,omitempty"`
-	RadarrInstances []ArrsInstanceConfig `yaml:"radarr_instances" mapstructure:"radarr_instances" json:"radarr_instances"`
-	SonarrInstances []ArrsInstanceConfig `yaml:"sonarr_instances" mapstructure:"sonarr_instances" json:"sonarr_instances"`
+	Enabled                  *bool                `yaml:"enabled" mapstructure:"enabled" json:"enabled"`
+	MaxWorkers               int                  `yaml:"max_workers" mapstructure:"max_workers" json:"max_workers,omitempty"`
+	DefaultSyncIntervalHours int                  `yaml:"default_sync_interval_hours" mapstructure:"default_sync_interval_hours" json:"default_sync_interval_hours,omitempty"` // Used when an instance leaves sync_interval_hours unset
+	RadarrInstances          []ArrsInstanceConfig `yaml:"radarr_instances" mapstructure:"radarr_instances" json:"radarr_instances"`
+	SonarrInstances          []ArrsInstanceConfig `yaml:"sonarr_instances" mapstructure:"sonarr_instances" json:"sonarr_instances"`
+	LidarrInstances          []ArrsInstanceConfig `yaml:"lidarr_instances" mapstructure:"lidarr_instances" json:"lidarr_instances"`
+	ReadarrInstances         []ArrsInstanceConfig `yaml:"readarr_instances" mapstructure:"readarr_instances" json:"readarr_instances"`
+}
+
+// EffectiveSyncIntervalHours returns the sync interval for an instance: its own
+// sync_interval_hours when set, otherwise the global default_sync_interval_hours
+func (a ArrsConfig) EffectiveSyncIntervalHours(instance ArrsInstanceConfig) int {
+	if instance.SyncIntervalHours != nil {
+		return *instance.SyncIntervalHours
+	}
+	return a.DefaultSyncIntervalHours
 }
 
 // ArrsInstanceConfig represents a single arrs instance configuration
@@ -293,6 +648,8 @@ type ArrsInstanceConfig struct {
 	APIKey            string `yaml:"api_key" mapstructure:"api_key" json:"api_key"`
 	Enabled           *bool  `yaml:"enabled" mapstructure:"enabled" json:"enabled,omitempty"`
 	SyncIntervalHours *int   `yaml:"sync_interval_hours" mapstructure:"sync_interval_hours" json:"sync_interval_hours,omitempty"`
+	// Tags limits repair rescans to items carrying at least one of these tags (empty = all items)
+	Tags []string `yaml:"tags,omitempty" mapstructure:"tags" json:"tags,omitempty"`
 }
 
 // DeepCopy returns a deep copy of the configuration
@@ -304,6 +661,40 @@ func (c *Config) DeepCopy() *Config {
 	// Start with a shallow copy of value fields
 	copyCfg := *c
 
+	// Deep copy secret references resolved from the environment
+	if c.secretRefs != nil {
+		copyCfg.secretRefs = make(map[string]secretRef, len(c.secretRefs))
+		for k, v := range c.secretRefs {
+			copyCfg.secretRefs[k] = v
+		}
+	}
+
+	// Deep copy paths resolved against the config directory
+	if c.pathRefs != nil {
+		copyCfg.pathRefs = make(map[string]pathRef, len(c.pathRefs))
+		for k, v := range c.pathRefs {
+			copyCfg.pathRefs[k] = v
+		}
+	}
+
+	// Deep copy includes, the values they merged are never modified and can be shared
+	if c.Includes != nil {
+		copyCfg.Includes = slices.Clone(c.Includes)
+	}
+
+	// Deep copy Log.ComponentLevels map
+	if c.Log.ComponentLevels != nil {
+		copyCfg.Log.ComponentLevels = maps.Clone(c.Log.ComponentLevels)
+	}
+
+	// Deep copy the network allow-list
+	if c.Auth.AllowedNetworks != nil {
+		copyCfg.Auth.AllowedNetworks = slices.Clone(c.Auth.AllowedNetworks)
+	}
+	if c.Auth.TrustedProxies != nil {
+		copyCfg.Auth.TrustedProxies = slices.Clone(c.Auth.TrustedProxies)
+	}
+
 	// Deep copy Auth.LoginRequired pointer
 	if c.Auth.LoginRequired != nil {
 		v := *c.Auth.LoginRequired
@@ -336,6 +727,22 @@ func (c *Config) DeepCopy() *Config {
 		copyCfg.Health.CleanupOrphanedFiles = nil
 	}
 
+	// Deep copy Health.AutoRepairEnabled pointer
+	if c.Health.AutoRepairEnabled != nil {
+		v := *c.Health.AutoRepairEnabled
+		copyCfg.Health.AutoRepairEnabled = &v
+	} else {
+		copyCfg.Health.AutoRepairEnabled = nil
+	}
+
+	// Deep copy Health.DryRun pointer
+	if c.Health.DryRun != nil {
+		v := *c.Health.DryRun
+		copyCfg.Health.DryRun = &v
+	} else {
+		copyCfg.Health.DryRun = nil
+	}
+
 	// Deep copy Metadata.DeleteSourceNzbOnRemoval pointer
 	if c.Metadata.DeleteSourceNzbOnRemoval != nil {
 		v := *c.Metadata.DeleteSourceNzbOnRemoval
@@ -352,6 +759,27 @@ func (c *Config) DeepCopy() *Config {
 		copyCfg.Import.ImportDir = nil
 	}
 
+	// Deep copy Health.SampleTiers slice
+	if c.Health.SampleTiers != nil {
+		copyCfg.Health.SampleTiers = slices.Clone(c.Health.SampleTiers)
+	}
+
+	// Deep copy Import.ExtensionRules map
+	if c.Import.ExtensionRules != nil {
+		copyCfg.Import.ExtensionRules = make(map[string]ExtensionRule, len(c.Import.ExtensionRules))
+		for ext, rule := range c.Import.ExtensionRules {
+			if rule.Allowed != nil {
+				v := *rule.Allowed
+				rule.Allowed = &v
+			}
+			if rule.MinFileSizeMB != nil {
+				v := *rule.MinFileSizeMB
+				rule.MinFileSizeMB = &v
+			}
+			copyCfg.Import.ExtensionRules[ext] = rule
+		}
+	}
+
 	// Deep copy RClone.RCEnabled pointer
 	if c.RClone.RCEnabled != nil {
 		v := *c.RClone.RCEnabled
@@ -368,6 +796,16 @@ func (c *Config) DeepCopy() *Config {
 		copyCfg.RClone.MountEnabled = nil
 	}
 
+	// Deep copy RClone.RCOptions map
+	if c.RClone.RCOptions != nil {
+		copyCfg.RClone.RCOptions = make(map[string]string, len(c.RClone.RCOptions))
+		for k, v := range c.RClone.RCOptions {
+			copyCfg.RClone.RCOptions[k] = v
+		}
+	} else {
+		copyCfg.RClone.RCOptions = nil
+	}
+
 	// Deep copy RClone.MountOptions map
 	if c.RClone.MountOptions != nil {
 		copyCfg.RClone.MountOptions = make(map[string]string, len(c.RClone.MountOptions))
@@ -413,6 +851,12 @@ func (c *Config) DeepCopy() *Config {
 	if c.SABnzbd.Categories != nil {
 		copyCfg.SABnzbd.Categories = make([]SABnzbdCategory, len(c.SABnzbd.Categories))
 		copy(copyCfg.SABnzbd.Categories, c.SABnzbd.Categories)
+		for i, category := range c.SABnzbd.Categories {
+			if category.ImportDir != nil {
+				v := *category.ImportDir
+				copyCfg.SABnzbd.Categories[i].ImportDir = &v
+			}
+		}
 	} else {
 		copyCfg.SABnzbd.Categories = nil
 	}
@@ -429,93 +873,182 @@ func (c *Config) DeepCopy() *Config {
 		copyCfg.Arrs.Enabled = nil
 	}
 
-	// Deep copy Scraper Radarr instances
-	if c.Arrs.RadarrInstances != nil {
-		copyCfg.Arrs.RadarrInstances = make([]ArrsInstanceConfig, len(c.Arrs.RadarrInstances))
-		for i, inst := range c.Arrs.RadarrInstances {
-			ic := inst // copy struct value
-			if inst.Enabled != nil {
-				ev := *inst.Enabled
-				ic.Enabled = &ev
-			} else {
-				ic.Enabled = nil
-			}
-			if inst.SyncIntervalHours != nil {
-				iv := *inst.SyncIntervalHours
-				ic.SyncIntervalHours = &iv
-			} else {
-				ic.SyncIntervalHours = nil
-			}
+	// Deep copy arrs instances
+	copyCfg.Arrs.RadarrInstances = copyArrsInstances(c.Arrs.RadarrInstances)
+	copyCfg.Arrs.SonarrInstances = copyArrsInstances(c.Arrs.SonarrInstances)
+	copyCfg.Arrs.LidarrInstances = copyArrsInstances(c.Arrs.LidarrInstances)
+	copyCfg.Arrs.ReadarrInstances = copyArrsInstances(c.Arrs.ReadarrInstances)
+
+	return &copyCfg
+}
 
-			copyCfg.Arrs.RadarrInstances[i] = ic
+// copyArrsInstances returns a deep copy of a list of arrs instances
+func copyArrsInstances(instances []ArrsInstanceConfig) []ArrsInstanceConfig {
+	if instances == nil {
+		return nil
+	}
+
+	copied := make([]ArrsInstanceConfig, len(instances))
+	for i, inst := range instances {
+		ic := inst // copy struct value
+		if inst.Enabled != nil {
+			ev := *inst.Enabled
+			ic.Enabled = &ev
 		}
-	} else {
-		copyCfg.Arrs.RadarrInstances = nil
+		if inst.SyncIntervalHours != nil {
+			iv := *inst.SyncIntervalHours
+			ic.SyncIntervalHours = &iv
+		}
+		if inst.Tags != nil {
+			ic.Tags = append([]string(nil), inst.Tags...)
+		}
+		copied[i] = ic
 	}
 
-	// Deep copy Scraper Sonarr instances
-	if c.Arrs.SonarrInstances != nil {
-		copyCfg.Arrs.SonarrInstances = make([]ArrsInstanceConfig, len(c.Arrs.SonarrInstances))
-		for i, inst := range c.Arrs.SonarrInstances {
-			ic := inst // copy struct value
-			if inst.Enabled != nil {
-				ev := *inst.Enabled
-				ic.Enabled = &ev
-			} else {
-				ic.Enabled = nil
-			}
-			if inst.SyncIntervalHours != nil {
-				iv := *inst.SyncIntervalHours
-				ic.SyncIntervalHours = &iv
-			} else {
-				ic.SyncIntervalHours = nil
-			}
+	return copied
+}
 
-			copyCfg.Arrs.SonarrInstances[i] = ic
+// ImportSettingsForCategory returns the import strategy and directory to use for NZBs in the
+// given category. Settings the category does not override fall back to the global import config.
+func (c *Config) ImportSettingsForCategory(category string) (ImportStrategy, *string) {
+	strategy := c.Import.ImportStrategy
+	importDir := c.Import.ImportDir
+
+	for _, cat := range c.SABnzbd.Categories {
+		if cat.Name != category {
+			continue
 		}
-	} else {
-		copyCfg.Arrs.SonarrInstances = nil
+		if cat.ImportStrategy != "" {
+			strategy = cat.ImportStrategy
+		}
+		if cat.ImportDir != nil && *cat.ImportDir != "" {
+			importDir = cat.ImportDir
+		}
+		break
 	}
 
-	return &copyCfg
+	return strategy, importDir
 }
 
-// Validate validates the configuration
+// Validate validates the configuration. All problems are reported together as ValidationErrors.
 func (c *Config) Validate() error {
+	var errs ValidationErrors
+
+	if c.Database.JournalMode != "" && !slices.Contains(sqliteJournalModes, c.Database.GetJournalMode()) {
+		errs.add("database.journal_mode", "database journal_mode must be one of: %s", strings.Join(sqliteJournalModes, ", "))
+	}
+	if c.Database.BusyTimeoutMs < 0 {
+		errs.add("database.busy_timeout_ms", "database busy_timeout_ms must be 0 (default) or greater")
+	}
+	if c.Database.MaintenanceIntervalHours < 0 {
+		errs.add("database.maintenance_interval_hours", "database maintenance_interval_hours must be 0 (disabled) or greater")
+	}
+	if c.Database.Synchronous != "" && !slices.Contains(sqliteSynchronousLevels, c.Database.GetSynchronous()) {
+		errs.add("database.synchronous", "database synchronous must be one of: %s", strings.Join(sqliteSynchronousLevels, ", "))
+	}
+
+	for i, network := range c.Auth.AllowedNetworks {
+		if !isValidNetwork(network) {
+			errs.add(fmt.Sprintf("auth.allowed_networks[%d]", i), "auth allowed_networks entry %q must be a CIDR such as 192.168.1.0/24 or an IP address", network)
+		}
+	}
+	for i, proxy := range c.Auth.TrustedProxies {
+		if !isValidNetwork(proxy) {
+			errs.add(fmt.Sprintf("auth.trusted_proxies[%d]", i), "auth trusted_proxies entry %q must be a CIDR such as 172.16.0.0/12 or an IP address", proxy)
+		}
+	}
+
 	if c.WebDAV.Port <= 0 || c.WebDAV.Port > 65535 {
-		return fmt.Errorf("webdav port must be between 1 and 65535")
+		errs.add("webdav.port", "webdav port must be between 1 and 65535")
+	}
+
+	if c.WebDAV.MaxPropfindDepth < 0 {
+		errs.add("webdav.max_propfind_depth", "webdav max_propfind_depth must be 0 (unlimited) or greater")
+	}
+
+	if c.WebDAV.Prefix == "" {
+		c.WebDAV.Prefix = DefaultWebDAVPrefix
+	}
+	c.WebDAV.Prefix = strings.TrimRight(c.WebDAV.Prefix, "/")
+	switch {
+	case !strings.HasPrefix(c.WebDAV.Prefix, "/") || c.WebDAV.Prefix == "":
+		errs.add("webdav.prefix", "webdav prefix must start with / and cannot be the root path")
+	case c.WebDAV.Prefix == "/api" || strings.HasPrefix(c.WebDAV.Prefix, "/api/"):
+		errs.add("webdav.prefix", "webdav prefix cannot be under /api")
 	}
 
 	if c.Streaming.MaxDownloadWorkers <= 0 {
-		return fmt.Errorf("streaming max_download_workers must be greater than 0")
+		errs.add("streaming.max_download_workers", "streaming max_download_workers must be greater than 0")
 	}
 
 	if c.Streaming.MaxCacheSizeMB <= 0 {
 		c.Streaming.MaxCacheSizeMB = 32 // Default to 32MB if not set
 	}
 
+	if c.Streaming.ReadAheadMB < 0 || c.Streaming.ReadAheadMB > 1024 {
+		errs.add("streaming.read_ahead_mb", "streaming read_ahead_mb must be between 0 and 1024")
+	}
+
+	if c.Streaming.MaxConcurrentStreamsPerKey < 0 {
+		errs.add("streaming.max_concurrent_streams_per_key", "streaming max_concurrent_streams_per_key must be non-negative")
+	}
+
+	if c.Streaming.DiskCacheMaxSizeMB < 0 {
+		errs.add("streaming.disk_cache_max_size_mb", "streaming disk_cache_max_size_mb must be non-negative")
+	} else if c.Streaming.DiskCacheDir != "" && c.Streaming.DiskCacheMaxSizeMB == 0 {
+		c.Streaming.DiskCacheMaxSizeMB = 10240 // Default to 10GB if not set
+	}
+
+	if c.Streaming.ProbeWindowKB < 0 || c.Streaming.ProbeWindowKB > 65536 {
+		errs.add("streaming.probe_window_kb", "streaming probe_window_kb must be between 0 and 65536")
+	}
+
+	if c.Streaming.ProbeCacheSizeMB < 0 {
+		errs.add("streaming.probe_cache_size_mb", "streaming probe_cache_size_mb must be non-negative")
+	}
+
 	if c.Import.MaxProcessorWorkers <= 0 {
-		return fmt.Errorf("import max_processor_workers must be greater than 0")
+		errs.add("import.max_processor_workers", "import max_processor_workers must be greater than 0")
+	}
+
+	if c.Import.HighPriorityWorkers < 0 {
+		errs.add("import.high_priority_workers", "import high_priority_workers must be 0 or greater")
+	} else if c.Import.MaxProcessorWorkers > 0 && c.Import.HighPriorityWorkers >= c.Import.MaxProcessorWorkers {
+		errs.add("import.high_priority_workers", "import high_priority_workers must be less than max_processor_workers so normal priority items are still processed")
 	}
 
 	if c.Import.QueueProcessingIntervalSeconds < 1 {
-		return fmt.Errorf("import queue_processing_interval_seconds must be at least 1 second")
+		errs.add("import.queue_processing_interval_seconds", "import queue_processing_interval_seconds must be at least 1 second")
 	}
 
 	if c.Import.QueueProcessingIntervalSeconds > 300 {
-		return fmt.Errorf("import queue_processing_interval_seconds must not exceed 300 seconds")
+		errs.add("import.queue_processing_interval_seconds", "import queue_processing_interval_seconds must not exceed 300 seconds")
 	}
 
 	if c.Import.MaxImportConnections <= 0 {
-		return fmt.Errorf("import max_import_connections must be greater than 0")
+		errs.add("import.max_import_connections", "import max_import_connections must be greater than 0")
 	}
 
 	if c.Import.ImportCacheSizeMB <= 0 {
-		return fmt.Errorf("import import_cache_size_mb must be greater than 0")
+		errs.add("import.import_cache_size_mb", "import import_cache_size_mb must be greater than 0")
 	}
 
 	if c.Import.SegmentSamplePercentage < 1 || c.Import.SegmentSamplePercentage > 100 {
-		return fmt.Errorf("import segment_sample_percentage must be between 1 and 100")
+		errs.add("import.segment_sample_percentage", "import segment_sample_percentage must be between 1 and 100")
+	}
+
+	switch c.Import.SampleStrategy {
+	case SampleStrategyPercentage, SampleStrategyHeadTail, SampleStrategyDistributed:
+	default:
+		errs.add("import.sample_strategy", "import sample_strategy must be one of: PERCENTAGE, HEAD_TAIL, DISTRIBUTED")
+	}
+
+	if c.Import.MinFileSizeMB < 0 {
+		errs.add("import.min_file_size_mb", "import min_file_size_mb must be non-negative")
+	}
+
+	if c.Import.MaxQueueDepth < 0 {
+		errs.add("import.max_queue_depth", "import max_queue_depth must be non-negative")
 	}
 
 	// Validate import strategy
@@ -525,31 +1058,74 @@ func (c *Config) Validate() error {
 		ImportStrategySTRM:    true,
 	}
 	if !validStrategies[c.Import.ImportStrategy] {
-		return fmt.Errorf("import_strategy must be one of: NONE, SYMLINK, STRM")
+		errs.add("import.import_strategy", "import_strategy must be one of: NONE, SYMLINK, STRM")
 	}
 
 	// Validate import directory when strategy requires it
 	if c.Import.ImportStrategy == ImportStrategySYMLINK || c.Import.ImportStrategy == ImportStrategySTRM {
 		if c.Import.ImportDir == nil || *c.Import.ImportDir == "" {
-			return fmt.Errorf("import_dir cannot be empty when import strategy is %s", c.Import.ImportStrategy)
+			errs.add("import.import_dir", "import_dir cannot be empty when import strategy is %s", c.Import.ImportStrategy)
+		} else if !filepath.IsAbs(*c.Import.ImportDir) {
+			errs.add("import.import_dir", "import_dir must be an absolute path")
+		}
+	}
+
+	// Validate per-extension rules
+	seenExtensions := make(map[string]string, len(c.Import.ExtensionRules))
+	for _, key := range slices.Sorted(maps.Keys(c.Import.ExtensionRules)) {
+		rule := c.Import.ExtensionRules[key]
+		field := fmt.Sprintf("import.extension_rules.%s", key)
+		ext := normalizeExtension(key)
+		if len(ext) < 2 || strings.ContainsAny(ext[1:], "./\\") {
+			errs.add(field, "import extension rule %q: not a valid file extension", key)
+			continue
 		}
-		if !filepath.IsAbs(*c.Import.ImportDir) {
-			return fmt.Errorf("import_dir must be an absolute path")
+		if other, ok := seenExtensions[ext]; ok {
+			errs.add(field, "import extension rules %q and %q refer to the same extension", other, key)
+		}
+		seenExtensions[ext] = key
+
+		if rule.MinFileSizeMB != nil && *rule.MinFileSizeMB < 0 {
+			errs.add(field+".min_file_size_mb", "import extension rule %q: min_file_size_mb must be non-negative", key)
+		}
+
+		if rule.Allowed != nil && !*rule.Allowed && len(c.Import.AllowedFileExtensions) == 0 {
+			errs.add(field+".allowed", "import extension rule %q: disallowing an extension requires allowed_file_extensions to be set", key)
+		}
+
+		if rule.Strategy == "" {
+			continue
+		}
+		if !validStrategies[rule.Strategy] {
+			errs.add(field+".strategy", "import extension rule %q: strategy must be one of: NONE, SYMLINK, STRM", key)
+		} else if (rule.Strategy == ImportStrategySYMLINK || rule.Strategy == ImportStrategySTRM) && (c.Import.ImportDir == nil || *c.Import.ImportDir == "") {
+			errs.add(field+".strategy", "import extension rule %q: import_dir cannot be empty when strategy is %s", key, rule.Strategy)
 		}
 	}
 
-	// Validate log level (both old and new config)
-	if c.Log.Level != "" {
-		validLevels := []string{"debug", "info", "warn", "error"}
-		isValid := false
-		for _, level := range validLevels {
-			if c.Log.Level == level {
-				isValid = true
-				break
-			}
+	if c.Import.PostImportWebhookURL != "" {
+		if u, err := url.Parse(c.Import.PostImportWebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs.add("import.post_import_webhook_url", "import post_import_webhook_url %q must be a valid http:// or https:// URL", c.Import.PostImportWebhookURL)
 		}
-		if !isValid {
-			return fmt.Errorf("log_level must be one of: debug, info, warn, error")
+	}
+
+	// Validate per-category import overrides against the same rules, inheriting unset values
+	for i, category := range c.SABnzbd.Categories {
+		if category.ImportStrategy == "" && category.ImportDir == nil {
+			continue
+		}
+
+		field := fmt.Sprintf("sabnzbd.categories[%d]", i)
+		if category.ImportStrategy != "" && !validStrategies[category.ImportStrategy] {
+			errs.add(field+".import_strategy", "sabnzbd category '%s': import_strategy must be one of: NONE, SYMLINK, STRM", category.Name)
+			continue
+		}
+
+		strategy, importDir := c.ImportSettingsForCategory(category.Name)
+		if category.ImportDir != nil && *category.ImportDir != "" && !filepath.IsAbs(*category.ImportDir) {
+			errs.add(field+".import_dir", "sabnzbd category '%s': import_dir must be an absolute path", category.Name)
+		} else if (strategy == ImportStrategySYMLINK || strategy == ImportStrategySTRM) && (importDir == nil || *importDir == "") {
+			errs.add(field+".import_dir", "sabnzbd category '%s': import_dir cannot be empty when import strategy is %s", category.Name, strategy)
 		}
 	}
 
@@ -564,60 +1140,109 @@ func (c *Config) Validate() error {
 			}
 		}
 		if !isValid {
-			return fmt.Errorf("log.level must be one of: debug, info, warn, error")
+			errs.add("log.level", "log.level must be one of: debug, info, warn, error")
+		}
+	}
+
+	for _, component := range slices.Sorted(maps.Keys(c.Log.ComponentLevels)) {
+		level := c.Log.ComponentLevels[component]
+		if !slices.Contains(LogComponents, component) {
+			errs.add("log.component_levels", "unknown log component %q, must be one of: %s", component, strings.Join(LogComponents, ", "))
+		}
+		if !slices.Contains([]string{"debug", "info", "warn", "error"}, level) {
+			errs.add("log.component_levels", "log level of component %q must be one of: debug, info, warn, error", component)
 		}
 	}
 
+	switch c.Log.Format {
+	case "", "text", "json":
+	default:
+		errs.add("log.format", "log.format must be one of: text, json")
+	}
+
 	if c.Log.MaxSize < 0 {
-		return fmt.Errorf("log.max_size must be non-negative")
+		errs.add("log.max_size", "log.max_size must be non-negative")
 	}
 
 	if c.Log.MaxAge < 0 {
-		return fmt.Errorf("log.max_age must be non-negative")
+		errs.add("log.max_age", "log.max_age must be non-negative")
 	}
 
 	if c.Log.MaxBackups < 0 {
-		return fmt.Errorf("log.max_backups must be non-negative")
+		errs.add("log.max_backups", "log.max_backups must be non-negative")
 	}
 
 	// Validate metadata configuration (now required)
 	if c.Metadata.RootPath == "" {
-		return fmt.Errorf("metadata root_path cannot be empty")
+		errs.add("metadata.root_path", "metadata root_path cannot be empty")
 	}
 
 	// Validate streaming configuration
 
 	// Validate health configuration (always active)
 	if c.Health.CheckIntervalSeconds <= 0 {
-		return fmt.Errorf("health check_interval_seconds must be greater than 0")
+		errs.add("health.check_interval_seconds", "health check_interval_seconds must be greater than 0")
 	}
 	if c.Health.MaxConnectionsForHealthChecks <= 0 {
-		return fmt.Errorf("health max_connections_for_health_checks must be greater than 0")
+		errs.add("health.max_connections_for_health_checks", "health max_connections_for_health_checks must be greater than 0")
 	}
 	if c.Health.LibrarySyncIntervalMinutes < 0 {
-		return fmt.Errorf("health library_sync_interval_minutes must be non-negative")
+		errs.add("health.library_sync_interval_minutes", "health library_sync_interval_minutes must be non-negative")
+	}
+	if c.Health.CheckTimeoutSeconds <= 0 {
+		errs.add("health.check_timeout_seconds", "health check_timeout_seconds must be greater than 0")
+	}
+	if c.Health.RetryBackoffBaseSeconds < 0 {
+		errs.add("health.retry_backoff_base_seconds", "health retry_backoff_base_seconds must be non-negative")
+	}
+	if c.Health.RetryBackoffMaxSeconds < 0 {
+		errs.add("health.retry_backoff_max_seconds", "health retry_backoff_max_seconds must be non-negative")
+	} else if c.Health.RetryBackoffMaxSeconds > 0 && c.Health.RetryBackoffMaxSeconds < c.Health.RetryBackoffBaseSeconds {
+		errs.add("health.retry_backoff_max_seconds", "health retry_backoff_max_seconds must be at least retry_backoff_base_seconds")
+	}
+	if c.Health.RepairCooldownSeconds < 0 {
+		errs.add("health.repair_cooldown_seconds", "health repair_cooldown_seconds must be non-negative")
+	}
+	if c.Health.CorruptionWebhookURL != "" {
+		if u, err := url.Parse(c.Health.CorruptionWebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs.add("health.corruption_webhook_url", "health corruption_webhook_url %q must be a valid http:// or https:// URL", c.Health.CorruptionWebhookURL)
+		}
+	}
+	if c.Health.MaxConcurrentJobs < 0 {
+		errs.add("health.max_concurrent_jobs", "health max_concurrent_jobs must be non-negative")
 	}
 	if c.Health.SegmentSamplePercentage < 1 || c.Health.SegmentSamplePercentage > 100 {
-		return fmt.Errorf("health segment_sample_percentage must be between 1 and 100")
+		errs.add("health.segment_sample_percentage", "health segment_sample_percentage must be between 1 and 100")
+	}
+	seenTierAges := make(map[int]bool, len(c.Health.SampleTiers))
+	for i, tier := range c.Health.SampleTiers {
+		field := fmt.Sprintf("health.sample_tiers[%d]", i)
+		if tier.MaxAgeDays <= 0 {
+			errs.add(field+".max_age_days", "health sample tier %d: max_age_days must be greater than 0", i)
+		} else if seenTierAges[tier.MaxAgeDays] {
+			errs.add(field+".max_age_days", "health sample tier %d: duplicate max_age_days %d", i, tier.MaxAgeDays)
+		}
+		seenTierAges[tier.MaxAgeDays] = true
+		if tier.SamplePercentage < 1 || tier.SamplePercentage > 100 {
+			errs.add(field+".sample_percentage", "health sample tier %d: sample_percentage must be between 1 and 100", i)
+		}
 	}
 
 	// Validate health configuration - requires library_dir when enabled
 	if c.Health.Enabled != nil && *c.Health.Enabled {
 		if c.Health.LibraryDir == nil || *c.Health.LibraryDir == "" {
-			return fmt.Errorf("health library_dir is required when health system is enabled")
-		}
-		if !filepath.IsAbs(*c.Health.LibraryDir) {
-			return fmt.Errorf("health library_dir must be an absolute path")
+			errs.add("health.library_dir", "health library_dir is required when health system is enabled")
+		} else if !filepath.IsAbs(*c.Health.LibraryDir) {
+			errs.add("health.library_dir", "health library_dir must be an absolute path")
 		}
 	}
 
 	// Validate cleanup orphaned files - requires library_dir when enabled
 	if c.Health.CleanupOrphanedFiles != nil && *c.Health.CleanupOrphanedFiles {
 		if c.Health.LibraryDir == nil || *c.Health.LibraryDir == "" {
-			return fmt.Errorf("health library_dir is required when cleanup_orphaned_files is enabled")
-		}
-		if !filepath.IsAbs(*c.Health.LibraryDir) {
-			return fmt.Errorf("health library_dir must be an absolute path")
+			errs.add("health.library_dir", "health library_dir is required when cleanup_orphaned_files is enabled")
+		} else if !filepath.IsAbs(*c.Health.LibraryDir) {
+			errs.add("health.library_dir", "health library_dir must be an absolute path")
 		}
 	}
 
@@ -630,33 +1255,37 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	// Normalize free-form rclone options and check them against the known flags
+	c.RClone.RCOptions = normalizeRCloneOptions(&errs, "rclone.rc_options", c.RClone.RCOptions, knownRCOptions, c.RClone.StrictOptions)
+	c.RClone.MountOptions = normalizeRCloneOptions(&errs, "rclone.mount_options", c.RClone.MountOptions, knownMountOptions, c.RClone.StrictOptions)
+
 	// Validate RClone Mount configuration
 	if c.RClone.MountEnabled != nil && *c.RClone.MountEnabled {
 		if c.MountPath == "" {
-			return fmt.Errorf("rclone mount_path cannot be empty when mount is enabled")
-		}
-		if !filepath.IsAbs(c.MountPath) {
-			return fmt.Errorf("rclone mount_path must be an absolute path")
+			errs.add("mount_path", "rclone mount_path cannot be empty when mount is enabled")
+		} else if !filepath.IsAbs(c.MountPath) {
+			errs.add("mount_path", "rclone mount_path must be an absolute path")
 		}
 	}
 
 	// Validate SABnzbd configuration
 	if c.SABnzbd.Enabled != nil && *c.SABnzbd.Enabled {
 		if c.SABnzbd.CompleteDir == "" {
-			return fmt.Errorf("sabnzbd complete_dir cannot be empty when SABnzbd is enabled")
-		}
-		if !filepath.IsAbs(c.SABnzbd.CompleteDir) {
-			return fmt.Errorf("sabnzbd complete_dir must be an absolute path")
+			errs.add("sabnzbd.complete_dir", "sabnzbd complete_dir cannot be empty when SABnzbd is enabled")
+		} else if !filepath.IsAbs(c.SABnzbd.CompleteDir) {
+			errs.add("sabnzbd.complete_dir", "sabnzbd complete_dir must be an absolute path")
 		}
 
 		// Validate categories if provided
 		categoryNames := make(map[string]bool)
 		for i, category := range c.SABnzbd.Categories {
 			if category.Name == "" {
-				return fmt.Errorf("sabnzbd category %d: name cannot be empty", i)
+				errs.add(fmt.Sprintf("sabnzbd.categories[%d].name", i), "sabnzbd category %d: name cannot be empty", i)
+				continue
 			}
 			if categoryNames[category.Name] {
-				return fmt.Errorf("sabnzbd category %d: duplicate category name '%s'", i, category.Name)
+				errs.add(fmt.Sprintf("sabnzbd.categories[%d].name", i), "sabnzbd category %d: duplicate category name '%s'", i, category.Name)
+				continue
 			}
 			categoryNames[category.Name] = true
 		}
@@ -665,7 +1294,7 @@ func (c *Config) Validate() error {
 		if c.SABnzbd.FallbackHost != "" {
 			// Basic URL validation
 			if !strings.HasPrefix(c.SABnzbd.FallbackHost, "http://") && !strings.HasPrefix(c.SABnzbd.FallbackHost, "https://") {
-				return fmt.Errorf("sabnzbd fallback_host must start with http:// or https://")
+				errs.add("sabnzbd.fallback_host", "sabnzbd fallback_host must start with http:// or https://")
 			}
 			// Warn if API key is missing (but don't fail validation)
 			if c.SABnzbd.FallbackAPIKey == "" {
@@ -676,54 +1305,202 @@ func (c *Config) Validate() error {
 
 	// Validate mount_path
 	if c.MountPath != "" && !filepath.IsAbs(c.MountPath) {
-		return fmt.Errorf("mount_path must be an absolute path")
+		errs.add("mount_path", "mount_path must be an absolute path")
 	}
 
 	// Validate scraper configuration
 	if c.Arrs.Enabled != nil && *c.Arrs.Enabled {
 		// Mount path is required when ARRs is enabled
 		if c.MountPath == "" {
-			return fmt.Errorf("mount_path is required when arrs is enabled")
+			errs.add("mount_path", "mount_path is required when arrs is enabled")
 		}
 		if c.Arrs.MaxWorkers <= 0 {
-			return fmt.Errorf("scraper max_workers must be greater than 0")
+			errs.add("arrs.max_workers", "scraper max_workers must be greater than 0")
+		}
+
+		if c.Arrs.DefaultSyncIntervalHours < 1 {
+			errs.add("arrs.default_sync_interval_hours", "arrs default_sync_interval_hours must be at least 1")
 		}
+
+		validateArrsInstances(&errs, "radarr_instances", c.Arrs.RadarrInstances)
+		validateArrsInstances(&errs, "sonarr_instances", c.Arrs.SonarrInstances)
+		validateArrsInstances(&errs, "lidarr_instances", c.Arrs.LidarrInstances)
+		validateArrsInstances(&errs, "readarr_instances", c.Arrs.ReadarrInstances)
+	}
+
+	if c.Pool.RetryAttempts < 0 || c.Pool.RetryAttempts > 10 {
+		errs.add("pool.retry_attempts", "pool retry_attempts must be between 0 and 10")
+	}
+	if c.Pool.RetryBackoffSeconds < 0 || c.Pool.RetryBackoffSeconds > 60 {
+		errs.add("pool.retry_backoff_seconds", "pool retry_backoff_seconds must be between 0 and 60")
 	}
 
 	// Validate each provider
 	for i, provider := range c.Providers {
 		if provider.Host == "" {
-			return fmt.Errorf("provider %d: host cannot be empty", i)
+			errs.add(fmt.Sprintf("providers[%d].host", i), "provider %d: host cannot be empty", i)
 		}
 		if provider.Port <= 0 || provider.Port > 65535 {
-			return fmt.Errorf("provider %d: port must be between 1 and 65535", i)
+			errs.add(fmt.Sprintf("providers[%d].port", i), "provider %d: port must be between 1 and 65535", i)
 		}
 		if provider.MaxConnections <= 0 {
-			return fmt.Errorf("provider %d: max_connections must be greater than 0", i)
+			errs.add(fmt.Sprintf("providers[%d].max_connections", i), "provider %d: max_connections must be greater than 0", i)
+		}
+		if provider.MaxConnectionIdleTimeSeconds < 0 {
+			errs.add(fmt.Sprintf("providers[%d].max_connection_idle_time_seconds", i), "provider %d: max_connection_idle_time_seconds must be non-negative", i)
+		}
+		if provider.MaxConnectionTTLSeconds < 0 {
+			errs.add(fmt.Sprintf("providers[%d].max_connection_ttl_seconds", i), "provider %d: max_connection_ttl_seconds must be non-negative", i)
+		}
+		if provider.SpeedLimitKBps < 0 {
+			errs.add(fmt.Sprintf("providers[%d].speed_limit_kbps", i), "provider %d: speed_limit_kbps must be non-negative", i)
+		}
+		if provider.Priority < 0 {
+			errs.add(fmt.Sprintf("providers[%d].priority", i), "provider %d: priority must be non-negative", i)
 		}
 	}
 
+	c.validateProviderIdentities(&errs)
+
+	if len(errs) > 0 {
+		return errs
+	}
+
 	return nil
 }
 
+// validateArrsInstances checks that every instance of one arrs type has a unique name, a
+// well-formed http(s) URL, when enabled an API key, and a sync interval of at least one hour
+// if it overrides the default. Errors name the offending instance.
+func validateArrsInstances(errs *ValidationErrors, key string, instances []ArrsInstanceConfig) {
+	names := make(map[string]bool)
+	for i, inst := range instances {
+		field := fmt.Sprintf("arrs.%s[%d]", key, i)
+
+		label := fmt.Sprintf("%d", i)
+		if inst.Name != "" {
+			label = fmt.Sprintf("'%s'", inst.Name)
+		}
+
+		if inst.Name == "" {
+			errs.add(field+".name", "arrs %s %s: name cannot be empty", key, label)
+		} else if names[inst.Name] {
+			errs.add(field+".name", "arrs %s %s: duplicate instance name", key, label)
+		}
+		names[inst.Name] = true
+
+		if inst.URL == "" {
+			errs.add(field+".url", "arrs %s %s: url cannot be empty", key, label)
+		} else if u, err := url.Parse(inst.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs.add(field+".url", "arrs %s %s: url %q must be a valid http:// or https:// URL", key, label, inst.URL)
+		}
+
+		if inst.Enabled != nil && *inst.Enabled && inst.APIKey == "" {
+			errs.add(field+".api_key", "arrs %s %s: api_key cannot be empty when the instance is enabled", key, label)
+		}
+
+		if inst.SyncIntervalHours != nil && *inst.SyncIntervalHours < 1 {
+			errs.add(field+".sync_interval_hours", "arrs %s %s: sync_interval_hours must be at least 1", key, label)
+		}
+	}
+}
+
+// validateProviderIdentities rejects providers that share an ID or the same host/port/username,
+// since both break provider diffing and pool setup. IDs that look auto-generated but no longer
+// match their connection details (e.g. a copy-pasted block) are only logged, because the API
+// keeps a provider's ID stable when its host is edited.
+func (c *Config) validateProviderIdentities(errs *ValidationErrors) {
+	seenIDs := make(map[string]int, len(c.Providers))
+	seenHosts := make(map[string]int, len(c.Providers))
+
+	for i, provider := range c.Providers {
+		generatedID := GenerateProviderID(provider.Host, provider.Port, provider.Username)
+		if j, ok := seenHosts[generatedID]; ok {
+			errs.add(fmt.Sprintf("providers[%d].host", i), "providers %d and %d: duplicate provider %s:%d with username %q", j, i, provider.Host, provider.Port, provider.Username)
+		} else {
+			seenHosts[generatedID] = i
+		}
+
+		if provider.ID == "" {
+			continue
+		}
+
+		if j, ok := seenIDs[provider.ID]; ok {
+			errs.add(fmt.Sprintf("providers[%d].id", i), "providers %d and %d: duplicate provider id %q", j, i, provider.ID)
+			continue
+		}
+		seenIDs[provider.ID] = i
+
+		if isGeneratedProviderID(provider.ID) && provider.ID != generatedID {
+			slog.Warn("Provider id does not match its host, port and username",
+				"provider_index", i,
+				"id", provider.ID,
+				"expected_id", generatedID)
+		}
+	}
+}
+
+// isGeneratedProviderID reports whether id has the shape produced by GenerateProviderID
+func isGeneratedProviderID(id string) bool {
+	if len(id) != 8 {
+		return false
+	}
+	for _, r := range id {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// isValidNetwork reports whether network is a CIDR or a single IPv4 or IPv6 address
+func isValidNetwork(network string) bool {
+	network = strings.TrimSpace(network)
+	if strings.Contains(network, "/") {
+		_, err := netip.ParsePrefix(network)
+		return err == nil
+	}
+	_, err := netip.ParseAddr(network)
+	return err == nil
+}
+
 // ValidateDirectories validates that all configured directories are writable
 // This performs actual filesystem checks and may create directories if needed
 func (c *Config) ValidateDirectories() error {
+	return c.validateDirectories(checkDirectoryWritable)
+}
+
+// CheckDirectories validates the configured directories like ValidateDirectories, but only
+// with stat calls: nothing is created or written. Missing directories pass when they can be
+// created, their permissions are only checked once the server starts.
+func (c *Config) CheckDirectories() error {
+	return c.validateDirectories(checkDirectoryUsable)
+}
+
+// validateDirectories runs check on every configured directory
+func (c *Config) validateDirectories(check func(path string) error) error {
 	// Check metadata directory
-	if err := checkDirectoryWritable(c.Metadata.RootPath); err != nil {
+	if err := check(c.Metadata.RootPath); err != nil {
 		return fmt.Errorf("metadata directory validation failed: %w", err)
 	}
 
 	// Check database directory
-	if err := checkFileDirectoryWritable(c.Database.Path, "database"); err != nil {
+	if err := checkFileDirectory(c.Database.Path, "database", check); err != nil {
 		return err
 	}
 
 	// Check log file directory (only if log file is configured)
-	if err := checkFileDirectoryWritable(c.Log.File, "log"); err != nil {
+	if err := checkFileDirectory(c.Log.File, "log", check); err != nil {
 		return err
 	}
 
+	// Check segment disk cache directory (only if the disk cache is enabled)
+	if c.Streaming.DiskCacheDir != "" {
+		if err := check(c.Streaming.DiskCacheDir); err != nil {
+			return fmt.Errorf("segment disk cache directory validation failed: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -761,6 +1538,10 @@ func (c *Config) ProvidersEqual(other *Config) bool {
 			oldProvider.MaxConnections != newProvider.MaxConnections ||
 			oldProvider.TLS != newProvider.TLS ||
 			oldProvider.InsecureTLS != newProvider.InsecureTLS ||
+			oldProvider.MaxConnectionIdleTimeSeconds != newProvider.MaxConnectionIdleTimeSeconds ||
+			oldProvider.MaxConnectionTTLSeconds != newProvider.MaxConnectionTTLSeconds ||
+			oldProvider.SpeedLimitKBps != newProvider.SpeedLimitKBps ||
+			oldProvider.Priority != newProvider.Priority ||
 			*oldProvider.Enabled != *newProvider.Enabled ||
 			*oldProvider.IsBackupProvider != *newProvider.IsBackupProvider {
 			return false // Provider modified
@@ -777,33 +1558,50 @@ func (c *Config) ProvidersEqual(other *Config) bool {
 	return true // All providers are identical
 }
 
-// ToNNTPProviders converts ProviderConfig slice to nntppool.UsenetProviderConfig slice (enabled only)
+// ToNNTPProviders converts ProviderConfig slice to nntppool.UsenetProviderConfig slice (enabled only).
+// The pool takes connections from the first provider with a free one, so the providers are
+// returned in failover order, see orderProviders.
 func (c *Config) ToNNTPProviders() []nntppool.UsenetProviderConfig {
-	var providers []nntppool.UsenetProviderConfig
+	var enabled []ProviderConfig
 	for _, p := range c.Providers {
 		// Only include enabled providers
 		if *p.Enabled {
-			isBackup := false
-			if p.IsBackupProvider != nil {
-				isBackup = *p.IsBackupProvider
-			}
-			providers = append(providers, nntppool.UsenetProviderConfig{
-				Host:                           p.Host,
-				Port:                           p.Port,
-				Username:                       p.Username,
-				Password:                       p.Password,
-				MaxConnections:                 p.MaxConnections,
-				MaxConnectionIdleTimeInSeconds: 60, // Default idle timeout
-				TLS:                            p.TLS,
-				InsecureSSL:                    p.InsecureTLS,
-				MaxConnectionTTLInSeconds:      60, // Default connection TTL
-				IsBackupProvider:               isBackup,
-			})
+			enabled = append(enabled, p)
 		}
 	}
+
+	var providers []nntppool.UsenetProviderConfig
+	for _, p := range orderProviders(enabled) {
+		isBackup := false
+		if p.IsBackupProvider != nil {
+			isBackup = *p.IsBackupProvider
+		}
+		providers = append(providers, nntppool.UsenetProviderConfig{
+			Host:                           p.Host,
+			Port:                           p.Port,
+			Username:                       p.Username,
+			Password:                       p.Password,
+			MaxConnections:                 p.MaxConnections,
+			MaxConnectionIdleTimeInSeconds: p.GetMaxConnectionIdleTimeSeconds(),
+			TLS:                            p.TLS,
+			InsecureSSL:                    p.InsecureTLS,
+			MaxConnectionTTLInSeconds:      p.GetMaxConnectionTTLSeconds(),
+			IsBackupProvider:               isBackup,
+		})
+	}
 	return providers
 }
 
+// orderProviders sorts providers by ascending priority, keeping the configured order within
+// a priority
+func orderProviders(providers []ProviderConfig) []ProviderConfig {
+	ordered := slices.Clone(providers)
+	slices.SortStableFunc(ordered, func(a, b ProviderConfig) int {
+		return cmp.Compare(a.Priority, b.Priority)
+	})
+	return ordered
+}
+
 // ChangeCallback represents a function called when configuration changes
 type ChangeCallback func(oldConfig, newConfig *Config)
 
@@ -812,13 +1610,13 @@ type ConfigGetter func() *Config
 
 // Manager manages configuration state and persistence
 type Manager struct {
-	current              *Config
-	configFile           string
-	mutex                sync.RWMutex
-	callbacks            []ChangeCallback
-	needsLibrarySync     bool
-	previousMountPath    string
-	librarySyncMutex     sync.RWMutex
+	current           *Config
+	configFile        string
+	mutex             sync.RWMutex
+	callbacks         []ChangeCallback
+	needsLibrarySync  bool
+	previousMountPath string
+	librarySyncMutex  sync.RWMutex
 }
 
 // NewManager creates a new configuration manager
@@ -892,17 +1690,43 @@ func (m *Manager) ValidateConfigUpdate(newConfig *Config) error {
 	if currentConfig != nil {
 		// Protect WebDAV port from API changes
 		if newConfig.WebDAV.Port != currentConfig.WebDAV.Port {
-			return fmt.Errorf("webdav port cannot be changed via API - requires server restart")
+			return ValidationErrors{{Field: "webdav.port", Message: "webdav port cannot be changed via API - requires server restart"}}
+		}
+
+		// Protect WebDAV prefix from API changes, the HTTP router is built at startup
+		if newConfig.WebDAV.Prefix != currentConfig.WebDAV.Prefix {
+			return ValidationErrors{{Field: "webdav.prefix", Message: "webdav prefix cannot be changed via API - requires server restart"}}
 		}
 
 		// Protect database path from API changes
 		if newConfig.Database.Path != currentConfig.Database.Path {
-			return fmt.Errorf("database path cannot be changed via API - requires server restart")
+			return ValidationErrors{{Field: "database.path", Message: "database path cannot be changed via API - requires server restart"}}
+		}
+
+		// Protect the SQLite settings, they are applied when the database is opened
+		if newConfig.Database.GetJournalMode() != currentConfig.Database.GetJournalMode() {
+			return ValidationErrors{{Field: "database.journal_mode", Message: "database journal_mode cannot be changed via API - requires server restart"}}
+		}
+		if newConfig.Database.GetBusyTimeout() != currentConfig.Database.GetBusyTimeout() {
+			return ValidationErrors{{Field: "database.busy_timeout_ms", Message: "database busy_timeout_ms cannot be changed via API - requires server restart"}}
+		}
+		if newConfig.Database.GetSynchronous() != currentConfig.Database.GetSynchronous() {
+			return ValidationErrors{{Field: "database.synchronous", Message: "database synchronous cannot be changed via API - requires server restart"}}
 		}
 
 		// Protect metadata root path from API changes
 		if newConfig.Metadata.RootPath != currentConfig.Metadata.RootPath {
-			return fmt.Errorf("metadata root_path cannot be changed via API - requires server restart")
+			return ValidationErrors{{Field: "metadata.root_path", Message: "metadata root_path cannot be changed via API - requires server restart"}}
+		}
+
+		// Protect segment disk cache directory from API changes, the cache is opened at startup
+		if newConfig.Streaming.DiskCacheDir != currentConfig.Streaming.DiskCacheDir {
+			return ValidationErrors{{Field: "streaming.disk_cache_dir", Message: "streaming disk_cache_dir cannot be changed via API - requires server restart"}}
+		}
+
+		// Protect rclone VFS cache directory from API changes, it is passed to the RC server at startup
+		if newConfig.RClone.CacheDir != currentConfig.RClone.CacheDir {
+			return ValidationErrors{{Field: "rclone.cache_dir", Message: "rclone cache_dir cannot be changed via API - requires server restart"}}
 		}
 
 	}
@@ -915,32 +1739,98 @@ func (m *Manager) ValidateConfig(config *Config) error {
 	return config.Validate()
 }
 
-// ReloadConfig reloads configuration from file
+// ReloadConfig reloads configuration from file and notifies change callbacks.
+// Settings that require a server restart keep their current values.
 func (m *Manager) ReloadConfig() error {
+	config, err := m.readConfigFile()
+	if err != nil {
+		return err
+	}
+
+	if current := m.GetConfig(); current != nil {
+		keepRestartRequiredSettings(current, config)
+	}
+
+	return m.UpdateConfig(config)
+}
+
+// readConfigFile reads and decodes the manager's config file without applying it
+func (m *Manager) readConfigFile() (*Config, error) {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
+	configFile := m.configFile
+	if configFile == "" {
+		configFile = viper.ConfigFileUsed()
+	}
+
 	// Set the config file for viper
-	viper.SetConfigFile(m.configFile)
+	viper.SetConfigFile(configFile)
 
 	// Read the configuration file
 	if err := viper.ReadInConfig(); err != nil {
-		return fmt.Errorf("error reading config file %s: %w", m.configFile, err)
+		return nil, fmt.Errorf("error reading config file %s: %w", configFile, err)
 	}
 
-	// Create default config and unmarshal into it
-	config := DefaultConfig()
-	if err := viper.Unmarshal(config); err != nil {
-		return fmt.Errorf("error unmarshaling config: %w", err)
+	return decodeConfig(m.configFile, true)
+}
+
+// keepRestartRequiredSettings copies settings that cannot change while the server is
+// running from current into next, logging any change that was skipped
+func keepRestartRequiredSettings(current, next *Config) {
+	if next.WebDAV.Port != current.WebDAV.Port {
+		slog.Warn("Skipping config change that requires a restart",
+			"setting", "webdav.port", "current", current.WebDAV.Port, "new", next.WebDAV.Port)
+		next.WebDAV.Port = current.WebDAV.Port
 	}
 
-	// Validate configuration
-	if err := config.Validate(); err != nil {
-		return fmt.Errorf("config validation failed: %w", err)
+	if next.WebDAV.Prefix != current.WebDAV.Prefix {
+		slog.Warn("Skipping config change that requires a restart",
+			"setting", "webdav.prefix", "current", current.WebDAV.Prefix, "new", next.WebDAV.Prefix)
+		next.WebDAV.Prefix = current.WebDAV.Prefix
 	}
 
-	m.current = config
-	return nil
+	if next.Database.Path != current.Database.Path {
+		slog.Warn("Skipping config change that requires a restart",
+			"setting", "database.path", "current", current.Database.Path, "new", next.Database.Path)
+		next.Database.Path = current.Database.Path
+	}
+
+	if next.Database.GetJournalMode() != current.Database.GetJournalMode() {
+		slog.Warn("Skipping config change that requires a restart",
+			"setting", "database.journal_mode", "current", current.Database.GetJournalMode(), "new", next.Database.GetJournalMode())
+		next.Database.JournalMode = current.Database.JournalMode
+	}
+
+	if next.Database.GetBusyTimeout() != current.Database.GetBusyTimeout() {
+		slog.Warn("Skipping config change that requires a restart",
+			"setting", "database.busy_timeout_ms", "current", current.Database.GetBusyTimeout(), "new", next.Database.GetBusyTimeout())
+		next.Database.BusyTimeoutMs = current.Database.BusyTimeoutMs
+	}
+
+	if next.Database.GetSynchronous() != current.Database.GetSynchronous() {
+		slog.Warn("Skipping config change that requires a restart",
+			"setting", "database.synchronous", "current", current.Database.GetSynchronous(), "new", next.Database.GetSynchronous())
+		next.Database.Synchronous = current.Database.Synchronous
+	}
+
+	if next.Metadata.RootPath != current.Metadata.RootPath {
+		slog.Warn("Skipping config change that requires a restart",
+			"setting", "metadata.root_path", "current", current.Metadata.RootPath, "new", next.Metadata.RootPath)
+		next.Metadata.RootPath = current.Metadata.RootPath
+	}
+
+	if next.Streaming.DiskCacheDir != current.Streaming.DiskCacheDir {
+		slog.Warn("Skipping config change that requires a restart",
+			"setting", "streaming.disk_cache_dir", "current", current.Streaming.DiskCacheDir, "new", next.Streaming.DiskCacheDir)
+		next.Streaming.DiskCacheDir = current.Streaming.DiskCacheDir
+	}
+
+	if next.RClone.CacheDir != current.RClone.CacheDir {
+		slog.Warn("Skipping config change that requires a restart",
+			"setting", "rclone.cache_dir", "current", current.RClone.CacheDir, "new", next.RClone.CacheDir)
+		next.RClone.CacheDir = current.RClone.CacheDir
+	}
 }
 
 // SaveConfig saves the current configuration to file
@@ -1004,9 +1894,11 @@ func isRunningInDocker() bool {
 func DefaultConfig(configDir ...string) *Config {
 	healthEnabled := false            // Health system disabled by default
 	cleanupOrphanedFiles := false     // Cleanup orphaned files disabled by default
+	autoRepairEnabled := true         // Notify ARRs to repair corrupted files by default
+	healthDryRun := false             // Health checks act on their results by default
 	deleteSourceNzbOnRemoval := false // Delete source NZB on removal disabled by default
 	vfsEnabled := false
-	mountEnabled := false   // Disabled by default
+	mountEnabled := false // Disabled by default
 	sabnzbdEnabled := false
 	scrapperEnabled := false
 	loginRequired := true // Require login by default
@@ -1036,10 +1928,13 @@ func DefaultConfig(configDir ...string) *Config {
 	}
 
 	return &Config{
+		Version: CurrentConfigVersion,
 		WebDAV: WebDAVConfig{
-			Port:     8080,
-			User:     "usenet",
-			Password: "usenet",
+			Port:             8080,
+			User:             "usenet",
+			Password:         "usenet",
+			Prefix:           DefaultWebDAVPrefix,
+			MaxPropfindDepth: 1,
 		},
 		API: APIConfig{
 			Prefix: "/api",
@@ -1048,7 +1943,10 @@ func DefaultConfig(configDir ...string) *Config {
 			LoginRequired: &loginRequired,
 		},
 		Database: DatabaseConfig{
-			Path: dbPath,
+			Path:          dbPath,
+			JournalMode:   "WAL",
+			BusyTimeoutMs: 30000,
+			Synchronous:   "NORMAL",
 		},
 		Metadata: MetadataConfig{
 			RootPath:                 metadataPath,
@@ -1057,6 +1955,9 @@ func DefaultConfig(configDir ...string) *Config {
 		Streaming: StreamingConfig{
 			MaxDownloadWorkers: 15, // Default: 15 download workers
 			MaxCacheSizeMB:     32, // Default: 32MB cache for ahead downloads
+			ReadAheadMB:        0,  // Default: prefetch within the cache size only
+			ProbeWindowKB:      1024,
+			ProbeCacheSizeMB:   64,
 		},
 		RClone: RCloneConfig{
 			Path:         rclonePath,
@@ -1110,27 +2011,36 @@ func DefaultConfig(configDir ...string) *Config {
 				".h265", ".hevc", ".ogv", ".ogm", ".strm", ".iso", ".img", ".divx",
 				".xvid", ".rm", ".rmvb", ".asf", ".asx", ".wtv", ".mk3d", ".dvr-ms",
 			},
-			MaxImportConnections:    5,                  // Default: 5 concurrent NNTP connections for validation and archive processing
-			ImportCacheSizeMB:       64,                 // Default: 64MB cache for archive analysis
-			SegmentSamplePercentage: 1,                  // Default: 1% segment sampling
-			ImportStrategy:          ImportStrategyNone, // Default: no import strategy (direct import)
-			ImportDir:               nil,                // No default import directory
+			MaxImportConnections:    5,                        // Default: 5 concurrent NNTP connections for validation and archive processing
+			ImportCacheSizeMB:       64,                       // Default: 64MB cache for archive analysis
+			SegmentSamplePercentage: 1,                        // Default: 1% segment sampling
+			SampleStrategy:          SampleStrategyPercentage, // Default: first and last segments plus random middle ones
+			ImportStrategy:          ImportStrategyNone,       // Default: no import strategy (direct import)
+			ImportDir:               nil,                      // No default import directory
 		},
 		Log: LogConfig{
 			File:       logPath, // Default log file path
 			Level:      "info",  // Default log level
+			Format:     "text",  // Plain text output
 			MaxSize:    100,     // 100MB max size
 			MaxAge:     30,      // Keep for 30 days
 			MaxBackups: 10,      // Keep 10 old files
 			Compress:   true,    // Compress old files
 		},
 		Health: HealthConfig{
-			Enabled:                       &healthEnabled,         // Disabled by default
+			Enabled:                       &healthEnabled,        // Disabled by default
 			CleanupOrphanedFiles:          &cleanupOrphanedFiles, // Disabled by default
+			AutoRepairEnabled:             &autoRepairEnabled,    // Enabled by default
+			DryRun:                        &healthDryRun,         // Disabled by default
 			CheckIntervalSeconds:          5,
 			MaxConnectionsForHealthChecks: 5,
-			SegmentSamplePercentage:       5,   // Default: 5% segment sampling
-			LibrarySyncIntervalMinutes:    360, // Default: sync every 6 hours
+			SegmentSamplePercentage:       5,    // Default: 5% segment sampling
+			LibrarySyncIntervalMinutes:    360,  // Default: sync every 6 hours
+			MaxConcurrentJobs:             1,    // Default: check one file at a time
+			CheckTimeoutSeconds:           600,  // Default: 10 minutes per file check
+			RetryBackoffBaseSeconds:       300,  // Default: first retry after 5 minutes
+			RetryBackoffMaxSeconds:        3600, // Default: retries at most 1 hour apart
+			RepairCooldownSeconds:         300,  // Default: rescan a library directory at most every 5 minutes
 		},
 		SABnzbd: SABnzbdConfig{
 			Enabled:        &sabnzbdEnabled,
@@ -1141,10 +2051,13 @@ func DefaultConfig(configDir ...string) *Config {
 		},
 		Providers: []ProviderConfig{},
 		Arrs: ArrsConfig{
-			Enabled:         &scrapperEnabled, // Disabled by default
-			MaxWorkers:      5,                // Default to 5 concurrent workers
-			RadarrInstances: []ArrsInstanceConfig{},
-			SonarrInstances: []ArrsInstanceConfig{},
+			Enabled:                  &scrapperEnabled, // Disabled by default
+			MaxWorkers:               5,                // Default to 5 concurrent workers
+			DefaultSyncIntervalHours: 24,               // Sync each instance once a day unless overridden
+			RadarrInstances:          []ArrsInstanceConfig{},
+			SonarrInstances:          []ArrsInstanceConfig{},
+			LidarrInstances:          []ArrsInstanceConfig{},
+			ReadarrInstances:         []ArrsInstanceConfig{},
 		},
 		MountPath: "", // Empty by default - required when ARRs is enabled
 	}
@@ -1162,24 +2075,74 @@ func SaveToFile(config *Config, filename string) error {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
-	// Marshal config to YAML
-	data, err := yaml.Marshal(config)
+	// Keep environment references and relative paths instead of writing the resolved
+	// values to disk
+	if len(config.secretRefs) > 0 || len(config.pathRefs) > 0 {
+		config = config.DeepCopy()
+		config.restoreSecretRefs()
+		config.restorePathRefs()
+	}
+
+	// Marshal config to YAML, keeping comments and key order of an existing file
+	data, err := marshalPreservingComments(config, filename)
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
 
 	// Write to file
-	if err := os.WriteFile(filename, data, 0644); err != nil {
+	if err := writeFileAtomic(filename, data, 0644); err != nil {
 		return fmt.Errorf("failed to write config file: %w", err)
 	}
 
 	return nil
 }
 
+// writeFileAtomic writes data to a temporary file in the target directory and renames it
+// into place, so a crash mid-write never leaves a truncated file behind. The permissions
+// of an existing file are preserved; perm is used for new files.
+func writeFileAtomic(filename string, data []byte, perm os.FileMode) error {
+	if info, err := os.Stat(filename); err == nil {
+		perm = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpName := tmp.Name()
+
+	// Remove the temp file on any failure before the rename succeeds
+	success := false
+	defer func() {
+		if !success {
+			_ = os.Remove(tmpName)
+		}
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to sync temp file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temp file: %w", err)
+	}
+	if err := os.Chmod(tmpName, perm); err != nil {
+		return fmt.Errorf("failed to set temp file permissions: %w", err)
+	}
+	if err := os.Rename(tmpName, filename); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", filename, err)
+	}
+
+	success = true
+	return nil
+}
+
 // LoadConfig loads configuration from file and merges with defaults
 func LoadConfig(configFile string) (*Config, error) {
-	config := DefaultConfig()
-
 	var targetConfigFile string
 	if configFile != "" {
 		viper.SetConfigFile(configFile)
@@ -1220,20 +2183,82 @@ func LoadConfig(configFile string) (*Config, error) {
 		}
 	}
 
+	return decodeConfig(configFile, true)
+}
+
+// ReadConfig loads and validates configFile like LoadConfig without ever writing to disk:
+// a missing file is an error instead of creating a default one, and config migrations are
+// only applied in memory.
+func ReadConfig(configFile string) (*Config, error) {
+	if _, err := os.Stat(configFile); err != nil {
+		return nil, fmt.Errorf("error reading config file %s: %w", configFile, err)
+	}
+
+	viper.SetConfigFile(configFile)
+	if err := viper.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("error reading config file %s: %w", configFile, err)
+	}
+
+	return decodeConfig(configFile, false)
+}
+
+// decodeConfig migrates, unmarshals and validates the configuration read by viper.
+// configFile is used to derive default paths and may be empty. saveMigrated persists
+// a migrated config so the upgrade only happens once.
+func decodeConfig(configFile string, saveMigrated bool) (*Config, error) {
+	config := DefaultConfig()
+
+	// Merge included fragments into a copy of the file settings, so neither they nor
+	// migrations ever leave overrides on the global viper
+	settings := viper.AllSettings()
+	includeBase := configFile
+	if includeBase == "" {
+		includeBase = viper.ConfigFileUsed()
+	}
+	included, err := applyIncludes(settings, includeBase)
+	if err != nil {
+		return nil, fmt.Errorf("error reading included config: %w", err)
+	}
+
+	v := viper.New()
+	if err := v.MergeConfigMap(settings); err != nil {
+		return nil, fmt.Errorf("error reading config settings: %w", err)
+	}
+
+	// Upgrade older config schemas before unmarshalling
+	migrated, err := migrateConfig(v)
+	if err != nil {
+		return nil, fmt.Errorf("error migrating config: %w", err)
+	}
+
 	// Unmarshal the config
-	if err := viper.Unmarshal(config); err != nil {
+	if err := v.Unmarshal(config); err != nil {
 		return nil, fmt.Errorf("error unmarshaling config: %w", err)
 	}
+	config.included = included
+
+	// Resolve secrets supplied through environment variables
+	if err := config.expandEnvSecrets(); err != nil {
+		return nil, fmt.Errorf("error resolving environment variables: %w", err)
+	}
+
+	// Resolve relative paths against the directory of the config file, so they pass the
+	// absolute path checks
+	if includeBase != "" {
+		if err := config.resolveRelativePaths(filepath.Dir(includeBase)); err != nil {
+			return nil, fmt.Errorf("error resolving relative paths: %w", err)
+		}
+	}
 
 	// If log file was not explicitly set in the config file and we have a specific config file path,
 	// derive log file path from config file location
-	if configFile != "" && !viper.IsSet("log.file") {
+	if configFile != "" && !v.IsSet("log.file") {
 		configDir := filepath.Dir(configFile)
 		config.Log.File = filepath.Join(configDir, "altmount.log")
 	}
 
 	// If cache_dir was not explicitly set or is empty, derive it from config file location
-	if configFile != "" && (!viper.IsSet("rclone.cache_dir") || config.RClone.CacheDir == "") {
+	if configFile != "" && (!v.IsSet("rclone.cache_dir") || config.RClone.CacheDir == "") {
 		configDir := filepath.Dir(configFile)
 		config.RClone.CacheDir = filepath.Join(configDir, "cache")
 	}
@@ -1257,6 +2282,13 @@ func LoadConfig(configFile string) (*Config, error) {
 		return nil, fmt.Errorf("config validation failed: %w", err)
 	}
 
+	// Persist migrated config so the upgrade only happens once
+	if migrated && saveMigrated {
+		if err := SaveToFile(config, viper.ConfigFileUsed()); err != nil {
+			return nil, fmt.Errorf("failed to save migrated config: %w", err)
+		}
+	}
+
 	return config, nil
 }
 
@@ -0,0 +1,344 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// providerIDs returns the ids of providers in order
+func providerIDs(providers []ProviderConfig) []string {
+	ids := make([]string, len(providers))
+	for i, p := range providers {
+		ids[i] = p.ID
+	}
+	return ids
+}
+
+func TestOrderProvidersByPriority(t *testing.T) {
+	tests := []struct {
+		name      string
+		providers []ProviderConfig
+		want      []string
+	}{
+		{
+			name:      "no priorities keeps configured order",
+			providers: []ProviderConfig{{ID: "a"}, {ID: "b"}, {ID: "c"}},
+			want:      []string{"a", "b", "c"},
+		},
+		{
+			name:      "lower priority first",
+			providers: []ProviderConfig{{ID: "a", Priority: 2}, {ID: "b", Priority: 0}, {ID: "c", Priority: 1}},
+			want:      []string{"b", "c", "a"},
+		},
+		{
+			name:      "configured order within a priority",
+			providers: []ProviderConfig{{ID: "a", Priority: 1}, {ID: "b", Priority: 0}, {ID: "c", Priority: 1}, {ID: "d", Priority: 0}},
+			want:      []string{"b", "d", "a", "c"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := providerIDs(tt.providers)
+
+			if got := providerIDs(orderProviders(tt.providers)); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+			if !slices.Equal(providerIDs(tt.providers), original) {
+				t.Errorf("orderProviders modified its input: %v", providerIDs(tt.providers))
+			}
+		})
+	}
+}
+
+func TestToNNTPProvidersSkipsDisabled(t *testing.T) {
+	enabled, disabled := true, false
+	cfg := &Config{Providers: []ProviderConfig{
+		{Host: "b.example.com", Priority: 1, Enabled: &enabled},
+		{Host: "off.example.com", Priority: 0, Enabled: &disabled},
+		{Host: "a.example.com", Priority: 0, Enabled: &enabled},
+	}}
+
+	var hosts []string
+	for _, p := range cfg.ToNNTPProviders() {
+		hosts = append(hosts, p.Host)
+	}
+	if want := []string{"a.example.com", "b.example.com"}; !slices.Equal(hosts, want) {
+		t.Errorf("got %v, want %v", hosts, want)
+	}
+}
+
+func TestCheckDirectoriesChangesNothing(t *testing.T) {
+	base := t.TempDir()
+	file := filepath.Join(base, "file")
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		metadata string
+		wantErr  bool
+	}{
+		{name: "existing directory", metadata: base},
+		{name: "missing directory", metadata: filepath.Join(base, "missing", "metadata")},
+		{name: "file", metadata: file, wantErr: true},
+		{name: "below a file", metadata: filepath.Join(file, "metadata"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig(base)
+			cfg.Metadata.RootPath = tt.metadata
+			cfg.Database.Path = filepath.Join(base, "db", "altmount.db")
+			cfg.Log.File = ""
+			cfg.Streaming.DiskCacheDir = ""
+
+			err := cfg.CheckDirectories()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckDirectories: got %v, want error %v", err, tt.wantErr)
+			}
+
+			entries, err := os.ReadDir(base)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != 1 {
+				t.Errorf("CheckDirectories changed %s: %v", base, entries)
+			}
+		})
+	}
+}
+
+func TestValidateConfigUpdateRejectsDatabaseSettings(t *testing.T) {
+	current := DefaultConfig(t.TempDir())
+	m := NewManager(current, "")
+
+	tests := []struct {
+		field   string
+		update  func(cfg *Config)
+		wantErr bool
+	}{
+		{field: "database.journal_mode", update: func(cfg *Config) { cfg.Database.JournalMode = "DELETE" }, wantErr: true},
+		{field: "database.busy_timeout_ms", update: func(cfg *Config) { cfg.Database.BusyTimeoutMs = 5000 }, wantErr: true},
+		{field: "database.synchronous", update: func(cfg *Config) { cfg.Database.Synchronous = "FULL" }, wantErr: true},
+		// The defaults spelled out are no change
+		{field: "database.journal_mode", update: func(cfg *Config) { cfg.Database.JournalMode = "wal" }},
+		{field: "database.busy_timeout_ms", update: func(cfg *Config) { cfg.Database.BusyTimeoutMs = 30000 }},
+	}
+
+	for _, tt := range tests {
+		updated := current.DeepCopy()
+		tt.update(updated)
+
+		err := m.ValidateConfigUpdate(updated)
+		if !tt.wantErr {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tt.field, err)
+			}
+			continue
+		}
+
+		var verrs ValidationErrors
+		if !errors.As(err, &verrs) || len(verrs) != 1 || verrs[0].Field != tt.field {
+			t.Errorf("%s: got %v, want a validation error for the field", tt.field, err)
+		}
+	}
+}
+
+func TestKeepRestartRequiredSettingsKeepsDatabaseSettings(t *testing.T) {
+	current := DefaultConfig(t.TempDir())
+
+	next := current.DeepCopy()
+	next.Database.JournalMode = "DELETE"
+	next.Database.BusyTimeoutMs = 5000
+	next.Database.Synchronous = "FULL"
+	keepRestartRequiredSettings(current, next)
+
+	if next.Database.JournalMode != current.Database.JournalMode ||
+		next.Database.BusyTimeoutMs != current.Database.BusyTimeoutMs ||
+		next.Database.Synchronous != current.Database.Synchronous {
+		t.Errorf("reloaded database settings were applied: %+v", next.Database)
+	}
+
+	// The defaults spelled out are no change and are kept as written
+	next = current.DeepCopy()
+	next.Database.JournalMode = "wal"
+	keepRestartRequiredSettings(current, next)
+	if next.Database.JournalMode != "wal" {
+		t.Errorf("journal_mode: got %q, want the unchanged wal", next.Database.JournalMode)
+	}
+}
+
+func TestEffectiveSyncIntervalHours(t *testing.T) {
+	hours := func(h int) *int { return &h }
+	arrs := ArrsConfig{DefaultSyncIntervalHours: 24}
+
+	if got := arrs.EffectiveSyncIntervalHours(ArrsInstanceConfig{}); got != 24 {
+		t.Errorf("instance without an interval: got %d, want the default 24", got)
+	}
+	if got := arrs.EffectiveSyncIntervalHours(ArrsInstanceConfig{SyncIntervalHours: hours(6)}); got != 6 {
+		t.Errorf("instance with an interval: got %d, want its own 6", got)
+	}
+}
+
+func TestValidateSyncIntervals(t *testing.T) {
+	hours := func(h int) *int { return &h }
+	enabled := true
+
+	tests := []struct {
+		name      string
+		def       int
+		instance  *int
+		wantField string
+	}{
+		{name: "defaults", def: 24},
+		{name: "instance override", def: 24, instance: hours(1)},
+		{name: "default below 1", def: 0, wantField: "arrs.default_sync_interval_hours"},
+		{name: "override below 1", def: 24, instance: hours(0), wantField: "arrs.radarr_instances[0].sync_interval_hours"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig(t.TempDir())
+			cfg.Arrs.Enabled = &enabled
+			cfg.Arrs.DefaultSyncIntervalHours = tt.def
+			cfg.Arrs.RadarrInstances = []ArrsInstanceConfig{{
+				Name: "radarr", URL: "http://localhost:7878", APIKey: "key", SyncIntervalHours: tt.instance,
+			}}
+
+			var verrs ValidationErrors
+			errors.As(cfg.Validate(), &verrs)
+
+			found := slices.ContainsFunc(verrs, func(e ValidationError) bool { return e.Field == tt.wantField })
+			if tt.wantField != "" && !found {
+				t.Errorf("expected an error for %s, got %v", tt.wantField, verrs)
+			}
+			if tt.wantField == "" && slices.ContainsFunc(verrs, func(e ValidationError) bool {
+				return strings.Contains(e.Field, "sync_interval_hours")
+			}) {
+				t.Errorf("unexpected sync interval error: %v", verrs)
+			}
+		})
+	}
+}
+
+func TestEffectiveAllowedExtensions(t *testing.T) {
+	allow, deny := true, false
+	minSize := 100
+
+	tests := []struct {
+		name    string
+		allowed []string
+		rules   map[string]ExtensionRule
+		want    []string
+	}{
+		{
+			name:    "no rules normalizes the list",
+			allowed: []string{"MKV", ".mp4", "mkv"},
+			want:    []string{".mkv", ".mp4"},
+		},
+		{
+			name:    "allowed rule adds its extension",
+			allowed: []string{".mkv"},
+			rules:   map[string]ExtensionRule{"iso": {Allowed: &allow}},
+			want:    []string{".mkv", ".iso"},
+		},
+		{
+			name:    "disallowed rule removes its extension",
+			allowed: []string{".mkv", ".iso"},
+			rules:   map[string]ExtensionRule{".ISO": {Allowed: &deny}},
+			want:    []string{".mkv"},
+		},
+		{
+			name:    "rules without allowed inherit",
+			allowed: []string{".mkv", ".iso"},
+			rules: map[string]ExtensionRule{
+				"mkv": {Strategy: ImportStrategySTRM},
+				"iso": {MinFileSizeMB: &minSize},
+				"avi": {Strategy: ImportStrategySYMLINK},
+			},
+			want: []string{".mkv", ".iso"},
+		},
+		{
+			name:  "empty list allows everything",
+			rules: map[string]ExtensionRule{"iso": {Allowed: &allow}, "mkv": {Strategy: ImportStrategySTRM}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ic := ImportConfig{AllowedFileExtensions: tt.allowed, ExtensionRules: tt.rules}
+			if got := ic.EffectiveAllowedExtensions(); !slices.Equal(got, tt.want) {
+				t.Errorf("EffectiveAllowedExtensions: got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStrategyForFile(t *testing.T) {
+	ic := ImportConfig{ExtensionRules: map[string]ExtensionRule{
+		"mkv":  {Strategy: ImportStrategySTRM},
+		".ISO": {Strategy: ImportStrategySYMLINK},
+		"srt":  {},
+	}}
+
+	tests := []struct {
+		filename string
+		want     ImportStrategy
+	}{
+		{filename: "/movies/Movie.mkv", want: ImportStrategySTRM},
+		{filename: "/movies/Disc.iso", want: ImportStrategySYMLINK},
+		{filename: "/movies/Movie.srt", want: ImportStrategyNone},
+		{filename: "/movies/Movie.mp4", want: ImportStrategyNone},
+		{filename: "/movies/README", want: ImportStrategyNone},
+	}
+
+	for _, tt := range tests {
+		if got := ic.StrategyForFile(tt.filename, ImportStrategyNone); got != tt.want {
+			t.Errorf("StrategyForFile(%s): got %s, want %s", tt.filename, got, tt.want)
+		}
+	}
+}
+
+func TestValidateExtensionRules(t *testing.T) {
+	allow, deny := true, false
+	minSize := 100
+
+	tests := []struct {
+		name      string
+		allowed   []string
+		rule      ExtensionRule
+		wantField string
+	}{
+		{name: "min size only without allowed list", rule: ExtensionRule{MinFileSizeMB: &minSize}},
+		{name: "allowed without allowed list", rule: ExtensionRule{Allowed: &allow}},
+		{name: "disallowed with allowed list", allowed: []string{".iso"}, rule: ExtensionRule{Allowed: &deny}},
+		{name: "disallowed without allowed list", rule: ExtensionRule{Allowed: &deny}, wantField: "import.extension_rules.iso.allowed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig(t.TempDir())
+			cfg.Import.AllowedFileExtensions = tt.allowed
+			cfg.Import.ExtensionRules = map[string]ExtensionRule{"iso": tt.rule}
+
+			var verrs ValidationErrors
+			errors.As(cfg.Validate(), &verrs)
+
+			ruleErrors := slices.DeleteFunc(slices.Clone(verrs), func(e ValidationError) bool {
+				return !strings.HasPrefix(e.Field, "import.extension_rules")
+			})
+			if tt.wantField == "" && len(ruleErrors) > 0 {
+				t.Errorf("unexpected errors: %v", ruleErrors)
+			}
+			if tt.wantField != "" && (len(ruleErrors) != 1 || ruleErrors[0].Field != tt.wantField) {
+				t.Errorf("got %v, want one error for %s", ruleErrors, tt.wantField)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// jsonNull is the JSON encoding of null
+var jsonNull = []byte("null")
+
+// MergePatch returns a copy of the configuration with the JSON merge patch applied, in the
+// manner of RFC 7396. Settings missing from the patch keep their current value, objects
+// are merged recursively and maps are merged key by key. null resets a setting to its zero
+// value, or removes the entry when used as a map value. Lists and other values are
+// replaced as a whole, except lists whose items hold secrets hidden from the API, such as
+// providers: replacing them would drop the secrets, so they are rejected and must be
+// changed through their own endpoints. Unknown keys are ignored. The configuration itself
+// is not modified.
+func (c *Config) MergePatch(patch []byte) (*Config, error) {
+	merged := c.DeepCopy()
+	if err := mergeJSON(reflect.ValueOf(merged).Elem(), patch, ""); err != nil {
+		return nil, err
+	}
+
+	return merged, nil
+}
+
+// mergeJSON merges the JSON patch into dst, which must be settable. path is the location
+// of dst, used in error messages.
+func mergeJSON(dst reflect.Value, patch json.RawMessage, path string) error {
+	patch = bytes.TrimSpace(patch)
+	if bytes.Equal(patch, jsonNull) {
+		dst.SetZero()
+		return nil
+	}
+
+	// The secrets of replaced items cannot be sent back by clients and would be lost
+	if dst.Kind() == reflect.Slice && hasHiddenFields(dst.Type().Elem()) {
+		return fmt.Errorf("%s: cannot be replaced by a patch because its items hold secrets, update the items individually", fieldPath(path, ""))
+	}
+
+	// Only JSON objects are merged, anything else replaces the current value
+	if len(patch) == 0 || patch[0] != '{' || implementsUnmarshaler(dst) {
+		return decodeJSON(dst, patch, path)
+	}
+
+	switch dst.Kind() {
+	case reflect.Pointer:
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		return mergeJSON(dst.Elem(), patch, path)
+
+	case reflect.Struct:
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(patch, &fields); err != nil {
+			return fmt.Errorf("%s: %w", fieldPath(path, ""), err)
+		}
+		for key, value := range fields {
+			field, ok := jsonField(dst, key)
+			if !ok {
+				continue
+			}
+			if err := mergeJSON(field, value, fieldPath(path, key)); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case reflect.Map:
+		if dst.Type().Key().Kind() != reflect.String {
+			return decodeJSON(dst, patch, path)
+		}
+
+		var entries map[string]json.RawMessage
+		if err := json.Unmarshal(patch, &entries); err != nil {
+			return fmt.Errorf("%s: %w", fieldPath(path, ""), err)
+		}
+		if dst.IsNil() {
+			dst.Set(reflect.MakeMapWithSize(dst.Type(), len(entries)))
+		}
+		for key, value := range entries {
+			mapKey := reflect.ValueOf(key).Convert(dst.Type().Key())
+			if bytes.Equal(bytes.TrimSpace(value), jsonNull) {
+				dst.SetMapIndex(mapKey, reflect.Value{})
+				continue
+			}
+
+			// Map entries are not addressable, merge into a copy and store it back
+			entry := reflect.New(dst.Type().Elem()).Elem()
+			if current := dst.MapIndex(mapKey); current.IsValid() {
+				entry.Set(current)
+			}
+			if err := mergeJSON(entry, value, fieldPath(path, key)); err != nil {
+				return err
+			}
+			dst.SetMapIndex(mapKey, entry)
+		}
+		return nil
+
+	default:
+		return decodeJSON(dst, patch, path)
+	}
+}
+
+// decodeJSON replaces dst with the decoded JSON value
+func decodeJSON(dst reflect.Value, value json.RawMessage, path string) error {
+	decoded := reflect.New(dst.Type())
+	if err := json.Unmarshal(value, decoded.Interface()); err != nil {
+		return fmt.Errorf("%s: %w", fieldPath(path, ""), err)
+	}
+	dst.Set(decoded.Elem())
+
+	return nil
+}
+
+// implementsUnmarshaler reports whether values of the type of v decode themselves
+func implementsUnmarshaler(v reflect.Value) bool {
+	return reflect.PointerTo(v.Type()).Implements(reflect.TypeFor[json.Unmarshaler]())
+}
+
+// hasHiddenFields reports whether values of type t have fields hidden from JSON
+func hasHiddenFields(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if name, _, _ := strings.Cut(field.Tag.Get("json"), ","); name == "-" || hasHiddenFields(field.Type) {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonField returns the field of the struct v that is encoded as key. Like encoding/json,
+// an exact match of the name is preferred over a case-insensitive one.
+func jsonField(v reflect.Value, key string) (reflect.Value, bool) {
+	var fold reflect.Value
+	for i := range v.NumField() {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			// Fields hidden from JSON, such as secrets, cannot be patched
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		if name == key {
+			return v.Field(i), true
+		}
+		if !fold.IsValid() && strings.EqualFold(name, key) {
+			fold = v.Field(i)
+		}
+	}
+
+	return fold, fold.IsValid()
+}
+
+// fieldPath appends key to the dotted path of a setting
+func fieldPath(path, key string) string {
+	switch {
+	case path == "":
+		if key == "" {
+			return "config"
+		}
+		return key
+	case key == "":
+		return path
+	default:
+		return path + "." + key
+	}
+}
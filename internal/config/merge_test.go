@@ -0,0 +1,76 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func newMergeTestConfig() *Config {
+	cfg := DefaultConfig()
+	cfg.Providers = []ProviderConfig{{
+		ID:             "primary",
+		Host:           "news.example.com",
+		Port:           563,
+		Username:       "user",
+		Password:       "secret",
+		MaxConnections: 10,
+	}}
+	cfg.RClone.Password = "rclone-secret"
+	return cfg
+}
+
+func TestMergePatchKeepsUnsentSettings(t *testing.T) {
+	cfg := newMergeTestConfig()
+
+	merged, err := cfg.MergePatch([]byte(`{"webdav": {"port": 9090}, "rclone": {"path": "/rclone"}}`))
+	if err != nil {
+		t.Fatalf("MergePatch: %v", err)
+	}
+
+	if merged.WebDAV.Port != 9090 || merged.RClone.Path != "/rclone" {
+		t.Errorf("patch not applied: webdav.port=%d rclone.path=%q", merged.WebDAV.Port, merged.RClone.Path)
+	}
+	if merged.WebDAV.User != cfg.WebDAV.User {
+		t.Errorf("webdav.user: got %q, want %q", merged.WebDAV.User, cfg.WebDAV.User)
+	}
+	if merged.RClone.Password != "rclone-secret" {
+		t.Errorf("rclone.password: got %q, want it kept", merged.RClone.Password)
+	}
+	if len(merged.Providers) != 1 || merged.Providers[0].Password != "secret" {
+		t.Errorf("providers changed by an unrelated patch: %+v", merged.Providers)
+	}
+	if cfg.WebDAV.Port == 9090 {
+		t.Error("MergePatch modified the original config")
+	}
+}
+
+func TestMergePatchRejectsListsWithSecrets(t *testing.T) {
+	cfg := newMergeTestConfig()
+
+	for _, patch := range []string{
+		`{"providers": [{"id": "primary", "host": "news.example.com", "port": 563, "username": "user", "max_connections": 20}]}`,
+		`{"providers": []}`,
+	} {
+		_, err := cfg.MergePatch([]byte(patch))
+		if err == nil || !strings.Contains(err.Error(), "providers") {
+			t.Errorf("MergePatch(%s): got error %v, want providers to be rejected", patch, err)
+		}
+	}
+
+	if cfg.Providers[0].Password != "secret" {
+		t.Errorf("original provider password changed: %q", cfg.Providers[0].Password)
+	}
+}
+
+func TestMergePatchReplacesPlainLists(t *testing.T) {
+	cfg := newMergeTestConfig()
+	cfg.Auth.AllowedNetworks = []string{"10.0.0.0/8"}
+
+	merged, err := cfg.MergePatch([]byte(`{"auth": {"allowed_networks": ["192.168.1.0/24"]}}`))
+	if err != nil {
+		t.Fatalf("MergePatch: %v", err)
+	}
+	if got := merged.Auth.AllowedNetworks; len(got) != 1 || got[0] != "192.168.1.0/24" {
+		t.Errorf("auth.allowed_networks: got %v, want [192.168.1.0/24]", got)
+	}
+}
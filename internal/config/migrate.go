@@ -0,0 +1,100 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/spf13/cast"
+	"github.com/spf13/viper"
+)
+
+// CurrentConfigVersion is the schema version written to new and migrated config files
+const CurrentConfigVersion = 2
+
+// configMigration upgrades raw config values from one schema version to the next
+type configMigration struct {
+	from        int
+	description string
+	migrate     func(v *viper.Viper)
+}
+
+// configMigrations lists the schema migrations in order. Files without a version field are
+// treated as version 0.
+var configMigrations = []configMigration{
+	{
+		from:        0,
+		description: "move top-level log_level into log.level",
+		migrate: func(v *viper.Viper) {
+			if v.IsSet("log_level") && !v.IsSet("log.level") {
+				v.Set("log.level", v.GetString("log_level"))
+			}
+		},
+	},
+	{
+		from:        1,
+		description: "move provider retry settings into the pool section",
+		migrate:     migrateProviderRetrySettings,
+	},
+}
+
+// migrateProviderRetrySettings moves the per-provider retry_attempts and retry_backoff_seconds
+// into the pool section. The pool applied the highest values among the enabled providers to
+// all of them, so those are the values kept.
+func migrateProviderRetrySettings(v *viper.Viper) {
+	providers, ok := v.Get("providers").([]any)
+	if !ok {
+		return
+	}
+
+	var attempts, backoff int
+	for _, item := range providers {
+		provider, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		if enabled, ok := provider["enabled"].(bool); !ok || enabled {
+			attempts = max(attempts, cast.ToInt(provider["retry_attempts"]))
+			backoff = max(backoff, cast.ToInt(provider["retry_backoff_seconds"]))
+		}
+
+		delete(provider, "retry_attempts")
+		delete(provider, "retry_backoff_seconds")
+	}
+	v.Set("providers", providers)
+
+	if attempts > 0 && !v.IsSet("pool.retry_attempts") {
+		v.Set("pool.retry_attempts", attempts)
+	}
+	if backoff > 0 && !v.IsSet("pool.retry_backoff_seconds") {
+		v.Set("pool.retry_backoff_seconds", backoff)
+	}
+}
+
+// migrateConfig applies all pending migrations to the values read by v before they are
+// unmarshalled. It reports whether any migration ran so the caller can persist the result.
+func migrateConfig(v *viper.Viper) (bool, error) {
+	version := v.GetInt("version")
+	if version > CurrentConfigVersion {
+		return false, fmt.Errorf("config version %d is newer than the supported version %d", version, CurrentConfigVersion)
+	}
+
+	migrated := false
+	for _, m := range configMigrations {
+		if m.from < version {
+			continue
+		}
+
+		slog.Info("Migrating configuration",
+			"from_version", m.from,
+			"to_version", m.from+1,
+			"migration", m.description)
+		m.migrate(v)
+		version = m.from + 1
+		migrated = true
+	}
+
+	v.Set("version", version)
+
+	return migrated, nil
+}
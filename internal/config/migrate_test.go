@@ -0,0 +1,98 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestMigrateProviderRetrySettings(t *testing.T) {
+	tests := []struct {
+		name        string
+		yaml        string
+		wantAttempt int
+		wantBackoff int
+	}{
+		{
+			name: "highest values of enabled providers",
+			yaml: `
+version: 1
+providers:
+  - host: a.example.com
+    retry_attempts: 3
+    retry_backoff_seconds: 10
+  - host: b.example.com
+    enabled: true
+    retry_attempts: 5
+    retry_backoff_seconds: 2
+  - host: off.example.com
+    enabled: false
+    retry_attempts: 9
+    retry_backoff_seconds: 60
+`,
+			wantAttempt: 5,
+			wantBackoff: 10,
+		},
+		{
+			name: "pool settings already set",
+			yaml: `
+version: 1
+pool:
+  retry_attempts: 1
+providers:
+  - host: a.example.com
+    retry_attempts: 3
+    retry_backoff_seconds: 10
+`,
+			wantAttempt: 1,
+			wantBackoff: 10,
+		},
+		{
+			name: "no retry settings",
+			yaml: `
+version: 1
+providers:
+  - host: a.example.com
+`,
+		},
+		{name: "no providers", yaml: "version: 1\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := viper.New()
+			v.SetConfigType("yaml")
+			if err := v.ReadConfig(strings.NewReader(tt.yaml)); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := migrateConfig(v); err != nil {
+				t.Fatalf("migrateConfig: %v", err)
+			}
+
+			var cfg Config
+			if err := v.Unmarshal(&cfg); err != nil {
+				t.Fatal(err)
+			}
+			if cfg.Version != CurrentConfigVersion {
+				t.Errorf("version: got %d, want %d", cfg.Version, CurrentConfigVersion)
+			}
+			if cfg.Pool.RetryAttempts != tt.wantAttempt || cfg.Pool.RetryBackoffSeconds != tt.wantBackoff {
+				t.Errorf("pool: got %d attempts and %ds backoff, want %d and %ds",
+					cfg.Pool.RetryAttempts, cfg.Pool.RetryBackoffSeconds, tt.wantAttempt, tt.wantBackoff)
+			}
+
+			providers, _ := v.Get("providers").([]any)
+			for i, item := range providers {
+				provider := item.(map[string]any)
+				if _, ok := provider["retry_attempts"]; ok {
+					t.Errorf("provider %d still sets retry_attempts", i)
+				}
+				if _, ok := provider["retry_backoff_seconds"]; ok {
+					t.Errorf("provider %d still sets retry_backoff_seconds", i)
+				}
+			}
+		})
+	}
+}
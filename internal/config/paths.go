@@ -0,0 +1,92 @@
+package config
+
+import (
+	"path/filepath"
+	"strconv"
+)
+
+// pathRef remembers the relative value a path had in the config file before it was
+// resolved against the config directory, so that saving the config keeps it portable.
+type pathRef struct {
+	raw      string
+	resolved string
+}
+
+// pathField is a config path that must be absolute and may be written relative to the
+// config file instead
+type pathField struct {
+	key   string  // Stable key used to track the original file value
+	value *string // Pointer to the value inside the config
+}
+
+// pathFields returns the paths that are resolved against the config directory. Category
+// import directories are keyed by category name so reordering categories does not mix
+// up their original file values.
+func (c *Config) pathFields() []pathField {
+	fields := []pathField{
+		{key: "mount_path", value: &c.MountPath},
+		{key: "sabnzbd.complete_dir", value: &c.SABnzbd.CompleteDir},
+	}
+	if c.Import.ImportDir != nil {
+		fields = append(fields, pathField{key: "import.import_dir", value: c.Import.ImportDir})
+	}
+	if c.Health.LibraryDir != nil {
+		fields = append(fields, pathField{key: "health.library_dir", value: c.Health.LibraryDir})
+	}
+
+	for i := range c.SABnzbd.Categories {
+		category := &c.SABnzbd.Categories[i]
+		if category.ImportDir == nil {
+			continue
+		}
+		key := "sabnzbd.categories." + strconv.Itoa(i) + ".import_dir"
+		if category.Name != "" {
+			key = "sabnzbd.categories.name:" + category.Name + ".import_dir"
+		}
+		fields = append(fields, pathField{key: key, value: category.ImportDir})
+	}
+
+	return fields
+}
+
+// resolveRelativePaths makes the relative paths of pathFields absolute by resolving them
+// against configDir, so a config template can use paths relative to its own location.
+// Absolute and empty paths are left untouched.
+func (c *Config) resolveRelativePaths(configDir string) error {
+	refs := make(map[string]pathRef)
+
+	for _, field := range c.pathFields() {
+		raw := *field.value
+		if raw == "" || filepath.IsAbs(raw) {
+			continue
+		}
+
+		resolved, err := filepath.Abs(filepath.Join(configDir, raw))
+		if err != nil {
+			return err
+		}
+		*field.value = resolved
+		refs[field.key] = pathRef{raw: raw, resolved: resolved}
+	}
+
+	if len(refs) > 0 {
+		c.pathRefs = refs
+	}
+
+	return nil
+}
+
+// restorePathRefs puts back the relative file values of paths that were resolved against
+// the config directory and have not been changed since.
+func (c *Config) restorePathRefs() {
+	if len(c.pathRefs) == 0 {
+		return
+	}
+
+	for _, field := range c.pathFields() {
+		ref, ok := c.pathRefs[field.key]
+		if ok && *field.value == ref.resolved {
+			*field.value = ref.raw
+		}
+	}
+}
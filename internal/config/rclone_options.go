@@ -0,0 +1,149 @@
+package config
+
+import (
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// knownMountOptions lists the rclone mount and VFS flags accepted in rclone.mount_options,
+// in their normalized form
+var knownMountOptions = map[string]bool{
+	"allow-non-empty":           true,
+	"allow-other":               true,
+	"allow-root":                true,
+	"async-read":                true,
+	"attr-timeout":              true,
+	"buffer-size":               true,
+	"cache-dir":                 true,
+	"daemon-timeout":            true,
+	"debug-fuse":                true,
+	"default-permissions":       true,
+	"devname":                   true,
+	"dir-cache-time":            true,
+	"dir-perms":                 true,
+	"direct-io":                 true,
+	"file-perms":                true,
+	"fuse-flag":                 true,
+	"gid":                       true,
+	"links":                     true,
+	"log-level":                 true,
+	"max-read-ahead":            true,
+	"mount-case-insensitive":    true,
+	"network-mode":              true,
+	"no-checksum":               true,
+	"no-modtime":                true,
+	"no-seek":                   true,
+	"noappledouble":             true,
+	"noapplexattr":              true,
+	"option":                    true,
+	"poll-interval":             true,
+	"read-only":                 true,
+	"syslog":                    true,
+	"timeout":                   true,
+	"transfers":                 true,
+	"uid":                       true,
+	"umask":                     true,
+	"use-mmap":                  true,
+	"vfs-cache-max-age":         true,
+	"vfs-cache-max-size":        true,
+	"vfs-cache-min-free-space":  true,
+	"vfs-cache-mode":            true,
+	"vfs-cache-poll-interval":   true,
+	"vfs-case-insensitive":      true,
+	"vfs-disk-space-total-size": true,
+	"vfs-fast-fingerprint":      true,
+	"vfs-read-ahead":            true,
+	"vfs-read-chunk-size":       true,
+	"vfs-read-chunk-size-limit": true,
+	"vfs-read-chunk-streams":    true,
+	"vfs-read-wait":             true,
+	"vfs-refresh":               true,
+	"vfs-used-is-size":          true,
+	"vfs-write-back":            true,
+	"vfs-write-wait":            true,
+	"volname":                   true,
+	"write-back-cache":          true,
+}
+
+// knownRCOptions lists the rclone remote control flags accepted in rclone.rc_options,
+// in their normalized form
+var knownRCOptions = map[string]bool{
+	"rc-addr":                    true,
+	"rc-allow-origin":            true,
+	"rc-baseurl":                 true,
+	"rc-cert":                    true,
+	"rc-client-ca":               true,
+	"rc-enable-metrics":          true,
+	"rc-files":                   true,
+	"rc-htpasswd":                true,
+	"rc-job-expire-duration":     true,
+	"rc-job-expire-interval":     true,
+	"rc-key":                     true,
+	"rc-max-header-bytes":        true,
+	"rc-metrics-addr":            true,
+	"rc-min-tls-version":         true,
+	"rc-no-auth":                 true,
+	"rc-pass":                    true,
+	"rc-realm":                   true,
+	"rc-salt":                    true,
+	"rc-serve":                   true,
+	"rc-server-read-timeout":     true,
+	"rc-server-write-timeout":    true,
+	"rc-template":                true,
+	"rc-user":                    true,
+	"rc-user-from-header":        true,
+	"rc-web-fetch-url":           true,
+	"rc-web-gui":                 true,
+	"rc-web-gui-force-update":    true,
+	"rc-web-gui-no-open-browser": true,
+	"rc-web-gui-update":          true,
+}
+
+// NormalizeRCloneOptionKey returns an rclone flag name in its canonical form: lower case,
+// without leading dashes and with underscores replaced by dashes, e.g. "--VFS_Cache_Mode"
+// becomes "vfs-cache-mode"
+func NormalizeRCloneOptionKey(key string) string {
+	key = strings.TrimLeft(strings.TrimSpace(key), "-")
+	return strings.ReplaceAll(strings.ToLower(key), "_", "-")
+}
+
+// normalizeRCloneOptions returns options with normalized keys. Keys that collide after
+// normalization are rejected, and unknown keys are rejected in strict mode or logged otherwise.
+func normalizeRCloneOptions(errs *ValidationErrors, field string, options map[string]string, known map[string]bool, strict bool) map[string]string {
+	if options == nil {
+		return nil
+	}
+
+	normalized := make(map[string]string, len(options))
+	originals := make(map[string]string, len(options))
+
+	// Sort keys so collisions and unknown keys are reported in a stable order
+	for _, key := range slices.Sorted(maps.Keys(options)) {
+		name := NormalizeRCloneOptionKey(key)
+		if name == "" {
+			errs.add(field, "%s: option name cannot be empty", field)
+			continue
+		}
+
+		if original, ok := originals[name]; ok {
+			errs.add(field+"."+key, "%s: %q and %q set the same option %q", field, original, key, name)
+			continue
+		}
+		originals[name] = key
+		normalized[name] = options[key]
+
+		if !known[name] {
+			if strict {
+				errs.add(field+"."+key, "%s: unknown rclone option %q", field, key)
+			} else {
+				slog.Warn("Unknown rclone option, it may be ignored by rclone",
+					"field", field,
+					"option", key)
+			}
+		}
+	}
+
+	return normalized
+}
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestNormalizeRCloneOptions(t *testing.T) {
+	var errs ValidationErrors
+	got := normalizeRCloneOptions(&errs, "rclone.mount_options", map[string]string{
+		"--VFS_Cache_Mode": "full",
+		"dir-cache-time":   "5m",
+		"made-up-flag":     "1",
+	}, knownMountOptions, false)
+
+	want := map[string]string{"vfs-cache-mode": "full", "dir-cache-time": "5m", "made-up-flag": "1"}
+	if !maps.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if len(errs) != 0 {
+		t.Errorf("unexpected errors in lenient mode: %v", errs)
+	}
+}
+
+func TestNormalizeRCloneOptionsErrors(t *testing.T) {
+	var errs ValidationErrors
+	normalizeRCloneOptions(&errs, "rclone.mount_options", map[string]string{
+		"vfs_cache_mode": "full",
+		"vfs-cache-mode": "writes",
+		"made-up-flag":   "1",
+	}, knownMountOptions, true)
+
+	if len(errs) != 2 {
+		t.Fatalf("got %d errors, want a collision and an unknown option: %v", len(errs), errs)
+	}
+}
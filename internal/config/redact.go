@@ -0,0 +1,82 @@
+package config
+
+import (
+	"slices"
+	"strings"
+)
+
+// RedactedValue replaces secret values in configs returned by Redacted
+const RedactedValue = "***"
+
+// redactedRCOptions are rc_options keys, in normalized form, whose values are secrets
+var redactedRCOptions = []string{"rc-pass", "rc-htpasswd", "rc-key", "rc-salt"}
+
+// secretValues returns pointers to every secret value in the configuration.
+// New secret fields must be added here so Redacted hides them.
+func (c *Config) secretValues() []*string {
+	values := []*string{
+		&c.WebDAV.Password,
+		&c.RClone.Password,
+		&c.RClone.Salt,
+		&c.RClone.RCPass,
+		&c.SABnzbd.FallbackAPIKey,
+		&c.Import.PostImportWebhookSecret,
+	}
+
+	for i := range c.Providers {
+		values = append(values, &c.Providers[i].Password)
+	}
+
+	for _, instances := range [][]ArrsInstanceConfig{
+		c.Arrs.RadarrInstances,
+		c.Arrs.SonarrInstances,
+		c.Arrs.LidarrInstances,
+		c.Arrs.ReadarrInstances,
+	} {
+		for i := range instances {
+			values = append(values, &instances[i].APIKey)
+		}
+	}
+
+	return values
+}
+
+// Redacted returns a deep copy of the configuration with every password, API key and salt
+// replaced by RedactedValue, safe to log or include in diagnostics. Secrets that are not
+// set stay empty so the copy still shows which ones are configured.
+func (c *Config) Redacted() *Config {
+	redacted := c.DeepCopy()
+	redacted.secretRefs = nil
+
+	for _, value := range redacted.secretValues() {
+		if *value != "" {
+			*value = RedactedValue
+		}
+	}
+
+	for key := range redacted.RClone.RCOptions {
+		if slices.Contains(redactedRCOptions, NormalizeRCloneOptionKey(key)) {
+			redacted.RClone.RCOptions[key] = RedactedValue
+		}
+	}
+
+	return redacted
+}
+
+// RedactText replaces every secret value of the configuration that appears in text with
+// RedactedValue, for free-form output such as log lines
+func (c *Config) RedactText(text string) string {
+	for _, value := range c.secretValues() {
+		if *value != "" {
+			text = strings.ReplaceAll(text, *value, RedactedValue)
+		}
+	}
+
+	for key, value := range c.RClone.RCOptions {
+		if value != "" && slices.Contains(redactedRCOptions, NormalizeRCloneOptionKey(key)) {
+			text = strings.ReplaceAll(text, value, RedactedValue)
+		}
+	}
+
+	return text
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ValidationError describes a single invalid configuration value
+type ValidationError struct {
+	Field   string `json:"field"`   // Config path of the invalid value, e.g. "providers[0].port"
+	Message string `json:"message"` // Human readable description of the problem
+}
+
+// Error implements the error interface
+func (e ValidationError) Error() string {
+	return e.Message
+}
+
+// ValidationErrors collects every problem found while validating a configuration
+type ValidationErrors []ValidationError
+
+// Error joins all validation messages into a single string
+func (e ValidationErrors) Error() string {
+	if len(e) == 1 {
+		return e[0].Message
+	}
+
+	messages := make([]string, len(e))
+	for i, verr := range e {
+		messages[i] = verr.Message
+	}
+
+	return fmt.Sprintf("%d configuration errors: %s", len(e), strings.Join(messages, "; "))
+}
+
+// add records a validation problem, skipping exact duplicates
+func (e *ValidationErrors) add(field, format string, args ...any) {
+	verr := ValidationError{Field: field, Message: fmt.Sprintf(format, args...)}
+	for _, existing := range *e {
+		if existing == verr {
+			return
+		}
+	}
+
+	*e = append(*e, verr)
+}
+
+// AsValidationErrors extracts the individual validation problems from err.
+// Errors that are not validation errors are reported under the "config" field.
+func AsValidationErrors(err error) ValidationErrors {
+	if err == nil {
+		return nil
+	}
+
+	var verrs ValidationErrors
+	if errors.As(err, &verrs) {
+		return verrs
+	}
+
+	var verr ValidationError
+	if errors.As(err, &verr) {
+		return ValidationErrors{verr}
+	}
+
+	return ValidationErrors{{Field: "config", Message: err.Error()}}
+}
+
+// FirstValidationError returns only the first problem reported by err, for callers that
+// expect the single-error behaviour Validate used to have
+func FirstValidationError(err error) error {
+	var verrs ValidationErrors
+	if errors.As(err, &verrs) && len(verrs) > 0 {
+		return verrs[0]
+	}
+
+	return err
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+// configWatchDebounce is how long the file must be quiet before a change is applied,
+// so editors that write in several steps do not trigger a reload of a partial file
+const configWatchDebounce = 500 * time.Millisecond
+
+// WatchConfig watches the config file and applies changes made on disk until ctx is done.
+// Reloaded configs are checked with ValidateConfigUpdate; invalid ones are logged and ignored.
+func (m *Manager) WatchConfig(ctx context.Context) error {
+	configFile := m.configFile
+	if configFile == "" {
+		configFile = viper.ConfigFileUsed()
+	}
+	if configFile == "" {
+		return fmt.Errorf("no config file to watch")
+	}
+
+	configFile, err := filepath.Abs(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to resolve config file path: %w", err)
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create config watcher: %w", err)
+	}
+
+	// Watch the directory rather than the file so atomic rename-based saves are seen
+	if err := watcher.Add(filepath.Dir(configFile)); err != nil {
+		_ = watcher.Close()
+		return fmt.Errorf("failed to watch config directory: %w", err)
+	}
+
+	logger := slog.Default().With("component", "config-watcher")
+	logger.InfoContext(ctx, "Watching config file for changes", "file", configFile)
+
+	go func() {
+		defer watcher.Close()
+
+		debounce := time.NewTimer(configWatchDebounce)
+		debounce.Stop()
+		defer debounce.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) != configFile ||
+					!event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) && !event.Has(fsnotify.Rename) {
+					continue
+				}
+				debounce.Reset(configWatchDebounce)
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				logger.ErrorContext(ctx, "Config watcher error", "error", err)
+			case <-debounce.C:
+				m.applyWatchedConfig(ctx, logger)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// applyWatchedConfig reloads the config file after a change on disk
+func (m *Manager) applyWatchedConfig(ctx context.Context, logger *slog.Logger) {
+	newConfig, err := m.readConfigFile()
+	if err != nil {
+		logger.ErrorContext(ctx, "Ignoring config file change", "error", err)
+		return
+	}
+
+	// Our own saves also trigger the watcher; skip them when nothing changed
+	if reflect.DeepEqual(newConfig, m.GetConfig()) {
+		return
+	}
+
+	if err := m.ValidateConfigUpdate(newConfig); err != nil {
+		logger.ErrorContext(ctx, "Ignoring invalid config file change", "error", err)
+		return
+	}
+
+	if err := m.UpdateConfig(newConfig); err != nil {
+		logger.ErrorContext(ctx, "Failed to apply config file change", "error", err)
+		return
+	}
+
+	logger.InfoContext(ctx, "Configuration reloaded from file")
+}
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// marshalPreservingComments marshals config and merges the result into the YAML document
+// already stored at filename, so comments, key order and quoting chosen by the user survive
+// a save. If the existing file is missing or cannot be parsed, the plain marshalled output
+// is returned.
+func marshalPreservingComments(config *Config, filename string) ([]byte, error) {
+	var updated yaml.Node
+	if err := updated.Encode(config); err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	if err := config.included.strip(&updated); err != nil {
+		return nil, err
+	}
+
+	existingData, err := os.ReadFile(filename)
+	if err != nil || len(bytes.TrimSpace(existingData)) == 0 {
+		return yaml.Marshal(&updated)
+	}
+
+	var existing yaml.Node
+	if err := yaml.Unmarshal(existingData, &existing); err != nil ||
+		existing.Kind != yaml.DocumentNode || len(existing.Content) == 0 ||
+		existing.Content[0].Kind != yaml.MappingNode {
+		return yaml.Marshal(&updated)
+	}
+
+	existing.Content[0] = mergeYAMLNodes(existing.Content[0], &updated)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&existing); err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// mergeYAMLNodes returns a node holding the values of updated with the comments, key order
+// and scalar style of existing. Keys missing from updated are dropped, new keys are appended.
+func mergeYAMLNodes(existing, updated *yaml.Node) *yaml.Node {
+	if existing == nil {
+		return updated
+	}
+
+	switch {
+	case existing.Kind == yaml.MappingNode && updated.Kind == yaml.MappingNode:
+		return mergeYAMLMappings(existing, updated)
+	case existing.Kind == yaml.SequenceNode && updated.Kind == yaml.SequenceNode:
+		merged := *updated
+		merged.Content = make([]*yaml.Node, len(updated.Content))
+		for i, item := range updated.Content {
+			if i < len(existing.Content) {
+				merged.Content[i] = mergeYAMLNodes(existing.Content[i], item)
+			} else {
+				merged.Content[i] = item
+			}
+		}
+		copyYAMLComments(&merged, existing)
+		merged.Style = existing.Style
+		return &merged
+	case existing.Kind == yaml.ScalarNode && updated.Kind == yaml.ScalarNode:
+		merged := *updated
+		copyYAMLComments(&merged, existing)
+		// Keep the user's quoting when the value is still a string
+		if existing.Tag == updated.Tag {
+			merged.Style = existing.Style
+		}
+		return &merged
+	default:
+		merged := *updated
+		copyYAMLComments(&merged, existing)
+		return &merged
+	}
+}
+
+// mergeYAMLMappings merges two mapping nodes key by key
+func mergeYAMLMappings(existing, updated *yaml.Node) *yaml.Node {
+	updatedValues := make(map[string]*yaml.Node, len(updated.Content)/2)
+	for i := 0; i+1 < len(updated.Content); i += 2 {
+		updatedValues[updated.Content[i].Value] = updated.Content[i+1]
+	}
+
+	merged := *existing
+	merged.Content = make([]*yaml.Node, 0, len(updated.Content))
+	seen := make(map[string]bool, len(updatedValues))
+
+	for i := 0; i+1 < len(existing.Content); i += 2 {
+		key := existing.Content[i]
+		value, ok := updatedValues[key.Value]
+		if !ok || seen[key.Value] {
+			continue
+		}
+		seen[key.Value] = true
+		merged.Content = append(merged.Content, key, mergeYAMLNodes(existing.Content[i+1], value))
+	}
+
+	for i := 0; i+1 < len(updated.Content); i += 2 {
+		key := updated.Content[i]
+		if seen[key.Value] {
+			continue
+		}
+		merged.Content = append(merged.Content, key, updated.Content[i+1])
+	}
+
+	return &merged
+}
+
+// copyYAMLComments copies the comments attached to src onto dst
+func copyYAMLComments(dst, src *yaml.Node) {
+	dst.HeadComment = src.HeadComment
+	dst.LineComment = src.LineComment
+	dst.FootComment = src.FootComment
+}
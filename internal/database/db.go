@@ -3,7 +3,9 @@ package database
 import (
 	"database/sql"
 	"embed"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
@@ -13,22 +15,53 @@ import (
 //go:embed migrations/*.sql
 var embedMigrations embed.FS
 
+// ErrSchemaTooNew is returned when the database was migrated by a newer release than this one
+var ErrSchemaTooNew = errors.New("database schema is newer than this release supports")
+
 // DB wraps the database connection and provides access to operations
 type DB struct {
-	conn       *sql.DB
-	Repository *QueueRepository
+	conn         *sql.DB
+	Repository   *QueueRepository
+	schemaStatus SchemaStatus
+	journalMode  string
+}
+
+// SchemaStatus describes the database schema version and the migrations run when it was opened
+type SchemaStatus struct {
+	Version         int64 `json:"version"`          // Schema version after migrations
+	PreviousVersion int64 `json:"previous_version"` // Schema version found when the database was opened
+	LatestVersion   int64 `json:"latest_version"`   // Latest schema version known by this release
+	Migrated        bool  `json:"migrated"`         // Whether migrations were applied on this startup
 }
 
 // Config holds database configuration
 type Config struct {
 	DatabasePath string
+	JournalMode  string        // SQLite journal mode, WAL when empty
+	BusyTimeout  time.Duration // How long a query waits for a lock, 30 seconds when zero
+	Synchronous  string        // SQLite synchronous level, NORMAL when empty
 }
 
 // NewDB creates a new database connection and runs migrations
 func NewDB(config Config) (*DB, error) {
-	// Configure connection string optimized for write-heavy queue operations
-	connString := fmt.Sprintf("%s?_journal_mode=WAL&_synchronous=NORMAL&_cache_size=-32000&_temp_store=MEMORY&_busy_timeout=30000",
-		config.DatabasePath)
+	journalMode := config.JournalMode
+	if journalMode == "" {
+		journalMode = "WAL"
+	}
+	synchronous := config.Synchronous
+	if synchronous == "" {
+		synchronous = "NORMAL"
+	}
+	busyTimeout := config.BusyTimeout
+	if busyTimeout <= 0 {
+		busyTimeout = 30 * time.Second
+	}
+
+	// Configure connection string optimized for write-heavy queue operations. Settings in the
+	// connection string are applied by the driver to every connection of the pool, unlike
+	// pragmas executed below, which only reach the connection that runs them.
+	connString := fmt.Sprintf("%s?_journal_mode=%s&_synchronous=%s&_cache_size=-32000&_temp_store=MEMORY&_busy_timeout=%d",
+		config.DatabasePath, journalMode, synchronous, busyTimeout.Milliseconds())
 
 	conn, err := sql.Open("sqlite3", connString)
 	if err != nil {
@@ -50,14 +83,14 @@ func NewDB(config Config) (*DB, error) {
 	// Set SQLite pragmas optimized for write-heavy queue operations
 	pragmas := []string{
 		"PRAGMA foreign_keys = ON",
-		"PRAGMA journal_mode = WAL",       // WAL mode for concurrency
-		"PRAGMA synchronous = NORMAL",     // Good balance for queue operations
-		"PRAGMA cache_size = -32000",      // 32MB cache (smaller than main DB)
-		"PRAGMA temp_store = MEMORY",      // Memory temp storage
-		"PRAGMA busy_timeout = 30000",     // 30 second timeout
-		"PRAGMA wal_autocheckpoint = 500", // More frequent checkpoints for writes
-		"PRAGMA optimize",                 // Optimize query planner
-		"PRAGMA mmap_size = 268435456",    // 256MB memory map
+		"PRAGMA journal_mode = " + journalMode,                              // WAL mode for concurrency by default
+		"PRAGMA synchronous = " + synchronous,                               // NORMAL is a good balance for queue operations
+		"PRAGMA cache_size = -32000",                                        // 32MB cache (smaller than main DB)
+		"PRAGMA temp_store = MEMORY",                                        // Memory temp storage
+		fmt.Sprintf("PRAGMA busy_timeout = %d", busyTimeout.Milliseconds()), // Wait for locks instead of failing
+		"PRAGMA wal_autocheckpoint = 500",                                   // More frequent checkpoints for writes
+		"PRAGMA optimize",                                                   // Optimize query planner
+		"PRAGMA mmap_size = 268435456",                                      // 256MB memory map
 	}
 
 	for _, pragma := range pragmas {
@@ -67,14 +100,25 @@ func NewDB(config Config) (*DB, error) {
 		}
 	}
 
+	// SQLite keeps the previous journal mode when the requested one is not supported,
+	// e.g. WAL on some network filesystems
+	var activeJournalMode string
+	if err := conn.QueryRow("PRAGMA journal_mode").Scan(&activeJournalMode); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to read journal mode: %w", err)
+	}
+
 	// Run database migrations
-	if err := runMigrations(conn); err != nil {
+	schemaStatus, err := runMigrations(conn)
+	if err != nil {
 		conn.Close()
 		return nil, fmt.Errorf("failed to run migrations: %w", err)
 	}
 
 	db := &DB{
-		conn: conn,
+		conn:         conn,
+		schemaStatus: schemaStatus,
+		journalMode:  strings.ToUpper(activeJournalMode),
 	}
 
 	db.Repository = NewQueueRepository(conn)
@@ -82,20 +126,60 @@ func NewDB(config Config) (*DB, error) {
 	return db, nil
 }
 
-// runMigrations runs database migrations using Goose
-func runMigrations(db *sql.DB) error {
+// runMigrations runs database migrations using Goose and reports the resulting schema version
+func runMigrations(db *sql.DB) (SchemaStatus, error) {
+	var status SchemaStatus
+
 	// Set the migration provider for embedded filesystem
 	goose.SetBaseFS(embedMigrations)
 
 	if err := goose.SetDialect("sqlite3"); err != nil {
-		return fmt.Errorf("failed to set goose dialect: %w", err)
+		return status, fmt.Errorf("failed to set goose dialect: %w", err)
+	}
+
+	migrations, err := goose.CollectMigrations("migrations", 0, goose.MaxVersion)
+	if err != nil {
+		return status, fmt.Errorf("failed to collect migrations: %w", err)
+	}
+	latest, err := migrations.Last()
+	if err != nil {
+		return status, fmt.Errorf("failed to find latest migration: %w", err)
+	}
+	status.LatestVersion = latest.Version
+
+	status.PreviousVersion, err = goose.GetDBVersion(db)
+	if err != nil {
+		return status, fmt.Errorf("failed to get database schema version: %w", err)
+	}
+
+	// Migrations are never rolled back automatically, a newer schema may not work with this release
+	if status.PreviousVersion > status.LatestVersion {
+		return status, fmt.Errorf("%w: database is at version %d but this release only knows up to version %d, upgrade AltMount or restore a backup of the database",
+			ErrSchemaTooNew, status.PreviousVersion, status.LatestVersion)
 	}
 
 	if err := goose.Up(db, "migrations"); err != nil {
-		return fmt.Errorf("failed to run queue migrations: %w", err)
+		return status, fmt.Errorf("failed to run queue migrations: %w", err)
 	}
 
-	return nil
+	status.Version, err = goose.GetDBVersion(db)
+	if err != nil {
+		return status, fmt.Errorf("failed to get database schema version: %w", err)
+	}
+	status.Migrated = status.Version != status.PreviousVersion
+
+	return status, nil
+}
+
+// SchemaStatus returns the schema version of the database and whether it was migrated on startup
+func (db *DB) SchemaStatus() SchemaStatus {
+	return db.schemaStatus
+}
+
+// JournalMode returns the journal mode SQLite is using, which may differ from the
+// configured one when the filesystem does not support it
+func (db *DB) JournalMode() string {
+	return db.journalMode
 }
 
 // Close closes the database connection
@@ -106,12 +106,12 @@ func (r *HealthRepository) GetUnhealthyFiles(ctx context.Context, limit int) ([]
 		SELECT id, file_path, status, last_checked, last_error, retry_count, max_retries,
 		       repair_retry_count, max_repair_retries, source_nzb_path,
 		       error_details, created_at, updated_at, release_date, scheduled_check_at,
-			   library_path
+			   library_path, priority
 		FROM file_health
 		WHERE scheduled_check_at IS NOT NULL
 		  AND scheduled_check_at <= datetime('now')
-		  AND retry_count < 1
-		ORDER BY scheduled_check_at ASC
+		  AND retry_count < max_retries
+		ORDER BY priority DESC, scheduled_check_at ASC
 		LIMIT ?
 	`
 
@@ -132,6 +132,7 @@ func (r *HealthRepository) GetUnhealthyFiles(ctx context.Context, limit int) ([]
 			&health.CreatedAt, &health.UpdatedAt, &health.ReleaseDate,
 			&health.ScheduledCheckAt,
 			&health.LibraryPath,
+			&health.Priority,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan file health: %w", err)
@@ -188,18 +189,19 @@ func (r *HealthRepository) GetFilesForRepairNotification(ctx context.Context, li
 	return files, nil
 }
 
-// IncrementRetryCount increments the retry count
-func (r *HealthRepository) IncrementRetryCount(ctx context.Context, filePath string, errorMessage *string) error {
+// IncrementRetryCount increments the retry count and schedules the retry at nextCheckTime
+func (r *HealthRepository) IncrementRetryCount(ctx context.Context, filePath string, errorMessage *string, nextCheckTime time.Time) error {
 	query := `
 		UPDATE file_health
 		SET retry_count = retry_count + 1,
 		    last_error = ?,
 			status = 'pending',
+		    scheduled_check_at = ?,
 		    updated_at = datetime('now')
 		WHERE file_path = ?
 	`
 
-	_, err := r.db.ExecContext(ctx, query, errorMessage, filePath)
+	_, err := r.db.ExecContext(ctx, query, errorMessage, nextCheckTime, filePath)
 	if err != nil {
 		return fmt.Errorf("failed to increment retry count: %w", err)
 	}
@@ -261,6 +263,33 @@ func (r *HealthRepository) SetCorrupted(ctx context.Context, filePath string, er
 	return nil
 }
 
+// SetUnmanaged sets a file's status to unmanaged, for corrupted files AltMount does not repair
+func (r *HealthRepository) SetUnmanaged(ctx context.Context, filePath string, errorMessage *string) error {
+	query := `
+		UPDATE file_health
+		SET status = ?,
+		    last_error = ?,
+		    updated_at = datetime('now')
+		WHERE file_path = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, query, HealthStatusUnmanaged, errorMessage, filePath)
+	if err != nil {
+		return fmt.Errorf("failed to update file status to unmanaged: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("no file found to update status: %s", filePath)
+	}
+
+	return nil
+}
+
 // IncrementRepairRetryCount increments the repair retry count
 func (r *HealthRepository) IncrementRepairRetryCount(ctx context.Context, filePath string, errorMessage *string) error {
 	query := `
@@ -307,8 +336,10 @@ func (r *HealthRepository) MarkAsCorrupted(ctx context.Context, filePath string,
 	return nil
 }
 
-// GetHealthStats returns statistics about file health
-func (r *HealthRepository) GetHealthStats(ctx context.Context) (map[HealthStatus]int, error) {
+// CountByStatus returns the number of tracked files in each health status.
+// Statuses without files are not included in the result.
+func (r *HealthRepository) CountByStatus(ctx context.Context) (map[HealthStatus]int64, error) {
+	// Served from idx_file_health_status without reading the table rows
 	query := `
 		SELECT status, COUNT(*) 
 		FROM file_health 
@@ -321,10 +352,10 @@ func (r *HealthRepository) GetHealthStats(ctx context.Context) (map[HealthStatus
 	}
 	defer rows.Close()
 
-	stats := make(map[HealthStatus]int)
+	stats := make(map[HealthStatus]int64)
 	for rows.Next() {
 		var status HealthStatus
-		var count int
+		var count int64
 		err := rows.Scan(&status, &count)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan health stats: %w", err)
@@ -599,11 +630,12 @@ func (r *HealthRepository) CountHealthItems(ctx context.Context, statusFilter *H
 	return count, nil
 }
 
-// SetFileChecking sets a file's status to 'checking'
+// SetFileChecking sets a file's status to 'checking' and clears any queue priority
 func (r *HealthRepository) SetFileChecking(ctx context.Context, filePath string) error {
 	query := `
 		UPDATE file_health 
 		SET status = ?,
+		    priority = 0,
 		    updated_at = datetime('now')
 		WHERE file_path = ?
 	`
@@ -616,6 +648,93 @@ func (r *HealthRepository) SetFileChecking(ctx context.Context, filePath string)
 	return nil
 }
 
+// ScheduleCheck queues a file for a health check with the given priority.
+// High priority files are due immediately; normal priority keeps an existing schedule.
+func (r *HealthRepository) ScheduleCheck(ctx context.Context, filePath string, priority HealthPriority) error {
+	query := `
+		UPDATE file_health
+		SET priority = ?,
+		    scheduled_check_at = CASE
+		        WHEN ? > 0 THEN datetime('now')
+		        ELSE COALESCE(scheduled_check_at, datetime('now'))
+		    END,
+		    updated_at = datetime('now')
+		WHERE file_path = ?
+	`
+
+	_, err := r.db.ExecContext(ctx, query, priority, priority, filePath)
+	if err != nil {
+		return fmt.Errorf("failed to schedule health check: %w", err)
+	}
+
+	return nil
+}
+
+// SetFileSkipped sets a file's status to 'skipped' without touching its retry counts
+func (r *HealthRepository) SetFileSkipped(ctx context.Context, filePath string) error {
+	query := `
+		UPDATE file_health
+		SET status = ?,
+		    updated_at = datetime('now')
+		WHERE file_path = ?
+	`
+
+	_, err := r.db.ExecContext(ctx, query, HealthStatusSkipped, filePath)
+	if err != nil {
+		return fmt.Errorf("failed to set file status to skipped: %w", err)
+	}
+
+	return nil
+}
+
+// SkipDueFiles marks every file that is due for a health check as 'skipped'.
+// Used when no providers are available so the checks do not count as failures.
+func (r *HealthRepository) SkipDueFiles(ctx context.Context) (int, error) {
+	query := `
+		UPDATE file_health
+		SET status = ?,
+		    updated_at = datetime('now')
+		WHERE scheduled_check_at IS NOT NULL
+		  AND scheduled_check_at <= datetime('now')
+		  AND retry_count < max_retries
+		  AND status NOT IN (?, ?)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, HealthStatusSkipped, HealthStatusSkipped, HealthStatusChecking)
+	if err != nil {
+		return 0, fmt.Errorf("failed to skip due files: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
+// RequeueSkippedFiles moves all 'skipped' files back to 'pending' so they are checked again
+func (r *HealthRepository) RequeueSkippedFiles(ctx context.Context) (int, error) {
+	query := `
+		UPDATE file_health
+		SET status = ?,
+		    updated_at = datetime('now')
+		WHERE status = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, query, HealthStatusPending, HealthStatusSkipped)
+	if err != nil {
+		return 0, fmt.Errorf("failed to requeue skipped files: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
 func (r *HealthRepository) ResetFileAllChecking(ctx context.Context) error {
 	query := `
 		UPDATE file_health
@@ -824,6 +943,35 @@ func (r *HealthRepository) MarkAsHealthy(ctx context.Context, filePath string, n
 	return nil
 }
 
+// RecordObservedStatus stores the outcome of a check and schedules the next one without
+// touching retry or repair state. Used by the health worker in dry-run mode.
+func (r *HealthRepository) RecordObservedStatus(ctx context.Context, filePath string, status HealthStatus, errorMessage *string, nextCheckTime time.Time) error {
+	query := `
+		UPDATE file_health
+		SET status = ?,
+		    last_error = ?,
+		    scheduled_check_at = ?,
+		    updated_at = datetime('now')
+		WHERE file_path = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, query, status, errorMessage, nextCheckTime, filePath)
+	if err != nil {
+		return fmt.Errorf("failed to record observed status: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("no health check found for file: %s", filePath)
+	}
+
+	return nil
+}
+
 // GetAllHealthCheckRecords returns all health check records tracked in health system
 func (r *HealthRepository) GetAllHealthCheckRecords(ctx context.Context) ([]AutomaticHealthCheckRecord, error) {
 	query := `
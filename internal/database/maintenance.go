@@ -0,0 +1,140 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// maintenanceCheckInterval is how often the maintenance worker checks whether a run is due.
+// A run postponed because the server was busy is retried at the next check.
+const maintenanceCheckInterval = 5 * time.Minute
+
+// Optimize lets SQLite refresh the query planner statistics of tables whose contents changed
+func (db *DB) Optimize(ctx context.Context) error {
+	if _, err := db.conn.ExecContext(ctx, "PRAGMA optimize"); err != nil {
+		return fmt.Errorf("failed to optimize database: %w", err)
+	}
+	return nil
+}
+
+// Vacuum rebuilds the database file, returning the pages left free by deleted rows to the
+// filesystem. It returns the number of bytes reclaimed. Writers are blocked while it runs.
+func (db *DB) Vacuum(ctx context.Context) (int64, error) {
+	before, err := db.size(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := db.conn.ExecContext(ctx, "VACUUM"); err != nil {
+		return 0, fmt.Errorf("failed to vacuum database: %w", err)
+	}
+
+	// Fold the rebuilt pages back into the database file, so the WAL does not keep the space
+	if _, err := db.conn.ExecContext(ctx, "PRAGMA wal_checkpoint(TRUNCATE)"); err != nil {
+		return 0, fmt.Errorf("failed to checkpoint database: %w", err)
+	}
+
+	after, err := db.size(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return before - after, nil
+}
+
+// size returns the size of the database in bytes
+func (db *DB) size(ctx context.Context) (int64, error) {
+	var pageCount, pageSize int64
+	if err := db.conn.QueryRowContext(ctx, "PRAGMA page_count").Scan(&pageCount); err != nil {
+		return 0, fmt.Errorf("failed to read database page count: %w", err)
+	}
+	if err := db.conn.QueryRowContext(ctx, "PRAGMA page_size").Scan(&pageSize); err != nil {
+		return 0, fmt.Errorf("failed to read database page size: %w", err)
+	}
+	return pageCount * pageSize, nil
+}
+
+// MaintenanceWorker periodically optimizes and vacuums the database, which keeps queries
+// fast and the file small after lots of imports and deletions. Runs are postponed while
+// the server is busy, since VACUUM locks the database.
+type MaintenanceWorker struct {
+	db       *DB
+	interval func() time.Duration // Time between runs, read on every check so config changes apply, 0 disables
+	busy     func() bool          // Reports whether imports or health checks are in progress
+	lastRun  time.Time
+}
+
+// NewMaintenanceWorker creates a maintenance worker for db. interval returns the time
+// between runs and busy whether a run must be postponed, busy may be nil.
+func NewMaintenanceWorker(db *DB, interval func() time.Duration, busy func() bool) *MaintenanceWorker {
+	return &MaintenanceWorker{
+		db:       db,
+		interval: interval,
+		busy:     busy,
+		lastRun:  time.Now(), // The first run happens one interval after startup
+	}
+}
+
+// Start runs the worker in a background goroutine until ctx is cancelled
+func (w *MaintenanceWorker) Start(ctx context.Context) {
+	go w.run(ctx)
+}
+
+func (w *MaintenanceWorker) run(ctx context.Context) {
+	ticker := time.NewTicker(maintenanceCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.runIfDue(ctx)
+		}
+	}
+}
+
+// runIfDue runs maintenance when an interval has passed since the last run and the
+// server is idle
+func (w *MaintenanceWorker) runIfDue(ctx context.Context) {
+	interval := w.interval()
+	if interval <= 0 {
+		return
+	}
+
+	if time.Since(w.lastRun) < interval {
+		return
+	}
+
+	if w.busy != nil && w.busy() {
+		slog.DebugContext(ctx, "Postponing database maintenance, server is busy")
+		return
+	}
+
+	if err := w.maintain(ctx); err != nil {
+		slog.ErrorContext(ctx, "Database maintenance failed", "error", err)
+	}
+}
+
+// maintain optimizes and vacuums the database, logging the space reclaimed
+func (w *MaintenanceWorker) maintain(ctx context.Context) error {
+	start := time.Now()
+	w.lastRun = start
+
+	if err := w.db.Optimize(ctx); err != nil {
+		return err
+	}
+
+	reclaimed, err := w.db.Vacuum(ctx)
+	if err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "Database maintenance completed",
+		"reclaimed_bytes", reclaimed,
+		"duration", time.Since(start))
+
+	return nil
+}
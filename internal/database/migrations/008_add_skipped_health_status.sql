@@ -0,0 +1,120 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Add 'skipped' status for files whose checks were skipped because no providers were available
+-- SQLite cannot alter CHECK constraints, so the table is rebuilt
+CREATE TABLE file_health_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    file_path TEXT NOT NULL UNIQUE,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK(status IN ('pending', 'checking', 'healthy', 'repair_triggered', 'corrupted', 'skipped')),
+    last_checked DATETIME DEFAULT CURRENT_TIMESTAMP,
+    last_error TEXT DEFAULT NULL,
+    retry_count INTEGER NOT NULL DEFAULT 0,
+    max_retries INTEGER NOT NULL DEFAULT 2,
+    repair_retry_count INTEGER NOT NULL DEFAULT 0,
+    max_repair_retries INTEGER NOT NULL DEFAULT 3,
+    source_nzb_path TEXT DEFAULT NULL,
+    error_details TEXT DEFAULT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    release_date DATETIME,
+    scheduled_check_at DATETIME,
+    library_path TEXT DEFAULT NULL
+);
+
+-- Copy data from old table to new table
+INSERT INTO file_health_new (
+    id, file_path, status, last_checked, last_error, retry_count, max_retries,
+    repair_retry_count, max_repair_retries, source_nzb_path, error_details,
+    created_at, updated_at, release_date, scheduled_check_at, library_path
+)
+SELECT
+    id, file_path, status, last_checked, last_error, retry_count, max_retries,
+    repair_retry_count, max_repair_retries, source_nzb_path, error_details,
+    created_at, updated_at, release_date, scheduled_check_at, library_path
+FROM file_health;
+
+-- Drop the old table
+DROP TABLE file_health;
+
+-- Rename the new table
+ALTER TABLE file_health_new RENAME TO file_health;
+
+-- Recreate indexes for the new table
+CREATE INDEX idx_file_health_status ON file_health(status);
+CREATE INDEX idx_file_health_path ON file_health(file_path);
+CREATE INDEX idx_file_health_source ON file_health(source_nzb_path);
+CREATE INDEX idx_file_health_updated ON file_health(updated_at);
+CREATE INDEX idx_file_health_scheduled ON file_health(scheduled_check_at) WHERE scheduled_check_at IS NOT NULL;
+CREATE INDEX idx_file_health_release_date ON file_health(release_date) WHERE release_date IS NOT NULL;
+CREATE INDEX idx_file_health_library_path ON file_health(library_path);
+
+-- Recreate the update trigger
+CREATE TRIGGER update_file_health_timestamp
+AFTER UPDATE ON file_health
+BEGIN
+    UPDATE file_health SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+-- Skipped files go back to pending so they are checked again
+UPDATE file_health
+SET status = 'pending'
+WHERE status = 'skipped';
+
+CREATE TABLE file_health_original (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    file_path TEXT NOT NULL UNIQUE,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK(status IN ('pending', 'checking', 'healthy', 'repair_triggered', 'corrupted')),
+    last_checked DATETIME DEFAULT CURRENT_TIMESTAMP,
+    last_error TEXT DEFAULT NULL,
+    retry_count INTEGER NOT NULL DEFAULT 0,
+    max_retries INTEGER NOT NULL DEFAULT 2,
+    repair_retry_count INTEGER NOT NULL DEFAULT 0,
+    max_repair_retries INTEGER NOT NULL DEFAULT 3,
+    source_nzb_path TEXT DEFAULT NULL,
+    error_details TEXT DEFAULT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    release_date DATETIME,
+    scheduled_check_at DATETIME,
+    library_path TEXT DEFAULT NULL
+);
+
+-- Copy data back
+INSERT INTO file_health_original (
+    id, file_path, status, last_checked, last_error, retry_count, max_retries,
+    repair_retry_count, max_repair_retries, source_nzb_path, error_details,
+    created_at, updated_at, release_date, scheduled_check_at, library_path
+)
+SELECT
+    id, file_path, status, last_checked, last_error, retry_count, max_retries,
+    repair_retry_count, max_repair_retries, source_nzb_path, error_details,
+    created_at, updated_at, release_date, scheduled_check_at, library_path
+FROM file_health;
+
+-- Drop current table and restore original
+DROP TABLE file_health;
+ALTER TABLE file_health_original RENAME TO file_health;
+
+-- Recreate original indexes
+CREATE INDEX idx_file_health_status ON file_health(status);
+CREATE INDEX idx_file_health_path ON file_health(file_path);
+CREATE INDEX idx_file_health_source ON file_health(source_nzb_path);
+CREATE INDEX idx_file_health_updated ON file_health(updated_at);
+CREATE INDEX idx_file_health_scheduled ON file_health(scheduled_check_at) WHERE scheduled_check_at IS NOT NULL;
+CREATE INDEX idx_file_health_release_date ON file_health(release_date) WHERE release_date IS NOT NULL;
+CREATE INDEX idx_file_health_library_path ON file_health(library_path);
+
+-- Recreate the update trigger
+CREATE TRIGGER update_file_health_timestamp
+AFTER UPDATE ON file_health
+BEGIN
+    UPDATE file_health SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Add priority so manually requested checks are processed before scheduled ones
+ALTER TABLE file_health ADD COLUMN priority INTEGER NOT NULL DEFAULT 0;
+
+-- Index for priority-ordered scheduling queries
+CREATE INDEX IF NOT EXISTS idx_file_health_priority_scheduled
+    ON file_health(priority DESC, scheduled_check_at)
+    WHERE scheduled_check_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_file_health_priority_scheduled;
+ALTER TABLE file_health DROP COLUMN priority;
+-- +goose StatementEnd
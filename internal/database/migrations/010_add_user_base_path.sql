@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Restrict a user's WebDAV view to a subtree of the library (NULL means the whole tree)
+ALTER TABLE users ADD COLUMN base_path TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN base_path;
+-- +goose StatementEnd
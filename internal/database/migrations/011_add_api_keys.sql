@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Named API keys a user can create for each client and revoke on their own, in addition
+-- to the key stored on the user
+CREATE TABLE api_keys (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id TEXT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    name TEXT NOT NULL,                     -- Label chosen by the user, e.g. the client using the key
+    api_key TEXT UNIQUE NOT NULL,
+    expires_at DATETIME,                    -- Key is rejected after this time, NULL never expires
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE(user_id, name)
+);
+
+CREATE INDEX idx_api_keys_user_id ON api_keys(user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_api_keys_user_id;
+DROP TABLE IF EXISTS api_keys;
+-- +goose StatementEnd
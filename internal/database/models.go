@@ -65,13 +65,23 @@ const (
 	HealthStatusHealthy         HealthStatus = "healthy"          // File passed health check
 	HealthStatusRepairTriggered HealthStatus = "repair_triggered" // File repair has been triggered in Arrs
 	HealthStatusCorrupted       HealthStatus = "corrupted"        // File has missing segments or is corrupted
+	HealthStatusSkipped         HealthStatus = "skipped"          // Check skipped because no providers were available
+	HealthStatusUnmanaged       HealthStatus = "unmanaged"        // Corrupted, but its ARR item lacks the configured tags so no repair was triggered
+)
+
+// HealthPriority orders files that are due for a health check; higher values are checked first
+type HealthPriority int
+
+const (
+	HealthPriorityNormal HealthPriority = 0 // Routine scheduled check
+	HealthPriorityHigh   HealthPriority = 1 // Manually requested check
 )
 
 // FileHealth represents the health tracking of files in the filesystem
 type FileHealth struct {
 	ID               int64        `db:"id"`
 	FilePath         string       `db:"file_path"`
-	LibraryPath      *string      `db:"library_path"`      // Path to file in library directory (symlink or .strm file)
+	LibraryPath      *string      `db:"library_path"` // Path to file in library directory (symlink or .strm file)
 	Status           HealthStatus `db:"status"`
 	LastChecked      time.Time    `db:"last_checked"`
 	LastError        *string      `db:"last_error"`
@@ -84,8 +94,9 @@ type FileHealth struct {
 	CreatedAt        time.Time    `db:"created_at"`
 	UpdatedAt        time.Time    `db:"updated_at"`
 	// Health check scheduling fields
-	ReleaseDate      *time.Time `db:"release_date"`       // Cached from metadata for scheduling
-	ScheduledCheckAt *time.Time `db:"scheduled_check_at"` // Next check time
+	ReleaseDate      *time.Time     `db:"release_date"`       // Cached from metadata for scheduling
+	ScheduledCheckAt *time.Time     `db:"scheduled_check_at"` // Next check time
+	Priority         HealthPriority `db:"priority"`           // Check ordering, higher first
 }
 
 // User represents a user account in the system
@@ -103,13 +114,30 @@ type User struct {
 	CreatedAt    time.Time  `db:"created_at"`    // Account creation timestamp
 	UpdatedAt    time.Time  `db:"updated_at"`    // Last profile update timestamp
 	LastLogin    *time.Time `db:"last_login"`    // Last login timestamp (nullable)
+	BasePath     *string    `db:"base_path"`     // WebDAV and stream root the user is restricted to (nullable)
+}
+
+// APIKey is a named API key of a user. A user can hold several, one per client, and
+// revoke each of them without affecting the others.
+type APIKey struct {
+	ID        int64      `db:"id"`
+	UserID    string     `db:"user_id"`    // Owner of the key
+	Name      string     `db:"name"`       // Label chosen by the user, unique per user
+	Key       string     `db:"api_key"`    // The key itself
+	ExpiresAt *time.Time `db:"expires_at"` // Key is rejected after this time (nullable, never expires)
+	CreatedAt time.Time  `db:"created_at"` // Creation timestamp
+}
+
+// IsExpired reports whether the key has expired at now
+func (k *APIKey) IsExpired(now time.Time) bool {
+	return k.ExpiresAt != nil && !now.Before(*k.ExpiresAt)
 }
 
 // MediaFile represents a media file tracked by scrapers
 type MediaFile struct {
 	ID           int64     `db:"id"`
 	InstanceName string    `db:"instance_name"` // Name from configuration
-	InstanceType string    `db:"instance_type"` // "radarr" or "sonarr"
+	InstanceType string    `db:"instance_type"` // "radarr", "sonarr", "lidarr" or "readarr"
 	ExternalID   int64     `db:"external_id"`   // Movie ID or Episode ID from API
 	FileID       int64     `db:"file_id"`       // Movie File ID or Episode File ID from API (nullable)
 	FilePath     string    `db:"file_path"`     // Full file path
@@ -93,8 +93,30 @@ func (r *QueueRepository) IsFileInQueue(ctx context.Context, filePath string) (b
 	return true, nil
 }
 
+// CountActiveQueueItems returns the number of queue items that are pending or processing
+func (r *QueueRepository) CountActiveQueueItems(ctx context.Context) (int, error) {
+	query := `SELECT COUNT(*) FROM import_queue WHERE status IN ('pending', 'processing')`
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count active queue items: %w", err)
+	}
+
+	return count, nil
+}
+
 // ClaimNextQueueItem atomically claims and returns the next available queue item
 func (r *QueueRepository) ClaimNextQueueItem(ctx context.Context) (*ImportQueueItem, error) {
+	return r.claimNextQueueItem(ctx, false)
+}
+
+// ClaimNextHighPriorityQueueItem atomically claims and returns the next available high priority queue item
+func (r *QueueRepository) ClaimNextHighPriorityQueueItem(ctx context.Context) (*ImportQueueItem, error) {
+	return r.claimNextQueueItem(ctx, true)
+}
+
+// claimNextQueueItem atomically claims the next available queue item, optionally only high priority ones
+func (r *QueueRepository) claimNextQueueItem(ctx context.Context, highPriorityOnly bool) (*ImportQueueItem, error) {
 	// Use immediate transaction to atomically claim an item
 	var claimedItem *ImportQueueItem
 
@@ -107,8 +129,18 @@ func (r *QueueRepository) ClaimNextQueueItem(ctx context.Context) (*ImportQueueI
 			ORDER BY priority ASC, created_at ASC
 			LIMIT 1
 		`
+		args := []any{}
+		if highPriorityOnly {
+			selectQuery = `
+				SELECT id FROM import_queue
+				WHERE status = 'pending' AND priority <= ?
+				ORDER BY priority ASC, created_at ASC
+				LIMIT 1
+			`
+			args = append(args, QueuePriorityHigh)
+		}
 
-		err := txRepo.db.QueryRowContext(ctx, selectQuery).Scan(&itemID)
+		err := txRepo.db.QueryRowContext(ctx, selectQuery, args...).Scan(&itemID)
 		if err != nil {
 			if err == sql.ErrNoRows {
 				// No items available
@@ -315,6 +347,39 @@ func (r *QueueRepository) GetQueueItem(ctx context.Context, id int64) (*ImportQu
 	return &item, nil
 }
 
+// GetActiveQueueItems returns all pending and processing queue items in processing order
+func (r *QueueRepository) GetActiveQueueItems(ctx context.Context) ([]*ImportQueueItem, error) {
+	query := `
+		SELECT id, nzb_path, relative_path, category, priority, status, created_at, updated_at,
+		       started_at, completed_at, retry_count, max_retries, error_message, batch_id, metadata, file_size, storage_path
+		FROM import_queue
+		WHERE status IN ('pending', 'processing')
+		ORDER BY priority ASC, created_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active queue items: %w", err)
+	}
+	defer rows.Close()
+
+	var items []*ImportQueueItem
+	for rows.Next() {
+		var item ImportQueueItem
+		err := rows.Scan(
+			&item.ID, &item.NzbPath, &item.RelativePath, &item.Category, &item.Priority, &item.Status,
+			&item.CreatedAt, &item.UpdatedAt, &item.StartedAt, &item.CompletedAt,
+			&item.RetryCount, &item.MaxRetries, &item.ErrorMessage, &item.BatchID, &item.Metadata, &item.FileSize, &item.StoragePath,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan queue item: %w", err)
+		}
+		items = append(items, &item)
+	}
+
+	return items, rows.Err()
+}
+
 // withQueueTransaction executes a function within a queue database transaction
 func (r *QueueRepository) withQueueTransaction(ctx context.Context, fn func(*QueueRepository) error) error {
 	// Cast to *sql.DB to access BeginTx method
@@ -5,8 +5,18 @@ import (
 	"crypto/rand"
 	"database/sql"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+var (
+	// ErrAPIKeyNameTaken is returned when a user already has a named API key with the same name
+	ErrAPIKeyNameTaken = errors.New("an API key with this name already exists")
+	// ErrAPIKeyNotFound is returned when a named API key does not exist or belongs to another user
+	ErrAPIKeyNotFound = errors.New("API key not found")
 )
 
 // UserRepository handles user database operations
@@ -23,7 +33,7 @@ func NewUserRepository(db *sql.DB) *UserRepository {
 func (r *UserRepository) GetUserByID(ctx context.Context, userID string) (*User, error) {
 	query := `
 		SELECT id, user_id, email, name, avatar_url, provider, provider_id,
-		       password_hash, api_key, is_admin, created_at, updated_at, last_login
+		       password_hash, api_key, is_admin, created_at, updated_at, last_login, base_path
 		FROM users
 		WHERE user_id = ?
 	`
@@ -32,7 +42,7 @@ func (r *UserRepository) GetUserByID(ctx context.Context, userID string) (*User,
 	err := r.db.QueryRowContext(ctx, query, userID).Scan(
 		&user.ID, &user.UserID, &user.Email, &user.Name, &user.AvatarURL,
 		&user.Provider, &user.ProviderID, &user.PasswordHash, &user.APIKey, &user.IsAdmin,
-		&user.CreatedAt, &user.UpdatedAt, &user.LastLogin,
+		&user.CreatedAt, &user.UpdatedAt, &user.LastLogin, &user.BasePath,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -48,7 +58,7 @@ func (r *UserRepository) GetUserByID(ctx context.Context, userID string) (*User,
 func (r *UserRepository) GetUserByProvider(ctx context.Context, provider, providerID string) (*User, error) {
 	query := `
 		SELECT id, user_id, email, name, avatar_url, provider, provider_id,
-		       password_hash, api_key, is_admin, created_at, updated_at, last_login
+		       password_hash, api_key, is_admin, created_at, updated_at, last_login, base_path
 		FROM users
 		WHERE provider = ? AND provider_id = ?
 	`
@@ -57,7 +67,7 @@ func (r *UserRepository) GetUserByProvider(ctx context.Context, provider, provid
 	err := r.db.QueryRowContext(ctx, query, provider, providerID).Scan(
 		&user.ID, &user.UserID, &user.Email, &user.Name, &user.AvatarURL,
 		&user.Provider, &user.ProviderID, &user.PasswordHash, &user.APIKey, &user.IsAdmin,
-		&user.CreatedAt, &user.UpdatedAt, &user.LastLogin,
+		&user.CreatedAt, &user.UpdatedAt, &user.LastLogin, &user.BasePath,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -171,11 +181,36 @@ func (r *UserRepository) SetAdminStatus(ctx context.Context, userID string, isAd
 	return nil
 }
 
+// SetBasePath restricts a user's WebDAV view and streams to basePath. A nil basePath removes the restriction.
+func (r *UserRepository) SetBasePath(ctx context.Context, userID string, basePath *string) error {
+	query := `
+		UPDATE users
+		SET base_path = ?, updated_at = datetime('now')
+		WHERE user_id = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, query, basePath, userID)
+	if err != nil {
+		return fmt.Errorf("failed to set base path: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("user not found: %s", userID)
+	}
+
+	return nil
+}
+
 // ListUsers returns a list of all users with pagination
 func (r *UserRepository) ListUsers(ctx context.Context, limit, offset int) ([]*User, error) {
 	query := `
 		SELECT id, user_id, email, name, avatar_url, provider, provider_id,
-		       password_hash, api_key, is_admin, created_at, updated_at, last_login
+		       password_hash, api_key, is_admin, created_at, updated_at, last_login, base_path
 		FROM users
 		ORDER BY created_at DESC
 		LIMIT ? OFFSET ?
@@ -193,7 +228,7 @@ func (r *UserRepository) ListUsers(ctx context.Context, limit, offset int) ([]*U
 		err := rows.Scan(
 			&user.ID, &user.UserID, &user.Email, &user.Name, &user.AvatarURL,
 			&user.Provider, &user.ProviderID, &user.PasswordHash, &user.APIKey, &user.IsAdmin,
-			&user.CreatedAt, &user.UpdatedAt, &user.LastLogin,
+			&user.CreatedAt, &user.UpdatedAt, &user.LastLogin, &user.BasePath,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan user: %w", err)
@@ -223,6 +258,12 @@ func (r *UserRepository) GetUserCount(ctx context.Context) (int, error) {
 
 // DeleteUser deletes a user by their user ID
 func (r *UserRepository) DeleteUser(ctx context.Context, userID string) error {
+	// Foreign keys are not enforced on every pooled connection, so the named API keys are
+	// removed explicitly rather than relying on ON DELETE CASCADE
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM api_keys WHERE user_id = ?`, userID); err != nil {
+		return fmt.Errorf("failed to delete user API keys: %w", err)
+	}
+
 	query := `DELETE FROM users WHERE user_id = ?`
 
 	result, err := r.db.ExecContext(ctx, query, userID)
@@ -246,7 +287,7 @@ func (r *UserRepository) DeleteUser(ctx context.Context, userID string) error {
 func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (*User, error) {
 	query := `
 		SELECT id, user_id, email, name, avatar_url, provider, provider_id,
-		       password_hash, api_key, is_admin, created_at, updated_at, last_login
+		       password_hash, api_key, is_admin, created_at, updated_at, last_login, base_path
 		FROM users
 		WHERE email = ? AND provider = 'direct'
 	`
@@ -255,7 +296,7 @@ func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (*Use
 	err := r.db.QueryRowContext(ctx, query, email).Scan(
 		&user.ID, &user.UserID, &user.Email, &user.Name, &user.AvatarURL,
 		&user.Provider, &user.ProviderID, &user.PasswordHash, &user.APIKey, &user.IsAdmin,
-		&user.CreatedAt, &user.UpdatedAt, &user.LastLogin,
+		&user.CreatedAt, &user.UpdatedAt, &user.LastLogin, &user.BasePath,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -271,7 +312,7 @@ func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (*Use
 func (r *UserRepository) GetUserByUsername(ctx context.Context, username string) (*User, error) {
 	query := `
 		SELECT id, user_id, email, name, avatar_url, provider, provider_id,
-		       password_hash, api_key, is_admin, created_at, updated_at, last_login
+		       password_hash, api_key, is_admin, created_at, updated_at, last_login, base_path
 		FROM users
 		WHERE user_id = ? AND provider = 'direct'
 	`
@@ -280,7 +321,7 @@ func (r *UserRepository) GetUserByUsername(ctx context.Context, username string)
 	err := r.db.QueryRowContext(ctx, query, username).Scan(
 		&user.ID, &user.UserID, &user.Email, &user.Name, &user.AvatarURL,
 		&user.Provider, &user.ProviderID, &user.PasswordHash, &user.APIKey, &user.IsAdmin,
-		&user.CreatedAt, &user.UpdatedAt, &user.LastLogin,
+		&user.CreatedAt, &user.UpdatedAt, &user.LastLogin, &user.BasePath,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -362,11 +403,12 @@ func (r *UserRepository) RegenerateAPIKey(ctx context.Context, userID string) (s
 	return apiKey, nil
 }
 
-// GetUserByAPIKey retrieves a user by their API key
+// GetUserByAPIKey retrieves a user by their API key or one of their named API keys.
+// Expired named keys match no user.
 func (r *UserRepository) GetUserByAPIKey(ctx context.Context, apiKey string) (*User, error) {
 	query := `
 		SELECT id, user_id, email, name, avatar_url, provider, provider_id,
-		       password_hash, api_key, is_admin, created_at, updated_at, last_login
+		       password_hash, api_key, is_admin, created_at, updated_at, last_login, base_path
 		FROM users
 		WHERE api_key = ?
 	`
@@ -375,23 +417,176 @@ func (r *UserRepository) GetUserByAPIKey(ctx context.Context, apiKey string) (*U
 	err := r.db.QueryRowContext(ctx, query, apiKey).Scan(
 		&user.ID, &user.UserID, &user.Email, &user.Name, &user.AvatarURL,
 		&user.Provider, &user.ProviderID, &user.PasswordHash, &user.APIKey, &user.IsAdmin,
-		&user.CreatedAt, &user.UpdatedAt, &user.LastLogin,
+		&user.CreatedAt, &user.UpdatedAt, &user.LastLogin, &user.BasePath,
+	)
+	if err == nil {
+		return &user, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to get user by API key: %w", err)
+	}
+
+	key, err := r.getAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil || key.IsExpired(time.Now()) {
+		return nil, nil
+	}
+
+	return r.GetUserByID(ctx, key.UserID)
+}
+
+// getAPIKey retrieves a named API key by its value, expired or not
+func (r *UserRepository) getAPIKey(ctx context.Context, apiKey string) (*APIKey, error) {
+	query := `
+		SELECT id, user_id, name, api_key, expires_at, created_at
+		FROM api_keys
+		WHERE api_key = ?
+	`
+
+	var key APIKey
+	err := r.db.QueryRowContext(ctx, query, apiKey).Scan(
+		&key.ID, &key.UserID, &key.Name, &key.Key, &key.ExpiresAt, &key.CreatedAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
-		return nil, fmt.Errorf("failed to get user by API key: %w", err)
+		return nil, fmt.Errorf("failed to get API key: %w", err)
 	}
 
-	return &user, nil
+	return &key, nil
+}
+
+// CreateAPIKey generates a named API key for a user. A nil expiresAt creates a key that
+// never expires. It returns ErrAPIKeyNameTaken when the user already has a key with that name.
+func (r *UserRepository) CreateAPIKey(ctx context.Context, userID, name string, expiresAt *time.Time) (*APIKey, error) {
+	apiKey, err := r.generateAPIKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate API key: %w", err)
+	}
+
+	if expiresAt != nil {
+		utc := expiresAt.UTC()
+		expiresAt = &utc
+	}
+
+	query := `
+		INSERT INTO api_keys (user_id, name, api_key, expires_at)
+		VALUES (?, ?, ?, ?)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, userID, name, apiKey, expiresAt)
+	if err != nil {
+		var sqliteErr sqlite3.Error
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return nil, fmt.Errorf("%w: %s", ErrAPIKeyNameTaken, name)
+		}
+		return nil, fmt.Errorf("failed to create API key: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API key ID: %w", err)
+	}
+
+	return &APIKey{
+		ID:        id,
+		UserID:    userID,
+		Name:      name,
+		Key:       apiKey,
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// ListAPIKeys returns the named API keys of a user, including expired ones, oldest first
+func (r *UserRepository) ListAPIKeys(ctx context.Context, userID string) ([]*APIKey, error) {
+	query := `
+		SELECT id, user_id, name, api_key, expires_at, created_at
+		FROM api_keys
+		WHERE user_id = ?
+		ORDER BY created_at, id
+	`
+
+	return r.queryAPIKeys(ctx, query, userID)
+}
+
+// GetActiveAPIKeys returns the named API keys of all users that have not expired
+func (r *UserRepository) GetActiveAPIKeys(ctx context.Context) ([]*APIKey, error) {
+	query := `
+		SELECT id, user_id, name, api_key, expires_at, created_at
+		FROM api_keys
+		ORDER BY created_at, id
+	`
+
+	keys, err := r.queryAPIKeys(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	active := keys[:0]
+	for _, key := range keys {
+		if !key.IsExpired(now) {
+			active = append(active, key)
+		}
+	}
+
+	return active, nil
+}
+
+// queryAPIKeys runs a query selecting api_keys rows
+func (r *UserRepository) queryAPIKeys(ctx context.Context, query string, args ...any) ([]*APIKey, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []*APIKey
+	for rows.Next() {
+		var key APIKey
+		if err := rows.Scan(&key.ID, &key.UserID, &key.Name, &key.Key, &key.ExpiresAt, &key.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan API key: %w", err)
+		}
+		keys = append(keys, &key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate API keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+// DeleteAPIKey revokes a named API key of a user
+func (r *UserRepository) DeleteAPIKey(ctx context.Context, userID string, id int64) error {
+	query := `DELETE FROM api_keys WHERE id = ? AND user_id = ?`
+
+	result, err := r.db.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete API key: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%w: %d", ErrAPIKeyNotFound, id)
+	}
+
+	return nil
 }
 
 // GetAllUsers retrieves all users with API keys for authentication purposes
 func (r *UserRepository) GetAllUsers(ctx context.Context) ([]*User, error) {
 	query := `
 		SELECT id, user_id, email, name, avatar_url, provider, provider_id,
-		       password_hash, api_key, is_admin, created_at, updated_at, last_login
+		       password_hash, api_key, is_admin, created_at, updated_at, last_login, base_path
 		FROM users
 		WHERE api_key IS NOT NULL AND api_key != ''
 		ORDER BY created_at
@@ -409,7 +604,7 @@ func (r *UserRepository) GetAllUsers(ctx context.Context) ([]*User, error) {
 		err := rows.Scan(
 			&user.ID, &user.UserID, &user.Email, &user.Name, &user.AvatarURL,
 			&user.Provider, &user.ProviderID, &user.PasswordHash, &user.APIKey, &user.IsAdmin,
-			&user.CreatedAt, &user.UpdatedAt, &user.LastLogin,
+			&user.CreatedAt, &user.UpdatedAt, &user.LastLogin, &user.BasePath,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan user: %w", err)
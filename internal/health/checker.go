@@ -12,6 +12,7 @@ import (
 	"github.com/javi11/altmount/internal/metadata"
 	metapb "github.com/javi11/altmount/internal/metadata/proto"
 	"github.com/javi11/altmount/internal/pool"
+	"github.com/javi11/altmount/internal/progress"
 	"github.com/javi11/altmount/internal/usenet"
 	"github.com/javi11/altmount/pkg/rclonecli"
 )
@@ -90,16 +91,31 @@ func (hc *HealthChecker) getMaxConnectionsForHealthChecks() int {
 	return connections
 }
 
-func (hc *HealthChecker) getSegmentSamplePercentage() int {
-	percentage := hc.configGetter().Health.SegmentSamplePercentage
+// getSegmentSamplePercentage returns the percentage of segments to check for a file,
+// taking the sample tier matching its age into account
+func (hc *HealthChecker) getSegmentSamplePercentage(fileMeta *metapb.FileMetadata) int {
+	percentage := hc.configGetter().Health.SamplePercentageForAge(fileAge(fileMeta, time.Now()))
 	if percentage < 1 || percentage > 100 {
 		return 5 // Default: 5%
 	}
 	return percentage
 }
 
-// CheckFile checks the health of a specific file
-func (hc *HealthChecker) CheckFile(ctx context.Context, filePath string) HealthEvent {
+// fileAge returns how long ago a file was released, falling back to when it was imported
+func fileAge(fileMeta *metapb.FileMetadata, now time.Time) time.Duration {
+	released := fileMeta.ReleaseDate
+	if released == 0 {
+		released = fileMeta.CreatedAt
+	}
+	if released == 0 {
+		return 0
+	}
+	return now.Sub(time.Unix(released, 0))
+}
+
+// CheckFile checks the health of a specific file.
+// The optional progressTracker receives the number of segments validated so far.
+func (hc *HealthChecker) CheckFile(ctx context.Context, filePath string, progressTracker progress.ProgressTracker) HealthEvent {
 	// Get file metadata
 	fileMeta, err := hc.metadataService.ReadFileMetadata(filePath)
 	if err != nil {
@@ -125,11 +141,11 @@ func (hc *HealthChecker) CheckFile(ctx context.Context, filePath string) HealthE
 	}
 
 	// Perform the health check
-	return hc.checkSingleFile(ctx, filePath, fileMeta)
+	return hc.checkSingleFile(ctx, filePath, fileMeta, progressTracker)
 }
 
 // checkSingleFile performs a health check on a single file
-func (hc *HealthChecker) checkSingleFile(ctx context.Context, filePath string, fileMeta *metapb.FileMetadata) HealthEvent {
+func (hc *HealthChecker) checkSingleFile(ctx context.Context, filePath string, fileMeta *metapb.FileMetadata, progressTracker progress.ProgressTracker) HealthEvent {
 	event := HealthEvent{
 		FilePath:  filePath,
 		Timestamp: time.Now(),
@@ -143,7 +159,8 @@ func (hc *HealthChecker) checkSingleFile(ctx context.Context, filePath string, f
 		return event
 	}
 
-	slog.InfoContext(ctx, "Checking segment availability", "file_path", filePath, "total_segments", len(fileMeta.SegmentData), "sample_percentage", hc.getSegmentSamplePercentage())
+	samplePercentage := hc.getSegmentSamplePercentage(fileMeta)
+	slog.InfoContext(ctx, "Checking segment availability", "file_path", filePath, "total_segments", len(fileMeta.SegmentData), "sample_percentage", samplePercentage)
 
 	// Validate segment availability using shared validation logic
 	checkErr := usenet.ValidateSegmentAvailability(
@@ -151,8 +168,9 @@ func (hc *HealthChecker) checkSingleFile(ctx context.Context, filePath string, f
 		fileMeta.SegmentData,
 		hc.poolManager,
 		hc.getMaxConnectionsForHealthChecks(),
-		hc.getSegmentSamplePercentage(),
-		nil, // No progress callback for health checks
+		samplePercentage,
+		config.SampleStrategyPercentage,
+		progressTracker,
 	)
 
 	if checkErr != nil {
@@ -201,6 +219,6 @@ func (hc *HealthChecker) notifyRcloneVFS(filePath string, event HealthEvent) {
 }
 
 // GetHealthStats returns current health statistics
-func (hc *HealthChecker) GetHealthStats(ctx context.Context) (map[database.HealthStatus]int, error) {
-	return hc.healthRepo.GetHealthStats(ctx)
+func (hc *HealthChecker) GetHealthStats(ctx context.Context) (map[database.HealthStatus]int64, error) {
+	return hc.healthRepo.CountByStatus(ctx)
 }
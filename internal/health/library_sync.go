@@ -16,11 +16,17 @@ import (
 
 	"github.com/javi11/altmount/internal/config"
 	"github.com/javi11/altmount/internal/database"
+	"github.com/javi11/altmount/internal/maintenance"
 	"github.com/javi11/altmount/internal/metadata"
+	"github.com/javi11/altmount/internal/slogutil"
 	"github.com/javi11/altmount/pkg/rclonecli"
 	"github.com/sourcegraph/conc/pool"
 )
 
+// orphanedMetadataMinAge keeps the metadata of recent imports from being cleaned up before
+// their library symlinks or STRM files are created
+const orphanedMetadataMinAge = time.Hour
+
 // SyncProgress tracks the progress of an ongoing library sync
 type SyncProgress struct {
 	TotalFiles     int       `json:"total_files"`
@@ -73,6 +79,7 @@ type LibrarySyncWorker struct {
 	lastSyncResult  *SyncResult
 	manualTrigger   chan struct{}
 	rcloneClient    rclonecli.RcloneRcClient
+	maintenance     *maintenance.State // Scheduled syncs are skipped while maintenance mode is on
 }
 
 // NewLibrarySyncWorker creates a new library sync worker
@@ -93,6 +100,12 @@ func NewLibrarySyncWorker(
 	}
 }
 
+// SetMaintenanceState makes the worker skip its scheduled syncs while maintenance mode is
+// on. Manually triggered syncs still run.
+func (lsw *LibrarySyncWorker) SetMaintenanceState(state *maintenance.State) {
+	lsw.maintenance = state
+}
+
 // StartLibrarySync starts the library sync worker in a background goroutine
 func (lsw *LibrarySyncWorker) StartLibrarySync(ctx context.Context) {
 	lsw.mu.Lock()
@@ -104,7 +117,7 @@ func (lsw *LibrarySyncWorker) StartLibrarySync(ctx context.Context) {
 	}
 
 	// Create cancellable context
-	ctx, cancel := context.WithCancel(ctx)
+	ctx, cancel := context.WithCancel(slogutil.With(ctx, slogutil.ComponentKey, "library-sync"))
 	lsw.cancelFunc = cancel
 	lsw.running = true
 
@@ -220,6 +233,10 @@ func (lsw *LibrarySyncWorker) run(ctx context.Context) {
 			slog.InfoContext(ctx, "Library sync worker stopped by context")
 			return
 		case <-ticker.C:
+			if lsw.maintenance.Enabled() {
+				slog.DebugContext(ctx, "Skipping library sync - maintenance mode is on")
+				continue
+			}
 			lsw.SyncLibrary(ctx, false)
 		case <-lsw.manualTrigger:
 			slog.InfoContext(ctx, "Manual library sync trigger received")
@@ -425,8 +442,8 @@ func (lsw *LibrarySyncWorker) SyncLibrary(ctx context.Context, dryRun bool) *Dry
 			"new_mount", newMountPath)
 	}
 
-	// Check import strategy - if NONE, only sync DB with metadata files
-	if cfg.Import.ImportStrategy == config.ImportStrategyNone {
+	// Check import strategy - if NONE everywhere, only sync DB with metadata files
+	if !usesImportStrategy(cfg) {
 		slog.InfoContext(ctx, "Import strategy is NONE, performing metadata-only sync")
 		return lsw.syncMetadataOnly(ctx, startTime, dryRun)
 	}
@@ -607,28 +624,23 @@ func (lsw *LibrarySyncWorker) SyncLibrary(ctx context.Context, dryRun bool) *Dry
 	// Additional cleanup of orphaned metadata files if enabled
 	metadataDeletedCount := 0
 	if cfg.Health.CleanupOrphanedFiles != nil && *cfg.Health.CleanupOrphanedFiles {
-		// We already have libraryFiles from earlier in the function
-		for relativeMountPath := range metaFileSet {
-			select {
-			case <-ctx.Done():
+		gc, err := lsw.metadataService.GarbageCollect(ctx, func(virtualPath string) bool {
+			return lsw.getLibraryPath(strings.TrimPrefix(virtualPath, "/"), filesInUse) != nil
+		}, metadata.GCOptions{MinAge: orphanedMetadataMinAge, DryRun: dryRun})
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
 				return nil
-			default:
-			}
-
-			libraryPath := lsw.getLibraryPath(relativeMountPath, filesInUse)
-
-			if libraryPath == nil {
-				if !dryRun {
-					err := lsw.metadataService.DeleteFileMetadata(relativeMountPath)
-					if err != nil {
-						slog.ErrorContext(ctx, "Failed to delete metadata file", "error", err)
-						continue
-					}
-				}
-				metadataDeletedCount++
 			}
+			slog.ErrorContext(ctx, "Failed to clean up orphaned metadata", "error", err)
 		}
+		metadataDeletedCount = gc.Reclaimed
 
+		if gc.Reclaimed > 0 {
+			slog.InfoContext(ctx, "Orphaned metadata cleaned up",
+				"files", gc.Reclaimed,
+				"bytes", gc.ReclaimedBytes,
+				"dry_run", dryRun)
+		}
 	}
 
 	// Cleanup orphaned library files (symlinks and STRM files without metadata)
@@ -885,35 +897,75 @@ func (lsw *LibrarySyncWorker) getAllLibraryFiles(ctx context.Context, oldMountPa
 	return result, symlinkUpdates, nil
 }
 
-// getAllImportDirFiles collects both regular files and .strm files from import directory in a single pass
+// getAllImportDirFiles collects both regular files and .strm files from the import directories
+// (the global one and any per-category overrides) in a single pass each
 func (lsw *LibrarySyncWorker) getAllImportDirFiles(ctx context.Context, oldMountPath, newMountPath string) (*UsedFiles, int, error) {
 	cfg := lsw.configGetter()
 
-	// Get import directory
-	if cfg.Import.ImportDir == nil || *cfg.Import.ImportDir == "" {
-		// No import directory configured - return empty result
-		return &UsedFiles{
-			Symlinks:  make(map[string]string),
-			StrmFiles: make(map[string]string),
-		}, 0, nil
+	result := &UsedFiles{
+		Symlinks:  make(map[string]string),
+		StrmFiles: make(map[string]string),
+	}
+
+	symlinkUpdates := 0
+	for _, importDir := range importDirectories(cfg) {
+		// Check if directory exists
+		if _, err := os.Stat(importDir); os.IsNotExist(err) {
+			slog.WarnContext(ctx, "Import directory does not exist", "import_dir", importDir)
+			continue
+		}
+
+		updated, err := lsw.walkImportDir(ctx, importDir, result, oldMountPath, newMountPath)
+		if err != nil {
+			return nil, 0, err
+		}
+		symlinkUpdates += updated
 	}
 
-	importDir := *cfg.Import.ImportDir
+	return result, symlinkUpdates, nil
+}
 
-	// Check if directory exists
-	if _, err := os.Stat(importDir); os.IsNotExist(err) {
-		slog.WarnContext(ctx, "Import directory does not exist", "import_dir", importDir)
-		return &UsedFiles{
-			Symlinks:  make(map[string]string),
-			StrmFiles: make(map[string]string),
-		}, 0, nil
+// usesImportStrategy reports whether the global import config or any category override
+// creates symlinks or STRM files
+func usesImportStrategy(cfg *config.Config) bool {
+	if cfg.Import.ImportStrategy != config.ImportStrategyNone {
+		return true
+	}
+	for _, category := range cfg.SABnzbd.Categories {
+		if category.ImportStrategy != "" && category.ImportStrategy != config.ImportStrategyNone {
+			return true
+		}
 	}
+	return cfg.Import.HasExtensionStrategy(config.ImportStrategySYMLINK) || cfg.Import.HasExtensionStrategy(config.ImportStrategySTRM)
+}
 
-	result := &UsedFiles{
-		Symlinks:  make(map[string]string),
-		StrmFiles: make(map[string]string),
+// importDirectories returns the distinct import directories used by the global import
+// config and per-category overrides
+func importDirectories(cfg *config.Config) []string {
+	var dirs []string
+	seen := make(map[string]bool)
+
+	add := func(dir *string) {
+		if dir == nil || *dir == "" || seen[*dir] {
+			return
+		}
+		seen[*dir] = true
+		dirs = append(dirs, *dir)
+	}
+
+	add(cfg.Import.ImportDir)
+	for _, category := range cfg.SABnzbd.Categories {
+		add(category.ImportDir)
 	}
 
+	return dirs
+}
+
+// walkImportDir adds the symlinks and .strm files under importDir to result, updating
+// symlinks that still point at the old mount path. It returns the number of symlinks updated.
+func (lsw *LibrarySyncWorker) walkImportDir(ctx context.Context, importDir string, result *UsedFiles, oldMountPath, newMountPath string) (int, error) {
+	cfg := lsw.configGetter()
+
 	symlinkUpdates := 0
 	shouldUpdateSymlinks := oldMountPath != "" && newMountPath != "" && oldMountPath != newMountPath
 	oldMountPathClean := filepath.Clean(oldMountPath)
@@ -1025,11 +1077,11 @@ func (lsw *LibrarySyncWorker) getAllImportDirFiles(ctx context.Context, oldMount
 	})
 
 	if err != nil {
-		slog.ErrorContext(ctx, "Error during import directory file scan", "error", err)
-		return nil, 0, err
+		slog.ErrorContext(ctx, "Error during import directory file scan", "import_dir", importDir, "error", err)
+		return 0, err
 	}
 
-	return result, symlinkUpdates, nil
+	return symlinkUpdates, nil
 }
 
 // getLibraryPath looks up the library path for a given mount relative path
@@ -34,3 +34,19 @@ func calculateNextCheck(releaseDate, lastCheck time.Time) time.Time {
 	// NextCheck = ReleaseDate + 2 * (LastCheck - ReleaseDate)
 	return releaseDate.Add(2 * timeSinceRelease)
 }
+
+// calculateRetryBackoff calculates how long to wait before retrying a failed health check
+// Uses the exponential backoff formula: Delay = Base * 3^RetryCount
+// capped at maxDelay (e.g. 5m, 15m, 45m, 1h with the defaults)
+func calculateRetryBackoff(retryCount int, base, maxDelay time.Duration) time.Duration {
+	delay := base
+	for i := 0; i < retryCount && delay < maxDelay; i++ {
+		delay *= 3
+	}
+
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+
+	return delay
+}
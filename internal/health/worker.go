@@ -5,17 +5,30 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"path/filepath"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/javi11/altmount/internal/arrs"
 	"github.com/javi11/altmount/internal/config"
 	"github.com/javi11/altmount/internal/database"
+	"github.com/javi11/altmount/internal/maintenance"
 	"github.com/javi11/altmount/internal/metadata"
 	metapb "github.com/javi11/altmount/internal/metadata/proto"
+	"github.com/javi11/altmount/internal/slogutil"
+	"github.com/javi11/altmount/internal/webhook"
 	"github.com/sourcegraph/conc"
 )
 
+// backgroundCheckShutdownTimeout bounds how long Stop waits for cancelled background checks
+const backgroundCheckShutdownTimeout = 30 * time.Second
+
+// readFailureWindow is how long further read failures of a file are ignored once one was
+// reported, so a player retrying a broken file does not start a check for every attempt
+const readFailureWindow = 10 * time.Minute
+
 // WorkerStatus represents the current status of the health worker
 type WorkerStatus string
 
@@ -28,20 +41,49 @@ const (
 
 // WorkerStats represents statistics about the health worker
 type WorkerStats struct {
-	Status                 WorkerStatus `json:"status"`
-	LastRunTime            *time.Time   `json:"last_run_time,omitempty"`
-	NextRunTime            *time.Time   `json:"next_run_time,omitempty"`
-	TotalRunsCompleted     int64        `json:"total_runs_completed"`
-	TotalFilesChecked      int64        `json:"total_files_checked"`
-	TotalFilesHealthy      int64        `json:"total_files_healthy"`
-	TotalFilesCorrupted    int64        `json:"total_files_corrupted"`
-	CurrentRunStartTime    *time.Time   `json:"current_run_start_time,omitempty"`
-	CurrentRunFilesChecked int          `json:"current_run_files_checked"`
-	PendingManualChecks    int          `json:"pending_manual_checks"`
-	LastError              *string      `json:"last_error,omitempty"`
-	ErrorCount             int64        `json:"error_count"`
+	Status                 WorkerStatus  `json:"status"`
+	LastRunTime            *time.Time    `json:"last_run_time,omitempty"`
+	NextRunTime            *time.Time    `json:"next_run_time,omitempty"`
+	TotalRunsCompleted     int64         `json:"total_runs_completed"`
+	TotalFilesChecked      int64         `json:"total_files_checked"`
+	TotalFilesHealthy      int64         `json:"total_files_healthy"`
+	TotalFilesCorrupted    int64         `json:"total_files_corrupted"`
+	CurrentRunStartTime    *time.Time    `json:"current_run_start_time,omitempty"`
+	CurrentRunFilesChecked int           `json:"current_run_files_checked"`
+	PendingManualChecks    int           `json:"pending_manual_checks"`
+	LastError              *string       `json:"last_error,omitempty"`
+	ErrorCount             int64         `json:"error_count"`
+	ActiveChecks           []ActiveCheck `json:"active_checks,omitempty"`
 }
 
+// ActiveCheck describes a health check that is currently in progress
+type ActiveCheck struct {
+	FilePath        string    `json:"file_path"`
+	StartedAt       time.Time `json:"started_at"` // When the check started, or began waiting for a slot
+	Waiting         bool      `json:"waiting"`    // Waiting for a free check slot
+	SegmentsChecked int       `json:"segments_checked"`
+	TotalSegments   int       `json:"total_segments"`
+}
+
+// activeCheck tracks a running check. It implements progress.ProgressTracker so the
+// segment validation can report how far the check has progressed.
+type activeCheck struct {
+	cancel          context.CancelFunc
+	startedAt       time.Time // Guarded by activeChecksMu
+	waiting         atomic.Bool
+	segmentsChecked atomic.Int64
+	totalSegments   atomic.Int64
+}
+
+// Update records the number of segments validated so far
+func (a *activeCheck) Update(current, total int) {
+	a.segmentsChecked.Store(int64(current))
+	a.totalSegments.Store(int64(total))
+}
+
+// UpdateAbsolute is a no-op; health checks report progress in segments
+func (a *activeCheck) UpdateAbsolute(percentage int) {}
+
 // HealthWorker manages continuous health monitoring and manual check requests
 type HealthWorker struct {
 	healthChecker   *HealthChecker
@@ -49,6 +91,7 @@ type HealthWorker struct {
 	metadataService *metadata.MetadataService
 	arrsService     *arrs.Service
 	configGetter    config.ConfigGetter
+	webhookClient   *webhook.Client
 
 	// Worker state
 	status       WorkerStatus
@@ -58,10 +101,32 @@ type HealthWorker struct {
 	wg           sync.WaitGroup
 	mu           sync.RWMutex
 
+	// Checks started by PerformBackgroundCheck, cancelled and awaited on Stop
+	backgroundCtx    context.Context
+	backgroundCancel context.CancelFunc
+	backgroundWg     sync.WaitGroup
+
 	// Active checks tracking for cancellation
-	activeChecks   map[string]context.CancelFunc // filePath -> cancel function
+	activeChecks   map[string]*activeCheck // filePath -> running check
 	activeChecksMu sync.RWMutex
 
+	// Bounds concurrent checks across cycles and background checks
+	checkSlots *checkLimiter
+
+	// Set when skipped files may exist and must be re-queued once providers are available
+	requeueSkipped atomic.Bool
+
+	// Scheduled cycles are skipped while maintenance mode is on
+	maintenance *maintenance.State
+
+	// ARR rescans per library directory, used to batch the repairs of a series or season
+	repairBatches   map[string]*repairBatch
+	repairBatchesMu sync.Mutex
+
+	// Last reported read failure per file, used to deduplicate checks requested while streaming
+	readFailures   map[string]time.Time
+	readFailuresMu sync.Mutex
+
 	// Statistics
 	stats   WorkerStats
 	statsMu sync.RWMutex
@@ -81,15 +146,25 @@ func NewHealthWorker(
 		metadataService: metadataService,
 		arrsService:     arrsService,
 		configGetter:    configGetter,
+		webhookClient:   webhook.NewClient(),
 		status:          WorkerStatusStopped,
 		stopChan:        make(chan struct{}),
-		activeChecks:    make(map[string]context.CancelFunc),
+		activeChecks:    make(map[string]*activeCheck),
+		checkSlots:      newCheckLimiter(),
+		repairBatches:   make(map[string]*repairBatch),
+		readFailures:    make(map[string]time.Time),
 		stats: WorkerStats{
 			Status: WorkerStatusStopped,
 		},
 	}
 }
 
+// SetMaintenanceState makes the worker skip its scheduled cycles while maintenance mode is
+// on. Checks requested explicitly, e.g. through the API, still run.
+func (hw *HealthWorker) SetMaintenanceState(state *maintenance.State) {
+	hw.maintenance = state
+}
+
 // Start begins the health worker service
 func (hw *HealthWorker) Start(ctx context.Context) error {
 	hw.mu.Lock()
@@ -98,6 +173,7 @@ func (hw *HealthWorker) Start(ctx context.Context) error {
 	if hw.running {
 		return fmt.Errorf("health worker already running")
 	}
+	ctx = slogutil.With(ctx, slogutil.ComponentKey, "health")
 	hw.running = true
 	hw.status = WorkerStatusStarting
 	hw.updateStats(func(s *WorkerStats) {
@@ -111,6 +187,16 @@ func (hw *HealthWorker) Start(ctx context.Context) error {
 		// Don't fail startup for this - just log and continue
 	}
 
+	// Files skipped during a previous run are re-queued on the first cycle with providers
+	hw.requeueSkipped.Store(true)
+
+	hw.backgroundCtx, hw.backgroundCancel = context.WithCancel(slogutil.With(context.Background(), slogutil.ComponentKey, "health"))
+
+	// Batches dropped by a previous Stop are notified again by the repair cycle
+	hw.repairBatchesMu.Lock()
+	hw.repairBatches = make(map[string]*repairBatch)
+	hw.repairBatchesMu.Unlock()
+
 	// Start the main worker goroutine
 	hw.wg.Add(1)
 	go func() {
@@ -123,7 +209,7 @@ func (hw *HealthWorker) Start(ctx context.Context) error {
 		s.Status = WorkerStatusRunning
 	})
 
-	slog.InfoContext(ctx, "Health worker started successfully", "check_interval", hw.getCheckInterval(), "max_concurrent_jobs", 1)
+	slog.InfoContext(ctx, "Health worker started successfully", "check_interval", hw.getCheckInterval(), "max_concurrent_jobs", hw.getMaxConcurrentChecks())
 	return nil
 }
 
@@ -145,8 +231,12 @@ func (hw *HealthWorker) Stop(ctx context.Context) error {
 	close(hw.stopChan)
 	hw.running = false
 
+	// Cancel background checks so they release their NNTP connections
+	hw.backgroundCancel()
+
 	// Wait for all goroutines to finish
 	hw.wg.Wait()
+	hw.waitForBackgroundChecks(ctx)
 
 	hw.status = WorkerStatusStopped
 	hw.updateStats(func(s *WorkerStats) {
@@ -159,6 +249,27 @@ func (hw *HealthWorker) Stop(ctx context.Context) error {
 	return nil
 }
 
+// waitForBackgroundChecks waits for cancelled background checks to return, giving up after
+// backgroundCheckShutdownTimeout or when ctx is done
+func (hw *HealthWorker) waitForBackgroundChecks(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		hw.backgroundWg.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(backgroundCheckShutdownTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+	case <-timer.C:
+		slog.WarnContext(ctx, "Timed out waiting for background health checks to stop")
+	case <-ctx.Done():
+		slog.WarnContext(ctx, "Stopped waiting for background health checks", "error", ctx.Err())
+	}
+}
+
 // IsRunning returns whether the health worker is currently running
 func (hw *HealthWorker) IsRunning() bool {
 	hw.mu.RLock()
@@ -176,26 +287,54 @@ func (hw *HealthWorker) GetStatus() WorkerStatus {
 // GetStats returns current worker statistics
 func (hw *HealthWorker) GetStats() WorkerStats {
 	hw.statsMu.RLock()
-	defer hw.statsMu.RUnlock()
-
 	stats := hw.stats
+	hw.statsMu.RUnlock()
+
 	stats.PendingManualChecks = 0 // No manual queue anymore
+	stats.ActiveChecks = hw.getActiveChecks()
 
 	return stats
 }
 
+// getActiveChecks returns a snapshot of the checks in progress, oldest first
+func (hw *HealthWorker) getActiveChecks() []ActiveCheck {
+	hw.activeChecksMu.RLock()
+	defer hw.activeChecksMu.RUnlock()
+
+	if len(hw.activeChecks) == 0 {
+		return nil
+	}
+
+	checks := make([]ActiveCheck, 0, len(hw.activeChecks))
+	for filePath, check := range hw.activeChecks {
+		checks = append(checks, ActiveCheck{
+			FilePath:        filePath,
+			StartedAt:       check.startedAt,
+			Waiting:         check.waiting.Load(),
+			SegmentsChecked: int(check.segmentsChecked.Load()),
+			TotalSegments:   int(check.totalSegments.Load()),
+		})
+	}
+
+	sort.Slice(checks, func(i, j int) bool {
+		return checks[i].StartedAt.Before(checks[j].StartedAt)
+	})
+
+	return checks
+}
+
 // CancelHealthCheck cancels an active health check for the specified file
 func (hw *HealthWorker) CancelHealthCheck(ctx context.Context, filePath string) error {
 	hw.activeChecksMu.Lock()
 	defer hw.activeChecksMu.Unlock()
 
-	cancelFunc, exists := hw.activeChecks[filePath]
+	check, exists := hw.activeChecks[filePath]
 	if !exists {
 		return fmt.Errorf("no active health check found for file: %s", filePath)
 	}
 
 	// Cancel the context
-	cancelFunc()
+	check.cancel()
 
 	// Remove from active checks
 	delete(hw.activeChecks, filePath)
@@ -227,6 +366,17 @@ func (hw *HealthWorker) IsCycleRunning() bool {
 	return hw.cycleRunning
 }
 
+// IsBusy reports whether a health check cycle or individual health checks are running
+func (hw *HealthWorker) IsBusy() bool {
+	if hw.IsCycleRunning() {
+		return true
+	}
+
+	hw.activeChecksMu.RLock()
+	defer hw.activeChecksMu.RUnlock()
+	return len(hw.activeChecks) > 0
+}
+
 // run is the main worker loop
 func (hw *HealthWorker) run(ctx context.Context) {
 	ticker := time.NewTicker(hw.getCheckInterval())
@@ -251,6 +401,11 @@ func (hw *HealthWorker) run(ctx context.Context) {
 				continue
 			}
 
+			if hw.maintenance.Enabled() {
+				slog.DebugContext(ctx, "Skipping health check cycle - maintenance mode is on")
+				continue
+			}
+
 			if err := hw.runHealthCheckCycle(ctx); err != nil {
 				slog.ErrorContext(ctx, "Health check cycle failed", "error", err)
 				hw.updateStats(func(s *WorkerStats) {
@@ -263,8 +418,9 @@ func (hw *HealthWorker) run(ctx context.Context) {
 	}
 }
 
-// AddToHealthCheck adds a file to the health check list with pending status
-func (hw *HealthWorker) AddToHealthCheck(ctx context.Context, filePath string, sourceNzb *string) error {
+// AddToHealthCheck adds a file to the health check list with pending status.
+// Files queued with database.HealthPriorityHigh are checked before the scheduled backlog.
+func (hw *HealthWorker) AddToHealthCheck(ctx context.Context, filePath string, sourceNzb *string, priority database.HealthPriority) error {
 	// Check if file already exists in health database
 	existingHealth, err := hw.healthRepo.GetFileHealth(ctx, filePath)
 	if err != nil {
@@ -304,25 +460,112 @@ func (hw *HealthWorker) AddToHealthCheck(ctx context.Context, filePath string, s
 		}
 	}
 
+	if err := hw.healthRepo.ScheduleCheck(ctx, filePath, priority); err != nil {
+		return fmt.Errorf("failed to schedule health check: %w", err)
+	}
+
 	return nil
 }
 
+// ReportReadFailure starts a check of a file that failed to read while streaming, so a
+// corrupted file is repaired without waiting for its next scheduled check. Failures of a file
+// already reported within the last readFailureWindow are ignored. It returns false when the
+// worker is not running and the report could not be taken.
+func (hw *HealthWorker) ReportReadFailure(ctx context.Context, filePath string, sourceNzb *string) bool {
+	if !hw.IsRunning() {
+		return false
+	}
+
+	if !hw.claimReadFailure(filePath) || hw.IsCheckActive(filePath) {
+		return true
+	}
+
+	ctx = slogutil.With(ctx, slogutil.ComponentKey, "health")
+
+	slog.InfoContext(ctx, "Read failure while streaming, checking file", "file_path", filePath)
+
+	// Queue the check first so it still runs on the next cycle if it cannot start now
+	if err := hw.AddToHealthCheck(ctx, filePath, sourceNzb, database.HealthPriorityHigh); err != nil {
+		slog.ErrorContext(ctx, "Failed to queue health check after read failure", "file_path", filePath, "error", err)
+		hw.releaseReadFailure(filePath)
+		return false
+	}
+
+	// In maintenance mode the queued check waits for the first cycle after it ends
+	if hw.maintenance.Enabled() {
+		return true
+	}
+
+	if err := hw.healthRepo.SetFileChecking(ctx, filePath); err != nil {
+		slog.WarnContext(ctx, "Failed to set checking status after read failure", "file_path", filePath, "error", err)
+		return true
+	}
+
+	if err := hw.PerformBackgroundCheck(ctx, filePath); err != nil {
+		slog.WarnContext(ctx, "Failed to start health check after read failure", "file_path", filePath, "error", err)
+	}
+
+	return true
+}
+
+// claimReadFailure records a read failure of filePath and reports whether a check should start.
+// It returns false while the file is still within the read failure window.
+func (hw *HealthWorker) claimReadFailure(filePath string) bool {
+	hw.readFailuresMu.Lock()
+	defer hw.readFailuresMu.Unlock()
+
+	now := time.Now()
+	if last, ok := hw.readFailures[filePath]; ok && now.Sub(last) < readFailureWindow {
+		return false
+	}
+
+	// Drop expired entries so the map does not grow with every file that ever failed
+	for path, last := range hw.readFailures {
+		if now.Sub(last) >= readFailureWindow {
+			delete(hw.readFailures, path)
+		}
+	}
+
+	hw.readFailures[filePath] = now
+	return true
+}
+
+// releaseReadFailure forgets the last read failure of filePath after the check could not be queued
+func (hw *HealthWorker) releaseReadFailure(filePath string) {
+	hw.readFailuresMu.Lock()
+	defer hw.readFailuresMu.Unlock()
+
+	delete(hw.readFailures, filePath)
+}
+
 // PerformBackgroundCheck starts a health check in background and returns immediately
 func (hw *HealthWorker) PerformBackgroundCheck(ctx context.Context, filePath string) error {
-	if !hw.IsRunning() {
+	// Register the check while holding the lock so Stop cannot miss it
+	hw.mu.RLock()
+	if !hw.running {
+		hw.mu.RUnlock()
 		return fmt.Errorf("health worker is not running")
 	}
+	backgroundCtx := hw.backgroundCtx
+	hw.backgroundWg.Add(1)
+	hw.mu.RUnlock()
 
 	// Start health check in background
 	go func() {
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
-		defer cancel()
+		defer hw.backgroundWg.Done()
 
-		checkErr := hw.performDirectCheck(ctx, filePath)
+		// performDirectCheck bounds the check by the check timeout once it gets a slot
+		checkErr := hw.performDirectCheck(backgroundCtx, filePath)
 		if checkErr != nil {
-			if errors.Is(checkErr, context.DeadlineExceeded) {
-				slog.ErrorContext(ctx, "Background health check timed out after 10 minutes", "file_path", filePath)
-			} else {
+			// The check context may be done; the status update below must still run
+			ctx := context.WithoutCancel(backgroundCtx)
+
+			switch {
+			case errors.Is(checkErr, context.DeadlineExceeded):
+				slog.ErrorContext(ctx, "Background health check timed out", "file_path", filePath, "timeout", hw.getCheckTimeout())
+			case errors.Is(checkErr, context.Canceled):
+				slog.InfoContext(ctx, "Background health check cancelled", "file_path", filePath)
+			default:
 				slog.ErrorContext(ctx, "Background health check failed", "file_path", filePath, "error", checkErr)
 			}
 
@@ -347,13 +590,25 @@ func (hw *HealthWorker) PerformBackgroundCheck(ctx context.Context, filePath str
 
 // performDirectCheck performs a health check on a single file using the HealthChecker
 func (hw *HealthWorker) performDirectCheck(ctx context.Context, filePath string) error {
+	// Without providers the check can only fail, so skip it instead of counting a retry
+	if !hw.hasEnabledProviders() {
+		if err := hw.healthRepo.SetFileSkipped(ctx, filePath); err != nil {
+			return fmt.Errorf("failed to mark file as skipped: %w", err)
+		}
+		hw.requeueSkipped.Store(true)
+		slog.WarnContext(ctx, "Health check skipped, no providers available", "file_path", filePath)
+		return nil
+	}
+
 	// Create cancellable context for this check
 	checkCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	// Track active check
+	// Track the check before it waits for a slot, so it can be seen and cancelled while queued
+	check := &activeCheck{cancel: cancel, startedAt: time.Now()}
+	check.waiting.Store(true)
 	hw.activeChecksMu.Lock()
-	hw.activeChecks[filePath] = cancel
+	hw.activeChecks[filePath] = check
 	hw.activeChecksMu.Unlock()
 
 	// Ensure cleanup on exit
@@ -363,6 +618,21 @@ func (hw *HealthWorker) performDirectCheck(ctx context.Context, filePath string)
 		hw.activeChecksMu.Unlock()
 	}()
 
+	// Wait for a free check slot so checks never exceed the health connection budget
+	if err := hw.checkSlots.acquire(checkCtx, hw.getMaxConcurrentChecks()); err != nil {
+		return err
+	}
+	defer hw.checkSlots.release()
+
+	// The configured check timeout only counts the check itself, not the wait for a slot
+	checkCtx, cancelTimeout := context.WithTimeout(checkCtx, hw.getCheckTimeout())
+	defer cancelTimeout()
+
+	hw.activeChecksMu.Lock()
+	check.startedAt = time.Now()
+	hw.activeChecksMu.Unlock()
+	check.waiting.Store(false)
+
 	// Check if already cancelled
 	select {
 	case <-checkCtx.Done():
@@ -371,7 +641,7 @@ func (hw *HealthWorker) performDirectCheck(ctx context.Context, filePath string)
 	}
 
 	// Delegate to HealthChecker
-	event := hw.healthChecker.CheckFile(checkCtx, filePath)
+	event := hw.healthChecker.CheckFile(checkCtx, filePath, check)
 
 	// Check if cancelled during check
 	select {
@@ -405,6 +675,10 @@ func (hw *HealthWorker) performDirectCheck(ctx context.Context, filePath string)
 
 // handleHealthCheckResult handles the result of a health check
 func (hw *HealthWorker) handleHealthCheckResult(ctx context.Context, event HealthEvent) error {
+	if hw.isDryRun() {
+		return hw.recordDryRunResult(ctx, event)
+	}
+
 	switch event.Type {
 	case EventTypeFileHealthy:
 		// File is now healthy - update metadata
@@ -486,6 +760,7 @@ func (hw *HealthWorker) handleHealthCheckResult(ctx context.Context, event Healt
 					return fmt.Errorf("failed to mark file as corrupted: %w", err)
 				}
 				slog.ErrorContext(ctx, "File permanently marked as corrupted after repair retries exhausted", "file_path", event.FilePath)
+				hw.notifyCorruption(ctx, fileHealth, errorMsg)
 			} else {
 				slog.InfoContext(ctx, "Repair retry scheduled",
 					"file_path", event.FilePath,
@@ -504,13 +779,22 @@ func (hw *HealthWorker) handleHealthCheckResult(ctx context.Context, event Healt
 				slog.ErrorContext(ctx, "Health check failed", "file_path", event.FilePath, "error", event.Error)
 			}
 
-			// Increment health check retry count
-			if err := hw.healthRepo.IncrementRetryCount(ctx, event.FilePath, errorMsg); err != nil {
+			// Increment health check retry count and back off before the next attempt
+			nextCheck := time.Now().Add(calculateRetryBackoff(fileHealth.RetryCount, hw.getRetryBackoffBase(), hw.getRetryBackoffMax()))
+			if err := hw.healthRepo.IncrementRetryCount(ctx, event.FilePath, errorMsg, nextCheck); err != nil {
 				slog.ErrorContext(ctx, "Failed to increment retry count", "file_path", event.FilePath, "error", err)
 				return fmt.Errorf("failed to increment retry count: %w", err)
 			}
 
-			if fileHealth.RetryCount >= fileHealth.MaxRetries-1 {
+			if fileHealth.RetryCount >= fileHealth.MaxRetries-1 && !hw.isAutoRepairEnabled() {
+				// Max health check retries reached and repair is disabled - leave the file for manual review
+				if err := hw.healthRepo.MarkAsCorrupted(ctx, event.FilePath, errorMsg); err != nil {
+					slog.ErrorContext(ctx, "Failed to mark file as corrupted", "error", err)
+					return fmt.Errorf("failed to mark file as corrupted: %w", err)
+				}
+				slog.WarnContext(ctx, "Health check retries exhausted, file marked as corrupted (auto repair disabled)", "file_path", event.FilePath)
+				hw.notifyCorruption(ctx, fileHealth, errorMsg)
+			} else if fileHealth.RetryCount >= fileHealth.MaxRetries-1 {
 				// Max health check retries reached - trigger repair phase
 				if err := hw.triggerFileRepair(ctx, event.FilePath, errorMsg); err != nil {
 					slog.ErrorContext(ctx, "Failed to trigger repair", "error", err)
@@ -521,7 +805,8 @@ func (hw *HealthWorker) handleHealthCheckResult(ctx context.Context, event Healt
 				slog.InfoContext(ctx, "Health check retry scheduled",
 					"file_path", event.FilePath,
 					"retry_count", fileHealth.RetryCount+1,
-					"max_retries", fileHealth.MaxRetries)
+					"max_retries", fileHealth.MaxRetries,
+					"next_check", nextCheck)
 			}
 		}
 	}
@@ -529,6 +814,111 @@ func (hw *HealthWorker) handleHealthCheckResult(ctx context.Context, event Healt
 	return nil
 }
 
+// CorruptionEvent is the payload sent to the corruption webhook
+type CorruptionEvent struct {
+	Event            string    `json:"event"` // Always "file.corrupted"
+	FilePath         string    `json:"file_path"`
+	LibraryPath      string    `json:"library_path,omitempty"`
+	SourceNZB        string    `json:"source_nzb,omitempty"`
+	Error            string    `json:"error,omitempty"`
+	RetryCount       int       `json:"retry_count"`
+	MaxRetries       int       `json:"max_retries"`
+	RepairRetryCount int       `json:"repair_retry_count"`
+	MaxRepairRetries int       `json:"max_repair_retries"`
+	Timestamp        time.Time `json:"timestamp"`
+}
+
+// notifyCorruption sends a CorruptionEvent for a file that was just marked corrupted when a
+// webhook URL is configured. fileHealth is the record read before the final failure was
+// recorded, it is re-read so the event reports the final retry counts.
+func (hw *HealthWorker) notifyCorruption(ctx context.Context, fileHealth *database.FileHealth, errorMsg *string) {
+	webhookURL := hw.configGetter().Health.CorruptionWebhookURL
+	if webhookURL == "" {
+		return
+	}
+
+	if updated, err := hw.healthRepo.GetFileHealth(ctx, fileHealth.FilePath); err != nil {
+		slog.WarnContext(ctx, "Failed to reload file health record for corruption webhook", "file_path", fileHealth.FilePath, "error", err)
+	} else if updated != nil {
+		fileHealth = updated
+	}
+
+	event := CorruptionEvent{
+		Event:            "file.corrupted",
+		FilePath:         fileHealth.FilePath,
+		RetryCount:       fileHealth.RetryCount,
+		MaxRetries:       fileHealth.MaxRetries,
+		RepairRetryCount: fileHealth.RepairRetryCount,
+		MaxRepairRetries: fileHealth.MaxRepairRetries,
+		Timestamp:        time.Now().UTC(),
+	}
+	if fileHealth.LibraryPath != nil {
+		event.LibraryPath = *fileHealth.LibraryPath
+	}
+	if fileHealth.SourceNzbPath != nil {
+		event.SourceNZB = *fileHealth.SourceNzbPath
+	}
+	if errorMsg != nil {
+		event.Error = *errorMsg
+	} else if fileHealth.LastError != nil {
+		event.Error = *fileHealth.LastError
+	}
+
+	hw.webhookClient.SendAsync(ctx, webhookURL, "", event)
+}
+
+// recordDryRunResult stores the observed status of a checked file without changing its metadata
+// or triggering repairs, logging the action that would have been taken instead
+func (hw *HealthWorker) recordDryRunResult(ctx context.Context, event HealthEvent) error {
+	var status database.HealthStatus
+	switch event.Type {
+	case EventTypeFileHealthy:
+		status = database.HealthStatusHealthy
+		slog.InfoContext(ctx, "Dry run: file is healthy, would mark metadata as healthy", "file_path", event.FilePath)
+	case EventTypeFileCorrupted, EventTypeCheckFailed:
+		status = database.HealthStatusCorrupted
+		action := "trigger ARR repair"
+		if !hw.isAutoRepairEnabled() {
+			action = "mark as corrupted"
+		}
+		slog.WarnContext(ctx, "Dry run: file is corrupted, would retry and then "+action,
+			"file_path", event.FilePath,
+			"error", event.Error)
+	default:
+		return nil
+	}
+
+	fileHealth, err := hw.healthRepo.GetFileHealth(ctx, event.FilePath)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get file health record", "file_path", event.FilePath, "error", err)
+		return fmt.Errorf("failed to get file health record: %w", err)
+	}
+	if fileHealth == nil {
+		slog.WarnContext(ctx, "File health record not found", "file_path", event.FilePath)
+		return fmt.Errorf("file health record not found for file: %s", event.FilePath)
+	}
+
+	var errorMsg *string
+	if event.Error != nil {
+		errorText := event.Error.Error()
+		errorMsg = &errorText
+	}
+
+	releaseDate := fileHealth.ReleaseDate
+	if releaseDate == nil {
+		releaseDate = &fileHealth.CreatedAt
+	}
+
+	// Follow the regular schedule so the file is not checked again on every cycle
+	nextCheck := calculateNextCheck(*releaseDate, time.Now())
+	if err := hw.healthRepo.RecordObservedStatus(ctx, event.FilePath, status, errorMsg, nextCheck); err != nil {
+		slog.ErrorContext(ctx, "Failed to record observed status", "file_path", event.FilePath, "error", err)
+		return fmt.Errorf("failed to record observed status: %w", err)
+	}
+
+	return nil
+}
+
 // processRepairNotification processes a file that needs repair notification to ARRs
 func (hw *HealthWorker) processRepairNotification(ctx context.Context, fileHealth *database.FileHealth) error {
 	// Check if context is cancelled
@@ -587,18 +977,54 @@ func (hw *HealthWorker) runHealthCheckCycle(ctx context.Context) error {
 		s.CurrentRunFilesChecked = 0
 	})
 
+	// Short-circuit the cycle when there is no provider to check against
+	if !hw.hasEnabledProviders() {
+		skipped, err := hw.healthRepo.SkipDueFiles(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to skip due files: %w", err)
+		}
+		if skipped > 0 {
+			hw.requeueSkipped.Store(true)
+			slog.WarnContext(ctx, "No providers available, skipped due health checks", "skipped_files", skipped)
+		}
+
+		hw.updateStats(func(s *WorkerStats) {
+			s.CurrentRunStartTime = nil
+			s.TotalRunsCompleted++
+			s.LastRunTime = &now
+			nextRun := now.Add(hw.getCheckInterval())
+			s.NextRunTime = &nextRun
+		})
+		return nil
+	}
+
+	// Providers are available again - put skipped files back in the queue
+	if hw.requeueSkipped.Swap(false) {
+		requeued, err := hw.healthRepo.RequeueSkippedFiles(ctx)
+		if err != nil {
+			hw.requeueSkipped.Store(true)
+			return fmt.Errorf("failed to requeue skipped files: %w", err)
+		}
+		if requeued > 0 {
+			slog.InfoContext(ctx, "Providers available, re-queued skipped health checks", "requeued_files", requeued)
+		}
+	}
+
+	maxConcurrent := hw.getMaxConcurrentChecks()
+
 	// Get files due for checking (ordered by scheduled_check_at)
-	// Hardcoded to 1 - process one file at a time
-	unhealthyFiles, err := hw.healthRepo.GetUnhealthyFiles(ctx, 1)
+	unhealthyFiles, err := hw.healthRepo.GetUnhealthyFiles(ctx, maxConcurrent)
 	if err != nil {
 		return fmt.Errorf("failed to get unhealthy files: %w", err)
 	}
 
-	// Get files that need repair notifications
-	// Hardcoded to 1 - process one file at a time
-	repairFiles, err := hw.healthRepo.GetFilesForRepairNotification(ctx, 1)
-	if err != nil {
-		return fmt.Errorf("failed to get files for repair notification: %w", err)
+	// Get files that need repair notifications (none when auto repair is disabled or in dry run)
+	var repairFiles []*database.FileHealth
+	if hw.isAutoRepairEnabled() && !hw.isDryRun() {
+		repairFiles, err = hw.healthRepo.GetFilesForRepairNotification(ctx, maxConcurrent)
+		if err != nil {
+			return fmt.Errorf("failed to get files for repair notification: %w", err)
+		}
 	}
 
 	totalFiles := len(unhealthyFiles) + len(repairFiles)
@@ -614,11 +1040,12 @@ func (hw *HealthWorker) runHealthCheckCycle(ctx context.Context) error {
 		return nil
 	}
 
+	// Files come back ordered by priority, so manually requested checks are started first
 	slog.InfoContext(ctx, "Found files to process",
 		"health_check_files", len(unhealthyFiles),
 		"repair_notification_files", len(repairFiles),
 		"total", totalFiles,
-		"max_concurrent_jobs", 1)
+		"max_concurrent_jobs", maxConcurrent)
 
 	// Process files in parallel using conc
 	wg := conc.NewWaitGroup()
@@ -704,6 +1131,81 @@ func (hw *HealthWorker) getCheckInterval() time.Duration {
 	return time.Duration(intervalSeconds) * time.Second
 }
 
+// isAutoRepairEnabled reports whether corrupted files should be sent to the ARRs for repair
+func (hw *HealthWorker) isAutoRepairEnabled() bool {
+	enabled := hw.configGetter().Health.AutoRepairEnabled
+	return enabled == nil || *enabled
+}
+
+// isDryRun reports whether check results should only be recorded, without changing metadata
+// or triggering repairs
+func (hw *HealthWorker) isDryRun() bool {
+	dryRun := hw.configGetter().Health.DryRun
+	return dryRun != nil && *dryRun
+}
+
+// hasEnabledProviders reports whether at least one NNTP provider is enabled
+func (hw *HealthWorker) hasEnabledProviders() bool {
+	for _, provider := range hw.configGetter().Providers {
+		if provider.Enabled == nil || *provider.Enabled {
+			return true
+		}
+	}
+	return false
+}
+
+func (hw *HealthWorker) getRetryBackoffBase() time.Duration {
+	seconds := hw.configGetter().Health.RetryBackoffBaseSeconds
+	if seconds <= 0 {
+		return 5 * time.Minute // Default
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func (hw *HealthWorker) getRetryBackoffMax() time.Duration {
+	seconds := hw.configGetter().Health.RetryBackoffMaxSeconds
+	if seconds <= 0 {
+		return time.Hour // Default
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// getRepairCooldown returns the minimum time between rescans of the same library directory.
+// Zero disables coalescing.
+func (hw *HealthWorker) getRepairCooldown() time.Duration {
+	seconds := hw.configGetter().Health.RepairCooldownSeconds
+	if seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func (hw *HealthWorker) getCheckTimeout() time.Duration {
+	timeoutSeconds := hw.configGetter().Health.CheckTimeoutSeconds
+	if timeoutSeconds <= 0 {
+		return 10 * time.Minute // Default
+	}
+	return time.Duration(timeoutSeconds) * time.Second
+}
+
+// getMaxConcurrentChecks returns how many files may be checked at once. Each check uses up to
+// max_connections_for_health_checks connections, so the job count never exceeds that budget.
+func (hw *HealthWorker) getMaxConcurrentChecks() int {
+	cfg := hw.configGetter()
+
+	jobs := cfg.Health.MaxConcurrentJobs
+	if jobs <= 0 {
+		jobs = 1 // Default
+	}
+
+	connections := cfg.Health.MaxConnectionsForHealthChecks
+	if connections <= 0 {
+		connections = 5 // Default
+	}
+
+	return min(jobs, connections)
+}
+
 // triggerFileRepair handles the business logic for triggering repair of a corrupted file
 // It directly queries ARR APIs to find which instance manages the file and triggers repair
 func (hw *HealthWorker) triggerFileRepair(ctx context.Context, filePath string, errorMsg *string) error {
@@ -725,23 +1227,236 @@ func (hw *HealthWorker) triggerFileRepair(ctx context.Context, filePath string,
 		return fmt.Errorf("no library path found for file: %s, trigger a manual library sync to fix this", filePath)
 	}
 
+	libraryPath := *healthRecord.LibraryPath
+
+	// Repair notifications carry no error, keep the one that started the repair
+	if errorMsg == nil {
+		errorMsg = healthRecord.LastError
+	}
+
+	// Coalesce the repairs of a library directory, e.g. the episodes of a corrupted season,
+	// so the ARR instance gets one search per cooldown instead of one per file. Files reported
+	// within the cooldown are rescanned together once it ends.
+	if hw.queueRepair(libraryPath, filePath) {
+		slog.InfoContext(ctx, "Batching ARR rescan with other files of the same library directory",
+			"file_path", filePath,
+			"library_path", libraryPath,
+			"cooldown", hw.getRepairCooldown())
+
+		return hw.healthRepo.SetRepairTriggered(ctx, filePath, errorMsg)
+	}
+
 	// Step 4: Trigger rescan through the ARR service
-	err = hw.arrsService.TriggerFileRescan(ctx, *healthRecord.LibraryPath)
+	err = hw.arrsService.TriggerFileRescan(ctx, libraryPath)
 	if err != nil {
-		slog.ErrorContext(ctx, "Failed to trigger ARR rescan",
-			"file_path", filePath,
-			"library_path", *healthRecord.LibraryPath,
-			"error", err)
+		// Let the next attempt try again instead of waiting out the cooldown
+		hw.releaseRepairTrigger(libraryPath)
 
-		// If we can't trigger repair, mark as corrupted for manual investigation
-		errMsg := err.Error()
-		return hw.healthRepo.SetCorrupted(ctx, filePath, &errMsg)
+		return hw.handleRepairTriggerError(ctx, filePath, libraryPath, err)
 	}
 
-	// ARR rescan was triggered successfully - set repair triggered status
 	slog.InfoContext(ctx, "Successfully triggered ARR rescan for file repair",
 		"file_path", filePath,
-		"library_path", *healthRecord.LibraryPath)
+		"library_path", libraryPath)
 
-	return nil
+	// ARR rescan was triggered successfully - set repair triggered status
+	return hw.healthRepo.SetRepairTriggered(ctx, filePath, errorMsg)
+}
+
+// handleRepairTriggerError records the outcome of a failed ARR rescan of a file
+func (hw *HealthWorker) handleRepairTriggerError(ctx context.Context, filePath, libraryPath string, err error) error {
+	errMsg := err.Error()
+
+	// Items without the instance's tags are left alone, marked apart from files that
+	// could not be repaired
+	if errors.Is(err, arrs.ErrItemNotTagged) {
+		slog.InfoContext(ctx, "ARR item is not managed by AltMount, leaving repair to the user",
+			"file_path", filePath,
+			"library_path", libraryPath,
+			"reason", err)
+
+		return hw.healthRepo.SetUnmanaged(ctx, filePath, &errMsg)
+	}
+
+	slog.ErrorContext(ctx, "Failed to trigger ARR rescan",
+		"file_path", filePath,
+		"library_path", libraryPath,
+		"error", err)
+
+	// If we can't trigger repair, mark as corrupted for manual investigation
+	return hw.healthRepo.SetCorrupted(ctx, filePath, &errMsg)
+}
+
+// repairBatch tracks the ARR rescans of one library directory within the repair cooldown
+type repairBatch struct {
+	lastTrigger time.Time
+	triggered   map[string]bool   // Library paths rescanned at lastTrigger
+	pending     map[string]string // Library path -> file path, rescanned when the cooldown ends
+}
+
+// queueRepair reports whether the rescan of libraryPath is coalesced with other rescans of
+// its directory. It returns false when the rescan must be triggered right away, which is
+// then recorded as the directory's last rescan. Otherwise the file was either rescanned
+// within the cooldown already or is added to the batch rescanned when the cooldown ends.
+func (hw *HealthWorker) queueRepair(libraryPath, filePath string) bool {
+	cooldown := hw.getRepairCooldown()
+	if cooldown <= 0 {
+		return false
+	}
+
+	hw.repairBatchesMu.Lock()
+	defer hw.repairBatchesMu.Unlock()
+
+	now := time.Now()
+	dir := filepath.Dir(libraryPath)
+	batch, ok := hw.repairBatches[dir]
+	if !ok || (len(batch.pending) == 0 && now.Sub(batch.lastTrigger) >= cooldown) {
+		// Drop expired batches so the map does not grow with every directory ever repaired
+		for d, b := range hw.repairBatches {
+			if len(b.pending) == 0 && now.Sub(b.lastTrigger) >= cooldown {
+				delete(hw.repairBatches, d)
+			}
+		}
+
+		hw.repairBatches[dir] = &repairBatch{
+			lastTrigger: now,
+			triggered:   map[string]bool{libraryPath: true},
+			pending:     make(map[string]string),
+		}
+		return false
+	}
+
+	if batch.triggered[libraryPath] {
+		return true
+	}
+
+	if len(batch.pending) == 0 {
+		hw.scheduleRepairBatch(dir, batch.lastTrigger.Add(cooldown).Sub(now))
+	}
+	batch.pending[libraryPath] = filePath
+
+	return true
+}
+
+// releaseRepairTrigger forgets the last rescan of libraryPath after a failed trigger
+func (hw *HealthWorker) releaseRepairTrigger(libraryPath string) {
+	hw.repairBatchesMu.Lock()
+	defer hw.repairBatchesMu.Unlock()
+
+	dir := filepath.Dir(libraryPath)
+	if batch, ok := hw.repairBatches[dir]; ok && len(batch.pending) == 0 {
+		delete(hw.repairBatches, dir)
+	}
+}
+
+// scheduleRepairBatch rescans the pending files of dir after wait. Batches still waiting
+// when the worker stops are dropped; their files stay repair_triggered and are notified
+// again by the next repair cycle.
+func (hw *HealthWorker) scheduleRepairBatch(dir string, wait time.Duration) {
+	hw.mu.RLock()
+	if !hw.running {
+		hw.mu.RUnlock()
+		return
+	}
+	backgroundCtx := hw.backgroundCtx
+	hw.backgroundWg.Add(1)
+	hw.mu.RUnlock()
+
+	go func() {
+		defer hw.backgroundWg.Done()
+
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+			hw.flushRepairBatch(backgroundCtx, dir)
+		case <-backgroundCtx.Done():
+		}
+	}()
+}
+
+// flushRepairBatch triggers one ARR rescan for the pending files of dir
+func (hw *HealthWorker) flushRepairBatch(ctx context.Context, dir string) {
+	hw.repairBatchesMu.Lock()
+	batch, ok := hw.repairBatches[dir]
+	if !ok || len(batch.pending) == 0 {
+		hw.repairBatchesMu.Unlock()
+		return
+	}
+
+	pending := batch.pending
+	batch.lastTrigger = time.Now()
+	batch.triggered = make(map[string]bool, len(pending))
+	batch.pending = make(map[string]string)
+	libraryPaths := make([]string, 0, len(pending))
+	for libraryPath := range pending {
+		batch.triggered[libraryPath] = true
+		libraryPaths = append(libraryPaths, libraryPath)
+	}
+	hw.repairBatchesMu.Unlock()
+
+	sort.Strings(libraryPaths)
+
+	slog.InfoContext(ctx, "Triggering batched ARR rescan for file repair",
+		"library_dir", dir,
+		"files", len(libraryPaths))
+
+	failed := hw.arrsService.TriggerFilesRescan(ctx, libraryPaths)
+	for _, libraryPath := range libraryPaths {
+		filePath := pending[libraryPath]
+
+		if err, ok := failed[libraryPath]; ok {
+			if err := hw.handleRepairTriggerError(ctx, filePath, libraryPath, err); err != nil {
+				slog.ErrorContext(ctx, "Failed to record failed repair trigger", "file_path", filePath, "error", err)
+			}
+			continue
+		}
+
+		slog.InfoContext(ctx, "Successfully triggered ARR rescan for file repair",
+			"file_path", filePath,
+			"library_path", libraryPath)
+	}
+}
+
+// checkLimiter is a counting semaphore whose limit is read on every acquire,
+// so config changes to the concurrency settings apply without a restart
+type checkLimiter struct {
+	mu       sync.Mutex
+	active   int
+	released chan struct{}
+}
+
+func newCheckLimiter() *checkLimiter {
+	return &checkLimiter{released: make(chan struct{})}
+}
+
+// acquire blocks until fewer than limit slots are in use or ctx is done
+func (l *checkLimiter) acquire(ctx context.Context, limit int) error {
+	for {
+		l.mu.Lock()
+		if l.active < limit {
+			l.active++
+			l.mu.Unlock()
+			return nil
+		}
+		released := l.released
+		l.mu.Unlock()
+
+		select {
+		case <-released:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// release frees a slot and wakes up any waiters
+func (l *checkLimiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.active--
+	close(l.released)
+	l.released = make(chan struct{})
 }
@@ -10,12 +10,14 @@ import (
 	"strings"
 	"time"
 
+	"github.com/javi11/altmount/internal/config"
 	"github.com/javi11/altmount/internal/importer/parser"
 	"github.com/javi11/altmount/internal/importer/validation"
 	"github.com/javi11/altmount/internal/metadata"
 	metapb "github.com/javi11/altmount/internal/metadata/proto"
 	"github.com/javi11/altmount/internal/pool"
 	"github.com/javi11/altmount/internal/progress"
+	"github.com/javi11/altmount/internal/usenet"
 )
 
 var (
@@ -24,34 +26,15 @@ var (
 )
 
 // calculateSegmentsToValidate calculates the actual number of segments that will be validated
-// based on the validation mode (full or sampling) and sample percentage.
-// This mirrors the logic in usenet.ValidateSegmentAvailability which uses selectSegmentsForValidation.
+// across all files at the given sample percentage, using the same count as
+// usenet.ValidateSegmentAvailability.
 func calculateSegmentsToValidate(rarContents []Content, samplePercentage int) int {
 	total := 0
 	for _, content := range rarContents {
 		if content.IsDirectory {
 			continue
 		}
-
-		segmentCount := len(content.Segments)
-		if samplePercentage == 100 {
-			// Full validation mode: all segments will be validated
-			total += segmentCount
-		} else {
-			// Sampling mode: first 3 + last 2 + random middle samples
-			// Minimum 5 segments always validated for statistical validity
-			minSegments := 5
-			if segmentCount <= minSegments {
-				total += segmentCount
-			} else {
-				// Fixed segments: first 3 + last 2 = 5 segments
-				fixedSegments := 5
-				// Middle segments: based on sample percentage
-				middleSegmentCount := segmentCount - fixedSegments
-				sampledMiddle := (middleSegmentCount * samplePercentage) / 100
-				total += fixedSegments + sampledMiddle
-			}
-		}
+		total += usenet.SegmentsToValidate(len(content.Segments), samplePercentage)
 	}
 	return total
 }
@@ -77,6 +60,48 @@ func hasAllowedFiles(rarContents []Content, allowedExtensions []string) bool {
 	return false
 }
 
+// skipSmallFiles returns the contents that are not smaller than their minimum file size,
+// logging the ones that are skipped. Directories are always kept.
+func skipSmallFiles(ctx context.Context, contents []Content, minFileSize func(filename string) int64) []Content {
+	if minFileSize == nil {
+		return contents
+	}
+
+	kept := make([]Content, 0, len(contents))
+	for _, content := range contents {
+		if !content.IsDirectory {
+			if minSize := minFileSize(content.Filename); content.Size < minSize {
+				slog.InfoContext(ctx, "Skipping RAR file below minimum file size",
+					"file", content.InternalPath,
+					"size", content.Size,
+					"min_size", minSize)
+				continue
+			}
+		}
+		kept = append(kept, content)
+	}
+
+	return kept
+}
+
+// releaseFiles returns the extracted files as they are written, flattened into virtualDir
+func releaseFiles(virtualDir string, contents []Content) []metadata.ReleaseFile {
+	files := make([]metadata.ReleaseFile, 0, len(contents))
+	for _, content := range contents {
+		if content.IsDirectory {
+			continue
+		}
+
+		baseFilename := filepath.Base(strings.ReplaceAll(content.InternalPath, "\\", "/"))
+		files = append(files, metadata.ReleaseFile{
+			VirtualPath: strings.ReplaceAll(filepath.Join(virtualDir, baseFilename), string(filepath.Separator), "/"),
+			Size:        content.Size,
+			Segments:    content.Segments,
+		})
+	}
+	return files
+}
+
 // isAllowedFile checks if a filename has an allowed extension
 func isAllowedFile(filename string, allowedExtensions []string) bool {
 	if filename == "" {
@@ -99,6 +124,8 @@ func isAllowedFile(filename string, allowedExtensions []string) bool {
 
 // ProcessArchive analyzes and processes RAR archive files, creating metadata for all extracted files.
 // This function handles the complete workflow: analysis → file processing → metadata creation.
+// When every extracted file is already imported by an earlier release, nothing is written and
+// the directory of that release is returned.
 func ProcessArchive(
 	ctx context.Context,
 	virtualDir string,
@@ -113,10 +140,12 @@ func ProcessArchive(
 	validationProgressTracker *progress.Tracker,
 	maxValidationGoroutines int,
 	segmentSamplePercentage int,
+	sampleStrategy config.SampleStrategy,
 	allowedFileExtensions []string,
-) error {
+	minFileSize func(filename string) int64,
+) (string, error) {
 	if len(archiveFiles) == 0 {
-		return nil
+		return "", nil
 	}
 
 	slog.InfoContext(ctx, "Analyzing RAR archive content", "parts", len(archiveFiles))
@@ -128,13 +157,23 @@ func ProcessArchive(
 	rarContents, err := rarProcessor.AnalyzeRarContentFromNzb(ctx, archiveFiles, password, archiveProgressTracker)
 	if err != nil {
 		slog.ErrorContext(ctx, "Failed to analyze RAR archive content", "error", err)
-		return err
+		return "", err
 	}
 
+	// Drop samples and other small files before they get any metadata
+	rarContents = skipSmallFiles(ctx, rarContents, minFileSize)
+
 	// Validate file extensions before processing
 	if !hasAllowedFiles(rarContents, allowedFileExtensions) {
 		slog.WarnContext(ctx, "RAR archive contains no files with allowed extensions", "allowed_extensions", allowedFileExtensions)
-		return ErrNoAllowedFiles
+		return "", ErrNoAllowedFiles
+	}
+
+	// The same release grabbed twice resolves to the copy that is already imported
+	if existing, ok := metadataService.FindDuplicateRelease(virtualDir, releaseFiles(virtualDir, rarContents)); ok {
+		slog.InfoContext(ctx, "Skipping duplicate RAR archive, identical content is already imported",
+			"existing_path", existing)
+		return existing, nil
 	}
 
 	// Calculate total segments to validate for accurate progress tracking
@@ -145,7 +184,8 @@ func ProcessArchive(
 	slog.InfoContext(ctx, "Starting RAR archive validation",
 		"total_files", len(rarContents),
 		"total_segments_to_validate", totalSegmentsToValidate,
-		"sample_percentage", segmentSamplePercentage)
+		"sample_percentage", segmentSamplePercentage,
+		"sample_strategy", sampleStrategy)
 
 	// Process extracted files with segment-based progress tracking
 	// 80-95% for validation loop, 95-100% for metadata finalization
@@ -185,6 +225,7 @@ func ProcessArchive(
 			poolManager,
 			maxValidationGoroutines,
 			segmentSamplePercentage,
+			sampleStrategy,
 			offsetTracker, // Real-time segment progress with cumulative offset
 		); err != nil {
 			slog.WarnContext(ctx, "Skipping RAR file due to validation error", "error", err, "file", baseFilename)
@@ -193,22 +234,7 @@ func ProcessArchive(
 		}
 
 		// Calculate and track segments validated for this file (for next file's offset)
-		segmentCount := len(rarContent.Segments)
-		var fileSegmentsValidated int
-		if segmentSamplePercentage == 100 {
-			fileSegmentsValidated = segmentCount
-		} else {
-			// Sampling mode: calculate same as helper function
-			minSegments := 5
-			if segmentCount <= minSegments {
-				fileSegmentsValidated = segmentCount
-			} else {
-				fixedSegments := 5
-				middleSegmentCount := segmentCount - fixedSegments
-				sampledMiddle := (middleSegmentCount * segmentSamplePercentage) / 100
-				fileSegmentsValidated = fixedSegments + sampledMiddle
-			}
-		}
+		fileSegmentsValidated := usenet.SegmentsToValidate(len(rarContent.Segments), segmentSamplePercentage)
 
 		// Update cumulative segment count for next file's offset
 		validatedSegmentsCount += fileSegmentsValidated
@@ -218,14 +244,17 @@ func ProcessArchive(
 
 		// Delete old metadata if exists (simple collision handling)
 		metadataPath := metadataService.GetMetadataFilePath(virtualFilePath)
-		if _, err := os.Stat(metadataPath); err == nil {
+		_, statErr := os.Stat(metadataPath)
+		existed := statErr == nil
+		if existed {
 			_ = metadataService.DeleteFileMetadata(virtualFilePath)
 		}
 
 		// Write file metadata to disk
 		if err := metadataService.WriteFileMetadata(virtualFilePath, fileMeta); err != nil {
-			return fmt.Errorf("failed to write metadata for RAR file %s: %w", rarContent.Filename, err)
+			return "", fmt.Errorf("failed to write metadata for RAR file %s: %w", rarContent.Filename, err)
 		}
+		metadata.RecordImportedFile(ctx, virtualFilePath, existed)
 
 		slog.DebugContext(ctx, "Created metadata for RAR extracted file",
 			"file", baseFilename,
@@ -249,5 +278,5 @@ func ProcessArchive(
 
 	slog.InfoContext(ctx, "Successfully processed RAR archive files", "files_processed", len(rarContents))
 
-	return nil
+	return "", nil
 }
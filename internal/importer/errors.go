@@ -64,6 +64,9 @@ var ErrNoRetryable = &NonRetryableError{
 	cause:   nil,
 }
 
+// ErrQueueFull is returned by AddToQueue when the queue has reached import.max_queue_depth
+var ErrQueueFull = errors.New("import queue is full")
+
 // ErrNoVideoFiles indicates that an import contains no video files
 var ErrNoVideoFiles = &NonRetryableError{
 	message: "import contains no video files",
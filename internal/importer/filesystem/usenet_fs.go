@@ -268,7 +268,7 @@ func (uf *UsenetFile) createUsenetReader(ctx context.Context, start, end int64)
 	}
 
 	rg := usenet.GetSegmentsInRange(start, end, loader)
-	return usenet.NewUsenetReader(ctx, uf.poolManager.GetPool, rg, uf.maxWorkers, uf.maxCacheSizeMB)
+	return usenet.NewUsenetReader(ctx, uf.poolManager.GetPool, rg, uf.maxWorkers, uf.maxCacheSizeMB, 0, nil)
 }
 
 // dbSegmentLoader implements the segment loader interface for database segments
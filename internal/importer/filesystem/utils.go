@@ -95,11 +95,16 @@ func EnsureDirectoryExists(virtualDir string, metadataService *metadata.Metadata
 	return nil
 }
 
-// CreateNzbFolder creates a folder named after the NZB file
-func CreateNzbFolder(virtualDir, nzbFilename string, metadataService *metadata.MetadataService) (string, error) {
+// NzbFolderPath returns the virtual path of the folder named after the NZB file
+func NzbFolderPath(virtualDir, nzbFilename string) string {
 	nzbBaseName := strings.TrimSuffix(nzbFilename, filepath.Ext(nzbFilename))
 	nzbVirtualDir := filepath.Join(virtualDir, nzbBaseName)
-	nzbVirtualDir = strings.ReplaceAll(nzbVirtualDir, string(filepath.Separator), "/")
+	return strings.ReplaceAll(nzbVirtualDir, string(filepath.Separator), "/")
+}
+
+// CreateNzbFolder creates a folder named after the NZB file
+func CreateNzbFolder(virtualDir, nzbFilename string, metadataService *metadata.MetadataService) (string, error) {
+	nzbVirtualDir := NzbFolderPath(virtualDir, nzbFilename)
 
 	if err := EnsureDirectoryExists(nzbVirtualDir, metadataService); err != nil {
 		return "", err
@@ -108,6 +113,15 @@ func CreateNzbFolder(virtualDir, nzbFilename string, metadataService *metadata.M
 	return nzbVirtualDir, nil
 }
 
+// RemoveEmptyFolder removes a folder created for a release that resolved to an earlier
+// import. A folder holding anything, such as files of an earlier import, is kept.
+func RemoveEmptyFolder(virtualDir string, metadataService *metadata.MetadataService) {
+	if virtualDir == "/" {
+		return
+	}
+	_ = os.Remove(metadataService.GetMetadataDirectoryPath(virtualDir))
+}
+
 // CreateDirectoriesForFiles analyzes files and creates their parent directories
 func CreateDirectoriesForFiles(virtualDir string, files []parser.ParsedFile, metadataService *metadata.MetadataService) error {
 	// Collect unique directory paths
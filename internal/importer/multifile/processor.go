@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/javi11/altmount/internal/config"
 	"github.com/javi11/altmount/internal/importer/filesystem"
 	"github.com/javi11/altmount/internal/importer/parser"
 	"github.com/javi11/altmount/internal/importer/utils"
@@ -17,6 +18,22 @@ import (
 	"github.com/javi11/altmount/internal/pool"
 )
 
+// FindDuplicateRelease returns the directory of an earlier import holding every one of files
+// as they would be written under virtualDir
+func FindDuplicateRelease(virtualDir string, files []parser.ParsedFile, metadataService *metadata.MetadataService) (string, bool) {
+	releaseFiles := make([]metadata.ReleaseFile, 0, len(files))
+	for _, file := range files {
+		parentPath, filename := filesystem.DetermineFileLocation(file, virtualDir)
+		releaseFiles = append(releaseFiles, metadata.ReleaseFile{
+			VirtualPath: strings.ReplaceAll(filepath.Join(parentPath, filename), string(filepath.Separator), "/"),
+			Size:        file.Size,
+			Segments:    file.Segments,
+		})
+	}
+
+	return metadataService.FindDuplicateRelease(virtualDir, releaseFiles)
+}
+
 // ProcessRegularFiles processes multiple regular files
 func ProcessRegularFiles(
 	ctx context.Context,
@@ -28,6 +45,7 @@ func ProcessRegularFiles(
 	poolManager pool.Manager,
 	maxValidationGoroutines int,
 	segmentSamplePercentage int,
+	sampleStrategy config.SampleStrategy,
 	allowedFileExtensions []string,
 ) error {
 	if len(files) == 0 {
@@ -74,6 +92,7 @@ func ProcessRegularFiles(
 			poolManager,
 			maxValidationGoroutines,
 			segmentSamplePercentage,
+			sampleStrategy,
 			nil, // No progress callback for multi-file imports
 		); err != nil {
 			return err
@@ -91,10 +110,14 @@ func ProcessRegularFiles(
 			file.ReleaseDate.Unix(),
 			par2Refs,
 		)
+		// The PAR2 checksums let the file be verified without relying on sampling
+		fileMeta.Par2Info = file.Par2Info
 
 		// Delete old metadata if exists (simple collision handling)
 		metadataPath := metadataService.GetMetadataFilePath(virtualPath)
-		if _, err := os.Stat(metadataPath); err == nil {
+		_, statErr := os.Stat(metadataPath)
+		existed := statErr == nil
+		if existed {
 			_ = metadataService.DeleteFileMetadata(virtualPath)
 		}
 
@@ -102,6 +125,7 @@ func ProcessRegularFiles(
 		if err := metadataService.WriteFileMetadata(virtualPath, fileMeta); err != nil {
 			return fmt.Errorf("failed to write metadata for file %s: %w", filename, err)
 		}
+		metadata.RecordImportedFile(ctx, virtualPath, existed)
 
 		slog.DebugContext(ctx, "Created metadata file",
 			"file", filename,
@@ -31,12 +31,14 @@ func getFileInfo(
 	par2Filename := ""
 	par2FileSize := int64(0)
 
+	var par2Desc *par2.FileDescriptor
 	if len(hashToDescMap) > 0 {
 		// Calculate MD5 hash of first 16KB for PAR2 matching
 		md5Hash := md5.Sum(file.First16KB)
 
 		desc, ok := hashToDescMap[md5Hash]
 		if ok {
+			par2Desc = desc
 			par2Filename = desc.Name
 			par2FileSize = int64(desc.Length)
 		}
@@ -80,6 +82,7 @@ func getFileInfo(
 		YencHeaders:   file.Headers,
 		First16KB:     file.First16KB,
 		OriginalIndex: file.OriginalIndex,
+		Par2:          par2Desc,
 	}
 }
 
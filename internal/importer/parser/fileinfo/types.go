@@ -3,6 +3,7 @@ package fileinfo
 import (
 	"time"
 
+	"github.com/javi11/altmount/internal/importer/parser/par2"
 	"github.com/javi11/nntppool/v2/pkg/nntpcli"
 	"github.com/javi11/nzbparser"
 )
@@ -29,6 +30,7 @@ type FileInfo struct {
 	YencHeaders   *nntpcli.YencHeaders // yEnc headers from first segment
 	First16KB     []byte               // First 16KB of the file (for magic byte detection)
 	OriginalIndex int                  // Original position in the parsed NZB file list
+	Par2          *par2.FileDescriptor // PAR2 description matched by the 16KB hash, nil if none
 }
 
 // NzbFileWithFirstSegment represents an NZB file with its first segment data
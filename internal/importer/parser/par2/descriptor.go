@@ -76,7 +76,8 @@ func GetFileDescriptors(
 	return descriptors, nil
 }
 
-// readFileDescriptors streams through a PAR2 file and extracts all file descriptors
+// readFileDescriptors streams through a PAR2 file and extracts all file descriptors, along
+// with the slice checksums of the described files
 // Similar to C# Par2.ReadFileDescriptions
 // This function reads ALL segments of the PAR2 file sequentially to find all FileDesc packets
 func readFileDescriptors(
@@ -117,6 +118,11 @@ func readFileDescriptors(
 	maxPackets := 1000 // Limit the number of packets to process
 	packetCount := 0
 
+	// Main and IFSC packets can come before or after the FileDesc packets they apply to
+	var sliceSize uint64
+	sliceChecksums := make(map[[16]byte][]SliceChecksum)
+
+readLoop:
 	for packetCount < maxPackets {
 		select {
 		case <-ctx.Done():
@@ -137,8 +143,8 @@ func readFileDescriptors(
 
 		packetCount++
 
-		// Check if this is a FileDesc packet
-		if header.Type == PacketTypeFileDesc {
+		switch header.Type {
+		case PacketTypeFileDesc:
 			// Read and parse the file descriptor
 			desc, err := packetReader.ReadFileDescriptor(header)
 			if err != nil {
@@ -148,11 +154,37 @@ func readFileDescriptors(
 			}
 
 			descriptors = append(descriptors, *desc)
-		} else {
-			// Skip non-FileDesc packets
+		case PacketTypePARMain:
+			size, err := packetReader.ReadSliceSize(header)
+			if err != nil {
+				slog.DebugContext(ctx, "Failed to read main packet", "error", err)
+				break readLoop
+			}
+
+			sliceSize = size
+		case PacketTypeIFSC:
+			fileID, checksums, err := packetReader.ReadSliceChecksums(header)
+			if err != nil {
+				slog.DebugContext(ctx, "Failed to read slice checksums", "error", err)
+				break readLoop
+			}
+
+			sliceChecksums[fileID] = checksums
+		default:
+			// Skip recovery slices and other packets
 			if err := packetReader.SkipPacketBody(header); err != nil {
 				slog.DebugContext(ctx, "Failed to skip packet body", "error", err)
-				break
+				break readLoop
+			}
+		}
+	}
+
+	// Attach the slice checksums, which are only usable along with the slice size
+	if sliceSize > 0 {
+		for i := range descriptors {
+			if checksums, ok := sliceChecksums[descriptors[i].FileID]; ok {
+				descriptors[i].SliceSize = sliceSize
+				descriptors[i].SliceChecksums = checksums
 			}
 		}
 	}
@@ -98,6 +98,79 @@ func (pr *PacketReader) ReadFileDescriptor(header *PacketHeader) (*FileDescripto
 	return desc, nil
 }
 
+// ReadSliceSize reads the slice size from a Main packet body and skips the rest of it
+// The header must have already been read and validated as a Main packet
+// Reference: https://github.com/akalin/gopar/blob/main/par2/main_packet.go
+func (pr *PacketReader) ReadSliceSize(header *PacketHeader) (uint64, error) {
+	if header.Type != PacketTypePARMain {
+		return 0, fmt.Errorf("not a Main packet")
+	}
+
+	bodyLength := header.Length - PacketHeaderSize
+	if bodyLength < 12 { // Minimum: SliceSize (8) + recovery set file count (4) = 12 bytes
+		return 0, fmt.Errorf("main packet too small: %d bytes", bodyLength)
+	}
+	if (bodyLength-12)%16 != 0 { // Followed by 16 byte file IDs
+		return 0, fmt.Errorf("invalid main packet size: %d bytes", bodyLength)
+	}
+
+	var sliceSize uint64
+	if err := binary.Read(pr.r, binary.LittleEndian, &sliceSize); err != nil {
+		return 0, fmt.Errorf("failed to read slice size: %w", err)
+	}
+	if sliceSize == 0 || sliceSize%4 != 0 {
+		return 0, fmt.Errorf("invalid slice size: %d", sliceSize)
+	}
+
+	// The file IDs of the set are not needed, the FileDesc packets describe the same files
+	if _, err := io.CopyN(io.Discard, pr.r, int64(bodyLength-8)); err != nil {
+		return 0, fmt.Errorf("failed to skip main packet body: %w", err)
+	}
+
+	return sliceSize, nil
+}
+
+// ReadSliceChecksums reads an input file slice checksum (IFSC) packet body, returning the
+// ID of the file it describes and the checksums of each of its slices
+// The header must have already been read and validated as an IFSC packet
+// Reference: https://github.com/akalin/gopar/blob/main/par2/ifsc_packet.go
+func (pr *PacketReader) ReadSliceChecksums(header *PacketHeader) ([16]byte, []SliceChecksum, error) {
+	var fileID [16]byte
+
+	if header.Type != PacketTypeIFSC {
+		return fileID, nil, fmt.Errorf("not an IFSC packet")
+	}
+
+	bodyLength := header.Length - PacketHeaderSize
+	if bodyLength < 16 || (bodyLength-16)%SliceChecksumSize != 0 {
+		return fileID, nil, fmt.Errorf("invalid IFSC packet size: %d bytes", bodyLength)
+	}
+
+	if err := binary.Read(pr.r, binary.LittleEndian, &fileID); err != nil {
+		return fileID, nil, fmt.Errorf("failed to read FileID: %w", err)
+	}
+
+	count := (bodyLength - 16) / SliceChecksumSize
+	if count > MaxSliceCount {
+		return fileID, nil, fmt.Errorf("IFSC packet has %d slices, more than the %d PAR2 allows", count, MaxSliceCount)
+	}
+
+	// The count comes from the packet header, read in chunks so a truncated packet fails
+	// before a large slice is allocated for checksums that are not there
+	checksums := make([]SliceChecksum, 0, min(count, sliceChecksumChunk))
+	for remaining := int(count); remaining > 0; {
+		chunk := make([]SliceChecksum, min(remaining, sliceChecksumChunk))
+		if err := binary.Read(pr.r, binary.LittleEndian, chunk); err != nil {
+			return fileID, nil, fmt.Errorf("failed to read slice checksums: %w", err)
+		}
+
+		checksums = append(checksums, chunk...)
+		remaining -= len(chunk)
+	}
+
+	return fileID, checksums, nil
+}
+
 // SkipPacketBody skips the body of a packet (everything after the header)
 func (pr *PacketReader) SkipPacketBody(header *PacketHeader) error {
 	remainingBytes := header.Length - PacketHeaderSize
@@ -0,0 +1,214 @@
+package par2
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// packet builds a PAR2 packet of the given type, its header length taken from length when
+// set so that truncated and oversized packets can be described
+func packet(packetType [16]byte, body []byte, length uint64) []byte {
+	if length == 0 {
+		length = uint64(PacketHeaderSize + len(body))
+	}
+
+	var buf bytes.Buffer
+	_ = binary.Write(&buf, binary.LittleEndian, PacketHeader{
+		Magic:  MagicBytes,
+		Length: length,
+		Type:   packetType,
+	})
+	buf.Write(body)
+	return buf.Bytes()
+}
+
+// mainBody builds a Main packet body for the given slice size and file IDs
+func mainBody(sliceSize uint64, fileIDs ...[16]byte) []byte {
+	var buf bytes.Buffer
+	_ = binary.Write(&buf, binary.LittleEndian, sliceSize)
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(len(fileIDs)))
+	for _, id := range fileIDs {
+		buf.Write(id[:])
+	}
+	return buf.Bytes()
+}
+
+// ifscBody builds an IFSC packet body for fileID with the given checksums
+func ifscBody(fileID [16]byte, checksums []SliceChecksum) []byte {
+	var buf bytes.Buffer
+	buf.Write(fileID[:])
+	_ = binary.Write(&buf, binary.LittleEndian, checksums)
+	return buf.Bytes()
+}
+
+func TestReadSliceSize(t *testing.T) {
+	fileID := [16]byte{1}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		want    uint64
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			data: append(packet(PacketTypePARMain, mainBody(768000, fileID), 0), "next"...),
+			want: 768000,
+		},
+		{
+			name:    "slice size not a multiple of 4",
+			data:    packet(PacketTypePARMain, mainBody(1001, fileID), 0),
+			wantErr: true,
+		},
+		{
+			name:    "zero slice size",
+			data:    packet(PacketTypePARMain, mainBody(0), 0),
+			wantErr: true,
+		},
+		{
+			name:    "body too small",
+			data:    packet(PacketTypePARMain, make([]byte, 8), 0),
+			wantErr: true,
+		},
+		{
+			name:    "partial file ID",
+			data:    packet(PacketTypePARMain, append(mainBody(768000), make([]byte, 8)...), 0),
+			wantErr: true,
+		},
+		{
+			name:    "truncated",
+			data:    packet(PacketTypePARMain, mainBody(768000, fileID), PacketHeaderSize+12+16*1000),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := bytes.NewReader(tt.data)
+			pr := NewPacketReader(r)
+
+			header, err := pr.ReadHeader()
+			if err != nil {
+				t.Fatalf("ReadHeader: %v", err)
+			}
+
+			got, err := pr.ReadSliceSize(header)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got slice size %d", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadSliceSize: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("slice size: got %d, want %d", got, tt.want)
+			}
+			// The whole body is consumed
+			if r.Len() != len("next") {
+				t.Errorf("%d bytes left after the packet, want %d", r.Len(), len("next"))
+			}
+		})
+	}
+}
+
+func TestReadSliceChecksums(t *testing.T) {
+	fileID := [16]byte{1, 2, 3}
+	checksums := make([]SliceChecksum, 2*sliceChecksumChunk+5)
+	for i := range checksums {
+		checksums[i] = SliceChecksum{MD5: [16]byte{byte(i), byte(i >> 8)}, CRC32: uint32(i)}
+	}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		want    []SliceChecksum
+		wantErr bool
+	}{
+		{
+			name: "single slice",
+			data: packet(PacketTypeIFSC, ifscBody(fileID, checksums[:1]), 0),
+			want: checksums[:1],
+		},
+		{
+			name: "several chunks",
+			data: packet(PacketTypeIFSC, ifscBody(fileID, checksums), 0),
+			want: checksums,
+		},
+		{
+			name: "no slices",
+			data: packet(PacketTypeIFSC, ifscBody(fileID, nil), 0),
+			want: []SliceChecksum{},
+		},
+		{
+			name:    "partial checksum",
+			data:    packet(PacketTypeIFSC, append(ifscBody(fileID, checksums[:1]), 0, 0, 0, 0), 0),
+			wantErr: true,
+		},
+		{
+			name:    "truncated",
+			data:    packet(PacketTypeIFSC, ifscBody(fileID, checksums[:3]), PacketHeaderSize+16+SliceChecksumSize*MaxSliceCount),
+			wantErr: true,
+		},
+		{
+			name:    "more slices than PAR2 allows",
+			data:    packet(PacketTypeIFSC, ifscBody(fileID, checksums[:3]), PacketHeaderSize+16+SliceChecksumSize*(MaxSliceCount+1)),
+			wantErr: true,
+		},
+		{
+			name:    "oversized length",
+			data:    packet(PacketTypeIFSC, ifscBody(fileID, checksums[:3]), PacketHeaderSize+16+SliceChecksumSize*(1<<56)),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr := NewPacketReader(bytes.NewReader(tt.data))
+
+			header, err := pr.ReadHeader()
+			if err != nil {
+				t.Fatalf("ReadHeader: %v", err)
+			}
+
+			gotID, got, err := pr.ReadSliceChecksums(header)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got %d checksums", len(got))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadSliceChecksums: %v", err)
+			}
+			if gotID != fileID {
+				t.Errorf("file ID: got %x, want %x", gotID, fileID)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d checksums, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("checksum %d: got %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestReadPacketsOfWrongType(t *testing.T) {
+	pr := NewPacketReader(bytes.NewReader(packet(PacketTypeCreator, []byte("test"), 0)))
+
+	header, err := pr.ReadHeader()
+	if err != nil {
+		t.Fatalf("ReadHeader: %v", err)
+	}
+	if _, err := pr.ReadSliceSize(header); err == nil {
+		t.Error("ReadSliceSize: expected an error for a Creator packet")
+	}
+	if _, _, err := pr.ReadSliceChecksums(header); err == nil {
+		t.Error("ReadSliceChecksums: expected an error for a Creator packet")
+	}
+}
@@ -41,6 +41,17 @@ type FileDescriptor struct {
 	Hash16k    [16]byte // MD5 hash of first 16KB of file (for matching)
 	Length     uint64   // File length in bytes
 	Name       string   // Original filename (variable length, null-terminated, 4-byte aligned)
+
+	// Filled from the Main and IFSC packets of the set, empty when the index file has none
+	SliceSize      uint64          // Size of the slices the file is split into for recovery
+	SliceChecksums []SliceChecksum // Checksums of each slice, the last one zero padded
+}
+
+// SliceChecksum holds the checksums of one slice from an input file slice checksum packet
+// Reference: https://github.com/akalin/gopar/blob/main/par2/ifsc_packet.go
+type SliceChecksum struct {
+	MD5   [16]byte // MD5 hash of the slice
+	CRC32 uint32   // CRC32 of the slice
 }
 
 const (
@@ -50,4 +61,13 @@ const (
 	// MinFileDescPacketSize is the minimum size for a file description packet
 	// Header (64) + FileID (16) + FileMD5 (16) + Hash16k (16) + Length (8) = 120 bytes
 	MinFileDescPacketSize = 120
+
+	// SliceChecksumSize is the size of one entry of an IFSC packet: MD5 (16) + CRC32 (4)
+	SliceChecksumSize = 20
+
+	// MaxSliceCount is the most input slices a PAR2 recovery set can have
+	MaxSliceCount = 32768
+
+	// sliceChecksumChunk is how many slice checksums are read at once
+	sliceChecksumChunk = 1024
 )
@@ -2,6 +2,7 @@ package parser
 
 import (
 	"context"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
@@ -158,6 +159,13 @@ func (p *Parser) ParseFile(ctx context.Context, r io.Reader, nzbPath string) (*P
 		return nil, NewNonRetryableError("NZB file contains no valid files. This can be caused because the file has missing segments in your providers.", nil)
 	}
 
+	// The PAR2 set lists every file of the release, report the ones that cannot be imported
+	if missing := missingPar2Files(par2Descriptors, fileInfos); len(missing) > 0 {
+		p.log.WarnContext(ctx, "NZB is missing files described by its PAR2 set",
+			"missing_files", missing,
+			"par2_files", len(par2Descriptors))
+	}
+
 	concPool := concpool.NewWithResults[fileResult]().WithMaxGoroutines(runtime.NumCPU()).WithContext(ctx)
 
 	// Process files in parallel using conc pool
@@ -349,11 +357,59 @@ func (p *Parser) parseFile(ctx context.Context, meta map[string]string, nzbFilen
 		ReleaseDate:   info.ReleaseDate,
 		IsPar2Archive: info.IsPar2Archive,
 		OriginalIndex: info.OriginalIndex,
+		Par2Info:      par2FileInfo(info.Par2),
 	}
 
 	return parsedFile, nil
 }
 
+// par2FileInfo converts a PAR2 file descriptor to the form stored in the file metadata
+func par2FileInfo(desc *par2.FileDescriptor) *metapb.Par2FileInfo {
+	if desc == nil {
+		return nil
+	}
+
+	info := &metapb.Par2FileInfo{
+		FileId:  desc.FileID[:],
+		FileMd5: desc.FileMD5[:],
+		Hash16K: desc.Hash16k[:],
+	}
+
+	if desc.SliceSize > 0 && len(desc.SliceChecksums) > 0 {
+		info.SliceSize = int64(desc.SliceSize)
+		info.SliceChecksums = make([]byte, 0, len(desc.SliceChecksums)*par2.SliceChecksumSize)
+		for _, checksum := range desc.SliceChecksums {
+			info.SliceChecksums = append(info.SliceChecksums, checksum.MD5[:]...)
+			info.SliceChecksums = binary.LittleEndian.AppendUint32(info.SliceChecksums, checksum.CRC32)
+		}
+	}
+
+	return info
+}
+
+// missingPar2Files returns the names of the files described by the PAR2 set that are not
+// part of the NZB, or whose first segment could not be fetched
+func missingPar2Files(descriptors map[[16]byte]*par2.FileDescriptor, fileInfos []*fileinfo.FileInfo) []string {
+	found := make(map[[16]byte]bool, len(fileInfos))
+	for _, info := range fileInfos {
+		if info.Par2 != nil {
+			found[info.Par2.FileID] = true
+		}
+	}
+
+	var missing []string
+	for _, desc := range descriptors {
+		// Empty files have no data to post
+		if desc.Length == 0 || found[desc.FileID] {
+			continue
+		}
+		missing = append(missing, desc.Name)
+	}
+	sort.Strings(missing)
+
+	return missing
+}
+
 // fetchAllFirstSegments fetches the first segment data for all files in parallel
 // Returns a slice of FirstSegmentData preserving all fetched data
 func (p *Parser) fetchAllFirstSegments(ctx context.Context, files []nzbparser.NzbFile) ([]*FirstSegmentData, error) {
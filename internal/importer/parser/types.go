@@ -48,9 +48,10 @@ type ParsedFile struct {
 	IsRarArchive  bool
 	Is7zArchive   bool
 	IsPar2Archive bool
-	Encryption    metapb.Encryption // Encryption type (e.g., "rclone"), nil if not encrypted
-	Password      string            // Password from NZB meta, nil if not encrypted
-	Salt          string            // Salt from NZB meta, nil if not encrypted
-	ReleaseDate   time.Time         // Release date from the Usenet post
-	OriginalIndex int               // Original position in the parsed NZB file list
+	Encryption    metapb.Encryption    // Encryption type (e.g., "rclone"), nil if not encrypted
+	Password      string               // Password from NZB meta, nil if not encrypted
+	Salt          string               // Salt from NZB meta, nil if not encrypted
+	ReleaseDate   time.Time            // Release date from the Usenet post
+	OriginalIndex int                  // Original position in the parsed NZB file list
+	Par2Info      *metapb.Par2FileInfo // PAR2 description of the file, nil if no PAR2 set covers it
 }
@@ -9,6 +9,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/javi11/altmount/internal/config"
 	"github.com/javi11/altmount/internal/importer/archive/rar"
 	"github.com/javi11/altmount/internal/importer/archive/sevenzip"
 	"github.com/javi11/altmount/internal/importer/filesystem"
@@ -31,10 +32,12 @@ type Processor struct {
 	metadataService         *metadata.MetadataService
 	rarProcessor            rar.Processor
 	sevenZipProcessor       sevenzip.Processor
-	poolManager             pool.Manager // Pool manager for dynamic pool access
-	maxImportConnections    int          // Maximum concurrent NNTP connections for validation and archive processing
-	segmentSamplePercentage int          // Percentage of segments to check when sampling (1-100)
-	allowedFileExtensions   []string     // Allowed file extensions for validation (empty = allow all)
+	poolManager             pool.Manager                // Pool manager for dynamic pool access
+	maxImportConnections    int                         // Maximum concurrent NNTP connections for validation and archive processing
+	segmentSamplePercentage int                         // Percentage of segments to check when sampling (1-100)
+	sampleStrategy          config.SampleStrategy       // Which segments are checked when sampling
+	allowedFileExtensions   []string                    // Allowed file extensions for validation (empty = allow all)
+	minFileSize             func(filename string) int64 // Size in bytes below which a file is skipped (nil = no limit)
 	log                     *slog.Logger
 	broadcaster             *progress.ProgressBroadcaster // WebSocket progress broadcaster
 
@@ -45,7 +48,7 @@ type Processor struct {
 }
 
 // NewProcessor creates a new NZB processor using metadata storage
-func NewProcessor(metadataService *metadata.MetadataService, poolManager pool.Manager, maxImportConnections int, segmentSamplePercentage int, allowedFileExtensions []string, importCacheSizeMB int, broadcaster *progress.ProgressBroadcaster) *Processor {
+func NewProcessor(metadataService *metadata.MetadataService, poolManager pool.Manager, maxImportConnections int, segmentSamplePercentage int, sampleStrategy config.SampleStrategy, allowedFileExtensions []string, minFileSize func(filename string) int64, importCacheSizeMB int, broadcaster *progress.ProgressBroadcaster) *Processor {
 	return &Processor{
 		parser:                  parser.NewParser(poolManager),
 		strmParser:              parser.NewStrmParser(),
@@ -55,7 +58,9 @@ func NewProcessor(metadataService *metadata.MetadataService, poolManager pool.Ma
 		poolManager:             poolManager,
 		maxImportConnections:    maxImportConnections,
 		segmentSamplePercentage: segmentSamplePercentage,
+		sampleStrategy:          sampleStrategy,
 		allowedFileExtensions:   allowedFileExtensions,
+		minFileSize:             minFileSize,
 		log:                     slog.Default().With("component", "nzb-processor"),
 		broadcaster:             broadcaster,
 
@@ -140,6 +145,14 @@ func (proc *Processor) ProcessNzbFile(ctx context.Context, filePath, relativePat
 	// Step 3: Separate files by type (regular, archive, PAR2)
 	regularFiles, archiveFiles, par2Files := filesystem.SeparateFiles(parsed.Files, parsed.Type)
 
+	// Drop samples and other small files before they get any metadata
+	if len(regularFiles) > 0 {
+		regularFiles = proc.skipSmallFiles(ctx, regularFiles)
+		if len(regularFiles) == 0 && len(archiveFiles) == 0 {
+			return "", NewNonRetryableError("all files are smaller than the minimum file size", nil)
+		}
+	}
+
 	// Check for cancellation before main processing
 	if err := proc.checkCancellation(ctx); err != nil {
 		return "", err
@@ -180,6 +193,28 @@ func (proc *Processor) ProcessNzbFile(ctx context.Context, filePath, relativePat
 	return result, err
 }
 
+// skipSmallFiles returns the files that are not smaller than their minimum file size,
+// logging the ones that are skipped
+func (proc *Processor) skipSmallFiles(ctx context.Context, files []parser.ParsedFile) []parser.ParsedFile {
+	if proc.minFileSize == nil {
+		return files
+	}
+
+	kept := make([]parser.ParsedFile, 0, len(files))
+	for _, file := range files {
+		if minSize := proc.minFileSize(file.Filename); file.Size < minSize {
+			proc.log.InfoContext(ctx, "Skipping file below minimum file size",
+				"file", file.Filename,
+				"size", file.Size,
+				"min_size", minSize)
+			continue
+		}
+		kept = append(kept, file)
+	}
+
+	return kept
+}
+
 // processSingleFile handles single file imports
 func (proc *Processor) processSingleFile(
 	ctx context.Context,
@@ -208,6 +243,7 @@ func (proc *Processor) processSingleFile(
 		proc.poolManager,
 		proc.maxImportConnections,
 		proc.segmentSamplePercentage,
+		proc.sampleStrategy,
 		proc.allowedFileExtensions,
 	)
 	if err != nil {
@@ -225,6 +261,14 @@ func (proc *Processor) processMultiFile(
 	par2Files []parser.ParsedFile,
 	nzbPath string,
 ) (string, error) {
+	// The same release grabbed twice resolves to the copy that is already imported
+	nzbFolderPath := filesystem.NzbFolderPath(virtualDir, filepath.Base(nzbPath))
+	if existing, ok := multifile.FindDuplicateRelease(nzbFolderPath, regularFiles, proc.metadataService); ok {
+		proc.log.InfoContext(ctx, "Skipping duplicate release, identical content is already imported",
+			"existing_path", existing)
+		return existing, nil
+	}
+
 	// Create NZB folder
 	nzbFolder, err := filesystem.CreateNzbFolder(virtualDir, filepath.Base(nzbPath), proc.metadataService)
 	if err != nil {
@@ -247,6 +291,7 @@ func (proc *Processor) processMultiFile(
 		proc.poolManager,
 		proc.maxImportConnections,
 		proc.segmentSamplePercentage,
+		proc.sampleStrategy,
 		proc.allowedFileExtensions,
 	); err != nil {
 		return "", err
@@ -270,28 +315,6 @@ func (proc *Processor) processRarArchive(
 		return "", err
 	}
 
-	// Process regular files first if any
-	if len(regularFiles) > 0 {
-		if err := filesystem.CreateDirectoriesForFiles(nzbFolder, regularFiles, proc.metadataService); err != nil {
-			return "", err
-		}
-
-		if err := multifile.ProcessRegularFiles(
-			ctx,
-			nzbFolder,
-			regularFiles,
-			nil, // No PAR2 files for archive imports
-			parsed.Path,
-			proc.metadataService,
-			proc.poolManager,
-			proc.maxImportConnections,
-			proc.segmentSamplePercentage,
-			proc.allowedFileExtensions,
-		); err != nil {
-			slog.DebugContext(ctx, "Failed to process regular files", "error", err)
-		}
-	}
-
 	// Analyze and process RAR archive
 	if len(archiveFiles) > 0 {
 		proc.updateProgress(queueID, 50)
@@ -309,7 +332,7 @@ func (proc *Processor) processRarArchive(
 		validationProgressTracker := proc.broadcaster.CreateTracker(queueID, 80, 95)
 
 		// Process archive with unified aggregator
-		err := rar.ProcessArchive(
+		existing, err := rar.ProcessArchive(
 			ctx,
 			nzbFolder,
 			archiveFiles,
@@ -323,33 +346,24 @@ func (proc *Processor) processRarArchive(
 			validationProgressTracker,
 			proc.maxImportConnections,
 			proc.segmentSamplePercentage,
+			proc.sampleStrategy,
 			proc.allowedFileExtensions,
+			proc.minFileSize,
 		)
 		if err != nil {
 			return "", err
 		}
 		// Archive analysis complete, validation and finalization will happen in aggregator (80-100%)
-	}
 
-	return nzbFolder, nil
-}
-
-// processSevenZipArchive handles 7zip archive imports
-func (proc *Processor) processSevenZipArchive(
-	ctx context.Context,
-	virtualDir string,
-	regularFiles []parser.ParsedFile,
-	archiveFiles []parser.ParsedFile,
-	parsed *parser.ParsedNzb,
-	queueID int,
-) (string, error) {
-	// Create NZB folder
-	nzbFolder, err := filesystem.CreateNzbFolder(virtualDir, filepath.Base(parsed.Path), proc.metadataService)
-	if err != nil {
-		return "", err
+		// The same release grabbed twice resolves to the copy that is already imported, its
+		// loose files came with that import too
+		if existing != "" {
+			filesystem.RemoveEmptyFolder(nzbFolder, proc.metadataService)
+			return existing, nil
+		}
 	}
 
-	// Process regular files first if any
+	// Process regular files if any
 	if len(regularFiles) > 0 {
 		if err := filesystem.CreateDirectoriesForFiles(nzbFolder, regularFiles, proc.metadataService); err != nil {
 			return "", err
@@ -365,12 +379,31 @@ func (proc *Processor) processSevenZipArchive(
 			proc.poolManager,
 			proc.maxImportConnections,
 			proc.segmentSamplePercentage,
+			proc.sampleStrategy,
 			proc.allowedFileExtensions,
 		); err != nil {
 			slog.DebugContext(ctx, "Failed to process regular files", "error", err)
 		}
 	}
 
+	return nzbFolder, nil
+}
+
+// processSevenZipArchive handles 7zip archive imports
+func (proc *Processor) processSevenZipArchive(
+	ctx context.Context,
+	virtualDir string,
+	regularFiles []parser.ParsedFile,
+	archiveFiles []parser.ParsedFile,
+	parsed *parser.ParsedNzb,
+	queueID int,
+) (string, error) {
+	// Create NZB folder
+	nzbFolder, err := filesystem.CreateNzbFolder(virtualDir, filepath.Base(parsed.Path), proc.metadataService)
+	if err != nil {
+		return "", err
+	}
+
 	// Analyze and process 7zip archive
 	if len(archiveFiles) > 0 {
 		proc.updateProgress(queueID, 50)
@@ -388,7 +421,7 @@ func (proc *Processor) processSevenZipArchive(
 		validationProgressTracker := proc.broadcaster.CreateTracker(queueID, 80, 95)
 
 		// Process archive with unified aggregator
-		err := sevenzip.ProcessArchive(
+		existing, err := sevenzip.ProcessArchive(
 			ctx,
 			nzbFolder,
 			archiveFiles,
@@ -402,12 +435,44 @@ func (proc *Processor) processSevenZipArchive(
 			validationProgressTracker,
 			proc.maxImportConnections,
 			proc.segmentSamplePercentage,
+			proc.sampleStrategy,
 			proc.allowedFileExtensions,
+			proc.minFileSize,
 		)
 		if err != nil {
 			return "", err
 		}
 		// Archive analysis complete, validation and finalization will happen in aggregator (80-100%)
+
+		// The same release grabbed twice resolves to the copy that is already imported, its
+		// loose files came with that import too
+		if existing != "" {
+			filesystem.RemoveEmptyFolder(nzbFolder, proc.metadataService)
+			return existing, nil
+		}
+	}
+
+	// Process regular files if any
+	if len(regularFiles) > 0 {
+		if err := filesystem.CreateDirectoriesForFiles(nzbFolder, regularFiles, proc.metadataService); err != nil {
+			return "", err
+		}
+
+		if err := multifile.ProcessRegularFiles(
+			ctx,
+			nzbFolder,
+			regularFiles,
+			nil, // No PAR2 files for archive imports
+			parsed.Path,
+			proc.metadataService,
+			proc.poolManager,
+			proc.maxImportConnections,
+			proc.segmentSamplePercentage,
+			proc.sampleStrategy,
+			proc.allowedFileExtensions,
+		); err != nil {
+			slog.DebugContext(ctx, "Failed to process regular files", "error", err)
+		}
 	}
 
 	return nzbFolder, nil
@@ -0,0 +1,129 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/javi11/altmount/internal/database"
+)
+
+// throughputWindow is how far back completed imports count towards the measured speed
+const throughputWindow = 5 * time.Minute
+
+// QueueSummary holds aggregate statistics about the pending work in the import queue
+type QueueSummary struct {
+	Slots          int           `json:"slots"`            // Pending and processing items
+	TotalBytes     int64         `json:"total_bytes"`      // Combined size of all active items
+	RemainingBytes int64         `json:"remaining_bytes"`  // Bytes not yet imported, using live progress for processing items
+	BytesPerSecond float64       `json:"bytes_per_second"` // Measured import throughput over the recent window
+	TimeLeft       time.Duration `json:"time_left"`        // Estimated time to drain the queue, 0 when unknown
+	Paused         bool          `json:"paused"`
+}
+
+// importSample records a completed import for throughput measurement
+type importSample struct {
+	started  time.Time
+	finished time.Time
+	bytes    int64
+}
+
+// throughputTracker measures import speed from recently completed imports
+type throughputTracker struct {
+	mu      sync.Mutex
+	samples []importSample
+}
+
+// record adds a completed import of the given size
+func (t *throughputTracker) record(started, finished time.Time, bytes int64) {
+	if bytes <= 0 {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.samples = append(t.samples, importSample{started: started, finished: finished, bytes: bytes})
+	t.pruneLocked(finished)
+}
+
+// bytesPerSecond returns the bytes imported per second over the recent window, counting
+// the time from the earliest start of a sample in the window so parallel workers add up
+func (t *throughputTracker) bytesPerSecond(now time.Time) float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.pruneLocked(now)
+	if len(t.samples) == 0 {
+		return 0
+	}
+
+	var total int64
+	earliest := now
+	for _, s := range t.samples {
+		total += s.bytes
+		if s.started.Before(earliest) {
+			earliest = s.started
+		}
+	}
+
+	elapsed := now.Sub(earliest)
+	if elapsed > throughputWindow {
+		elapsed = throughputWindow
+	}
+	if elapsed <= 0 {
+		return 0
+	}
+
+	return float64(total) / elapsed.Seconds()
+}
+
+// pruneLocked drops samples that finished before the window. Caller must hold t.mu.
+func (t *throughputTracker) pruneLocked(now time.Time) {
+	cutoff := now.Add(-throughputWindow)
+
+	i := 0
+	for i < len(t.samples) && t.samples[i].finished.Before(cutoff) {
+		i++
+	}
+	t.samples = t.samples[i:]
+}
+
+// GetQueueSummary returns the size of the pending work in the queue together with the
+// measured import speed and an estimate of the time needed to finish it
+func (s *Service) GetQueueSummary(ctx context.Context) (*QueueSummary, error) {
+	items, err := s.database.Repository.GetActiveQueueItems(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active queue items: %w", err)
+	}
+
+	summary := &QueueSummary{
+		Slots:          len(items),
+		BytesPerSecond: s.throughput.bytesPerSecond(time.Now()),
+		Paused:         s.IsPaused(),
+	}
+
+	for _, item := range items {
+		if item.FileSize == nil {
+			continue
+		}
+
+		size := *item.FileSize
+		summary.TotalBytes += size
+
+		remaining := size
+		if item.Status == database.QueueStatusProcessing && s.broadcaster != nil {
+			if percentage, ok := s.broadcaster.GetProgress(int(item.ID)); ok {
+				remaining = size * int64(100-percentage) / 100
+			}
+		}
+		summary.RemainingBytes += remaining
+	}
+
+	if summary.BytesPerSecond > 0 && summary.RemainingBytes > 0 {
+		summary.TimeLeft = time.Duration(float64(summary.RemainingBytes) / summary.BytesPerSecond * float64(time.Second))
+	}
+
+	return summary, nil
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
@@ -16,24 +17,31 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/avast/retry-go/v4"
 	"github.com/javi11/altmount/internal/config"
 	"github.com/javi11/altmount/internal/database"
+	"github.com/javi11/altmount/internal/maintenance"
 	"github.com/javi11/altmount/internal/metadata"
 	"github.com/javi11/altmount/internal/pool"
 	"github.com/javi11/altmount/internal/progress"
 	"github.com/javi11/altmount/internal/sabnzbd"
+	"github.com/javi11/altmount/internal/webhook"
 	"github.com/javi11/altmount/pkg/rclonecli"
 	"github.com/javi11/nzbparser"
 )
 
 // ServiceConfig holds configuration for the NZB import service
 type ServiceConfig struct {
-	Workers int // Number of parallel queue workers (default: 2)
+	Workers             int // Number of parallel queue workers (default: 2)
+	HighPriorityWorkers int // Workers, out of Workers, that only claim high priority items (default: 0)
 }
 
+// fallbackTimeout bounds sending a failed item to the SABnzbd fallback host, retries included
+const fallbackTimeout = 2 * time.Minute
+
 // ScanStatus represents the current status of a manual scan
 type ScanStatus string
 
@@ -63,6 +71,7 @@ type Service struct {
 	rcloneClient    rclonecli.RcloneRcClient      // Optional rclone client for VFS notifications
 	configGetter    config.ConfigGetter           // Config getter for dynamic configuration access
 	sabnzbdClient   *sabnzbd.SABnzbdClient        // SABnzbd client for fallback
+	webhookClient   *webhook.Client               // Client for the post-import webhook
 	broadcaster     *progress.ProgressBroadcaster // WebSocket progress broadcaster
 	userRepo        *database.UserRepository      // User repository for API key lookup
 	log             *slog.Logger
@@ -74,6 +83,18 @@ type Service struct {
 	cancel  context.CancelFunc
 	wg      sync.WaitGroup
 
+	// paused stops workers from claiming new queue items; in-flight imports still finish
+	paused atomic.Bool
+
+	// maintenance pauses queue processing like paused while maintenance mode is on
+	maintenance *maintenance.State
+
+	// Import speed measured from recently completed items
+	throughput throughputTracker
+
+	// queueMu serializes the depth check and insert of AddToQueue so max_queue_depth holds
+	queueMu sync.Mutex
+
 	// Cancellation tracking for processing items
 	cancelFuncs map[int64]context.CancelFunc
 	cancelMu    sync.RWMutex
@@ -90,16 +111,20 @@ func NewService(config ServiceConfig, metadataService *metadata.MetadataService,
 	if config.Workers == 0 {
 		config.Workers = 2
 	}
+	// At least one worker must be left for normal priority items
+	if config.HighPriorityWorkers >= config.Workers {
+		config.HighPriorityWorkers = config.Workers - 1
+	}
 
 	// Get the initial config to pass import settings
 	currentConfig := configGetter()
 	maxImportConnections := currentConfig.Import.MaxImportConnections
 	segmentSamplePercentage := currentConfig.Import.SegmentSamplePercentage
-	allowedFileExtensions := currentConfig.Import.AllowedFileExtensions
+	allowedFileExtensions := currentConfig.Import.EffectiveAllowedExtensions()
 	importCacheSizeMB := currentConfig.Import.ImportCacheSizeMB
 
 	// Create processor with poolManager for dynamic pool access
-	processor := NewProcessor(metadataService, poolManager, maxImportConnections, segmentSamplePercentage, allowedFileExtensions, importCacheSizeMB, broadcaster)
+	processor := NewProcessor(metadataService, poolManager, maxImportConnections, segmentSamplePercentage, currentConfig.Import.SampleStrategy, allowedFileExtensions, currentConfig.Import.MinFileSizeBytes, importCacheSizeMB, broadcaster)
 
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -111,6 +136,7 @@ func NewService(config ServiceConfig, metadataService *metadata.MetadataService,
 		rcloneClient:    rcloneClient,
 		configGetter:    configGetter,
 		sabnzbdClient:   sabnzbd.NewSABnzbdClient(),
+		webhookClient:   webhook.NewClient(),
 		broadcaster:     broadcaster,
 		userRepo:        userRepo,
 		log:             slog.Default().With("component", "importer-service"),
@@ -145,7 +171,8 @@ func (s *Service) Start(ctx context.Context) error {
 	}
 
 	s.running = true
-	s.log.InfoContext(ctx, fmt.Sprintf("NZB import service started successfully with %d workers", s.config.Workers))
+	s.log.InfoContext(ctx, fmt.Sprintf("NZB import service started successfully with %d workers", s.config.Workers),
+		"high_priority_workers", s.config.HighPriorityWorkers)
 
 	return nil
 }
@@ -196,6 +223,39 @@ func (s *Service) IsRunning() bool {
 	return s.running
 }
 
+// Pause stops queue workers from claiming new items. Imports already in progress are
+// allowed to finish.
+func (s *Service) Pause() {
+	if !s.paused.Swap(true) {
+		s.log.InfoContext(context.Background(), "Import queue paused")
+	}
+}
+
+// Resume lets queue workers claim new items again after Pause
+func (s *Service) Resume() {
+	if s.paused.Swap(false) {
+		s.log.InfoContext(context.Background(), "Import queue resumed")
+	}
+}
+
+// IsPaused returns whether queue processing is paused
+func (s *Service) IsPaused() bool {
+	return s.paused.Load()
+}
+
+// IsBusy reports whether queue items are being imported
+func (s *Service) IsBusy() bool {
+	s.cancelMu.RLock()
+	defer s.cancelMu.RUnlock()
+	return len(s.cancelFuncs) > 0
+}
+
+// SetMaintenanceState makes queue workers stop claiming new items while maintenance mode
+// is on. It is independent of Pause, leaving maintenance does not resume a paused queue.
+func (s *Service) SetMaintenanceState(state *maintenance.State) {
+	s.maintenance = state
+}
+
 // SetRcloneClient sets or updates the RClone client for VFS notifications
 func (s *Service) SetRcloneClient(client rclonecli.RcloneRcClient) {
 	s.mu.Lock()
@@ -346,8 +406,11 @@ func (s *Service) performManualScan(ctx context.Context, scanPath string) {
 			return nil
 		}
 
-		// Add to queue
+		// Add to queue, stopping the scan once the queue is full
 		if _, err := s.AddToQueue(path, &scanPath, nil, nil); err != nil {
+			if errors.Is(err, ErrQueueFull) {
+				return err
+			}
 			s.log.ErrorContext(ctx, "Failed to add file to queue during scan", "file", path, "error", err)
 		}
 
@@ -382,8 +445,16 @@ func (s *Service) isFileAlreadyInQueue(filePath string) bool {
 	return inQueue
 }
 
-// AddToQueue adds a new NZB file to the import queue with optional category and priority
+// AddToQueue adds a new NZB file to the import queue with optional category and priority.
+// It returns an error wrapping ErrQueueFull when the queue has reached import.max_queue_depth.
 func (s *Service) AddToQueue(filePath string, relativePath *string, category *string, priority *database.QueuePriority) (*database.ImportQueueItem, error) {
+	s.queueMu.Lock()
+	defer s.queueMu.Unlock()
+
+	if err := s.checkQueueDepth(filePath); err != nil {
+		return nil, err
+	}
+
 	// Calculate file size before adding to queue
 	var fileSize *int64
 	if size, err := s.CalculateFileSizeOnly(filePath); err != nil {
@@ -426,6 +497,36 @@ func (s *Service) AddToQueue(filePath string, relativePath *string, category *st
 	return item, nil
 }
 
+// checkQueueDepth returns an error wrapping ErrQueueFull if adding filePath would take the
+// queue over import.max_queue_depth. Files already in the queue are updated in place, so
+// they are always accepted.
+func (s *Service) checkQueueDepth(filePath string) error {
+	maxDepth := s.configGetter().Import.MaxQueueDepth
+	if maxDepth <= 0 {
+		return nil
+	}
+
+	ctx := context.Background()
+	depth, err := s.database.Repository.CountActiveQueueItems(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check queue depth: %w", err)
+	}
+	if depth < maxDepth {
+		return nil
+	}
+
+	if s.isFileAlreadyInQueue(filePath) {
+		return nil
+	}
+
+	s.log.WarnContext(ctx, "Rejected NZB, import queue is full",
+		"file", filePath,
+		"depth", depth,
+		"max_queue_depth", maxDepth)
+
+	return fmt.Errorf("%w: %d items pending or processing, max_queue_depth is %d", ErrQueueFull, depth, maxDepth)
+}
+
 // workerLoop processes queue items
 func (s *Service) workerLoop(workerID int) {
 	defer s.wg.Done()
@@ -450,6 +551,12 @@ func (s *Service) workerLoop(workerID int) {
 	}
 }
 
+// isHighPriorityWorker reports whether the worker is reserved for high priority items.
+// The first HighPriorityWorkers workers are reserved, the rest claim items of any priority.
+func (s *Service) isHighPriorityWorker(workerID int) bool {
+	return workerID < s.config.HighPriorityWorkers
+}
+
 // isDatabaseContentionError checks if an error is a retryable database contention error
 func isDatabaseContentionError(err error) bool {
 	if err == nil {
@@ -465,7 +572,12 @@ func (s *Service) claimItemWithRetry(ctx context.Context, workerID int) (*databa
 
 	err := retry.Do(
 		func() error {
-			claimedItem, err := s.database.Repository.ClaimNextQueueItem(ctx)
+			claim := s.database.Repository.ClaimNextQueueItem
+			if s.isHighPriorityWorker(workerID) {
+				claim = s.database.Repository.ClaimNextHighPriorityQueueItem
+			}
+
+			claimedItem, err := claim(ctx)
 			if err != nil {
 				return err
 			}
@@ -508,6 +620,10 @@ func (s *Service) claimItemWithRetry(ctx context.Context, workerID int) (*databa
 
 // processQueueItems gets and processes pending queue items using two-database workflow
 func (s *Service) processQueueItems(ctx context.Context, workerID int) {
+	if s.IsPaused() || s.maintenance.Enabled() {
+		return
+	}
+
 	// Step 1: Atomically claim next available item from queue database with retry logic
 	item, err := s.claimItemWithRetry(ctx, workerID)
 	if err != nil {
@@ -540,15 +656,19 @@ func (s *Service) processQueueItems(ctx context.Context, workerID int) {
 	}()
 
 	// Step 3: Process the NZB file and write to main database using cancellable context
+	itemCtx, record := metadata.WithImportRecord(itemCtx)
+	startedAt := time.Now()
 	resultingPath, processingErr := s.processNzbItem(itemCtx, item)
 
 	// Step 4: Update queue database with results
 	if processingErr != nil {
 		// Handle failure in queue database
-		s.handleProcessingFailure(ctx, item, processingErr)
+		s.handleProcessingFailure(ctx, item, processingErr, itemCtx.Err(), record)
 	} else {
 		// Handle success (storage path, VFS notification, symlinks, status update)
-		s.handleProcessingSuccess(ctx, item, resultingPath)
+		if err := s.handleProcessingSuccess(ctx, item, resultingPath); err == nil && item.FileSize != nil {
+			s.throughput.record(startedAt, time.Now(), *item.FileSize)
+		}
 	}
 }
 
@@ -572,7 +692,11 @@ func (s *Service) refreshMountPathIfNeeded(ctx context.Context, resultingPath st
 
 // processNzbItem processes the NZB file for a queue item
 func (s *Service) processNzbItem(ctx context.Context, item *database.ImportQueueItem) (string, error) {
-	// Determine the base path, incorporating category if present
+	return s.processor.ProcessNzbFile(ctx, item.NzbPath, itemBasePath(item), int(item.ID))
+}
+
+// itemBasePath returns the base path a queue item is imported under, incorporating its category if present
+func itemBasePath(item *database.ImportQueueItem) string {
 	basePath := ""
 	if item.RelativePath != nil {
 		basePath = *item.RelativePath
@@ -583,7 +707,7 @@ func (s *Service) processNzbItem(ctx context.Context, item *database.ImportQueue
 		basePath = filepath.Join(basePath, *item.Category)
 	}
 
-	return s.processor.ProcessNzbFile(ctx, item.NzbPath, basePath, int(item.ID))
+	return basePath
 }
 
 // handleProcessingSuccess handles all steps after successful NZB processing
@@ -630,26 +754,67 @@ func (s *Service) handleProcessingSuccess(ctx context.Context, item *database.Im
 	}
 
 	s.log.InfoContext(ctx, "Successfully processed queue item", "queue_id", item.ID, "file", item.NzbPath)
+
+	// Notify the post-import webhook (async, never fails the import)
+	s.notifyPostImportWebhook(ctx, item, resultingPath)
+
 	return nil
 }
 
-// handleProcessingFailure handles when processing fails
-func (s *Service) handleProcessingFailure(ctx context.Context, item *database.ImportQueueItem, processingErr error) {
+// PostImportEvent is the payload sent to the post-import webhook
+type PostImportEvent struct {
+	Event     string                `json:"event"` // Always "import.completed"
+	QueueID   int64                 `json:"queue_id"`
+	Path      string                `json:"path"` // Imported path relative to the mount
+	Size      int64                 `json:"size"` // Total size in bytes, 0 when unknown
+	Strategy  config.ImportStrategy `json:"strategy"`
+	Category  string                `json:"category,omitempty"`
+	SourceNZB string                `json:"source_nzb"`
+	Timestamp time.Time             `json:"timestamp"`
+}
+
+// notifyPostImportWebhook sends a PostImportEvent for item when a webhook URL is configured
+func (s *Service) notifyPostImportWebhook(ctx context.Context, item *database.ImportQueueItem, resultingPath string) {
+	cfg := s.configGetter()
+	if cfg.Import.PostImportWebhookURL == "" {
+		return
+	}
+
+	strategy, _ := s.importSettingsForItem(cfg, item)
+	event := PostImportEvent{
+		Event:     "import.completed",
+		QueueID:   item.ID,
+		Path:      resultingPath,
+		Strategy:  strategy,
+		SourceNZB: item.NzbPath,
+		Timestamp: time.Now().UTC(),
+	}
+	if item.FileSize != nil {
+		event.Size = *item.FileSize
+	}
+	if item.Category != nil {
+		event.Category = *item.Category
+	}
+
+	s.webhookClient.SendAsync(ctx, cfg.Import.PostImportWebhookURL, cfg.Import.PostImportWebhookSecret, event)
+}
+
+// handleProcessingFailure handles when processing fails. itemErr is the error of the item's own
+// context, record lists the files the run created.
+func (s *Service) handleProcessingFailure(ctx context.Context, item *database.ImportQueueItem, processingErr, itemErr error, record *metadata.ImportRecord) {
 	errorMessage := processingErr.Error()
 
-	// Check if the error was due to cancellation
-	if strings.Contains(errorMessage, "context canceled") || strings.Contains(errorMessage, "processing cancelled") {
-		errorMessage = "Processing cancelled by user request"
-		s.log.InfoContext(ctx, "Processing cancelled by user",
-			"queue_id", item.ID,
-			"file", item.NzbPath)
-	} else {
-		s.log.WarnContext(ctx, "Processing failed",
-			"queue_id", item.ID,
-			"file", item.NzbPath,
-			"error", processingErr)
+	// Check if the item was cancelled
+	if errors.Is(itemErr, context.Canceled) {
+		s.handleProcessingCancelled(ctx, item, record)
+		return
 	}
 
+	s.log.WarnContext(ctx, "Processing failed",
+		"queue_id", item.ID,
+		"file", item.NzbPath,
+		"error", processingErr)
+
 	// Mark as failed in queue database (no automatic retry)
 	if err := s.database.Repository.UpdateQueueItemStatus(ctx, item.ID, database.QueueStatusFailed, &errorMessage); err != nil {
 		s.log.ErrorContext(ctx, "Failed to mark item as failed", "queue_id", item.ID, "error", err)
@@ -665,29 +830,80 @@ func (s *Service) handleProcessingFailure(ctx context.Context, item *database.Im
 	}
 
 	cfg := s.configGetter()
-	// Attempt SABnzbd fallback if configured
+	// Attempt SABnzbd fallback if configured, in the background so retries against a slow
+	// host do not hold up the worker. Stop cancels the send and waits for it like the workers.
 	if cfg.SABnzbd.FallbackHost != "" && cfg.SABnzbd.FallbackAPIKey != "" {
-		if err := s.attemptSABnzbdFallback(ctx, item); err != nil {
-			s.log.ErrorContext(ctx, "Failed to send to external SABnzbd",
-				"queue_id", item.ID,
-				"file", item.NzbPath,
-				"fallback_host", s.configGetter().SABnzbd.FallbackHost,
-				"error", err)
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.sendToFallback(s.ctx, item)
+		}()
+	}
+}
+
+// sendToFallback sends a failed item to the SABnzbd fallback host and marks it as fallback
+// once the host accepted it. It gives up after fallbackTimeout.
+func (s *Service) sendToFallback(ctx context.Context, item *database.ImportQueueItem) {
+	ctx, cancel := context.WithTimeout(ctx, fallbackTimeout)
+	defer cancel()
 
+	if err := s.attemptSABnzbdFallback(ctx, item); errors.Is(err, sabnzbd.ErrCircuitOpen) {
+		// The item stays failed with its original error; it can be retried once the host is back
+		s.log.WarnContext(ctx, "Skipping SABnzbd fallback, fallback host is unavailable",
+			"queue_id", item.ID,
+			"file", item.NzbPath,
+			"fallback_host", s.configGetter().SABnzbd.FallbackHost,
+			"breaker_state", s.sabnzbdClient.BreakerStatus().State)
+	} else if err != nil {
+		s.log.ErrorContext(ctx, "Failed to send to external SABnzbd",
+			"queue_id", item.ID,
+			"file", item.NzbPath,
+			"fallback_host", s.configGetter().SABnzbd.FallbackHost,
+			"breaker_state", s.sabnzbdClient.BreakerStatus().State,
+			"error", err)
+	} else {
+		// Mark item as fallback instead of removing from queue. The host already has the item,
+		// so record it even if the service is stopping.
+		if err := s.database.Repository.UpdateQueueItemStatus(context.WithoutCancel(ctx), item.ID, database.QueueStatusFallback, nil); err != nil {
+			s.log.ErrorContext(ctx, "Failed to mark item as fallback", "queue_id", item.ID, "error", err)
 		} else {
-			// Mark item as fallback instead of removing from queue
-			if err := s.database.Repository.UpdateQueueItemStatus(ctx, item.ID, database.QueueStatusFallback, nil); err != nil {
-				s.log.ErrorContext(ctx, "Failed to mark item as fallback", "queue_id", item.ID, "error", err)
-			} else {
-				s.log.DebugContext(ctx, "Item marked as fallback after successful SABnzbd transfer",
-					"queue_id", item.ID,
-					"file", item.NzbPath,
-					"fallback_host", s.configGetter().SABnzbd.FallbackHost)
-			}
+			s.log.DebugContext(ctx, "Item marked as fallback after successful SABnzbd transfer",
+				"queue_id", item.ID,
+				"file", item.NzbPath,
+				"fallback_host", s.configGetter().SABnzbd.FallbackHost)
 		}
 	}
 }
 
+// handleProcessingCancelled removes the files a cancelled item already imported and marks it as failed.
+// Cancelled items are not sent to the SABnzbd fallback, they can be retried from the queue.
+func (s *Service) handleProcessingCancelled(ctx context.Context, item *database.ImportQueueItem, record *metadata.ImportRecord) {
+	s.log.InfoContext(ctx, "Processing cancelled by user",
+		"queue_id", item.ID,
+		"file", item.NzbPath)
+
+	deleted, err := s.metadataService.DeleteImportedFiles(record)
+	if err != nil {
+		s.log.WarnContext(ctx, "Failed to remove partially imported files",
+			"queue_id", item.ID,
+			"error", err)
+	}
+	if len(deleted) > 0 {
+		s.log.InfoContext(ctx, "Removed partially imported files",
+			"queue_id", item.ID,
+			"files", len(deleted))
+	}
+
+	errorMessage := "Processing cancelled by user request"
+	if err := s.database.Repository.UpdateQueueItemStatus(ctx, item.ID, database.QueueStatusFailed, &errorMessage); err != nil {
+		s.log.ErrorContext(ctx, "Failed to mark cancelled item as failed", "queue_id", item.ID, "error", err)
+	}
+
+	if s.broadcaster != nil {
+		s.broadcaster.ClearProgress(int(item.ID))
+	}
+}
+
 // attemptSABnzbdFallback attempts to send a failed import to an external SABnzbd instance
 func (s *Service) attemptSABnzbdFallback(ctx context.Context, item *database.ImportQueueItem) error {
 	cfg := s.configGetter()
@@ -731,20 +947,31 @@ func (s *Service) attemptSABnzbdFallback(ctx context.Context, item *database.Imp
 	return nil
 }
 
+// GetFallbackStatus returns the circuit breaker state of the SABnzbd fallback client
+func (s *Service) GetFallbackStatus() sabnzbd.BreakerStatus {
+	return s.sabnzbdClient.BreakerStatus()
+}
+
 // ServiceStats holds statistics about the service
 type ServiceStats struct {
 	IsRunning  bool                 `json:"is_running"`
+	IsPaused   bool                 `json:"is_paused"`
 	Workers    int                  `json:"workers"`
 	QueueStats *database.QueueStats `json:"queue_stats,omitempty"`
 	ScanInfo   ScanInfo             `json:"scan_info"`
+
+	HighPriorityWorkers int `json:"high_priority_workers"`
 }
 
 // GetStats returns service statistics
 func (s *Service) GetStats(ctx context.Context) (*ServiceStats, error) {
 	stats := &ServiceStats{
 		IsRunning: s.IsRunning(),
+		IsPaused:  s.IsPaused(),
 		Workers:   s.config.Workers,
 		ScanInfo:  s.GetScanStatus(),
+
+		HighPriorityWorkers: s.config.HighPriorityWorkers,
 	}
 
 	// Add queue statistics
@@ -859,12 +1086,13 @@ func (s *Service) ProcessItemInBackground(ctx context.Context, itemID int64) {
 		}()
 
 		// Process the NZB file using cancellable context
+		itemCtx, record := metadata.WithImportRecord(itemCtx)
 		resultingPath, processingErr := s.processNzbItem(itemCtx, item)
 
 		// Update queue database with results
 		if processingErr != nil {
 			// Handle failure
-			s.handleProcessingFailure(ctx, item, processingErr)
+			s.handleProcessingFailure(ctx, item, processingErr, itemCtx.Err(), record)
 		} else {
 			// Handle success (storage path, VFS notification, symlinks, status update)
 			s.handleProcessingSuccess(ctx, item, resultingPath)
@@ -961,16 +1189,26 @@ func (s *Service) convertPriorityToSABnzbd(priority database.QueuePriority) stri
 	}
 }
 
+// importSettingsForItem returns the import strategy and directory for the item's category
+func (s *Service) importSettingsForItem(cfg *config.Config, item *database.ImportQueueItem) (config.ImportStrategy, *string) {
+	category := ""
+	if item.Category != nil {
+		category = *item.Category
+	}
+	return cfg.ImportSettingsForCategory(category)
+}
+
 // createSymlinks creates symlinks for an imported file or directory in the category folder
 func (s *Service) createSymlinks(item *database.ImportQueueItem, resultingPath string) error {
 	cfg := s.configGetter()
 
-	// Check if symlinks are enabled
-	if cfg.Import.ImportStrategy != config.ImportStrategySYMLINK {
+	// Check if symlinks are enabled for the item's category or any extension rule
+	strategy, importDir := s.importSettingsForItem(cfg, item)
+	if strategy != config.ImportStrategySYMLINK && !cfg.Import.HasExtensionStrategy(config.ImportStrategySYMLINK) {
 		return nil // Skip if not enabled
 	}
 
-	if cfg.Import.ImportDir == nil || *cfg.Import.ImportDir == "" {
+	if importDir == nil || *importDir == "" {
 		return fmt.Errorf("symlink directory not configured")
 	}
 
@@ -988,14 +1226,20 @@ func (s *Service) createSymlinks(item *database.ImportQueueItem, resultingPath s
 		metaFile := metadataPath + ".meta"
 		if _, metaErr := os.Stat(metaFile); metaErr == nil {
 			// It's a single file
-			return s.createSingleSymlink(actualPath, resultingPath)
+			if cfg.Import.StrategyForFile(resultingPath, strategy) != config.ImportStrategySYMLINK {
+				return nil
+			}
+			return s.createSingleSymlink(*importDir, actualPath, resultingPath)
 		}
 		return fmt.Errorf("failed to stat metadata path: %w", err)
 	}
 
 	if !fileInfo.IsDir() {
 		// Single file - create one symlink
-		return s.createSingleSymlink(actualPath, resultingPath)
+		if cfg.Import.StrategyForFile(resultingPath, strategy) != config.ImportStrategySYMLINK {
+			return nil
+		}
+		return s.createSingleSymlink(*importDir, actualPath, resultingPath)
 	}
 
 	// Directory - walk through and create symlinks for all files
@@ -1034,6 +1278,11 @@ func (s *Service) createSymlinks(item *database.ImportQueueItem, resultingPath s
 		// Remove .meta extension to get the actual filename
 		relPath = strings.TrimSuffix(relPath, ".meta")
 
+		// Extension rules may import this file with a different strategy
+		if cfg.Import.StrategyForFile(relPath, strategy) != config.ImportStrategySYMLINK {
+			return nil
+		}
+
 		// Build the actual file path in the mount (mount root + virtual path)
 		actualFilePath := filepath.Join(cfg.MountPath, relPath)
 
@@ -1041,7 +1290,7 @@ func (s *Service) createSymlinks(item *database.ImportQueueItem, resultingPath s
 		fileResultingPath := relPath
 
 		// Create symlink for this file using the helper function
-		if err := s.createSingleSymlink(actualFilePath, fileResultingPath); err != nil {
+		if err := s.createSingleSymlink(*importDir, actualFilePath, fileResultingPath); err != nil {
 			s.log.ErrorContext(context.Background(), "Failed to create symlink",
 				"path", actualFilePath,
 				"error", err)
@@ -1069,18 +1318,16 @@ func (s *Service) createSymlinks(item *database.ImportQueueItem, resultingPath s
 	return nil
 }
 
-// createSingleSymlink creates a symlink for a single file
-func (s *Service) createSingleSymlink(actualPath, resultingPath string) error {
-	cfg := s.configGetter()
-
-	baseDir := filepath.Join(*cfg.Import.ImportDir, filepath.Dir(resultingPath))
+// createSingleSymlink creates a symlink for a single file under importDir
+func (s *Service) createSingleSymlink(importDir, actualPath, resultingPath string) error {
+	baseDir := filepath.Join(importDir, filepath.Dir(resultingPath))
 
 	// Ensure category directory exists
 	if err := os.MkdirAll(baseDir, 0755); err != nil {
 		return fmt.Errorf("failed to create symlink category directory: %w", err)
 	}
 
-	symlinkPath := filepath.Join(*cfg.Import.ImportDir, resultingPath)
+	symlinkPath := filepath.Join(importDir, resultingPath)
 
 	// Check if symlink already exists
 	if _, err := os.Lstat(symlinkPath); err == nil {
@@ -1102,12 +1349,13 @@ func (s *Service) createSingleSymlink(actualPath, resultingPath string) error {
 func (s *Service) createStrmFiles(item *database.ImportQueueItem, resultingPath string) error {
 	cfg := s.configGetter()
 
-	// Check if STRM is enabled
-	if cfg.Import.ImportStrategy != config.ImportStrategySTRM {
+	// Check if STRM is enabled for the item's category or any extension rule
+	strategy, importDir := s.importSettingsForItem(cfg, item)
+	if strategy != config.ImportStrategySTRM && !cfg.Import.HasExtensionStrategy(config.ImportStrategySTRM) {
 		return nil // Skip if not enabled
 	}
 
-	if cfg.Import.ImportDir == nil || *cfg.Import.ImportDir == "" {
+	if importDir == nil || *importDir == "" {
 		return fmt.Errorf("STRM directory not configured")
 	}
 
@@ -1121,14 +1369,20 @@ func (s *Service) createStrmFiles(item *database.ImportQueueItem, resultingPath
 		metaFile := metadataPath + ".meta"
 		if _, metaErr := os.Stat(metaFile); metaErr == nil {
 			// It's a single file
-			return s.createSingleStrmFile(resultingPath, cfg.WebDAV.Port)
+			if cfg.Import.StrategyForFile(resultingPath, strategy) != config.ImportStrategySTRM {
+				return nil
+			}
+			return s.createSingleStrmFile(*importDir, resultingPath, cfg.WebDAV.Port)
 		}
 		return fmt.Errorf("failed to stat metadata path: %w", err)
 	}
 
 	if !fileInfo.IsDir() {
 		// Single file - create one STRM file
-		return s.createSingleStrmFile(resultingPath, cfg.WebDAV.Port)
+		if cfg.Import.StrategyForFile(resultingPath, strategy) != config.ImportStrategySTRM {
+			return nil
+		}
+		return s.createSingleStrmFile(*importDir, resultingPath, cfg.WebDAV.Port)
 	}
 
 	// Directory - walk through and create STRM files for all files
@@ -1167,8 +1421,13 @@ func (s *Service) createStrmFiles(item *database.ImportQueueItem, resultingPath
 		// Remove .meta extension to get the actual filename
 		relPath = strings.TrimSuffix(relPath, ".meta")
 
+		// Extension rules may import this file with a different strategy
+		if cfg.Import.StrategyForFile(relPath, strategy) != config.ImportStrategySTRM {
+			return nil
+		}
+
 		// Create STRM file for this file
-		if err := s.createSingleStrmFile(relPath, cfg.WebDAV.Port); err != nil {
+		if err := s.createSingleStrmFile(*importDir, relPath, cfg.WebDAV.Port); err != nil {
 			s.log.ErrorContext(context.Background(), "Failed to create STRM file",
 				"path", relPath,
 				"error", err)
@@ -1196,12 +1455,11 @@ func (s *Service) createStrmFiles(item *database.ImportQueueItem, resultingPath
 	return nil
 }
 
-// createSingleStrmFile creates a STRM file for a single file with authentication
-func (s *Service) createSingleStrmFile(virtualPath string, port int) error {
+// createSingleStrmFile creates a STRM file for a single file under importDir with authentication
+func (s *Service) createSingleStrmFile(importDir, virtualPath string, port int) error {
 	ctx := context.Background()
-	cfg := s.configGetter()
 
-	baseDir := filepath.Join(*cfg.Import.ImportDir, filepath.Dir(virtualPath))
+	baseDir := filepath.Join(importDir, filepath.Dir(virtualPath))
 
 	// Ensure directory exists
 	if err := os.MkdirAll(baseDir, 0755); err != nil {
@@ -1212,7 +1470,7 @@ func (s *Service) createSingleStrmFile(virtualPath string, port int) error {
 	filename := filepath.Base(virtualPath)
 	filename = filename + ".strm"
 
-	strmPath := filepath.Join(*cfg.Import.ImportDir, filepath.Dir(virtualPath), filename)
+	strmPath := filepath.Join(importDir, filepath.Dir(virtualPath), filename)
 
 	// Get first admin user's API key for authentication
 	users, err := s.userRepo.GetAllUsers(ctx)
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/javi11/altmount/internal/config"
 	"github.com/javi11/altmount/internal/importer/parser"
 	"github.com/javi11/altmount/internal/importer/utils"
 	"github.com/javi11/altmount/internal/importer/validation"
@@ -27,6 +28,7 @@ func ProcessSingleFile(
 	poolManager pool.Manager,
 	maxValidationGoroutines int,
 	segmentSamplePercentage int,
+	sampleStrategy config.SampleStrategy,
 	allowedFileExtensions []string,
 ) (string, error) {
 	// Validate file extension before processing
@@ -41,6 +43,14 @@ func ProcessSingleFile(
 	virtualFilePath := filepath.Join(virtualDir, file.Filename)
 	virtualFilePath = strings.ReplaceAll(virtualFilePath, string(filepath.Separator), "/")
 
+	// The same release grabbed twice resolves to the copy that is already imported
+	if existing, ok := metadataService.FindDuplicateFile(virtualFilePath, file.Size, file.Segments); ok {
+		slog.InfoContext(ctx, "Skipping duplicate file, identical content is already imported",
+			"file", file.Filename,
+			"existing_path", existing)
+		return existing, nil
+	}
+
 	// Validate segments
 	if err := validation.ValidateSegmentsForFile(
 		ctx,
@@ -51,6 +61,7 @@ func ProcessSingleFile(
 		poolManager,
 		maxValidationGoroutines,
 		segmentSamplePercentage,
+		sampleStrategy,
 		nil, // No progress callback for single file imports
 	); err != nil {
 		return "", err
@@ -78,10 +89,14 @@ func ProcessSingleFile(
 		file.ReleaseDate.Unix(),
 		par2Refs,
 	)
+	// The PAR2 checksums let the file be verified without relying on sampling
+	fileMeta.Par2Info = file.Par2Info
 
 	// Delete old metadata if exists (simple collision handling)
 	metadataPath := metadataService.GetMetadataFilePath(virtualFilePath)
-	if _, err := os.Stat(metadataPath); err == nil {
+	_, statErr := os.Stat(metadataPath)
+	existed := statErr == nil
+	if existed {
 		_ = metadataService.DeleteFileMetadata(virtualFilePath)
 	}
 
@@ -89,6 +104,7 @@ func ProcessSingleFile(
 	if err := metadataService.WriteFileMetadata(virtualFilePath, fileMeta); err != nil {
 		return "", fmt.Errorf("failed to write metadata for single file %s: %w", file.Filename, err)
 	}
+	metadata.RecordImportedFile(ctx, virtualFilePath, existed)
 
 	slog.InfoContext(ctx, "Successfully processed single file",
 		"file", file.Filename,
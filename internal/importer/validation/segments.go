@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/javi11/altmount/internal/config"
 	"github.com/javi11/altmount/internal/encryption/rclone"
 	metapb "github.com/javi11/altmount/internal/metadata/proto"
 	"github.com/javi11/altmount/internal/pool"
@@ -27,6 +28,7 @@ func ValidateSegmentsForFile(
 	poolManager pool.Manager,
 	maxGoroutines int,
 	samplePercentage int,
+	sampleStrategy config.SampleStrategy,
 	progressTracker progress.ProgressTracker,
 ) error {
 	if len(segments) == 0 {
@@ -76,7 +78,7 @@ func ValidateSegmentsForFile(
 	}
 
 	// Validate segment availability using shared validation logic
-	if err := usenet.ValidateSegmentAvailability(ctx, segments, poolManager, maxGoroutines, samplePercentage, progressTracker); err != nil {
+	if err := usenet.ValidateSegmentAvailability(ctx, segments, poolManager, maxGoroutines, samplePercentage, sampleStrategy, progressTracker); err != nil {
 		return err
 	}
 
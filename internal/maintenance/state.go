@@ -0,0 +1,80 @@
+// Package maintenance holds the global maintenance mode. While it is enabled background
+// activity is frozen: the importer stops claiming queue items, the health and library sync
+// workers skip their scheduled runs and WebDAV rejects writes. Streaming stays available.
+package maintenance
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// State is the maintenance mode shared by the subsystems. A nil *State is never in
+// maintenance, so subsystems created without one behave as before.
+type State struct {
+	enabled atomic.Bool
+
+	mu     sync.Mutex
+	reason string
+	since  time.Time
+}
+
+// Status describes the maintenance mode for the API
+type Status struct {
+	Enabled bool       `json:"enabled"`
+	Reason  string     `json:"reason,omitempty"`
+	Since   *time.Time `json:"since,omitempty"`
+}
+
+// NewState creates a maintenance state with maintenance mode disabled
+func NewState() *State {
+	return &State{}
+}
+
+// Enabled reports whether maintenance mode is on
+func (s *State) Enabled() bool {
+	return s != nil && s.enabled.Load()
+}
+
+// Enable turns maintenance mode on, recording why. It reports whether the mode changed;
+// enabling it again only updates the reason.
+func (s *State) Enable(reason string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.reason = reason
+	if s.enabled.Load() {
+		return false
+	}
+
+	s.since = time.Now()
+	s.enabled.Store(true)
+	return true
+}
+
+// Disable turns maintenance mode off and reports whether the mode changed
+func (s *State) Disable() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.reason = ""
+	s.since = time.Time{}
+	return s.enabled.Swap(false)
+}
+
+// Status returns the current maintenance mode
+func (s *State) Status() Status {
+	if s == nil {
+		return Status{}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := Status{Enabled: s.enabled.Load(), Reason: s.reason}
+	if status.Enabled {
+		since := s.since
+		status.Since = &since
+	}
+	return status
+}
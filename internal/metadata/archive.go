@@ -0,0 +1,304 @@
+package metadata
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	metapb "github.com/javi11/altmount/internal/metadata/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// Metadata archives are gzip compressed tar files. The first entry is manifestName holding
+// an ArchiveManifest, followed by one entry per file under archiveFilesDir. Each file entry
+// holds the serialized FileMetadata protobuf, named after the file's virtual path relative
+// to the exported root with the .meta extension, e.g. "files/Movie (2024)/Movie.mkv.meta".
+const (
+	archiveFormat   = "altmount-metadata"
+	archiveVersion  = 1
+	manifestName    = "manifest.json"
+	archiveFilesDir = "files/"
+)
+
+// ArchiveManifest describes the contents of a metadata archive
+type ArchiveManifest struct {
+	Format    string    `json:"format"`
+	Version   int       `json:"version"`
+	Root      string    `json:"root"`  // Virtual path the archive was exported from
+	Files     int       `json:"files"` // Number of file entries
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CollisionPolicy decides what ImportSubtree does with a file that already has metadata
+type CollisionPolicy string
+
+const (
+	// CollisionSkip keeps the existing file and ignores the archived one
+	CollisionSkip CollisionPolicy = "skip"
+	// CollisionOverwrite replaces the existing file with the archived one
+	CollisionOverwrite CollisionPolicy = "overwrite"
+	// CollisionRename imports the archived file under a free name, e.g. "Movie (2).mkv"
+	CollisionRename CollisionPolicy = "rename"
+)
+
+// SubtreeImportResult summarizes an ImportSubtree call
+type SubtreeImportResult struct {
+	Root     string `json:"root"`     // Virtual path the files were imported under
+	Imported int    `json:"imported"` // Files written, including renamed and overwritten ones
+	Skipped  int    `json:"skipped"`  // Files left out because their path was taken
+	Renamed  int    `json:"renamed"`  // Files written under a different name
+}
+
+// ExportSubtree writes the metadata of every file under virtualPath to w as a metadata
+// archive and returns the number of files exported. virtualPath may also name a single file,
+// it is then exported relative to its directory.
+// The source NZB paths are exported as is, they may not exist on the importing instance.
+func (ms *MetadataService) ExportSubtree(virtualPath string, w io.Writer) (int, error) {
+	root := normalizeVirtualPath(virtualPath)
+
+	root, files, err := ms.subtreeFiles(root)
+	if err != nil {
+		return 0, err
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	manifest, err := json.MarshalIndent(ArchiveManifest{
+		Format:    archiveFormat,
+		Version:   archiveVersion,
+		Root:      root,
+		Files:     len(files),
+		CreatedAt: time.Now().UTC(),
+	}, "", "  ")
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode archive manifest: %w", err)
+	}
+	if err := writeArchiveEntry(tw, manifestName, manifest); err != nil {
+		return 0, err
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file.diskPath)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read metadata of %s: %w", file.relPath, err)
+		}
+		if err := writeArchiveEntry(tw, archiveFilesDir+file.relPath+".meta", data); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return 0, fmt.Errorf("failed to finish metadata archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return 0, fmt.Errorf("failed to finish metadata archive: %w", err)
+	}
+
+	return len(files), nil
+}
+
+// ImportSubtree writes the files of a metadata archive created by ExportSubtree under
+// targetPath, or under the path the archive was exported from when targetPath is empty.
+// Files whose path already has metadata are handled according to onCollision. Files
+// written before an error is returned are kept.
+func (ms *MetadataService) ImportSubtree(r io.Reader, targetPath string, onCollision CollisionPolicy) (*SubtreeImportResult, error) {
+	switch onCollision {
+	case CollisionSkip, CollisionOverwrite, CollisionRename:
+	default:
+		return nil, fmt.Errorf("invalid collision policy %q", onCollision)
+	}
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a metadata archive: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+
+	manifest, err := readArchiveManifest(tr)
+	if err != nil {
+		return nil, err
+	}
+
+	root := manifest.Root
+	if targetPath != "" {
+		root = targetPath
+	}
+	result := &SubtreeImportResult{Root: normalizeVirtualPath(root)}
+
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return result, fmt.Errorf("failed to read metadata archive: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		relPath, err := archiveEntryPath(header.Name)
+		if err != nil {
+			return result, err
+		}
+
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return result, fmt.Errorf("failed to read %s from metadata archive: %w", header.Name, err)
+		}
+		metadata := &metapb.FileMetadata{}
+		if err := proto.Unmarshal(data, metadata); err != nil {
+			return result, fmt.Errorf("invalid metadata for %s in archive: %w", relPath, err)
+		}
+
+		virtualPath := path.Join(result.Root, relPath)
+		if ms.FileExists(virtualPath) {
+			switch onCollision {
+			case CollisionSkip:
+				result.Skipped++
+				continue
+			case CollisionRename:
+				virtualPath = ms.freeVirtualPath(virtualPath)
+				result.Renamed++
+			}
+		}
+
+		// The generation belongs to the exporting instance, start over from the existing file
+		metadata.Generation = 0
+		if err := ms.WriteFileMetadata(virtualPath, metadata); err != nil {
+			return result, fmt.Errorf("failed to import %s: %w", virtualPath, err)
+		}
+		result.Imported++
+	}
+
+	return result, nil
+}
+
+// subtreeFile is a metadata file found under an exported root
+type subtreeFile struct {
+	diskPath string
+	relPath  string // Virtual path relative to the exported root, slash separated
+}
+
+// subtreeFiles returns the metadata files under the virtual path root, along with the
+// directory they are relative to. A single file is relative to its parent directory.
+func (ms *MetadataService) subtreeFiles(root string) (string, []subtreeFile, error) {
+	if root != "/" && ms.FileExists(root) {
+		return path.Dir(root), []subtreeFile{{
+			diskPath: ms.GetMetadataFilePath(root),
+			relPath:  path.Base(root),
+		}}, nil
+	}
+
+	dir := ms.GetMetadataDirectoryPath(root)
+	if !ms.DirectoryExists(root) {
+		return "", nil, fmt.Errorf("no metadata found at %s", root)
+	}
+
+	var files []subtreeFile
+	err := filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(p, ".meta") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		files = append(files, subtreeFile{
+			diskPath: p,
+			relPath:  filepath.ToSlash(strings.TrimSuffix(rel, ".meta")),
+		})
+
+		return nil
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to list metadata under %s: %w", root, err)
+	}
+
+	return root, files, nil
+}
+
+// freeVirtualPath returns the first of "name (2).ext", "name (3).ext"... without metadata
+func (ms *MetadataService) freeVirtualPath(virtualPath string) string {
+	ext := path.Ext(virtualPath)
+	base := strings.TrimSuffix(virtualPath, ext)
+
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s (%d)%s", base, i, ext)
+		if !ms.FileExists(candidate) {
+			return candidate
+		}
+	}
+}
+
+func writeArchiveEntry(tw *tar.Writer, name string, data []byte) error {
+	header := &tar.Header{
+		Name:     name,
+		Mode:     0644,
+		Size:     int64(len(data)),
+		ModTime:  time.Now(),
+		Typeflag: tar.TypeReg,
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write %s to metadata archive: %w", name, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s to metadata archive: %w", name, err)
+	}
+
+	return nil
+}
+
+// readArchiveManifest reads and checks the manifest, which must be the first entry
+func readArchiveManifest(tr *tar.Reader) (*ArchiveManifest, error) {
+	header, err := tr.Next()
+	if err != nil {
+		return nil, fmt.Errorf("not a metadata archive: %w", err)
+	}
+	if header.Name != manifestName {
+		return nil, fmt.Errorf("not a metadata archive: expected %s, found %s", manifestName, header.Name)
+	}
+
+	var manifest ArchiveManifest
+	if err := json.NewDecoder(tr).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("invalid metadata archive manifest: %w", err)
+	}
+	if manifest.Format != archiveFormat {
+		return nil, fmt.Errorf("not a metadata archive: unknown format %q", manifest.Format)
+	}
+	if manifest.Version > archiveVersion {
+		return nil, fmt.Errorf("metadata archive version %d is not supported, upgrade AltMount", manifest.Version)
+	}
+
+	return &manifest, nil
+}
+
+// archiveEntryPath returns the virtual path, relative to the archive root, of a file entry.
+// Names escaping the root are rejected.
+func archiveEntryPath(name string) (string, error) {
+	rel, ok := strings.CutPrefix(name, archiveFilesDir)
+	if !ok || !strings.HasSuffix(rel, ".meta") {
+		return "", fmt.Errorf("unexpected entry %s in metadata archive", name)
+	}
+	rel = strings.TrimSuffix(rel, ".meta")
+
+	if rel == "" || path.IsAbs(rel) || !filepath.IsLocal(filepath.FromSlash(rel)) {
+		return "", fmt.Errorf("invalid path %s in metadata archive", name)
+	}
+
+	return path.Clean(rel), nil
+}
@@ -0,0 +1,268 @@
+package metadata
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	metapb "github.com/javi11/altmount/internal/metadata/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// writeTestFile writes metadata for a file of the given size at virtualPath
+func writeTestFile(t *testing.T, ms *MetadataService, virtualPath string, size int64) *metapb.FileMetadata {
+	t.Helper()
+
+	metadata := &metapb.FileMetadata{
+		FileSize:      size,
+		SourceNzbPath: "/nzbs/" + filepath.Base(virtualPath) + ".nzb",
+		Status:        metapb.FileStatus_FILE_STATUS_HEALTHY,
+		SegmentData: []*metapb.SegmentData{
+			{Id: filepath.Base(virtualPath) + "@example", StartOffset: 0, EndOffset: size - 1, SegmentSize: size},
+		},
+	}
+	if err := ms.WriteFileMetadata(virtualPath, metadata); err != nil {
+		t.Fatalf("WriteFileMetadata(%s): %v", virtualPath, err)
+	}
+	return metadata
+}
+
+// readTestFile reads the metadata at virtualPath, failing the test when there is none
+func readTestFile(t *testing.T, ms *MetadataService, virtualPath string) *metapb.FileMetadata {
+	t.Helper()
+
+	metadata, err := ms.ReadFileMetadata(virtualPath)
+	if err != nil || metadata == nil {
+		t.Fatalf("ReadFileMetadata(%s): %v, %v", virtualPath, metadata, err)
+	}
+	return metadata
+}
+
+// sameFile reports whether two files have the same content, ignoring their generations
+func sameFile(a, b *metapb.FileMetadata) bool {
+	a, b = proto.Clone(a).(*metapb.FileMetadata), proto.Clone(b).(*metapb.FileMetadata)
+	a.Generation, b.Generation = 0, 0
+	return proto.Equal(a, b)
+}
+
+func exportTestSubtree(t *testing.T, ms *MetadataService, virtualPath string, wantFiles int) []byte {
+	t.Helper()
+
+	var archive bytes.Buffer
+	files, err := ms.ExportSubtree(virtualPath, &archive)
+	if err != nil {
+		t.Fatalf("ExportSubtree(%s): %v", virtualPath, err)
+	}
+	if files != wantFiles {
+		t.Fatalf("ExportSubtree(%s): exported %d files, want %d", virtualPath, files, wantFiles)
+	}
+	return archive.Bytes()
+}
+
+func TestExportImportSubtreeRoundTrip(t *testing.T) {
+	src := NewMetadataService(t.TempDir())
+	movie := writeTestFile(t, src, "/movies/Movie (2024)/Movie.mkv", 1000)
+	extra := writeTestFile(t, src, "/movies/Movie (2024)/Extras/Trailer.mkv", 200)
+	writeTestFile(t, src, "/tv/Show/S01E01.mkv", 500)
+
+	archive := exportTestSubtree(t, src, "/movies", 2)
+
+	t.Run("to the exported path", func(t *testing.T) {
+		dst := NewMetadataService(t.TempDir())
+		result, err := dst.ImportSubtree(bytes.NewReader(archive), "", CollisionSkip)
+		if err != nil {
+			t.Fatalf("ImportSubtree: %v", err)
+		}
+		if result.Root != "/movies" || result.Imported != 2 {
+			t.Errorf("result: got %+v, want 2 files imported under /movies", result)
+		}
+
+		if got := readTestFile(t, dst, "/movies/Movie (2024)/Movie.mkv"); !sameFile(got, movie) {
+			t.Errorf("movie: got %v, want %v", got, movie)
+		}
+		if got := readTestFile(t, dst, "/movies/Movie (2024)/Extras/Trailer.mkv"); !sameFile(got, extra) {
+			t.Errorf("extra: got %v, want %v", got, extra)
+		}
+		if dst.FileExists("/tv/Show/S01E01.mkv") {
+			t.Error("file outside the exported subtree was imported")
+		}
+	})
+
+	t.Run("to a target path", func(t *testing.T) {
+		dst := NewMetadataService(t.TempDir())
+		if _, err := dst.ImportSubtree(bytes.NewReader(archive), "/library/films", CollisionSkip); err != nil {
+			t.Fatalf("ImportSubtree: %v", err)
+		}
+		if got := readTestFile(t, dst, "/library/films/Movie (2024)/Movie.mkv"); !sameFile(got, movie) {
+			t.Errorf("movie: got %v, want %v", got, movie)
+		}
+	})
+
+	t.Run("single file", func(t *testing.T) {
+		archive := exportTestSubtree(t, src, "/movies/Movie (2024)/Movie.mkv", 1)
+
+		dst := NewMetadataService(t.TempDir())
+		result, err := dst.ImportSubtree(bytes.NewReader(archive), "", CollisionSkip)
+		if err != nil {
+			t.Fatalf("ImportSubtree: %v", err)
+		}
+		if result.Root != "/movies/Movie (2024)" || result.Imported != 1 {
+			t.Errorf("result: got %+v, want 1 file imported under its directory", result)
+		}
+		readTestFile(t, dst, "/movies/Movie (2024)/Movie.mkv")
+	})
+}
+
+func TestImportSubtreeCollisions(t *testing.T) {
+	src := NewMetadataService(t.TempDir())
+	archived := writeTestFile(t, src, "/movies/Movie.mkv", 1000)
+	archive := exportTestSubtree(t, src, "/movies", 1)
+
+	tests := []struct {
+		policy       CollisionPolicy
+		wantExisting bool // The existing file keeps its content
+		wantRenamed  bool // The archived file is written as "Movie (2).mkv"
+	}{
+		{policy: CollisionSkip, wantExisting: true},
+		{policy: CollisionOverwrite},
+		{policy: CollisionRename, wantExisting: true, wantRenamed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			dst := NewMetadataService(t.TempDir())
+			existing := writeTestFile(t, dst, "/movies/Movie.mkv", 42)
+
+			if _, err := dst.ImportSubtree(bytes.NewReader(archive), "", tt.policy); err != nil {
+				t.Fatalf("ImportSubtree: %v", err)
+			}
+
+			got := readTestFile(t, dst, "/movies/Movie.mkv")
+			if want := map[bool]*metapb.FileMetadata{true: existing, false: archived}[tt.wantExisting]; !sameFile(got, want) {
+				t.Errorf("Movie.mkv: got %v, want %v", got, want)
+			}
+			if dst.FileExists("/movies/Movie (2).mkv") != tt.wantRenamed {
+				t.Errorf("Movie (2).mkv exists: got %v, want %v", !tt.wantRenamed, tt.wantRenamed)
+			}
+		})
+	}
+
+	if _, err := NewMetadataService(t.TempDir()).ImportSubtree(bytes.NewReader(archive), "", "merge"); err == nil {
+		t.Error("ImportSubtree: expected an error for an unknown collision policy")
+	}
+}
+
+// buildArchive creates a metadata archive holding the given entries after the manifest
+func buildArchive(t *testing.T, root string, entries map[string][]byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+
+	manifest, err := json.Marshal(ArchiveManifest{Format: archiveFormat, Version: archiveVersion, Root: root})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := writeArchiveEntry(tw, manifestName, manifest); err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range entries {
+		if err := writeArchiveEntry(tw, name, data); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestImportSubtreeRejectsPathTraversal(t *testing.T) {
+	data, err := proto.Marshal(&metapb.FileMetadata{FileSize: 1, SourceNzbPath: "/nzbs/evil.nzb"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{
+		"files/../../evil.mkv.meta",
+		"files/movies/../../../evil.mkv.meta",
+		"files//etc/evil.mkv.meta",
+		"../evil.mkv.meta",
+		"files/.meta",
+	} {
+		t.Run(name, func(t *testing.T) {
+			base := t.TempDir()
+			root := filepath.Join(base, "metadata")
+			ms := NewMetadataService(root)
+
+			archive := buildArchive(t, "/movies", map[string][]byte{name: data})
+			if _, err := ms.ImportSubtree(bytes.NewReader(archive), "", CollisionOverwrite); err == nil {
+				t.Error("ImportSubtree: expected the entry to be rejected")
+			}
+
+			assertNothingOutside(t, base, root)
+		})
+	}
+
+	// Roots escaping the metadata directory stay inside it
+	for _, root := range []string{"../../outside", "/../outside"} {
+		t.Run("root "+root, func(t *testing.T) {
+			base := t.TempDir()
+			metadataRoot := filepath.Join(base, "metadata")
+			ms := NewMetadataService(metadataRoot)
+
+			archive := buildArchive(t, root, map[string][]byte{"files/movie.mkv.meta": data})
+			result, err := ms.ImportSubtree(bytes.NewReader(archive), "", CollisionOverwrite)
+			if err != nil {
+				t.Fatalf("ImportSubtree: %v", err)
+			}
+			if result.Root != "/outside" {
+				t.Errorf("root: got %q, want /outside", result.Root)
+			}
+
+			assertNothingOutside(t, base, metadataRoot)
+		})
+	}
+}
+
+// assertNothingOutside fails the test when a file was written under base but not under root
+func assertNothingOutside(t *testing.T, base, root string) {
+	t.Helper()
+
+	_ = filepath.Walk(base, func(p string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() && !strings.HasPrefix(p, root+string(filepath.Separator)) {
+			t.Errorf("file written outside the metadata directory: %s", p)
+		}
+		return nil
+	})
+}
+
+func TestImportSubtreeRejectsOtherArchives(t *testing.T) {
+	var plain bytes.Buffer
+	gz := gzip.NewWriter(&plain)
+	tw := tar.NewWriter(gz)
+	if err := writeArchiveEntry(tw, "readme.txt", []byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	tw.Close()
+	gz.Close()
+
+	for name, archive := range map[string][]byte{
+		"not gzip":         []byte("plain text"),
+		"missing manifest": plain.Bytes(),
+	} {
+		if _, err := NewMetadataService(t.TempDir()).ImportSubtree(bytes.NewReader(archive), "", CollisionSkip); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,302 @@
+package metadata
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	metapb "github.com/javi11/altmount/internal/metadata/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// Fingerprint returns a hash identifying the content of a file by its size and the Usenet
+// segments (message ID and byte range) it is read from. Files that share a name but not
+// their segments get different fingerprints, while files extracted from the same archive
+// volumes differ by their byte ranges.
+func Fingerprint(fileSize int64, segments []*metapb.SegmentData) string {
+	if len(segments) == 0 {
+		return ""
+	}
+
+	h := sha256.New()
+	var buf [8]byte
+
+	binary.BigEndian.PutUint64(buf[:], uint64(fileSize))
+	h.Write(buf[:])
+
+	for _, segment := range segments {
+		h.Write([]byte(segment.Id))
+		h.Write([]byte{0})
+		binary.BigEndian.PutUint64(buf[:], uint64(segment.StartOffset))
+		h.Write(buf[:])
+		binary.BigEndian.PutUint64(buf[:], uint64(segment.EndOffset))
+		h.Write(buf[:])
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// fileFingerprint returns the fingerprint of the file described by metadata
+func fileFingerprint(metadata *metapb.FileMetadata) string {
+	return Fingerprint(metadata.FileSize, metadata.SegmentData)
+}
+
+// normalizeVirtualPath returns virtualPath as a clean, slash-separated absolute path
+func normalizeVirtualPath(virtualPath string) string {
+	return "/" + strings.TrimPrefix(filepath.ToSlash(filepath.Clean("/"+virtualPath)), "/")
+}
+
+// fingerprintIndex maps content fingerprints to the virtual paths holding that content.
+// It is built lazily from the metadata directory on the first lookup and kept up to date
+// by the writes and deletes made through MetadataService.
+type fingerprintIndex struct {
+	mu     sync.Mutex
+	built  bool
+	paths  map[string]string // fingerprint -> virtual path
+	byPath map[string]string // virtual path -> fingerprint
+
+	// While the directory is walked without holding mu, writes and deletes are queued in
+	// pending and applied over the walk's result. buildDone is closed once it is built.
+	buildDone chan struct{}
+	pending   []func()
+}
+
+func newFingerprintIndex() *fingerprintIndex {
+	return &fingerprintIndex{
+		paths:  make(map[string]string),
+		byPath: make(map[string]string),
+	}
+}
+
+// setLocked records the fingerprint of virtualPath. Caller must hold idx.mu.
+func (idx *fingerprintIndex) setLocked(virtualPath, fingerprint string) {
+	idx.removeLocked(virtualPath)
+	if fingerprint == "" {
+		return
+	}
+	idx.paths[fingerprint] = virtualPath
+	idx.byPath[virtualPath] = fingerprint
+}
+
+// removeLocked forgets virtualPath. Caller must hold idx.mu.
+func (idx *fingerprintIndex) removeLocked(virtualPath string) {
+	fingerprint, ok := idx.byPath[virtualPath]
+	if !ok {
+		return
+	}
+	delete(idx.byPath, virtualPath)
+	if idx.paths[fingerprint] == virtualPath {
+		delete(idx.paths, fingerprint)
+	}
+}
+
+// applyLocked applies a change now, and again over the walk's result when the index is
+// being built. Before the build starts changes are skipped, the walk reads them from disk.
+// Caller must hold idx.mu.
+func (idx *fingerprintIndex) applyLocked(change func()) {
+	if !idx.built && idx.buildDone != nil {
+		idx.pending = append(idx.pending, change)
+	}
+	change()
+}
+
+// update records a written file once the index is being built
+func (idx *fingerprintIndex) update(virtualPath string, metadata *metapb.FileMetadata) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if idx.built || idx.buildDone != nil {
+		virtualPath, fingerprint := normalizeVirtualPath(virtualPath), fileFingerprint(metadata)
+		idx.applyLocked(func() { idx.setLocked(virtualPath, fingerprint) })
+	}
+}
+
+// remove forgets a deleted file
+func (idx *fingerprintIndex) remove(virtualPath string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	virtualPath = normalizeVirtualPath(virtualPath)
+	idx.applyLocked(func() { idx.removeLocked(virtualPath) })
+}
+
+// removeTree forgets every file below a deleted directory
+func (idx *fingerprintIndex) removeTree(virtualDir string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	prefix := strings.TrimSuffix(normalizeVirtualPath(virtualDir), "/") + "/"
+	idx.applyLocked(func() {
+		for virtualPath := range idx.byPath {
+			if strings.HasPrefix(virtualPath, prefix) {
+				idx.removeLocked(virtualPath)
+			}
+		}
+	})
+}
+
+// ensureBuilt builds the index on first use. The metadata directory is walked without
+// holding idx.mu, so writes and deletes are not blocked meanwhile; concurrent lookups wait
+// for the same build.
+func (idx *fingerprintIndex) ensureBuilt(rootPath string) {
+	idx.mu.Lock()
+	if idx.built {
+		idx.mu.Unlock()
+		return
+	}
+	if idx.buildDone != nil {
+		done := idx.buildDone
+		idx.mu.Unlock()
+		<-done
+		return
+	}
+	idx.buildDone = make(chan struct{})
+	idx.mu.Unlock()
+
+	start := time.Now()
+	files := idx.finishBuild(scanFingerprints(rootPath))
+
+	slog.Info("Built metadata fingerprint index",
+		"files", files,
+		"duration", time.Since(start))
+}
+
+// finishBuild fills the index with the files found by the walk and replays the changes made
+// while it ran, returning the number of files indexed
+func (idx *fingerprintIndex) finishBuild(scanned []scannedFile) int {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	for _, file := range scanned {
+		idx.setLocked(file.virtualPath, file.fingerprint)
+	}
+	// Changes made during the walk are newer than what it read
+	for _, change := range idx.pending {
+		change()
+	}
+	idx.pending = nil
+	idx.built = true
+	close(idx.buildDone)
+	return len(idx.byPath)
+}
+
+// scannedFile is the fingerprint of a metadata file found by scanFingerprints
+type scannedFile struct {
+	virtualPath string
+	fingerprint string
+}
+
+// scanFingerprints walks the metadata directory and fingerprints every file
+func scanFingerprints(rootPath string) []scannedFile {
+	var scanned []scannedFile
+
+	_ = filepath.WalkDir(rootPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(d.Name(), ".meta") {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(rootPath, path)
+		if err != nil {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+
+		metadata := &metapb.FileMetadata{}
+		if err := proto.Unmarshal(data, metadata); err != nil {
+			return nil
+		}
+
+		scanned = append(scanned, scannedFile{
+			virtualPath: normalizeVirtualPath(strings.TrimSuffix(relPath, ".meta")),
+			fingerprint: fileFingerprint(metadata),
+		})
+		return nil
+	})
+
+	return scanned
+}
+
+// FindFileByFingerprint returns the virtual path of an existing file whose content has the
+// given fingerprint. The candidate is re-read from disk so entries that were moved or
+// changed outside the service are never reported as matches.
+func (ms *MetadataService) FindFileByFingerprint(fingerprint string) (string, bool) {
+	if fingerprint == "" {
+		return "", false
+	}
+
+	idx := ms.fingerprints
+	idx.ensureBuilt(ms.rootPath)
+
+	idx.mu.Lock()
+	virtualPath, ok := idx.paths[fingerprint]
+	idx.mu.Unlock()
+
+	if !ok {
+		return "", false
+	}
+
+	metadata, err := ms.ReadFileMetadata(virtualPath)
+	if err != nil || metadata == nil || fileFingerprint(metadata) != fingerprint {
+		idx.remove(virtualPath)
+		return "", false
+	}
+
+	return virtualPath, true
+}
+
+// FindDuplicateFile returns the virtual path of an existing file with the same content as
+// the file about to be written at virtualPath. A file already at virtualPath is not a
+// duplicate, so re-importing a release over itself still rewrites its metadata.
+func (ms *MetadataService) FindDuplicateFile(virtualPath string, fileSize int64, segments []*metapb.SegmentData) (string, bool) {
+	existing, ok := ms.FindFileByFingerprint(Fingerprint(fileSize, segments))
+	if !ok || existing == normalizeVirtualPath(virtualPath) {
+		return "", false
+	}
+	return existing, true
+}
+
+// ReleaseFile is a file of a release about to be imported
+type ReleaseFile struct {
+	VirtualPath string
+	Size        int64
+	Segments    []*metapb.SegmentData
+}
+
+// FindDuplicateRelease returns the directory of an earlier import that holds the content of
+// every file of the release being imported into releaseDir, each at the same path relative
+// to that directory. A release is only a duplicate as a whole: when any file is new or the
+// copies are laid out differently, it is imported normally so its directory is complete.
+func (ms *MetadataService) FindDuplicateRelease(releaseDir string, files []ReleaseFile) (string, bool) {
+	releaseDir = normalizeVirtualPath(releaseDir)
+
+	existingDir := ""
+	for _, file := range files {
+		existing, ok := ms.FindDuplicateFile(file.VirtualPath, file.Size, file.Segments)
+		if !ok {
+			return "", false
+		}
+
+		relPath, found := strings.CutPrefix(normalizeVirtualPath(file.VirtualPath), releaseDir+"/")
+		if !found {
+			return "", false
+		}
+		dir, found := strings.CutSuffix(existing, "/"+relPath)
+		if !found || dir == "" || (existingDir != "" && dir != existingDir) {
+			return "", false
+		}
+		existingDir = dir
+	}
+
+	return existingDir, existingDir != ""
+}
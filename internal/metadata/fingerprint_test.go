@@ -0,0 +1,198 @@
+package metadata
+
+import (
+	"os"
+	"testing"
+
+	metapb "github.com/javi11/altmount/internal/metadata/proto"
+)
+
+func TestFingerprint(t *testing.T) {
+	segments := []*metapb.SegmentData{{Id: "a@example", StartOffset: 0, EndOffset: 99}}
+
+	if Fingerprint(100, nil) != "" {
+		t.Error("file without segments has a fingerprint")
+	}
+	if Fingerprint(100, segments) != Fingerprint(100, []*metapb.SegmentData{{Id: "a@example", StartOffset: 0, EndOffset: 99}}) {
+		t.Error("same segments have different fingerprints")
+	}
+	// Files extracted from the same archive volume differ by their byte ranges
+	if Fingerprint(100, segments) == Fingerprint(100, []*metapb.SegmentData{{Id: "a@example", StartOffset: 100, EndOffset: 199}}) {
+		t.Error("different byte ranges have the same fingerprint")
+	}
+	if Fingerprint(100, segments) == Fingerprint(99, segments) {
+		t.Error("different sizes have the same fingerprint")
+	}
+}
+
+func TestFindFileByFingerprintBuildsLazily(t *testing.T) {
+	root := t.TempDir()
+	movie := writeTestFile(t, NewMetadataService(root), "/movies/Movie.mkv", 1000)
+
+	// A new service finds the files written before it was created
+	ms := NewMetadataService(root)
+	if ms.fingerprints.built {
+		t.Fatal("index built before the first lookup")
+	}
+
+	got, ok := ms.FindFileByFingerprint(fileFingerprint(movie))
+	if !ok || got != "/movies/Movie.mkv" {
+		t.Errorf("FindFileByFingerprint: got %q, %v, want /movies/Movie.mkv", got, ok)
+	}
+	if !ms.fingerprints.built {
+		t.Error("index not built by the lookup")
+	}
+
+	// Later writes and deletes keep the built index up to date
+	show := writeTestFile(t, ms, "/tv/Show/S01E01.mkv", 500)
+	if got, ok := ms.FindFileByFingerprint(fileFingerprint(show)); !ok || got != "/tv/Show/S01E01.mkv" {
+		t.Errorf("written file: got %q, %v", got, ok)
+	}
+
+	if err := ms.DeleteDirectory("/tv"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := ms.FindFileByFingerprint(fileFingerprint(show)); ok {
+		t.Error("file of a deleted directory is still found")
+	}
+}
+
+func TestFingerprintIndexReplaysChangesMadeDuringBuild(t *testing.T) {
+	ms := NewMetadataService(t.TempDir())
+	kept := writeTestFile(t, ms, "/movies/Kept.mkv", 100)
+	deleted := writeTestFile(t, ms, "/movies/Deleted.mkv", 200)
+
+	// Start a build and walk the directory as it is now
+	idx := ms.fingerprints
+	idx.buildDone = make(chan struct{})
+	scanned := scanFingerprints(ms.rootPath)
+
+	// Changes made while the walk runs are newer than what it read
+	added := writeTestFile(t, ms, "/movies/Added.mkv", 300)
+	if err := ms.DeleteFileMetadata("/movies/Deleted.mkv"); err != nil {
+		t.Fatal(err)
+	}
+	replaced := writeTestFile(t, ms, "/movies/Kept.mkv", 400)
+
+	if files := idx.finishBuild(scanned); files != 2 {
+		t.Errorf("finishBuild: indexed %d files, want 2", files)
+	}
+
+	tests := []struct {
+		name     string
+		metadata *metapb.FileMetadata
+		want     string
+	}{
+		{name: "added", metadata: added, want: "/movies/Added.mkv"},
+		{name: "deleted", metadata: deleted},
+		{name: "replaced, old content", metadata: kept},
+		{name: "replaced, new content", metadata: replaced, want: "/movies/Kept.mkv"},
+	}
+
+	for _, tt := range tests {
+		got, ok := ms.FindFileByFingerprint(fileFingerprint(tt.metadata))
+		if got != tt.want || ok != (tt.want != "") {
+			t.Errorf("%s: got %q, %v, want %q", tt.name, got, ok, tt.want)
+		}
+	}
+}
+
+func TestFindFileByFingerprintEvictsStaleEntries(t *testing.T) {
+	ms := NewMetadataService(t.TempDir())
+	movie := writeTestFile(t, ms, "/movies/Movie.mkv", 1000)
+	fingerprint := fileFingerprint(movie)
+
+	if _, ok := ms.FindFileByFingerprint(fingerprint); !ok {
+		t.Fatal("written file not found")
+	}
+
+	// Removed outside the service, the entry is dropped instead of reported
+	if err := os.Remove(ms.GetMetadataFilePath("/movies/Movie.mkv")); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := ms.FindFileByFingerprint(fingerprint); ok {
+		t.Error("file removed outside the service is still found")
+	}
+	if _, ok := ms.fingerprints.byPath["/movies/Movie.mkv"]; ok {
+		t.Error("stale entry kept in the index")
+	}
+}
+
+func TestFindDuplicateFile(t *testing.T) {
+	ms := NewMetadataService(t.TempDir())
+	movie := writeTestFile(t, ms, "/movies/Movie.mkv", 1000)
+
+	if got, ok := ms.FindDuplicateFile("/downloads/Movie.mkv", movie.FileSize, movie.SegmentData); !ok || got != "/movies/Movie.mkv" {
+		t.Errorf("same content elsewhere: got %q, %v", got, ok)
+	}
+	// Re-importing a release over itself is not a duplicate
+	if _, ok := ms.FindDuplicateFile("/movies/Movie.mkv", movie.FileSize, movie.SegmentData); ok {
+		t.Error("file at its own path reported as a duplicate")
+	}
+	// A file sharing the name but not the segments is different
+	other := []*metapb.SegmentData{{Id: "other@example", StartOffset: 0, EndOffset: 999, SegmentSize: 1000}}
+	if _, ok := ms.FindDuplicateFile("/downloads/Movie.mkv", movie.FileSize, other); ok {
+		t.Error("different content reported as a duplicate")
+	}
+}
+
+func TestFindDuplicateRelease(t *testing.T) {
+	ms := NewMetadataService(t.TempDir())
+	movie := writeTestFile(t, ms, "/movies/Release/Movie.mkv", 1000)
+	extra := writeTestFile(t, ms, "/movies/Release/Extras/Trailer.mkv", 200)
+	elsewhere := writeTestFile(t, ms, "/other/Trailer.mkv", 300)
+
+	file := func(virtualPath string, metadata *metapb.FileMetadata) ReleaseFile {
+		return ReleaseFile{VirtualPath: virtualPath, Size: metadata.FileSize, Segments: metadata.SegmentData}
+	}
+	newFile := ReleaseFile{
+		VirtualPath: "/downloads/Release/New.mkv",
+		Size:        10,
+		Segments:    []*metapb.SegmentData{{Id: "new@example", StartOffset: 0, EndOffset: 9}},
+	}
+
+	tests := []struct {
+		name       string
+		releaseDir string // Default: /downloads/Release
+		files      []ReleaseFile
+		want       string
+	}{
+		{
+			name:  "every file imported",
+			files: []ReleaseFile{file("/downloads/Release/Movie.mkv", movie), file("/downloads/Release/Extras/Trailer.mkv", extra)},
+			want:  "/movies/Release",
+		},
+		{
+			name:  "one new file",
+			files: []ReleaseFile{file("/downloads/Release/Movie.mkv", movie), newFile},
+		},
+		{
+			name:  "copies laid out differently",
+			files: []ReleaseFile{file("/downloads/Release/Movie.mkv", movie), file("/downloads/Release/Trailer.mkv", extra)},
+		},
+		{
+			name:  "copies in different releases",
+			files: []ReleaseFile{file("/downloads/Release/Movie.mkv", movie), file("/downloads/Release/Trailer.mkv", elsewhere)},
+		},
+		{
+			name:       "re-import over itself",
+			releaseDir: "/movies/Release",
+			files:      []ReleaseFile{file("/movies/Release/Movie.mkv", movie)},
+		},
+		{name: "no files"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			releaseDir := tt.releaseDir
+			if releaseDir == "" {
+				releaseDir = "/downloads/Release"
+			}
+
+			got, ok := ms.FindDuplicateRelease(releaseDir, tt.files)
+			if got != tt.want || ok != (tt.want != "") {
+				t.Errorf("FindDuplicateRelease: got %q, %v, want %q", got, ok, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package metadata
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// GCOptions controls a GarbageCollect run
+type GCOptions struct {
+	// MinAge protects recently written metadata, e.g. of an import whose library entries
+	// are not created yet. Files modified within MinAge are never collected.
+	MinAge time.Duration
+	// DryRun only counts the files that would be collected
+	DryRun bool
+}
+
+// GCResult reports the outcome of a GarbageCollect run
+type GCResult struct {
+	Scanned        int   `json:"scanned"`         // Metadata files examined
+	Reclaimed      int   `json:"reclaimed"`       // Metadata files removed, or that would be in a dry run
+	ReclaimedBytes int64 `json:"reclaimed_bytes"` // Disk space of the reclaimed files
+}
+
+// GarbageCollect removes the metadata of every file for which isReferenced returns false,
+// i.e. files no library entry points to any more. isReferenced receives the virtual path
+// of each file, with a leading slash. Every file is checked and removed while holding its
+// write lock, so a concurrent write is never lost; readers see the file until it is removed.
+// Directories left empty by the removed files are removed as well.
+// The result holds the files removed before an error or cancellation of ctx is returned.
+func (ms *MetadataService) GarbageCollect(ctx context.Context, isReferenced func(virtualPath string) bool, opts GCOptions) (GCResult, error) {
+	var result GCResult
+	cutoff := time.Now().Add(-opts.MinAge)
+
+	err := filepath.WalkDir(ms.rootPath, func(path string, d os.DirEntry, err error) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".meta") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(ms.rootPath, path)
+		if err != nil {
+			return err
+		}
+		virtualPath := "/" + filepath.ToSlash(strings.TrimSuffix(rel, ".meta"))
+
+		result.Scanned++
+		if isReferenced(virtualPath) {
+			return nil
+		}
+
+		size, err := ms.collect(path, virtualPath, cutoff, opts.DryRun)
+		if err != nil {
+			return err
+		}
+		if size >= 0 {
+			result.Reclaimed++
+			result.ReclaimedBytes += size
+		}
+
+		return nil
+	})
+	if err != nil {
+		return result, fmt.Errorf("metadata garbage collection failed: %w", err)
+	}
+
+	return result, nil
+}
+
+// collect removes the unreferenced metadata file at path unless it was modified after
+// cutoff, returning its size or -1 when it was kept
+func (ms *MetadataService) collect(path, virtualPath string, cutoff time.Time, dryRun bool) (int64, error) {
+	mu := ms.writeLock(virtualPath)
+	mu.Lock()
+	defer mu.Unlock()
+
+	// Check the age under the lock, a write that happened since the walk makes the file recent
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return -1, nil
+		}
+		return -1, fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+	if info.ModTime().After(cutoff) {
+		return -1, nil
+	}
+
+	if !dryRun {
+		if err := os.Remove(path); err != nil {
+			if os.IsNotExist(err) {
+				return -1, nil
+			}
+			return -1, fmt.Errorf("failed to delete metadata file: %w", err)
+		}
+		ms.fingerprints.remove(virtualPath)
+		ms.removeEmptyParents(path)
+	}
+
+	return info.Size(), nil
+}
+
+// removeEmptyParents removes the directories above path up to the metadata root as long as
+// they are empty. Directories that still hold entries are kept.
+func (ms *MetadataService) removeEmptyParents(path string) {
+	root := filepath.Clean(ms.rootPath) + string(filepath.Separator)
+	for dir := filepath.Dir(path); strings.HasPrefix(dir, root); dir = filepath.Dir(dir) {
+		if err := os.Remove(dir); err != nil {
+			return
+		}
+	}
+}
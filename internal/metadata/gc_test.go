@@ -0,0 +1,118 @@
+package metadata
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+// ageTestFile moves the modification time of the metadata at virtualPath into the past
+func ageTestFile(t *testing.T, ms *MetadataService, virtualPath string, age time.Duration) {
+	t.Helper()
+
+	old := time.Now().Add(-age)
+	if err := os.Chtimes(ms.GetMetadataFilePath(virtualPath), old, old); err != nil {
+		t.Fatalf("Chtimes(%s): %v", virtualPath, err)
+	}
+}
+
+func TestGarbageCollect(t *testing.T) {
+	referenced := map[string]bool{"/movies/Kept/Movie.mkv": true}
+	isReferenced := func(virtualPath string) bool { return referenced[virtualPath] }
+
+	setup := func(t *testing.T) *MetadataService {
+		ms := NewMetadataService(t.TempDir())
+		for _, virtualPath := range []string{"/movies/Kept/Movie.mkv", "/movies/Kept/Sample.mkv", "/movies/Gone/Movie.mkv", "/tv/Show/Season 1/S01E01.mkv"} {
+			writeTestFile(t, ms, virtualPath, 100)
+			ageTestFile(t, ms, virtualPath, 2*time.Hour)
+		}
+		// Written just now, e.g. by an import whose library entry does not exist yet
+		writeTestFile(t, ms, "/movies/New/Movie.mkv", 100)
+		return ms
+	}
+
+	t.Run("removes unreferenced files", func(t *testing.T) {
+		ms := setup(t)
+		result, err := ms.GarbageCollect(context.Background(), isReferenced, GCOptions{MinAge: time.Hour})
+		if err != nil {
+			t.Fatalf("GarbageCollect: %v", err)
+		}
+		if result.Scanned != 5 || result.Reclaimed != 3 || result.ReclaimedBytes <= 0 {
+			t.Errorf("result: got %+v, want 5 scanned and 3 reclaimed", result)
+		}
+
+		tests := []struct {
+			virtualPath string
+			want        bool
+		}{
+			{virtualPath: "/movies/Kept/Movie.mkv", want: true},
+			{virtualPath: "/movies/Kept/Sample.mkv"},
+			{virtualPath: "/movies/Gone/Movie.mkv"},
+			{virtualPath: "/tv/Show/Season 1/S01E01.mkv"},
+			{virtualPath: "/movies/New/Movie.mkv", want: true},
+		}
+		for _, tt := range tests {
+			if got := ms.FileExists(tt.virtualPath); got != tt.want {
+				t.Errorf("FileExists(%s): got %v, want %v", tt.virtualPath, got, tt.want)
+			}
+		}
+	})
+
+	t.Run("removes directories left empty", func(t *testing.T) {
+		ms := setup(t)
+		if err := ms.CreateDirectoryAll("/movies/Empty"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ms.GarbageCollect(context.Background(), isReferenced, GCOptions{MinAge: time.Hour}); err != nil {
+			t.Fatalf("GarbageCollect: %v", err)
+		}
+
+		tests := []struct {
+			virtualPath string
+			want        bool
+		}{
+			{virtualPath: "/movies", want: true},
+			{virtualPath: "/movies/Kept", want: true},
+			{virtualPath: "/movies/Gone"},
+			{virtualPath: "/tv"},
+			// Directories that were empty before are not the collector's to remove
+			{virtualPath: "/movies/Empty", want: true},
+		}
+		for _, tt := range tests {
+			if got := ms.DirectoryExists(tt.virtualPath); got != tt.want {
+				t.Errorf("DirectoryExists(%s): got %v, want %v", tt.virtualPath, got, tt.want)
+			}
+		}
+
+		// A later write recreates a removed directory
+		writeTestFile(t, ms, "/tv/Show/Season 1/S01E02.mkv", 100)
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		ms := setup(t)
+		result, err := ms.GarbageCollect(context.Background(), isReferenced, GCOptions{MinAge: time.Hour, DryRun: true})
+		if err != nil {
+			t.Fatalf("GarbageCollect: %v", err)
+		}
+		if result.Reclaimed != 3 {
+			t.Errorf("reclaimed: got %d, want 3", result.Reclaimed)
+		}
+		for _, virtualPath := range []string{"/movies/Kept/Sample.mkv", "/movies/Gone/Movie.mkv", "/tv/Show/Season 1/S01E01.mkv"} {
+			if !ms.FileExists(virtualPath) {
+				t.Errorf("%s removed in a dry run", virtualPath)
+			}
+		}
+	})
+
+	t.Run("without min age", func(t *testing.T) {
+		ms := setup(t)
+		result, err := ms.GarbageCollect(context.Background(), isReferenced, GCOptions{})
+		if err != nil {
+			t.Fatalf("GarbageCollect: %v", err)
+		}
+		if result.Reclaimed != 4 || ms.FileExists("/movies/New/Movie.mkv") {
+			t.Errorf("reclaimed: got %d, want 4 including the new file", result.Reclaimed)
+		}
+	})
+}
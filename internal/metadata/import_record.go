@@ -0,0 +1,43 @@
+package metadata
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+type importRecordKey struct{}
+
+// ImportRecord lists the metadata files created by one import run. A cancelled import
+// removes exactly these, never the files an earlier import of the same NZB left behind.
+type ImportRecord struct {
+	mu      sync.Mutex
+	created []string
+}
+
+// WithImportRecord returns a context whose import run is recorded in the returned record
+func WithImportRecord(ctx context.Context) (context.Context, *ImportRecord) {
+	record := &ImportRecord{}
+	return context.WithValue(ctx, importRecordKey{}, record), record
+}
+
+// RecordImportedFile notes that the import run of ctx wrote virtualPath. Files that existed
+// before the write were replaced by a complete import and are not recorded, so cancelling
+// the run keeps them. It does nothing when ctx has no record.
+func RecordImportedFile(ctx context.Context, virtualPath string, existed bool) {
+	record, ok := ctx.Value(importRecordKey{}).(*ImportRecord)
+	if !ok || existed {
+		return
+	}
+
+	record.mu.Lock()
+	record.created = append(record.created, virtualPath)
+	record.mu.Unlock()
+}
+
+// Created returns the virtual paths of the metadata files created by the run
+func (r *ImportRecord) Created() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.created)
+}
@@ -253,6 +253,83 @@ func (x *Par2FileReference) GetSegmentData() []*SegmentData {
 	return nil
 }
 
+// Par2FileInfo stores the PAR2 description of a file, used to verify its content
+type Par2FileInfo struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	FileId         []byte                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`                         // PAR2 file ID (MD5 of the 16KB hash, length and name)
+	FileMd5        []byte                 `protobuf:"bytes,2,opt,name=file_md5,json=fileMd5,proto3" json:"file_md5,omitempty"`                      // MD5 hash of the entire file
+	Hash16K        []byte                 `protobuf:"bytes,3,opt,name=hash16k,proto3" json:"hash16k,omitempty"`                                     // MD5 hash of the first 16KB of the file
+	SliceSize      int64                  `protobuf:"varint,4,opt,name=slice_size,json=sliceSize,proto3" json:"slice_size,omitempty"`               // Size of the PAR2 slices (blocks) in bytes
+	SliceChecksums []byte                 `protobuf:"bytes,5,opt,name=slice_checksums,json=sliceChecksums,proto3" json:"slice_checksums,omitempty"` // MD5 (16 bytes) and little endian CRC32 (4 bytes) of each slice
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Par2FileInfo) Reset() {
+	*x = Par2FileInfo{}
+	mi := &file_metadata_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Par2FileInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Par2FileInfo) ProtoMessage() {}
+
+func (x *Par2FileInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_metadata_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Par2FileInfo.ProtoReflect.Descriptor instead.
+func (*Par2FileInfo) Descriptor() ([]byte, []int) {
+	return file_metadata_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Par2FileInfo) GetFileId() []byte {
+	if x != nil {
+		return x.FileId
+	}
+	return nil
+}
+
+func (x *Par2FileInfo) GetFileMd5() []byte {
+	if x != nil {
+		return x.FileMd5
+	}
+	return nil
+}
+
+func (x *Par2FileInfo) GetHash16K() []byte {
+	if x != nil {
+		return x.Hash16K
+	}
+	return nil
+}
+
+func (x *Par2FileInfo) GetSliceSize() int64 {
+	if x != nil {
+		return x.SliceSize
+	}
+	return 0
+}
+
+func (x *Par2FileInfo) GetSliceChecksums() []byte {
+	if x != nil {
+		return x.SliceChecksums
+	}
+	return nil
+}
+
 // FileMetadata represents a single virtual file in the filesystem
 // The filename comes from the actual metadata filename on disk
 type FileMetadata struct {
@@ -270,13 +347,15 @@ type FileMetadata struct {
 	AesIv         []byte                 `protobuf:"bytes,11,opt,name=aes_iv,json=aesIv,proto3" json:"aes_iv,omitempty"`                          // AES initialization vector (for AES-encrypted archives)
 	ReleaseDate   int64                  `protobuf:"varint,12,opt,name=release_date,json=releaseDate,proto3" json:"release_date,omitempty"`       // Unix timestamp of the original Usenet post release date
 	Par2Files     []*Par2FileReference   `protobuf:"bytes,13,rep,name=par2_files,json=par2Files,proto3" json:"par2_files,omitempty"`              // Associated PAR2 repair files
+	Generation    int64                  `protobuf:"varint,14,opt,name=generation,proto3" json:"generation,omitempty"`                            // Incremented on every write, used to detect concurrent updates
+	Par2Info      *Par2FileInfo          `protobuf:"bytes,15,opt,name=par2_info,json=par2Info,proto3" json:"par2_info,omitempty"`                 // PAR2 description of the file, when a PAR2 set covers it
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *FileMetadata) Reset() {
 	*x = FileMetadata{}
-	mi := &file_metadata_proto_msgTypes[2]
+	mi := &file_metadata_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -288,7 +367,7 @@ func (x *FileMetadata) String() string {
 func (*FileMetadata) ProtoMessage() {}
 
 func (x *FileMetadata) ProtoReflect() protoreflect.Message {
-	mi := &file_metadata_proto_msgTypes[2]
+	mi := &file_metadata_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -301,7 +380,7 @@ func (x *FileMetadata) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FileMetadata.ProtoReflect.Descriptor instead.
 func (*FileMetadata) Descriptor() ([]byte, []int) {
-	return file_metadata_proto_rawDescGZIP(), []int{2}
+	return file_metadata_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *FileMetadata) GetFileSize() int64 {
@@ -395,6 +474,20 @@ func (x *FileMetadata) GetPar2Files() []*Par2FileReference {
 	return nil
 }
 
+func (x *FileMetadata) GetGeneration() int64 {
+	if x != nil {
+		return x.Generation
+	}
+	return 0
+}
+
+func (x *FileMetadata) GetPar2Info() *Par2FileInfo {
+	if x != nil {
+		return x.Par2Info
+	}
+	return nil
+}
+
 var File_metadata_proto protoreflect.FileDescriptor
 
 const file_metadata_proto_rawDesc = "" +
@@ -409,7 +502,14 @@ const file_metadata_proto_rawDesc = "" +
 	"\x11Par2FileReference\x12\x1a\n" +
 	"\bfilename\x18\x01 \x01(\tR\bfilename\x12\x1b\n" +
 	"\tfile_size\x18\x02 \x01(\x03R\bfileSize\x128\n" +
-	"\fsegment_data\x18\x03 \x03(\v2\x15.metadata.SegmentDataR\vsegmentData\"\xf0\x03\n" +
+	"\fsegment_data\x18\x03 \x03(\v2\x15.metadata.SegmentDataR\vsegmentData\"\xa4\x01\n" +
+	"\fPar2FileInfo\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\fR\x06fileId\x12\x19\n" +
+	"\bfile_md5\x18\x02 \x01(\fR\afileMd5\x12\x18\n" +
+	"\ahash16k\x18\x03 \x01(\fR\ahash16k\x12\x1d\n" +
+	"\n" +
+	"slice_size\x18\x04 \x01(\x03R\tsliceSize\x12'\n" +
+	"\x0fslice_checksums\x18\x05 \x01(\fR\x0esliceChecksums\"\xc5\x04\n" +
 	"\fFileMetadata\x12\x1b\n" +
 	"\tfile_size\x18\x01 \x01(\x03R\bfileSize\x12&\n" +
 	"\x0fsource_nzb_path\x18\x02 \x01(\tR\rsourceNzbPath\x12,\n" +
@@ -429,7 +529,11 @@ const file_metadata_proto_rawDesc = "" +
 	"\x06aes_iv\x18\v \x01(\fR\x05aesIv\x12!\n" +
 	"\frelease_date\x18\f \x01(\x03R\vreleaseDate\x12:\n" +
 	"\n" +
-	"par2_files\x18\r \x03(\v2\x1b.metadata.Par2FileReferenceR\tpar2Files*8\n" +
+	"par2_files\x18\r \x03(\v2\x1b.metadata.Par2FileReferenceR\tpar2Files\x12\x1e\n" +
+	"\n" +
+	"generation\x18\x0e \x01(\x03R\n" +
+	"generation\x123\n" +
+	"\tpar2_info\x18\x0f \x01(\v2\x16.metadata.Par2FileInfoR\bpar2Info*8\n" +
 	"\n" +
 	"Encryption\x12\b\n" +
 	"\x04NONE\x10\x00\x12\n" +
@@ -456,13 +560,14 @@ func file_metadata_proto_rawDescGZIP() []byte {
 }
 
 var file_metadata_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_metadata_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_metadata_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
 var file_metadata_proto_goTypes = []any{
 	(Encryption)(0),           // 0: metadata.Encryption
 	(FileStatus)(0),           // 1: metadata.FileStatus
 	(*SegmentData)(nil),       // 2: metadata.SegmentData
 	(*Par2FileReference)(nil), // 3: metadata.Par2FileReference
-	(*FileMetadata)(nil),      // 4: metadata.FileMetadata
+	(*Par2FileInfo)(nil),      // 4: metadata.Par2FileInfo
+	(*FileMetadata)(nil),      // 5: metadata.FileMetadata
 }
 var file_metadata_proto_depIdxs = []int32{
 	2, // 0: metadata.Par2FileReference.segment_data:type_name -> metadata.SegmentData
@@ -470,11 +575,12 @@ var file_metadata_proto_depIdxs = []int32{
 	0, // 2: metadata.FileMetadata.encryption:type_name -> metadata.Encryption
 	2, // 3: metadata.FileMetadata.segment_data:type_name -> metadata.SegmentData
 	3, // 4: metadata.FileMetadata.par2_files:type_name -> metadata.Par2FileReference
-	5, // [5:5] is the sub-list for method output_type
-	5, // [5:5] is the sub-list for method input_type
-	5, // [5:5] is the sub-list for extension type_name
-	5, // [5:5] is the sub-list for extension extendee
-	0, // [0:5] is the sub-list for field type_name
+	4, // 5: metadata.FileMetadata.par2_info:type_name -> metadata.Par2FileInfo
+	6, // [6:6] is the sub-list for method output_type
+	6, // [6:6] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
 }
 
 func init() { file_metadata_proto_init() }
@@ -488,7 +594,7 @@ func file_metadata_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_metadata_proto_rawDesc), len(file_metadata_proto_rawDesc)),
 			NumEnums:      2,
-			NumMessages:   3,
+			NumMessages:   4,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
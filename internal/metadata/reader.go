@@ -43,7 +43,7 @@ func (mr *MetadataReader) ListDirectoryContents(virtualPath string) ([]fs.FileIn
 	}
 
 	var dirs []fs.FileInfo
-	var files []*metapb.FileMetadata
+	var filePaths []string
 
 	for _, entry := range entries {
 		if entry.IsDir() {
@@ -53,18 +53,23 @@ func (mr *MetadataReader) ListDirectoryContents(virtualPath string) ([]fs.FileIn
 				dirs = append(dirs, info)
 			}
 		} else if filepath.Ext(entry.Name()) == ".meta" {
-			// It's a metadata file - read the FileMetadata
+			// It's a metadata file - collect it for a single batch read
 			virtualName := entry.Name()[:len(entry.Name())-5] // Remove .meta extension
-			virtualFilePath := filepath.Join(virtualPath, virtualName)
-
-			fileMeta, err := mr.service.ReadFileMetadata(virtualFilePath)
-			if err == nil && fileMeta != nil {
-				files = append(files, fileMeta)
-			}
+			filePaths = append(filePaths, filepath.Join(virtualPath, virtualName))
 		}
 		// Ignore other files (not directories or .meta files)
 	}
 
+	// Unreadable files are skipped, as before
+	fileMetas, _ := mr.service.ReadFileMetadataBatch(filePaths)
+
+	files := make([]*metapb.FileMetadata, 0, len(fileMetas))
+	for _, virtualFilePath := range filePaths {
+		if fileMeta, ok := fileMetas[virtualFilePath]; ok {
+			files = append(files, fileMeta)
+		}
+	}
+
 	return dirs, files, nil
 }
 
@@ -2,26 +2,45 @@ package metadata
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"hash/fnv"
 	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	metapb "github.com/javi11/altmount/internal/metadata/proto"
+	"github.com/sourcegraph/conc/pool"
 	"google.golang.org/protobuf/proto"
 )
 
+// ErrConcurrentUpdate is returned by UpdateFileMetadata when the file kept being changed by
+// other writers and the update could not be applied after retrying
+var ErrConcurrentUpdate = errors.New("metadata was modified concurrently")
+
+const (
+	// maxUpdateAttempts is how many times UpdateFileMetadata re-reads and re-applies an
+	// update that lost a race with another writer
+	maxUpdateAttempts = 3
+	// writeLockStripes is the number of locks that serialize writes to the same path
+	writeLockStripes = 64
+)
+
 // MetadataService provides low-level read/write operations for metadata files
 type MetadataService struct {
-	rootPath string
+	rootPath     string
+	fingerprints *fingerprintIndex
+	writeLocks   [writeLockStripes]sync.Mutex
 }
 
 // NewMetadataService creates a new metadata service
 func NewMetadataService(rootPath string) *MetadataService {
 	return &MetadataService{
-		rootPath: rootPath,
+		rootPath:     rootPath,
+		fingerprints: newFingerprintIndex(),
 	}
 }
 
@@ -41,8 +60,48 @@ func (ms *MetadataService) truncateFilename(filename string) string {
 	return filename[:maxLen] + fileExt
 }
 
-// WriteFileMetadata writes file metadata to disk
+// writeLock returns the lock serializing writes to virtualPath
+func (ms *MetadataService) writeLock(virtualPath string) *sync.Mutex {
+	h := fnv.New32a()
+	h.Write([]byte(normalizeVirtualPath(virtualPath)))
+	return &ms.writeLocks[h.Sum32()%writeLockStripes]
+}
+
+// WriteFileMetadata writes file metadata to disk. The generation is moved past the one of any
+// file it replaces so updates that read the old file detect the write.
 func (ms *MetadataService) WriteFileMetadata(virtualPath string, metadata *metapb.FileMetadata) error {
+	mu := ms.writeLock(virtualPath)
+	mu.Lock()
+	defer mu.Unlock()
+
+	if current, err := ms.ReadFileMetadata(virtualPath); err == nil && current != nil {
+		metadata.Generation = max(metadata.Generation, current.Generation+1)
+	}
+
+	return ms.writeFileMetadataLocked(virtualPath, metadata)
+}
+
+// writeFileMetadataIfGeneration writes metadata only if the file on disk still has the given
+// generation, returning ErrConcurrentUpdate otherwise
+func (ms *MetadataService) writeFileMetadataIfGeneration(virtualPath string, metadata *metapb.FileMetadata, generation int64) error {
+	mu := ms.writeLock(virtualPath)
+	mu.Lock()
+	defer mu.Unlock()
+
+	current, err := ms.ReadFileMetadata(virtualPath)
+	if err != nil {
+		return fmt.Errorf("failed to read metadata: %w", err)
+	}
+	if current == nil || current.Generation != generation {
+		return fmt.Errorf("%w: %s", ErrConcurrentUpdate, virtualPath)
+	}
+
+	metadata.Generation = generation + 1
+	return ms.writeFileMetadataLocked(virtualPath, metadata)
+}
+
+// writeFileMetadataLocked writes file metadata to disk. Caller must hold the path's write lock.
+func (ms *MetadataService) writeFileMetadataLocked(virtualPath string, metadata *metapb.FileMetadata) error {
 	// Ensure the directory exists
 	metadataDir := filepath.Join(ms.rootPath, filepath.Dir(virtualPath))
 	if err := os.MkdirAll(metadataDir, 0755); err != nil {
@@ -60,11 +119,20 @@ func (ms *MetadataService) WriteFileMetadata(virtualPath string, metadata *metap
 		return fmt.Errorf("failed to marshal metadata: %w", err)
 	}
 
-	// Write to file
-	if err := os.WriteFile(metadataPath, data, 0644); err != nil {
+	// Write to file. Garbage collection may remove the directory once it is empty, so it is
+	// created again if it disappeared in the meantime.
+	err = os.WriteFile(metadataPath, data, 0644)
+	if os.IsNotExist(err) {
+		if err = os.MkdirAll(metadataDir, 0755); err == nil {
+			err = os.WriteFile(metadataPath, data, 0644)
+		}
+	}
+	if err != nil {
 		return fmt.Errorf("failed to write metadata file: %w", err)
 	}
 
+	ms.fingerprints.update(virtualPath, metadata)
+
 	return nil
 }
 
@@ -93,6 +161,45 @@ func (ms *MetadataService) ReadFileMetadata(virtualPath string) (*metapb.FileMet
 	return metadata, nil
 }
 
+// batchReadConcurrency bounds the number of metadata files read in parallel by ReadFileMetadataBatch
+const batchReadConcurrency = 16
+
+// ReadFileMetadataBatch reads the metadata of many files in parallel, keyed by virtual path.
+// Paths without metadata are left out of the result. Files that fail to read are skipped
+// and reported together in the returned error, alongside the entries that were read.
+func (ms *MetadataService) ReadFileMetadataBatch(virtualPaths []string) (map[string]*metapb.FileMetadata, error) {
+	results := make(map[string]*metapb.FileMetadata, len(virtualPaths))
+	if len(virtualPaths) == 0 {
+		return results, nil
+	}
+
+	var (
+		mu   sync.Mutex
+		errs []error
+	)
+
+	p := pool.New().WithMaxGoroutines(min(batchReadConcurrency, len(virtualPaths)))
+	for _, virtualPath := range virtualPaths {
+		p.Go(func() {
+			metadata, err := ms.ReadFileMetadata(virtualPath)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", virtualPath, err))
+				return
+			}
+			if metadata != nil {
+				results[virtualPath] = metadata
+			}
+		})
+	}
+	p.Wait()
+
+	return results, errors.Join(errs...)
+}
+
 // FileExists checks if a metadata file exists for the given virtual path
 func (ms *MetadataService) FileExists(virtualPath string) bool {
 	filename := filepath.Base(virtualPath)
@@ -186,25 +293,38 @@ func (ms *MetadataService) CreateFileMetadata(
 	}
 }
 
-// UpdateFileMetadata updates the modified timestamp of metadata
+// UpdateFileMetadata applies updateFunc to the metadata of a file and updates its modified
+// timestamp. If another writer changes the file in between, the update is re-applied to a
+// fresh copy, so updateFunc may run more than once. ErrConcurrentUpdate is returned when
+// the update still conflicts after maxUpdateAttempts.
 func (ms *MetadataService) UpdateFileMetadata(virtualPath string, updateFunc func(*metapb.FileMetadata)) error {
-	// Read existing metadata
-	metadata, err := ms.ReadFileMetadata(virtualPath)
-	if err != nil {
-		return fmt.Errorf("failed to read metadata: %w", err)
-	}
-	if metadata == nil {
-		return fmt.Errorf("metadata not found for path: %s", virtualPath)
-	}
+	for attempt := 1; ; attempt++ {
+		// Read existing metadata
+		metadata, err := ms.ReadFileMetadata(virtualPath)
+		if err != nil {
+			return fmt.Errorf("failed to read metadata: %w", err)
+		}
+		if metadata == nil {
+			return fmt.Errorf("metadata not found for path: %s", virtualPath)
+		}
+		generation := metadata.Generation
+
+		// Apply update function
+		updateFunc(metadata)
 
-	// Apply update function
-	updateFunc(metadata)
+		// Update modified timestamp
+		metadata.ModifiedAt = time.Now().Unix()
 
-	// Update modified timestamp
-	metadata.ModifiedAt = time.Now().Unix()
+		// Write back to disk unless someone else wrote first
+		err = ms.writeFileMetadataIfGeneration(virtualPath, metadata, generation)
+		if !errors.Is(err, ErrConcurrentUpdate) || attempt >= maxUpdateAttempts {
+			return err
+		}
 
-	// Write back to disk
-	return ms.WriteFileMetadata(virtualPath, metadata)
+		slog.Debug("Metadata changed during update, retrying",
+			"virtual_path", virtualPath,
+			"attempt", attempt)
+	}
 }
 
 // UpdateFileStatus updates the status of a file in metadata
@@ -239,6 +359,7 @@ func (ms *MetadataService) DeleteFileMetadataWithSourceNzb(ctx context.Context,
 	if err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("failed to delete metadata file: %w", err)
 	}
+	ms.fingerprints.remove(virtualPath)
 
 	// Optionally delete the source NZB file (error-tolerant)
 	if deleteSourceNzb && sourceNzbPath != "" {
@@ -266,10 +387,26 @@ func (ms *MetadataService) DeleteDirectory(virtualPath string) error {
 	if err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("failed to delete metadata directory: %w", err)
 	}
+	ms.fingerprints.removeTree(virtualPath)
 
 	return nil
 }
 
+// DeleteImportedFiles deletes the metadata files created by an import run, e.g. the
+// partial output of a cancelled import. Files the run replaced are kept. It returns the
+// virtual paths that were deleted.
+func (ms *MetadataService) DeleteImportedFiles(record *ImportRecord) ([]string, error) {
+	var deleted []string
+	for _, virtualPath := range record.Created() {
+		if err := ms.DeleteFileMetadata(virtualPath); err != nil {
+			return deleted, fmt.Errorf("failed to delete imported file %s: %w", virtualPath, err)
+		}
+		deleted = append(deleted, virtualPath)
+	}
+
+	return deleted, nil
+}
+
 // ValidateSourceNzb validates that the source NZB file exists and matches metadata
 func (ms *MetadataService) ValidateSourceNzb(metadata *metapb.FileMetadata) error {
 	if metadata.SourceNzbPath == "" {
@@ -0,0 +1,57 @@
+package metadata
+
+import (
+	"errors"
+	"testing"
+
+	metapb "github.com/javi11/altmount/internal/metadata/proto"
+)
+
+func TestUpdateFileMetadataReappliesAfterConcurrentWrite(t *testing.T) {
+	ms := NewMetadataService(t.TempDir())
+	writeTestFile(t, ms, "/movies/Movie.mkv", 1000)
+
+	// Another writer replaces the file between the update's read and its write
+	calls := 0
+	err := ms.UpdateFileMetadata("/movies/Movie.mkv", func(metadata *metapb.FileMetadata) {
+		calls++
+		if calls == 1 {
+			writeTestFile(t, ms, "/movies/Movie.mkv", 2000)
+		}
+		metadata.Status = metapb.FileStatus_FILE_STATUS_CORRUPTED
+	})
+	if err != nil {
+		t.Fatalf("UpdateFileMetadata: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("update applied %d times, want 2", calls)
+	}
+
+	// The update is applied on top of the concurrent write instead of overwriting it
+	got := readTestFile(t, ms, "/movies/Movie.mkv")
+	if got.FileSize != 2000 || got.Status != metapb.FileStatus_FILE_STATUS_CORRUPTED {
+		t.Errorf("got size %d and status %v, want 2000 and corrupted", got.FileSize, got.Status)
+	}
+}
+
+func TestUpdateFileMetadataGivesUpAfterMaxAttempts(t *testing.T) {
+	ms := NewMetadataService(t.TempDir())
+	writeTestFile(t, ms, "/movies/Movie.mkv", 1000)
+
+	// Every attempt conflicts with another write
+	calls := 0
+	err := ms.UpdateFileMetadata("/movies/Movie.mkv", func(metadata *metapb.FileMetadata) {
+		calls++
+		writeTestFile(t, ms, "/movies/Movie.mkv", 2000)
+		metadata.Status = metapb.FileStatus_FILE_STATUS_CORRUPTED
+	})
+	if !errors.Is(err, ErrConcurrentUpdate) {
+		t.Fatalf("UpdateFileMetadata: got %v, want ErrConcurrentUpdate", err)
+	}
+	if calls != maxUpdateAttempts {
+		t.Errorf("update applied %d times, want %d", calls, maxUpdateAttempts)
+	}
+	if got := readTestFile(t, ms, "/movies/Movie.mkv"); got.Status == metapb.FileStatus_FILE_STATUS_CORRUPTED {
+		t.Error("conflicting update was written")
+	}
+}
@@ -0,0 +1,197 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/javi11/altmount/internal/database"
+	"github.com/javi11/altmount/internal/health"
+	"github.com/javi11/altmount/internal/pool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	namespace = "altmount"
+
+	// scrapeTimeout bounds the database queries made while collecting
+	scrapeTimeout = 5 * time.Second
+)
+
+// StreamStats reports the traffic of the stream handler
+type StreamStats interface {
+	BytesServed() int64
+	ActiveStreams() int
+}
+
+// Collector exposes the statistics AltMount already tracks internally as Prometheus metrics.
+// Values are read from their sources on every scrape, so nothing is double counted.
+type Collector struct {
+	poolManager  pool.Manager
+	queueRepo    *database.Repository
+	streamStats  StreamStats
+	healthWorker *health.HealthWorker
+	mu           sync.RWMutex
+
+	healthFilesChecked   *prometheus.Desc
+	healthFilesHealthy   *prometheus.Desc
+	healthFilesCorrupted *prometheus.Desc
+	healthActiveChecks   *prometheus.Desc
+	healthPendingChecks  *prometheus.Desc
+
+	poolActiveConnections *prometheus.Desc
+	poolIdleConnections   *prometheus.Desc
+	poolMaxConnections    *prometheus.Desc
+	poolProviderErrors    *prometheus.Desc
+	poolArticlesFound     *prometheus.Desc
+	poolArticlesMissing   *prometheus.Desc
+
+	streamBytesServed   *prometheus.Desc
+	streamActiveStreams *prometheus.Desc
+
+	queueItems *prometheus.Desc
+}
+
+// NewCollector creates a collector reading from the given sources, any of which may be nil
+func NewCollector(poolManager pool.Manager, queueRepo *database.Repository, streamStats StreamStats) *Collector {
+	providerLabels := []string{"provider"}
+
+	return &Collector{
+		poolManager: poolManager,
+		queueRepo:   queueRepo,
+		streamStats: streamStats,
+
+		healthFilesChecked: prometheus.NewDesc(prometheus.BuildFQName(namespace, "health", "files_checked_total"),
+			"Files checked by the health worker", nil, nil),
+		healthFilesHealthy: prometheus.NewDesc(prometheus.BuildFQName(namespace, "health", "files_healthy_total"),
+			"Health checks that found the file healthy", nil, nil),
+		healthFilesCorrupted: prometheus.NewDesc(prometheus.BuildFQName(namespace, "health", "files_corrupted_total"),
+			"Health checks that found the file corrupted", nil, nil),
+		healthActiveChecks: prometheus.NewDesc(prometheus.BuildFQName(namespace, "health", "active_checks"),
+			"Health checks currently running", nil, nil),
+		healthPendingChecks: prometheus.NewDesc(prometheus.BuildFQName(namespace, "health", "pending_manual_checks"),
+			"Manually requested health checks waiting to run", nil, nil),
+
+		poolActiveConnections: prometheus.NewDesc(prometheus.BuildFQName(namespace, "nntp", "active_connections"),
+			"NNTP connections currently serving a request", providerLabels, nil),
+		poolIdleConnections: prometheus.NewDesc(prometheus.BuildFQName(namespace, "nntp", "idle_connections"),
+			"Open NNTP connections waiting to be used", providerLabels, nil),
+		poolMaxConnections: prometheus.NewDesc(prometheus.BuildFQName(namespace, "nntp", "max_connections"),
+			"Maximum NNTP connections allowed", providerLabels, nil),
+		poolProviderErrors: prometheus.NewDesc(prometheus.BuildFQName(namespace, "nntp", "errors_total"),
+			"Errors returned by the NNTP provider", providerLabels, nil),
+		poolArticlesFound: prometheus.NewDesc(prometheus.BuildFQName(namespace, "nntp", "articles_found_total"),
+			"Article lookups the NNTP provider answered", providerLabels, nil),
+		poolArticlesMissing: prometheus.NewDesc(prometheus.BuildFQName(namespace, "nntp", "articles_missing_total"),
+			"Article lookups the NNTP provider answered with article not found", providerLabels, nil),
+
+		streamBytesServed: prometheus.NewDesc(prometheus.BuildFQName(namespace, "stream", "bytes_served_total"),
+			"Bytes sent by the stream endpoint", nil, nil),
+		streamActiveStreams: prometheus.NewDesc(prometheus.BuildFQName(namespace, "stream", "active_streams"),
+			"Streams currently being served", nil, nil),
+
+		queueItems: prometheus.NewDesc(prometheus.BuildFQName(namespace, "import", "queue_items"),
+			"Import queue items by status", []string{"status"}, nil),
+	}
+}
+
+// SetHealthWorker sets the health worker whose stats are exported, it starts after the collector is created
+func (c *Collector) SetHealthWorker(healthWorker *health.HealthWorker) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.healthWorker = healthWorker
+}
+
+// Describe implements prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.healthFilesChecked
+	ch <- c.healthFilesHealthy
+	ch <- c.healthFilesCorrupted
+	ch <- c.healthActiveChecks
+	ch <- c.healthPendingChecks
+	ch <- c.poolActiveConnections
+	ch <- c.poolIdleConnections
+	ch <- c.poolMaxConnections
+	ch <- c.poolProviderErrors
+	ch <- c.poolArticlesFound
+	ch <- c.poolArticlesMissing
+	ch <- c.streamBytesServed
+	ch <- c.streamActiveStreams
+	ch <- c.queueItems
+}
+
+// Collect implements prometheus.Collector
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.collectHealth(ch)
+	c.collectPool(ch)
+	c.collectStreams(ch)
+	c.collectQueue(ch)
+}
+
+func (c *Collector) collectHealth(ch chan<- prometheus.Metric) {
+	c.mu.RLock()
+	healthWorker := c.healthWorker
+	c.mu.RUnlock()
+
+	if healthWorker == nil {
+		return
+	}
+
+	stats := healthWorker.GetStats()
+	ch <- prometheus.MustNewConstMetric(c.healthFilesChecked, prometheus.CounterValue, float64(stats.TotalFilesChecked))
+	ch <- prometheus.MustNewConstMetric(c.healthFilesHealthy, prometheus.CounterValue, float64(stats.TotalFilesHealthy))
+	ch <- prometheus.MustNewConstMetric(c.healthFilesCorrupted, prometheus.CounterValue, float64(stats.TotalFilesCorrupted))
+	ch <- prometheus.MustNewConstMetric(c.healthActiveChecks, prometheus.GaugeValue, float64(len(stats.ActiveChecks)))
+	ch <- prometheus.MustNewConstMetric(c.healthPendingChecks, prometheus.GaugeValue, float64(stats.PendingManualChecks))
+}
+
+func (c *Collector) collectPool(ch chan<- prometheus.Metric) {
+	if c.poolManager == nil || !c.poolManager.HasPool() {
+		return
+	}
+
+	stats, err := c.poolManager.Stats()
+	if err != nil {
+		slog.Debug("Failed to collect NNTP pool metrics", "error", err)
+		return
+	}
+
+	for _, p := range stats.Providers {
+		ch <- prometheus.MustNewConstMetric(c.poolActiveConnections, prometheus.GaugeValue, float64(p.ActiveConnections), p.ID)
+		ch <- prometheus.MustNewConstMetric(c.poolIdleConnections, prometheus.GaugeValue, float64(p.IdleConnections), p.ID)
+		ch <- prometheus.MustNewConstMetric(c.poolMaxConnections, prometheus.GaugeValue, float64(p.MaxConnections), p.ID)
+		ch <- prometheus.MustNewConstMetric(c.poolProviderErrors, prometheus.CounterValue, float64(p.Errors), p.ID)
+		ch <- prometheus.MustNewConstMetric(c.poolArticlesFound, prometheus.CounterValue, float64(p.Articles.Total.Found), p.ID)
+		ch <- prometheus.MustNewConstMetric(c.poolArticlesMissing, prometheus.CounterValue, float64(p.Articles.Total.Missing), p.ID)
+	}
+}
+
+func (c *Collector) collectStreams(ch chan<- prometheus.Metric) {
+	if c.streamStats == nil {
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.streamBytesServed, prometheus.CounterValue, float64(c.streamStats.BytesServed()))
+	ch <- prometheus.MustNewConstMetric(c.streamActiveStreams, prometheus.GaugeValue, float64(c.streamStats.ActiveStreams()))
+}
+
+func (c *Collector) collectQueue(ch chan<- prometheus.Metric) {
+	if c.queueRepo == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), scrapeTimeout)
+	defer cancel()
+
+	stats, err := c.queueRepo.GetQueueStats(ctx)
+	if err != nil {
+		slog.DebugContext(ctx, "Failed to collect import queue metrics", "error", err)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.queueItems, prometheus.GaugeValue, float64(stats.TotalQueued), "queued")
+	ch <- prometheus.MustNewConstMetric(c.queueItems, prometheus.GaugeValue, float64(stats.TotalProcessing), "processing")
+	ch <- prometheus.MustNewConstMetric(c.queueItems, prometheus.GaugeValue, float64(stats.TotalCompleted), "completed")
+	ch <- prometheus.MustNewConstMetric(c.queueItems, prometheus.GaugeValue, float64(stats.TotalFailed), "failed")
+}
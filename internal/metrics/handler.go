@@ -0,0 +1,27 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// NewHandler returns an http.Handler serving the collector's metrics along with the
+// Go runtime and process metrics in the Prometheus text format
+func NewHandler(collector *Collector) (http.Handler, error) {
+	registry := prometheus.NewRegistry()
+
+	for _, c := range []prometheus.Collector{
+		collector,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	} {
+		if err := registry.Register(c); err != nil {
+			return nil, err
+		}
+	}
+
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{}), nil
+}
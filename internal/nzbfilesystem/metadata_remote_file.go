@@ -2,6 +2,9 @@ package nzbfilesystem
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
@@ -23,6 +26,7 @@ import (
 	"github.com/javi11/altmount/internal/usenet"
 	"github.com/javi11/altmount/internal/utils"
 	"github.com/spf13/afero"
+	"golang.org/x/net/webdav"
 )
 
 // MetadataRemoteFile implements the RemoteFile interface for metadata-backed virtual files
@@ -33,8 +37,18 @@ type MetadataRemoteFile struct {
 	configGetter     config.ConfigGetter // Dynamic config access
 	rcloneCipher     *rclone.RcloneCrypt // For rclone encryption/decryption
 	aesCipher        *aes.AesCipher      // For AES encryption/decryption
+
+	segmentCache       *usenet.SegmentCache // Optional on-disk segment cache
+	probeCache         *ProbeCache          // Optional in-memory cache of file starts and ends
+	readFailureHandler ReadFailureHandler   // Optional, told about files that fail to read
+
+	throughput *throughputTracker // Live throughput of the files being read
 }
 
+// ReadFailureHandler is told about files whose segments could not be read while streaming.
+// It returns false when it could not take the report, the file is then marked corrupted directly.
+type ReadFailureHandler func(ctx context.Context, filePath string, sourceNzbPath *string) bool
+
 // Configuration is now accessed dynamically through config.ConfigGetter
 // No longer need a separate config struct
 
@@ -64,9 +78,30 @@ func NewMetadataRemoteFile(
 		configGetter:     configGetter,
 		rcloneCipher:     rcloneCipher,
 		aesCipher:        aesCipher,
+		throughput:       newThroughputTracker(),
 	}
 }
 
+// SetSegmentCache sets the on-disk cache used for the segments of opened files
+func (mrf *MetadataRemoteFile) SetSegmentCache(cache *usenet.SegmentCache) {
+	mrf.segmentCache = cache
+}
+
+// SetProbeCache sets the in-memory cache used for reads at the start and end of opened files
+func (mrf *MetadataRemoteFile) SetProbeCache(cache *ProbeCache) {
+	mrf.probeCache = cache
+}
+
+// SetReadFailureHandler sets the handler told about opened files that fail to read
+func (mrf *MetadataRemoteFile) SetReadFailureHandler(handler ReadFailureHandler) {
+	mrf.readFailureHandler = handler
+}
+
+// Throughput returns the live throughput of the files being read
+func (mrf *MetadataRemoteFile) Throughput() ThroughputStats {
+	return mrf.throughput.stats(time.Now())
+}
+
 // Helper methods to get dynamic config values
 func (mrf *MetadataRemoteFile) getMaxDownloadWorkers() int {
 	return mrf.configGetter().Streaming.MaxDownloadWorkers
@@ -76,6 +111,14 @@ func (mrf *MetadataRemoteFile) getMaxCacheSizeMB() int {
 	return mrf.configGetter().Streaming.MaxCacheSizeMB
 }
 
+func (mrf *MetadataRemoteFile) getReadAheadMB() int {
+	return mrf.configGetter().Streaming.ReadAheadMB
+}
+
+func (mrf *MetadataRemoteFile) getProbeWindowKB() int {
+	return mrf.configGetter().Streaming.ProbeWindowKB
+}
+
 func (mrf *MetadataRemoteFile) getGlobalPassword() string {
 	return mrf.configGetter().RClone.Password
 }
@@ -157,10 +200,16 @@ func (mrf *MetadataRemoteFile) OpenFile(ctx context.Context, name string) (bool,
 		ctx:              ctx,
 		maxWorkers:       mrf.getMaxDownloadWorkers(),
 		maxCacheSizeMB:   mrf.getMaxCacheSizeMB(),
+		readAheadMB:      mrf.getReadAheadMB(),
 		rcloneCipher:     mrf.rcloneCipher,
 		aesCipher:        mrf.aesCipher,
+		segmentCache:     mrf.segmentCache,
+		probeCache:       mrf.probeCache,
+		probeWindow:      int64(mrf.getProbeWindowKB()) * 1024,
 		globalPassword:   mrf.getGlobalPassword(),
+		onReadFailure:    mrf.readFailureHandler,
 		globalSalt:       mrf.getGlobalSalt(),
+		throughput:       mrf.throughput,
 	}
 
 	return true, virtualFile, nil
@@ -282,6 +331,7 @@ func (mrf *MetadataRemoteFile) Stat(ctx context.Context, name string) (bool, fs.
 		mode:    0644, // Default file mode
 		modTime: time.Unix(fileMeta.ModifiedAt, 0),
 		isDir:   false,
+		etag:    metadataETag(fileMeta),
 	}
 
 	return true, info, nil
@@ -294,6 +344,7 @@ type MetadataFileInfo struct {
 	mode    os.FileMode
 	modTime time.Time
 	isDir   bool
+	etag    string // Set for healthy files only
 }
 
 func (mfi *MetadataFileInfo) Name() string       { return mfi.name }
@@ -303,6 +354,40 @@ func (mfi *MetadataFileInfo) ModTime() time.Time { return mfi.modTime }
 func (mfi *MetadataFileInfo) IsDir() bool        { return mfi.isDir }
 func (mfi *MetadataFileInfo) Sys() interface{}   { return nil }
 
+// ETag implements webdav.ETager. Files without a metadata-based ETag fall back to the
+// default modification time and size heuristic.
+func (mfi *MetadataFileInfo) ETag(ctx context.Context) (string, error) {
+	if mfi.etag == "" {
+		return "", webdav.ErrNotImplemented
+	}
+	return mfi.etag, nil
+}
+
+// metadataETag returns a strong ETag derived from the file size, encryption and segment
+// list, which only change when the file is re-imported. Corrupted files get no ETag.
+func metadataETag(fileMeta *metapb.FileMetadata) string {
+	if fileMeta.Status == metapb.FileStatus_FILE_STATUS_CORRUPTED {
+		return ""
+	}
+
+	h := sha256.New()
+	var buf [8]byte
+	writeInt := func(v int64) {
+		binary.BigEndian.PutUint64(buf[:], uint64(v))
+		h.Write(buf[:])
+	}
+
+	writeInt(fileMeta.FileSize)
+	writeInt(int64(fileMeta.Encryption))
+	for _, seg := range fileMeta.SegmentData {
+		h.Write([]byte(seg.Id))
+		writeInt(seg.StartOffset)
+		writeInt(seg.EndOffset)
+	}
+
+	return `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
 // MetadataSegmentLoader adapts metadata segments to the usenet.SegmentLoader interface
 type MetadataSegmentLoader struct {
 	segments []*metapb.SegmentData
@@ -393,10 +478,16 @@ func (mvd *MetadataVirtualDirectory) Readdir(count int) ([]fs.FileInfo, error) {
 		return nil, err
 	}
 
-	for _, fileName := range fileNames {
-		virtualFilePath := filepath.Join(mvd.normalizedPath, fileName)
-		fileMeta, err := mvd.metadataService.ReadFileMetadata(virtualFilePath)
-		if err != nil || fileMeta == nil {
+	// Read all file metadata in one batch instead of one lookup per child
+	virtualFilePaths := make([]string, len(fileNames))
+	for i, fileName := range fileNames {
+		virtualFilePaths[i] = filepath.Join(mvd.normalizedPath, fileName)
+	}
+	fileMetas, _ := mvd.metadataService.ReadFileMetadataBatch(virtualFilePaths)
+
+	for i, fileName := range fileNames {
+		fileMeta, ok := fileMetas[virtualFilePaths[i]]
+		if !ok {
 			continue
 		}
 
@@ -483,10 +574,17 @@ type MetadataVirtualFile struct {
 	ctx              context.Context
 	maxWorkers       int
 	maxCacheSizeMB   int // Maximum cache size in MB for ahead downloads
+	readAheadMB      int // Prefetch window in MB for sequential reads
 	rcloneCipher     *rclone.RcloneCrypt
 	aesCipher        *aes.AesCipher
+	segmentCache     *usenet.SegmentCache
+	probeCache       *ProbeCache
+	probeWindow      int64 // Bytes at each end of the file served from the probe cache
 	globalPassword   string
 	globalSalt       string
+	onReadFailure    ReadFailureHandler
+	throughput       *throughputTracker
+	meter            *streamMeter // Created on the first read, removed on close
 
 	// Reader state and position tracking
 	reader            io.ReadCloser
@@ -496,6 +594,10 @@ type MetadataVirtualFile struct {
 	currentRangeEnd   int64 // End of current reader's range
 	originalRangeEnd  int64 // Original end requested by client (-1 for unbounded)
 
+	// ETag is computed once since hashing the segment list is not free
+	etag     string
+	etagOnce sync.Once
+
 	mu sync.Mutex
 }
 
@@ -508,10 +610,31 @@ func (mvf *MetadataVirtualFile) Read(p []byte) (n int, err error) {
 	mvf.mu.Lock()
 	defer mvf.mu.Unlock()
 
+	// Probes of the start and end of the file are answered without starting a download
+	if n, ok := mvf.readProbeWindow(p); ok {
+		if mvf.meter == nil && mvf.throughput != nil {
+			mvf.meter = mvf.throughput.open(mvf.name, time.Now())
+		}
+		if mvf.meter != nil {
+			mvf.meter.add(n, time.Now())
+		}
+		mvf.position += int64(n)
+		return n, nil
+	}
+
 	if err := mvf.ensureReader(); err != nil {
 		return 0, err
 	}
 
+	if mvf.meter == nil && mvf.throughput != nil {
+		mvf.meter = mvf.throughput.open(mvf.name, time.Now())
+	}
+	defer func() {
+		if mvf.meter != nil && n > 0 {
+			mvf.meter.add(n, time.Now())
+		}
+	}()
+
 	totalRead, err := mvf.reader.Read(p)
 	if err != nil {
 		// Check if this is EOF and we have more data to read
@@ -608,6 +731,10 @@ func (mvf *MetadataVirtualFile) Seek(offset int64, whence int) (int64, error) {
 func (mvf *MetadataVirtualFile) Close() error {
 	mvf.mu.Lock()
 	defer mvf.mu.Unlock()
+	if mvf.meter != nil {
+		mvf.throughput.close(mvf.meter)
+		mvf.meter = nil
+	}
 	if mvf.reader != nil {
 		err := mvf.reader.Close()
 		mvf.reader = nil
@@ -651,11 +778,20 @@ func (mvf *MetadataVirtualFile) Stat() (fs.FileInfo, error) {
 		mode:    0644,
 		modTime: time.Unix(mvf.fileMeta.ModifiedAt, 0),
 		isDir:   false, // Files are never directories in simplified schema
+		etag:    mvf.getETag(),
 	}
 
 	return info, nil
 }
 
+// getETag returns the metadata-based ETag of the file, empty for corrupted files
+func (mvf *MetadataVirtualFile) getETag() string {
+	mvf.etagOnce.Do(func() {
+		mvf.etag = metadataETag(mvf.fileMeta)
+	})
+	return mvf.etag
+}
+
 // Write implements afero.File.Write (not supported)
 func (mvf *MetadataVirtualFile) Write(p []byte) (n int, err error) {
 	return 0, fmt.Errorf("write not supported")
@@ -724,25 +860,74 @@ func (mvf *MetadataVirtualFile) ensureReader() error {
 	mvf.currentRangeStart = start
 	mvf.currentRangeEnd = end
 
-	// Create reader for the calculated range using metadata segments
+	reader, err := mvf.newRangeReader(start, end)
+	if err != nil {
+		return err
+	}
+	mvf.reader = reader
+
+	mvf.readerInitialized = true
+	return nil
+}
+
+// newRangeReader creates a reader for the bytes start to end of the file, decrypting them
+// if the file is encrypted
+func (mvf *MetadataVirtualFile) newRangeReader(start, end int64) (io.ReadCloser, error) {
 	if mvf.fileMeta.Encryption != metapb.Encryption_NONE {
 		// Wrap the usenet reader with encryption
 		decryptedReader, err := mvf.wrapWithEncryption(start, end)
 		if err != nil {
-			return fmt.Errorf(ErrMsgFailedWrapEncryption, err)
+			return nil, fmt.Errorf(ErrMsgFailedWrapEncryption, err)
 		}
-		mvf.reader = decryptedReader
-	} else {
-		// Create plain usenet reader
-		ur, err := mvf.createUsenetReader(mvf.ctx, start, end)
+		return decryptedReader, nil
+	}
+
+	// Create plain usenet reader
+	return mvf.createUsenetReader(mvf.ctx, start, end)
+}
+
+// readProbeWindow serves a read at the start or end of the file from the probe cache,
+// downloading the whole window on a miss. It reports false when the read has to go
+// through a reader of its own: while a reader is open, outside the windows, or when the
+// window could not be downloaded, in which case the reader reports the error.
+func (mvf *MetadataVirtualFile) readProbeWindow(p []byte) (int, bool) {
+	if mvf.readerInitialized || mvf.probeCache == nil || !mvf.probeCache.enabled() {
+		return 0, false
+	}
+
+	start, length, ok := probeWindow(mvf.position, mvf.fileMeta.FileSize, mvf.probeWindow)
+	if !ok {
+		return 0, false
+	}
+
+	etag := mvf.getETag()
+	if etag == "" {
+		return 0, false
+	}
+
+	data, err := mvf.probeCache.get(etag, start, length, func() ([]byte, error) {
+		reader, err := mvf.newRangeReader(start, start+length-1)
 		if err != nil {
-			return err
+			return nil, err
+		}
+		defer reader.Close()
+
+		data := make([]byte, length)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, err
 		}
-		mvf.reader = ur
+		return data, nil
+	})
+	if err != nil {
+		slog.DebugContext(mvf.ctx, "Failed to download probe window, reading without the probe cache",
+			"file", mvf.name,
+			"start", start,
+			"length", length,
+			"error", err)
+		return 0, false
 	}
 
-	mvf.readerInitialized = true
-	return nil
+	return copy(p, data[mvf.position-start:]), true
 }
 
 // getRequestRange gets the range for reader creation based on HTTP range or current position
@@ -755,13 +940,14 @@ func (mvf *MetadataVirtualFile) getRequestRange() (start, end int64) {
 			rangeHeader, err := utils.ParseRangeHeader(rangeStr)
 			if err == nil && rangeHeader != nil {
 				mvf.originalRangeEnd = rangeHeader.End
-				return rangeHeader.Start, rangeHeader.End
+				// The start of the range may already have been served from the probe cache
+				return max(rangeHeader.Start, mvf.position), rangeHeader.End
 			}
 		}
 
 		// No range header, set unbounded
 		mvf.originalRangeEnd = -1
-		return 0, -1
+		return mvf.position, -1
 	}
 
 	// For subsequent reads, use current position and respect original range
@@ -774,7 +960,7 @@ func (mvf *MetadataVirtualFile) getRequestRange() (start, end int64) {
 		targetEnd = mvf.originalRangeEnd
 	}
 
-	return 0, targetEnd
+	return mvf.position, targetEnd
 }
 
 // createUsenetReader creates a new usenet reader for the specified range using metadata segments
@@ -799,7 +985,7 @@ func (mvf *MetadataVirtualFile) createUsenetReader(ctx context.Context, start, e
 		}
 	}
 
-	return usenet.NewUsenetReader(ctx, mvf.poolManager.GetPool, rg, mvf.maxWorkers, mvf.maxCacheSizeMB)
+	return usenet.NewUsenetReader(ctx, mvf.poolManager.GetPool, rg, mvf.maxWorkers, mvf.maxCacheSizeMB, mvf.readAheadMB, mvf.segmentCache)
 }
 
 // wrapWithEncryption wraps a usenet reader with encryption using metadata
@@ -896,6 +1082,11 @@ func (mvf *MetadataVirtualFile) updateFileHealthOnError(dataCorruptionErr *usene
 			sourceNzbPath = nil
 		}
 
+		// Let the health worker confirm the corruption with a check, which also triggers the repair
+		if mvf.onReadFailure != nil && mvf.onReadFailure(context.Background(), mvf.name, sourceNzbPath) {
+			return
+		}
+
 		// Create error details JSON
 		errorDetails := fmt.Sprintf(`{"missing_articles": %d, "total_articles": %d, "error_type": "ArticleNotFound"}`,
 			1, len(mvf.fileMeta.SegmentData)) // Simplified, could be enhanced
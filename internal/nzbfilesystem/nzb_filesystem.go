@@ -23,6 +23,16 @@ func NewNzbFilesystem(remoteFile *MetadataRemoteFile) *NzbFilesystem {
 	}
 }
 
+// SetReadFailureHandler sets the handler told about files that fail to read while streaming
+func (nfs *NzbFilesystem) SetReadFailureHandler(handler ReadFailureHandler) {
+	nfs.remoteFile.SetReadFailureHandler(handler)
+}
+
+// Throughput returns the live throughput of the files being read
+func (nfs *NzbFilesystem) Throughput() ThroughputStats {
+	return nfs.remoteFile.Throughput()
+}
+
 // Name returns the filesystem name
 func (nfs *NzbFilesystem) Name() string {
 	return "NzbFilesystem"
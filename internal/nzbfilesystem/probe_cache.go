@@ -0,0 +1,150 @@
+package nzbfilesystem
+
+import (
+	"container/list"
+	"sync"
+)
+
+// ProbeCache keeps the first and last bytes of recently read files in memory. Media servers
+// and players analyze a file by reading its header and its tail, where containers keep
+// their index (MP4 moov atom, MKV cues), often several times in a row. Serving those reads
+// from memory avoids starting a new download for every probe. The least recently used
+// windows are evicted once the cache grows over its size limit.
+type ProbeCache struct {
+	mu       sync.Mutex
+	maxSize  int64
+	size     int64
+	entries  map[probeKey]*list.Element
+	lru      *list.List // Most recently used at the front
+	inflight map[probeKey]*probeFetch
+}
+
+// probeKey identifies a window of a file. The ETag changes whenever the file is
+// re-imported, so a stale window is never served.
+type probeKey struct {
+	etag   string
+	start  int64
+	length int64
+}
+
+type probeEntry struct {
+	key  probeKey
+	data []byte
+}
+
+// probeFetch is a window being downloaded, concurrent readers of the window wait for it
+type probeFetch struct {
+	done chan struct{}
+	data []byte
+	err  error
+}
+
+// NewProbeCache creates a probe cache holding up to maxSizeMB of windows, 0 disables it
+func NewProbeCache(maxSizeMB int) *ProbeCache {
+	return &ProbeCache{
+		maxSize:  int64(maxSizeMB) * 1024 * 1024,
+		entries:  make(map[probeKey]*list.Element),
+		lru:      list.New(),
+		inflight: make(map[probeKey]*probeFetch),
+	}
+}
+
+// SetMaxSize changes the size limit, evicting windows if the cache is now over it
+func (c *ProbeCache) SetMaxSize(maxSizeMB int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.maxSize = int64(maxSizeMB) * 1024 * 1024
+	c.evictLocked()
+}
+
+// enabled reports whether windows can be cached at all
+func (c *ProbeCache) enabled() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.maxSize > 0
+}
+
+// get returns the window of the file with the given ETag, calling fetch to download it when
+// it is not cached. Concurrent calls for the same window share a single fetch.
+func (c *ProbeCache) get(etag string, start, length int64, fetch func() ([]byte, error)) ([]byte, error) {
+	key := probeKey{etag: etag, start: start, length: length}
+
+	c.mu.Lock()
+	if elem, ok := c.entries[key]; ok {
+		c.lru.MoveToFront(elem)
+		c.mu.Unlock()
+		return elem.Value.(*probeEntry).data, nil
+	}
+	if f, ok := c.inflight[key]; ok {
+		c.mu.Unlock()
+		<-f.done
+		return f.data, f.err
+	}
+	f := &probeFetch{done: make(chan struct{})}
+	c.inflight[key] = f
+	c.mu.Unlock()
+
+	f.data, f.err = fetch()
+
+	c.mu.Lock()
+	delete(c.inflight, key)
+	if f.err == nil {
+		c.putLocked(key, f.data)
+	}
+	c.mu.Unlock()
+	close(f.done)
+
+	return f.data, f.err
+}
+
+// putLocked stores a window, evicting the least recently used ones to make room
+func (c *ProbeCache) putLocked(key probeKey, data []byte) {
+	size := int64(len(data))
+	if size == 0 || size > c.maxSize {
+		return
+	}
+	if _, ok := c.entries[key]; ok {
+		return
+	}
+
+	c.entries[key] = c.lru.PushFront(&probeEntry{key: key, data: data})
+	c.size += size
+	c.evictLocked()
+}
+
+// evictLocked removes the least recently used windows until the cache fits its limit
+func (c *ProbeCache) evictLocked() {
+	for c.size > c.maxSize {
+		elem := c.lru.Back()
+		if elem == nil {
+			return
+		}
+
+		entry := elem.Value.(*probeEntry)
+		c.lru.Remove(elem)
+		delete(c.entries, entry.key)
+		c.size -= int64(len(entry.data))
+	}
+}
+
+// probeWindow returns the window of a file of the given size that holds offset, with
+// windowSize bytes at each end of the file. Files up to twice the window size are a
+// single window. ok is false when offset lies between the two windows.
+func probeWindow(offset, fileSize, windowSize int64) (start, length int64, ok bool) {
+	if windowSize <= 0 || offset < 0 || offset >= fileSize {
+		return 0, 0, false
+	}
+
+	switch {
+	case fileSize <= 2*windowSize:
+		return 0, fileSize, true
+	case offset < windowSize:
+		return 0, windowSize, true
+	case offset >= fileSize-windowSize:
+		return fileSize - windowSize, windowSize, true
+	default:
+		return 0, 0, false
+	}
+}
@@ -0,0 +1,128 @@
+package nzbfilesystem
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestProbeWindow(t *testing.T) {
+	const window = 100
+
+	tests := []struct {
+		name       string
+		offset     int64
+		fileSize   int64
+		wantStart  int64
+		wantLength int64
+		wantOK     bool
+	}{
+		{name: "start of file", offset: 0, fileSize: 1000, wantStart: 0, wantLength: 100, wantOK: true},
+		{name: "end of head window", offset: 99, fileSize: 1000, wantStart: 0, wantLength: 100, wantOK: true},
+		{name: "between windows", offset: 100, fileSize: 1000, wantOK: false},
+		{name: "start of tail window", offset: 900, fileSize: 1000, wantStart: 900, wantLength: 100, wantOK: true},
+		{name: "last byte", offset: 999, fileSize: 1000, wantStart: 900, wantLength: 100, wantOK: true},
+		{name: "past the end", offset: 1000, fileSize: 1000, wantOK: false},
+		{name: "small file is one window", offset: 150, fileSize: 200, wantStart: 0, wantLength: 200, wantOK: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, length, ok := probeWindow(tt.offset, tt.fileSize, window)
+			if ok != tt.wantOK {
+				t.Fatalf("probeWindow(%d, %d): got ok %v, want %v", tt.offset, tt.fileSize, ok, tt.wantOK)
+			}
+			if ok && (start != tt.wantStart || length != tt.wantLength) {
+				t.Errorf("probeWindow(%d, %d): got [%d, +%d), want [%d, +%d)", tt.offset, tt.fileSize, start, length, tt.wantStart, tt.wantLength)
+			}
+		})
+	}
+
+	if _, _, ok := probeWindow(0, 1000, 0); ok {
+		t.Error("probeWindow with a zero window: got ok, want disabled")
+	}
+}
+
+func TestProbeCacheSharesFetches(t *testing.T) {
+	c := NewProbeCache(1)
+
+	var fetches atomic.Int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+	fetch := func() ([]byte, error) {
+		if fetches.Add(1) == 1 {
+			close(started)
+		}
+		<-release
+		return []byte("window"), nil
+	}
+
+	get := func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		data, err := c.get("etag", 0, 6, fetch)
+		if err != nil || string(data) != "window" {
+			t.Errorf("get: got %q, %v", data, err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go get(&wg)
+	<-started
+
+	// Readers arriving while the window is downloaded wait for it, later ones hit the cache
+	for range 4 {
+		wg.Add(1)
+		go get(&wg)
+	}
+	close(release)
+	wg.Add(1)
+	get(&wg)
+	wg.Wait()
+
+	if got := fetches.Load(); got != 1 {
+		t.Errorf("got %d fetches, want 1", got)
+	}
+}
+
+func TestProbeCacheDoesNotCacheErrors(t *testing.T) {
+	c := NewProbeCache(1)
+
+	failed := errors.New("missing articles")
+	if _, err := c.get("etag", 0, 6, func() ([]byte, error) { return nil, failed }); !errors.Is(err, failed) {
+		t.Fatalf("get: got error %v, want %v", err, failed)
+	}
+
+	data, err := c.get("etag", 0, 6, func() ([]byte, error) { return []byte("window"), nil })
+	if err != nil || string(data) != "window" {
+		t.Errorf("get after a failed fetch: got %q, %v", data, err)
+	}
+}
+
+func TestProbeCacheEviction(t *testing.T) {
+	c := NewProbeCache(1) // 1MB
+	window := make([]byte, 400*1024)
+	fetch := func() ([]byte, error) { return window, nil }
+
+	for _, etag := range []string{"a", "b", "c"} {
+		if _, err := c.get(etag, 0, int64(len(window)), fetch); err != nil {
+			t.Fatalf("get %s: %v", etag, err)
+		}
+	}
+
+	// "a" is the least recently used and had to make room for "c"
+	refetched := false
+	_, _ = c.get("a", 0, int64(len(window)), func() ([]byte, error) {
+		refetched = true
+		return window, nil
+	})
+	if !refetched {
+		t.Error("least recently used window was not evicted")
+	}
+
+	c.SetMaxSize(0)
+	if c.enabled() || c.size != 0 {
+		t.Errorf("disabled cache still holds %d bytes", c.size)
+	}
+}
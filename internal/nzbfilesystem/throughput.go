@@ -0,0 +1,130 @@
+package nzbfilesystem
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// throughputWindow is the number of seconds the throughput of a stream is averaged over
+const throughputWindow = 10
+
+// StreamThroughput is the live throughput of an open file that is being read
+type StreamThroughput struct {
+	Path           string    `json:"path"`
+	BytesRead      int64     `json:"bytes_read"`
+	BytesPerSecond int64     `json:"bytes_per_second"` // Averaged over the last throughputWindow seconds
+	OpenedAt       time.Time `json:"opened_at"`
+}
+
+// ThroughputStats is the live throughput of every open file being read
+type ThroughputStats struct {
+	Streams             []StreamThroughput `json:"streams"`
+	TotalBytesPerSecond int64              `json:"total_bytes_per_second"`
+}
+
+// throughputTracker tracks the files being read, WebDAV and the stream handler both read
+// through the filesystem so every stream is counted
+type throughputTracker struct {
+	mu     sync.Mutex
+	meters map[*streamMeter]struct{}
+}
+
+func newThroughputTracker() *throughputTracker {
+	return &throughputTracker{
+		meters: make(map[*streamMeter]struct{}),
+	}
+}
+
+// open starts tracking a stream of path
+func (t *throughputTracker) open(path string, now time.Time) *streamMeter {
+	m := &streamMeter{path: path, openedAt: now}
+
+	t.mu.Lock()
+	t.meters[m] = struct{}{}
+	t.mu.Unlock()
+
+	return m
+}
+
+// close stops tracking a stream
+func (t *throughputTracker) close(m *streamMeter) {
+	t.mu.Lock()
+	delete(t.meters, m)
+	t.mu.Unlock()
+}
+
+// stats returns the throughput of every tracked stream, oldest first
+func (t *throughputTracker) stats(now time.Time) ThroughputStats {
+	t.mu.Lock()
+	meters := make([]*streamMeter, 0, len(t.meters))
+	for m := range t.meters {
+		meters = append(meters, m)
+	}
+	t.mu.Unlock()
+
+	stats := ThroughputStats{Streams: make([]StreamThroughput, 0, len(meters))}
+	for _, m := range meters {
+		s := m.throughput(now)
+		stats.Streams = append(stats.Streams, s)
+		stats.TotalBytesPerSecond += s.BytesPerSecond
+	}
+
+	slices.SortFunc(stats.Streams, func(a, b StreamThroughput) int {
+		return a.OpenedAt.Compare(b.OpenedAt)
+	})
+
+	return stats
+}
+
+// streamMeter counts the bytes read from a stream in one second buckets
+type streamMeter struct {
+	path     string
+	openedAt time.Time
+
+	mu      sync.Mutex
+	total   int64
+	bytes   [throughputWindow]int64
+	seconds [throughputWindow]int64 // Unix second each bucket holds the bytes of
+}
+
+// add records n bytes read at now
+func (m *streamMeter) add(n int, now time.Time) {
+	sec := now.Unix()
+	i := sec % throughputWindow
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.seconds[i] != sec {
+		m.seconds[i] = sec
+		m.bytes[i] = 0
+	}
+	m.bytes[i] += int64(n)
+	m.total += int64(n)
+}
+
+// throughput returns the bytes read per second over the last throughputWindow seconds,
+// or since the stream was opened when that is more recent
+func (m *streamMeter) throughput(now time.Time) StreamThroughput {
+	sec := now.Unix()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var recent int64
+	for i, s := range m.seconds {
+		if s > sec-throughputWindow && s <= sec {
+			recent += m.bytes[i]
+		}
+	}
+
+	window := min(max(int64(now.Sub(m.openedAt).Seconds()), 1), throughputWindow)
+
+	return StreamThroughput{
+		Path:           m.path,
+		BytesRead:      m.total,
+		BytesPerSecond: recent / window,
+		OpenedAt:       m.openedAt,
+	}
+}
@@ -0,0 +1,73 @@
+package nzbfilesystem
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStreamThroughput(t *testing.T) {
+	start := time.Unix(1_000_000, 0)
+	tracker := newThroughputTracker()
+
+	first := tracker.open("/movies/a.mkv", start)
+	second := tracker.open("/movies/b.mkv", start.Add(time.Second))
+
+	// first reads 1 MB every second for 20 seconds, second only during the last 5
+	for i := range 20 {
+		now := start.Add(time.Duration(i) * time.Second)
+		first.add(1_000_000, now)
+		if i >= 15 {
+			second.add(500_000, now)
+		}
+	}
+
+	now := start.Add(19*time.Second + 500*time.Millisecond)
+	stats := tracker.stats(now)
+	if len(stats.Streams) != 2 {
+		t.Fatalf("got %d streams, want 2", len(stats.Streams))
+	}
+
+	a, b := stats.Streams[0], stats.Streams[1]
+	if a.Path != "/movies/a.mkv" || b.Path != "/movies/b.mkv" {
+		t.Fatalf("streams not sorted by open time: %s, %s", a.Path, b.Path)
+	}
+	if a.BytesRead != 20_000_000 {
+		t.Errorf("first stream: got %d bytes read, want %d", a.BytesRead, 20_000_000)
+	}
+	if a.BytesPerSecond != 1_000_000 {
+		t.Errorf("first stream: got %d bytes/s, want %d", a.BytesPerSecond, 1_000_000)
+	}
+	// 2.5 MB over the 10 second window
+	if b.BytesPerSecond != 250_000 {
+		t.Errorf("second stream: got %d bytes/s, want %d", b.BytesPerSecond, 250_000)
+	}
+	if stats.TotalBytesPerSecond != a.BytesPerSecond+b.BytesPerSecond {
+		t.Errorf("got total %d bytes/s, want %d", stats.TotalBytesPerSecond, a.BytesPerSecond+b.BytesPerSecond)
+	}
+
+	// Idle streams drop to zero once their reads leave the window
+	stats = tracker.stats(now.Add(time.Minute))
+	for _, s := range stats.Streams {
+		if s.BytesPerSecond != 0 {
+			t.Errorf("%s: got %d bytes/s after a minute idle, want 0", s.Path, s.BytesPerSecond)
+		}
+	}
+
+	tracker.close(first)
+	if stats := tracker.stats(now); len(stats.Streams) != 1 || stats.Streams[0].Path != "/movies/b.mkv" {
+		t.Errorf("closed stream still tracked: %+v", stats.Streams)
+	}
+}
+
+func TestStreamThroughputRecentlyOpened(t *testing.T) {
+	start := time.Unix(1_000_000, 0)
+	m := newThroughputTracker().open("/movies/a.mkv", start)
+
+	m.add(2_000_000, start)
+	m.add(2_000_000, start.Add(time.Second))
+
+	// Averaged over the two seconds the stream has been open, not the whole window
+	if got := m.throughput(start.Add(2 * time.Second)).BytesPerSecond; got != 2_000_000 {
+		t.Errorf("got %d bytes/s, want %d", got, 2_000_000)
+	}
+}
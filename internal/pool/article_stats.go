@@ -0,0 +1,227 @@
+package pool
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/javi11/nntppool/v2/pkg/nntpcli"
+)
+
+const (
+	// articleStatsWindow is the period covered by the rolling article counts
+	articleStatsWindow = 7 * 24 * time.Hour
+	// articleStatsBucket is the resolution of the rolling window
+	articleStatsBucket = time.Hour
+
+	articleStatsBuckets = int(articleStatsWindow / articleStatsBucket)
+)
+
+// ArticleCounts holds how many articles a provider had and was missing
+type ArticleCounts struct {
+	Found    int64   `json:"found"`
+	Missing  int64   `json:"missing"`   // Article not found (430), e.g. past retention or taken down
+	MissRate float64 `json:"miss_rate"` // Missing / (Found + Missing), 0 without requests
+}
+
+// ProviderArticleStats reports the article lookups of a provider since startup and over
+// the last WindowHours hours
+type ProviderArticleStats struct {
+	Total       ArticleCounts `json:"total"`
+	Window      ArticleCounts `json:"window"`
+	WindowHours int           `json:"window_hours"`
+}
+
+// ArticleStats counts found and missing articles per provider. It outlives the pools the
+// manager creates, so the counts survive provider changes until the process restarts.
+type ArticleStats struct {
+	mu        sync.Mutex
+	providers map[string]*providerArticles // Keyed by provider ID (host_username)
+}
+
+// providerArticles holds the counts of one provider, with one bucket per hour of the window
+type providerArticles struct {
+	mu      sync.Mutex
+	found   int64
+	missing int64
+	buckets [articleStatsBuckets]articleBucket
+}
+
+type articleBucket struct {
+	slot    int64 // Hours since the epoch the bucket counts, stale buckets are reset on use
+	found   int64
+	missing int64
+}
+
+// NewArticleStats creates an empty article counter
+func NewArticleStats() *ArticleStats {
+	return &ArticleStats{providers: make(map[string]*providerArticles)}
+}
+
+// provider returns the counts of a provider, creating them on first use
+func (s *ArticleStats) provider(id string) *providerArticles {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.providers[id]
+	if !ok {
+		p = &providerArticles{}
+		s.providers[id] = p
+	}
+	return p
+}
+
+// Get returns the counts of a provider by ID, all zero when it has served no lookups
+func (s *ArticleStats) Get(id string) ProviderArticleStats {
+	s.mu.Lock()
+	p, ok := s.providers[id]
+	s.mu.Unlock()
+	if !ok {
+		p = &providerArticles{}
+	}
+
+	return p.snapshot(time.Now())
+}
+
+// record counts a lookup, found reports whether the provider had the article
+func (p *providerArticles) record(found bool, now time.Time) {
+	slot := now.Unix() / int64(articleStatsBucket/time.Second)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	b := &p.buckets[slot%int64(articleStatsBuckets)]
+	if b.slot != slot {
+		*b = articleBucket{slot: slot}
+	}
+	if found {
+		p.found++
+		b.found++
+	} else {
+		p.missing++
+		b.missing++
+	}
+}
+
+func (p *providerArticles) snapshot(now time.Time) ProviderArticleStats {
+	slot := now.Unix() / int64(articleStatsBucket/time.Second)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var windowFound, windowMissing int64
+	for _, b := range p.buckets {
+		if b.slot > slot-int64(articleStatsBuckets) {
+			windowFound += b.found
+			windowMissing += b.missing
+		}
+	}
+
+	return ProviderArticleStats{
+		Total:       newArticleCounts(p.found, p.missing),
+		Window:      newArticleCounts(windowFound, windowMissing),
+		WindowHours: int(articleStatsWindow / time.Hour),
+	}
+}
+
+func newArticleCounts(found, missing int64) ArticleCounts {
+	counts := ArticleCounts{Found: found, Missing: missing}
+	if total := found + missing; total > 0 {
+		counts.MissRate = float64(missing) / float64(total)
+	}
+	return counts
+}
+
+// countingClient dials NNTP connections that report the outcome of every article lookup
+type countingClient struct {
+	nntpcli.Client
+	stats *ArticleStats
+}
+
+// newCountingClient wraps client, recording article lookups in stats
+func newCountingClient(client nntpcli.Client, stats *ArticleStats) *countingClient {
+	return &countingClient{Client: client, stats: stats}
+}
+
+// Dial connects to a provider without TLS
+func (c *countingClient) Dial(ctx context.Context, host string, port int, dialConfig ...nntpcli.DialConfig) (nntpcli.Connection, error) {
+	conn, err := c.Client.Dial(ctx, host, port, dialConfig...)
+	if err != nil {
+		return nil, err
+	}
+	return c.wrap(conn, host), nil
+}
+
+// DialTLS connects to a provider over TLS
+func (c *countingClient) DialTLS(ctx context.Context, host string, port int, insecureSSL bool, dialConfig ...nntpcli.DialConfig) (nntpcli.Connection, error) {
+	conn, err := c.Client.DialTLS(ctx, host, port, insecureSSL, dialConfig...)
+	if err != nil {
+		return nil, err
+	}
+	return c.wrap(conn, host), nil
+}
+
+func (c *countingClient) wrap(conn nntpcli.Connection, host string) nntpcli.Connection {
+	return &countingConnection{Connection: conn, stats: c.stats, host: host}
+}
+
+// countingConnection records whether the articles requested through it were found. The
+// pool authenticates right after dialing, which tells the connection which provider
+// account it belongs to.
+type countingConnection struct {
+	nntpcli.Connection
+	stats    *ArticleStats
+	host     string
+	articles *providerArticles // Set by Authenticate, or on the first lookup without one
+}
+
+// providerID returns the ID nntppool gives the provider with the given host and username
+func providerID(host, username string) string {
+	return host + "_" + username
+}
+
+// Authenticate logs in and attributes the following lookups to the username's provider
+func (c *countingConnection) Authenticate(username, password string) error {
+	if err := c.Connection.Authenticate(username, password); err != nil {
+		return err
+	}
+	c.articles = c.stats.provider(providerID(c.host, username))
+	return nil
+}
+
+// BodyDecoded downloads an article, counting whether it was found
+func (c *countingConnection) BodyDecoded(msgID string, w io.Writer, discard int64) (int64, error) {
+	n, err := c.Connection.BodyDecoded(msgID, w, discard)
+	c.record(err)
+	return n, err
+}
+
+// BodyReader opens an article, counting whether it was found
+func (c *countingConnection) BodyReader(msgID string) (nntpcli.ArticleBodyReader, error) {
+	reader, err := c.Connection.BodyReader(msgID)
+	c.record(err)
+	return reader, err
+}
+
+// Stat checks an article exists, counting whether it was found
+func (c *countingConnection) Stat(msgID string) (int, error) {
+	n, err := c.Connection.Stat(msgID)
+	c.record(err)
+	return n, err
+}
+
+// record counts a lookup that succeeded or failed with article not found, other errors
+// say nothing about the provider's retention
+func (c *countingConnection) record(err error) {
+	if c.articles == nil {
+		c.articles = c.stats.provider(providerID(c.host, ""))
+	}
+
+	switch {
+	case err == nil:
+		c.articles.record(true, time.Now())
+	case nntpcli.IsArticleNotFoundError(err):
+		c.articles.record(false, time.Now())
+	}
+}
@@ -3,6 +3,7 @@ package pool
 import (
 	"context"
 	"log/slog"
+	"time"
 
 	"github.com/javi11/altmount/internal/config"
 )
@@ -12,15 +13,19 @@ func RegisterConfigHandlers(ctx context.Context, configManager *config.Manager,
 	configManager.OnConfigChange(func(oldConfig, newConfig *config.Config) {
 		slog.InfoContext(ctx, "Configuration updated")
 
-		// Handle provider changes dynamically using comprehensive comparison
+		// Handle provider changes dynamically using comprehensive comparison. The retry
+		// policy is applied when the pool is created, so changing it recreates the pool too.
 		providersChanged := !oldConfig.ProvidersEqual(newConfig)
+		retryChanged := oldConfig.Pool != newConfig.Pool
 
-		if providersChanged {
+		if providersChanged || retryChanged {
 			slog.InfoContext(ctx, "NNTP providers changed - updating connection pool",
 				"old_count", len(oldConfig.Providers),
 				"new_count", len(newConfig.Providers))
 
 			// Update pool with new providers
+			poolManager.SetRetryPolicy(RetryPolicyFromConfig(newConfig))
+			poolManager.SetSpeedLimits(SpeedLimitsFromConfig(newConfig))
 			providers := newConfig.ToNNTPProviders()
 			if err := poolManager.SetProviders(providers); err != nil {
 				slog.ErrorContext(ctx, "Failed to update NNTP connection pool", "err", err)
@@ -41,3 +46,14 @@ func RegisterConfigHandlers(ctx context.Context, configManager *config.Manager,
 		}
 	})
 }
+
+// RetryPolicyFromConfig derives the pool retry policy from the pool settings
+func RetryPolicyFromConfig(cfg *config.Config) RetryPolicy {
+	var policy RetryPolicy
+	if cfg.Pool.RetryAttempts > 0 {
+		policy.MaxRetries = uint(cfg.Pool.RetryAttempts)
+	}
+	policy.RetryDelay = time.Duration(cfg.Pool.RetryBackoffSeconds) * time.Second
+
+	return policy
+}
@@ -0,0 +1,287 @@
+package pool
+
+import (
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/javi11/nntppool/v2"
+	"github.com/javi11/nntppool/v2/pkg/nntpcli"
+)
+
+// connLimiter caps the connections open to a provider address across every pool the manager
+// created, so a draining pool and the pool replacing it stay within the provider's limit together
+type connLimiter struct {
+	mu      sync.Mutex
+	max     int
+	open    map[*limitedConnection]struct{}
+	changed chan struct{} // Closed when a slot may have become available
+}
+
+func newConnLimiter(max int) *connLimiter {
+	return &connLimiter{
+		max:     max,
+		open:    make(map[*limitedConnection]struct{}),
+		changed: make(chan struct{}),
+	}
+}
+
+// setMax changes the number of connections allowed
+func (l *connLimiter) setMax(max int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.max = max
+	l.notifyLocked()
+}
+
+// notify wakes the dials waiting for a slot so they check again
+func (l *connLimiter) notify() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.notifyLocked()
+}
+
+func (l *connLimiter) notifyLocked() {
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
+
+// acquire takes a slot for c, closing an idle connection of a retired pool when none is free
+// and otherwise waiting until a connection is closed or ctx is done
+func (l *connLimiter) acquire(ctx context.Context, c *limitedConnection) error {
+	for {
+		l.mu.Lock()
+		if len(l.open) < l.max {
+			l.open[c] = struct{}{}
+			l.mu.Unlock()
+			return nil
+		}
+
+		idle := l.retiredIdleLocked()
+		changed := l.changed
+		l.mu.Unlock()
+
+		if idle != nil {
+			_ = idle.Close()
+			continue
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// retiredIdleLocked returns an open connection of a retired pool that is not serving a request
+func (l *connLimiter) retiredIdleLocked() *limitedConnection {
+	for c := range l.open {
+		if c.busy == 0 && c.group.retired.Load() {
+			return c
+		}
+	}
+	return nil
+}
+
+// release frees the slot of c
+func (l *connLimiter) release(c *limitedConnection) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.open, c)
+	l.notifyLocked()
+}
+
+// updateConnLimiters returns the connection limiters for providers, reusing the limiters in
+// current so connections that are still open keep counting against their address. Providers
+// sharing an address share the sum of their connection limits.
+func updateConnLimiters(current map[string]*connLimiter, providers []nntppool.UsenetProviderConfig) map[string]*connLimiter {
+	limits := make(map[string]int)
+	for _, p := range providers {
+		if p.MaxConnections > 0 {
+			limits[providerAddress(p.Host, p.Port)] += p.MaxConnections
+		}
+	}
+
+	limiters := make(map[string]*connLimiter, len(limits))
+	for addr, limit := range limits {
+		if limiter, ok := current[addr]; ok {
+			limiter.setMax(limit)
+			limiters[addr] = limiter
+		} else {
+			limiters[addr] = newConnLimiter(limit)
+		}
+	}
+	return limiters
+}
+
+// connGroup is the set of connections dialed for one pool
+type connGroup struct {
+	retired atomic.Bool // Set once the pool is being replaced, its idle connections can then be closed
+}
+
+// limitedClient dials NNTP connections within the connection limit of their provider address
+type limitedClient struct {
+	nntpcli.Client
+	limiters map[string]*connLimiter
+	group    *connGroup
+}
+
+// newLimitedClient wraps client, counting the connections it dials in group
+func newLimitedClient(client nntpcli.Client, limiters map[string]*connLimiter, group *connGroup) *limitedClient {
+	return &limitedClient{Client: client, limiters: limiters, group: group}
+}
+
+// Dial connects to a provider without TLS
+func (c *limitedClient) Dial(ctx context.Context, host string, port int, dialConfig ...nntpcli.DialConfig) (nntpcli.Connection, error) {
+	return c.dial(ctx, host, port, func() (nntpcli.Connection, error) {
+		return c.Client.Dial(ctx, host, port, dialConfig...)
+	})
+}
+
+// DialTLS connects to a provider over TLS
+func (c *limitedClient) DialTLS(ctx context.Context, host string, port int, insecureSSL bool, dialConfig ...nntpcli.DialConfig) (nntpcli.Connection, error) {
+	return c.dial(ctx, host, port, func() (nntpcli.Connection, error) {
+		return c.Client.DialTLS(ctx, host, port, insecureSSL, dialConfig...)
+	})
+}
+
+// dial waits for a free slot at the provider's address before dialing
+func (c *limitedClient) dial(ctx context.Context, host string, port int, dial func() (nntpcli.Connection, error)) (nntpcli.Connection, error) {
+	limiter, ok := c.limiters[providerAddress(host, port)]
+	if !ok {
+		return dial()
+	}
+
+	// Busy until dialed so the slot is not taken back as idle
+	conn := &limitedConnection{limiter: limiter, group: c.group, busy: 1}
+	if err := limiter.acquire(ctx, conn); err != nil {
+		return nil, err
+	}
+
+	nntpConn, err := dial()
+	if err != nil {
+		limiter.release(conn)
+		return nil, err
+	}
+
+	conn.Connection = nntpConn
+	conn.end()
+	return conn, nil
+}
+
+// limitedConnection holds a slot of its provider's connection limit until it is closed. It
+// tracks whether it is serving a request, so that a retired pool's idle connections can be
+// closed for a new pool. Closing one between two commands of a request makes the request
+// fail like a connection the provider dropped, which the pool retries on another connection.
+type limitedConnection struct {
+	nntpcli.Connection
+	limiter *connLimiter
+	group   *connGroup
+	busy    int // Commands and body readers in progress, guarded by limiter.mu
+	closed  sync.Once
+}
+
+func (c *limitedConnection) begin() {
+	c.limiter.mu.Lock()
+	c.busy++
+	c.limiter.mu.Unlock()
+}
+
+func (c *limitedConnection) end() {
+	c.limiter.mu.Lock()
+	defer c.limiter.mu.Unlock()
+
+	c.busy--
+	if c.busy == 0 && c.group.retired.Load() {
+		c.limiter.notifyLocked()
+	}
+}
+
+// Close closes the connection and frees its slot. The pool may close a connection the
+// limiter already closed, only the first call does anything.
+func (c *limitedConnection) Close() error {
+	var err error
+	c.closed.Do(func() {
+		err = c.Connection.Close()
+		c.limiter.release(c)
+	})
+	return err
+}
+
+// Authenticate logs in, closing the connection when that fails since the pool drops it
+// without closing it
+func (c *limitedConnection) Authenticate(username, password string) error {
+	c.begin()
+	err := c.Connection.Authenticate(username, password)
+	c.end()
+
+	if err != nil {
+		_ = c.Close()
+	}
+	return err
+}
+
+func (c *limitedConnection) JoinGroup(name string) error {
+	c.begin()
+	defer c.end()
+	return c.Connection.JoinGroup(name)
+}
+
+func (c *limitedConnection) BodyDecoded(msgID string, w io.Writer, discard int64) (int64, error) {
+	c.begin()
+	defer c.end()
+	return c.Connection.BodyDecoded(msgID, w, discard)
+}
+
+// BodyReader opens an article body, the connection stays busy until the reader is closed
+func (c *limitedConnection) BodyReader(msgID string) (nntpcli.ArticleBodyReader, error) {
+	c.begin()
+	reader, err := c.Connection.BodyReader(msgID)
+	if err != nil {
+		c.end()
+		return nil, err
+	}
+	return &limitedBodyReader{ArticleBodyReader: reader, conn: c}, nil
+}
+
+func (c *limitedConnection) Post(r io.Reader) (int64, error) {
+	c.begin()
+	defer c.end()
+	return c.Connection.Post(r)
+}
+
+func (c *limitedConnection) Ping() error {
+	c.begin()
+	defer c.end()
+	return c.Connection.Ping()
+}
+
+func (c *limitedConnection) Stat(msgID string) (int, error) {
+	c.begin()
+	defer c.end()
+	return c.Connection.Stat(msgID)
+}
+
+func (c *limitedConnection) Capabilities() ([]string, error) {
+	c.begin()
+	defer c.end()
+	return c.Connection.Capabilities()
+}
+
+// limitedBodyReader marks its connection idle again once closed
+type limitedBodyReader struct {
+	nntpcli.ArticleBodyReader
+	conn   *limitedConnection
+	closed sync.Once
+}
+
+func (r *limitedBodyReader) Close() error {
+	err := r.ArticleBodyReader.Close()
+	r.closed.Do(r.conn.end)
+	return err
+}
@@ -0,0 +1,158 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/javi11/nntppool/v2"
+	"github.com/javi11/nntppool/v2/pkg/nntpcli"
+)
+
+// newTestConnLimiters returns limiters allowing max connections to news.example.com:563
+func newTestConnLimiters(max int) map[string]*connLimiter {
+	return updateConnLimiters(nil, []nntppool.UsenetProviderConfig{
+		{Host: "news.example.com", Port: 563, MaxConnections: max},
+	})
+}
+
+// dialTest dials news.example.com:563 within timeout
+func dialTest(client *limitedClient, timeout time.Duration) (nntpcli.Connection, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return client.DialTLS(ctx, "news.example.com", 563, false)
+}
+
+// underlying returns the fake connection a limited connection wraps
+func underlying(t *testing.T, conn nntpcli.Connection) *fakeConnection {
+	t.Helper()
+
+	limited, ok := conn.(*limitedConnection)
+	if !ok {
+		t.Fatalf("connection is %T, want it limited", conn)
+	}
+	return limited.Connection.(*fakeConnection)
+}
+
+func TestUpdateConnLimiters(t *testing.T) {
+	current := newTestConnLimiters(2)
+	limiter := current["news.example.com:563"]
+
+	limiters := updateConnLimiters(current, []nntppool.UsenetProviderConfig{
+		{Host: "news.example.com", Port: 563, MaxConnections: 10},
+		{Host: "news.example.com", Port: 563, MaxConnections: 5},
+		{Host: "backup.example.com", Port: 119, MaxConnections: 3},
+	})
+
+	if limiters["news.example.com:563"] != limiter {
+		t.Error("limiter of an address still in use was replaced")
+	}
+	for addr, want := range map[string]int{"news.example.com:563": 15, "backup.example.com:119": 3} {
+		if got := limiters[addr]; got == nil || got.max != want {
+			t.Errorf("%s: got %+v, want a limit of %d", addr, got, want)
+		}
+	}
+}
+
+func TestLimitedClientCapsConnections(t *testing.T) {
+	client := newLimitedClient(fakeClient{}, newTestConnLimiters(2), &connGroup{})
+
+	first, err := dialTest(client, time.Second)
+	if err != nil {
+		t.Fatalf("DialTLS: %v", err)
+	}
+	if _, err := dialTest(client, time.Second); err != nil {
+		t.Fatalf("DialTLS: %v", err)
+	}
+	if _, err := dialTest(client, 20*time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("DialTLS over the limit: got %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	// Closing a connection, twice as the pool may, frees exactly one slot
+	_ = first.Close()
+	_ = first.Close()
+	if _, err := dialTest(client, time.Second); err != nil {
+		t.Fatalf("DialTLS after Close: %v", err)
+	}
+	if _, err := dialTest(client, 20*time.Millisecond); err == nil {
+		t.Fatal("DialTLS over the limit: expected an error")
+	}
+
+	// Other addresses are not limited
+	if _, err := client.Dial(context.Background(), "other.example.com", 119); err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+}
+
+func TestLimitedClientFailedLoginFreesSlot(t *testing.T) {
+	client := newLimitedClient(fakeClient{}, newTestConnLimiters(1), &connGroup{})
+
+	conn, err := dialTest(client, time.Second)
+	if err != nil {
+		t.Fatalf("DialTLS: %v", err)
+	}
+	if err := conn.Authenticate("user", "wrong"); err == nil {
+		t.Fatal("Authenticate: expected an error")
+	}
+	if !underlying(t, conn).closed.Load() {
+		t.Error("connection with a failed login was left open")
+	}
+
+	if _, err := dialTest(client, time.Second); err != nil {
+		t.Fatalf("DialTLS after a failed login: %v", err)
+	}
+}
+
+func TestLimitedClientTakesOverRetiredConnections(t *testing.T) {
+	limiters := newTestConnLimiters(2)
+	oldConns := &connGroup{}
+	oldClient := newLimitedClient(fakeClient{}, limiters, oldConns)
+	newClient := newLimitedClient(fakeClient{}, limiters, &connGroup{})
+
+	idle, err := dialTest(oldClient, time.Second)
+	if err != nil {
+		t.Fatalf("DialTLS: %v", err)
+	}
+	reading, err := dialTest(oldClient, time.Second)
+	if err != nil {
+		t.Fatalf("DialTLS: %v", err)
+	}
+	reader, err := reading.BodyReader("reading@example")
+	if err != nil {
+		t.Fatalf("BodyReader: %v", err)
+	}
+
+	// The old pool's connections are not taken while it is in use
+	if _, err := dialTest(newClient, 20*time.Millisecond); err == nil {
+		t.Fatal("DialTLS: expected to wait for the old pool")
+	}
+
+	oldConns.retired.Store(true)
+	if _, err := dialTest(newClient, time.Second); err != nil {
+		t.Fatalf("DialTLS: %v", err)
+	}
+	if !underlying(t, idle).closed.Load() {
+		t.Error("idle connection of the retired pool was not closed")
+	}
+	if underlying(t, reading).closed.Load() {
+		t.Error("connection reading a body was closed")
+	}
+
+	// The connection still reading is taken over once its body is closed
+	done := make(chan error, 1)
+	go func() {
+		_, err := dialTest(newClient, time.Second)
+		done <- err
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	_ = reader.Close()
+
+	if err := <-done; err != nil {
+		t.Fatalf("DialTLS after the body was closed: %v", err)
+	}
+	if !underlying(t, reading).closed.Load() {
+		t.Error("retired connection was not closed after its body was read")
+	}
+}
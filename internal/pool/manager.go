@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/javi11/nntppool/v2"
+	"github.com/javi11/nntppool/v2/pkg/nntpcli"
 )
 
 // Manager provides centralized NNTP connection pool management
@@ -15,9 +16,16 @@ type Manager interface {
 	// GetPool returns the current connection pool or error if not available
 	GetPool() (nntppool.UsenetConnectionPool, error)
 
-	// SetProviders creates/recreates the pool with new providers
+	// SetProviders creates/recreates the pool with new providers, draining the old pool
 	SetProviders(providers []nntppool.UsenetProviderConfig) error
 
+	// SetRetryPolicy sets the retry policy applied the next time the pool is created
+	SetRetryPolicy(policy RetryPolicy)
+
+	// SetSpeedLimits sets the per-provider download caps, shared by the current pool and the
+	// pool created next
+	SetSpeedLimits(limits SpeedLimits)
+
 	// ClearPool shuts down and removes the current pool
 	ClearPool() error
 
@@ -26,13 +34,35 @@ type Manager interface {
 
 	// GetMetrics returns the current pool metrics with calculated speeds
 	GetMetrics() (MetricsSnapshot, error)
+
+	// Stats returns the live connection usage of each provider
+	Stats() (PoolStats, error)
+}
+
+const (
+	// providerDrainTimeout bounds how long a replaced pool is kept open for in-flight reads
+	providerDrainTimeout = 30 * time.Second
+	// drainPollInterval is how often a draining pool is checked for in-flight reads
+	drainPollInterval = 500 * time.Millisecond
+)
+
+// RetryPolicy controls how the pool retries article operations after transient provider errors
+type RetryPolicy struct {
+	MaxRetries uint          // Maximum retries per operation (0 = nntppool default)
+	RetryDelay time.Duration // Base delay for exponential backoff (0 = fixed 10ms delay)
 }
 
 // manager implements the Manager interface
 type manager struct {
 	mu             sync.RWMutex
 	pool           nntppool.UsenetConnectionPool
+	providers      []nntppool.UsenetProviderConfig
 	metricsTracker *MetricsTracker
+	retryPolicy    RetryPolicy
+	speedLimiters  map[string]*rateLimiter // By provider address, shared by all pools
+	connLimiters   map[string]*connLimiter // By provider address, shared by all pools
+	conns          *connGroup              // Connections of the current pool
+	articleStats   *ArticleStats
 	ctx            context.Context
 	logger         *slog.Logger
 }
@@ -40,8 +70,9 @@ type manager struct {
 // NewManager creates a new pool manager
 func NewManager(ctx context.Context) Manager {
 	return &manager{
-		ctx:    ctx,
-		logger: slog.Default().With("component", "pool"),
+		articleStats: NewArticleStats(),
+		ctx:          ctx,
+		logger:       slog.Default().With("component", "pool"),
 	}
 }
 
@@ -57,42 +88,89 @@ func (m *manager) GetPool() (nntppool.UsenetConnectionPool, error) {
 	return m.pool, nil
 }
 
-// SetProviders creates/recreates the pool with new providers
+// SetProviders creates/recreates the pool with new providers. The new pool replaces the
+// current one immediately, while the old pool is drained in the background so reads that
+// are in flight can finish before its connections are closed. Both pools share the
+// providers' connection and speed limits, the new pool takes over the old pool's idle
+// connection slots.
 func (m *manager) SetProviders(providers []nntppool.UsenetProviderConfig) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	// Shut down existing pool and metrics tracker if present
-	if m.pool != nil {
-		m.logger.InfoContext(m.ctx, "Shutting down existing NNTP connection pool")
-		if m.metricsTracker != nil {
-			m.metricsTracker.Stop()
+	// Clear the pool if no providers are configured
+	if len(providers) == 0 {
+		if m.pool != nil {
+			m.logger.InfoContext(m.ctx, "Draining existing NNTP connection pool")
+			m.drainPool(m.pool, m.metricsTracker)
+			m.pool = nil
+			m.providers = nil
 			m.metricsTracker = nil
+			m.conns = nil
 		}
-		m.pool.Quit()
-		m.pool = nil
-	}
 
-	// Return early if no providers (clear pool scenario)
-	if len(providers) == 0 {
 		m.logger.InfoContext(m.ctx, "No NNTP providers configured - pool cleared")
 		return nil
 	}
 
+	// Back off exponentially only when a provider asked for it, otherwise keep the fast fixed delay
+	delayType := nntppool.DelayTypeFixed
+	retryDelay := 10 * time.Millisecond
+	if m.retryPolicy.RetryDelay > 0 {
+		delayType = nntppool.DelayTypeExponential
+		retryDelay = m.retryPolicy.RetryDelay
+	}
+
 	// Create new pool with providers
-	m.logger.InfoContext(m.ctx, "Creating NNTP connection pool", "provider_count", len(providers))
-	pool, err := nntppool.NewConnectionPool(nntppool.Config{
+	m.logger.InfoContext(m.ctx, "Creating NNTP connection pool",
+		"provider_count", len(providers),
+		"max_retries", m.retryPolicy.MaxRetries,
+		"retry_delay", retryDelay)
+	poolConfig := nntppool.Config{
 		Providers:      providers,
 		Logger:         m.logger,
-		DelayType:      nntppool.DelayTypeFixed,
-		RetryDelay:     10 * time.Millisecond,
+		DelayType:      delayType,
+		RetryDelay:     retryDelay,
+		MaxRetries:     m.retryPolicy.MaxRetries,
 		MinConnections: 0,
-	})
+	}
+
+	// Keep the connections of both pools within the providers' limits, letting the new pool
+	// close the old pool's idle connections when it needs their slots
+	oldProviders := m.providers
+	m.connLimiters = updateConnLimiters(m.connLimiters, providers)
+	if m.conns != nil {
+		m.setRetired(m.conns, true)
+	}
+	conns := &connGroup{}
+	var client nntpcli.Client = newLimitedClient(nntpcli.New(), m.connLimiters, conns)
+
+	// Throttle connections only when a provider has a speed cap
+	if len(m.speedLimiters) > 0 {
+		m.logger.InfoContext(m.ctx, "Applying provider speed limits", "limited_providers", len(m.speedLimiters))
+		client = newThrottledClient(m.ctx, client, m.speedLimiters)
+	}
+	// Count found and missing articles per provider
+	poolConfig.NntpCli = newCountingClient(client, m.articleStats)
+
+	pool, err := nntppool.NewConnectionPool(poolConfig)
 	if err != nil {
+		// Keep serving from the old pool within its own limits
+		m.connLimiters = updateConnLimiters(m.connLimiters, oldProviders)
+		if m.conns != nil {
+			m.setRetired(m.conns, false)
+		}
 		return fmt.Errorf("failed to create NNTP connection pool: %w", err)
 	}
 
+	// Stop handing out connections from the old pool and let it drain
+	if m.pool != nil {
+		m.logProviderChanges(m.providers, providers)
+		m.drainPool(m.pool, m.metricsTracker)
+	}
+
 	m.pool = pool
+	m.providers = providers
+	m.conns = conns
 
 	// Start metrics tracker
 	m.metricsTracker = NewMetricsTracker(pool)
@@ -102,6 +180,98 @@ func (m *manager) SetProviders(providers []nntppool.UsenetProviderConfig) error
 	return nil
 }
 
+// drainPool closes a replaced pool once its in-flight requests have finished, or after
+// providerDrainTimeout has passed
+func (m *manager) drainPool(pool nntppool.UsenetConnectionPool, metricsTracker *MetricsTracker) {
+	if metricsTracker != nil {
+		metricsTracker.Stop()
+	}
+
+	go func() {
+		defer pool.Quit()
+
+		ctx, cancel := context.WithTimeout(m.ctx, providerDrainTimeout)
+		defer cancel()
+
+		ticker := time.NewTicker(drainPollInterval)
+		defer ticker.Stop()
+
+		for {
+			active := activeConnections(pool)
+			if active == 0 {
+				m.logger.InfoContext(m.ctx, "Drained old NNTP connection pool")
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				m.logger.WarnContext(m.ctx, "Timed out draining old NNTP connection pool, closing remaining connections",
+					"active_connections", active,
+					"timeout", providerDrainTimeout)
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// setRetired marks the connections of a pool as retired or not, waking the dials waiting
+// for a slot so they can take over the idle ones
+func (m *manager) setRetired(conns *connGroup, retired bool) {
+	conns.retired.Store(retired)
+	for _, limiter := range m.connLimiters {
+		limiter.notify()
+	}
+}
+
+// activeConnections returns the number of connections currently serving a request in pool
+func activeConnections(pool nntppool.UsenetConnectionPool) int {
+	active := 0
+	for _, provider := range pool.GetMetricsSnapshot().ProviderMetrics {
+		active += provider.ActiveConnections
+	}
+	return active
+}
+
+// logProviderChanges logs the providers added and removed by a provider update
+func (m *manager) logProviderChanges(oldProviders, newProviders []nntppool.UsenetProviderConfig) {
+	oldIDs := make(map[string]bool, len(oldProviders))
+	for _, p := range oldProviders {
+		oldIDs[p.ID()] = true
+	}
+
+	newIDs := make(map[string]bool, len(newProviders))
+	for _, p := range newProviders {
+		newIDs[p.ID()] = true
+		if !oldIDs[p.ID()] {
+			m.logger.InfoContext(m.ctx, "Adding NNTP provider", "host", p.Host, "username", p.Username)
+		}
+	}
+
+	for _, p := range oldProviders {
+		if !newIDs[p.ID()] {
+			m.logger.InfoContext(m.ctx, "Draining removed NNTP provider", "host", p.Host, "username", p.Username)
+		}
+	}
+}
+
+// SetRetryPolicy sets the retry policy applied the next time the pool is created
+func (m *manager) SetRetryPolicy(policy RetryPolicy) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.retryPolicy = policy
+}
+
+// SetSpeedLimits sets the per-provider download caps, shared by the current pool and the
+// pool created next
+func (m *manager) SetSpeedLimits(limits SpeedLimits) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.speedLimiters = updateRateLimiters(m.speedLimiters, limits)
+}
+
 // ClearPool shuts down and removes the current pool
 func (m *manager) ClearPool() error {
 	m.mu.Lock()
@@ -115,6 +285,8 @@ func (m *manager) ClearPool() error {
 		}
 		m.pool.Quit()
 		m.pool = nil
+		m.providers = nil
+		m.conns = nil
 	}
 
 	return nil
@@ -0,0 +1,95 @@
+package pool
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/javi11/nntppool/v2"
+)
+
+// ProviderStats holds the live connection usage of a single provider
+type ProviderStats struct {
+	ID                string `json:"id"`
+	Host              string `json:"host"`
+	Username          string `json:"username"`
+	IsBackup          bool   `json:"is_backup"`
+	State             string `json:"state"`
+	ActiveConnections int    `json:"active_connections"` // Connections currently serving a request
+	IdleConnections   int    `json:"idle_connections"`   // Open connections waiting to be used
+	TotalConnections  int    `json:"total_connections"`  // Open connections, active or idle
+	MaxConnections    int    `json:"max_connections"`
+	Errors            int64  `json:"errors"`
+	FailureReason     string `json:"failure_reason,omitempty"`
+
+	// Articles counts the lookups that found or missed an article, telling how much of the
+	// requested content the provider still carries
+	Articles ProviderArticleStats `json:"articles"`
+}
+
+// PoolStats is a snapshot of the connection usage across all providers
+type PoolStats struct {
+	Providers         []ProviderStats `json:"providers"`
+	ActiveConnections int             `json:"active_connections"`
+	IdleConnections   int             `json:"idle_connections"`
+	TotalConnections  int             `json:"total_connections"`
+	MaxConnections    int             `json:"max_connections"`
+	TotalErrors       int64           `json:"total_errors"`
+	Timestamp         time.Time       `json:"timestamp"`
+}
+
+// Stats returns the current connection usage of every provider in the pool
+func (m *manager) Stats() (PoolStats, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.pool == nil {
+		return PoolStats{}, fmt.Errorf("NNTP connection pool not available")
+	}
+
+	backups := make(map[string]bool, len(m.providers))
+	for _, p := range m.providers {
+		backups[p.ID()] = p.IsBackupProvider
+	}
+
+	return buildPoolStats(m.pool.GetProvidersInfo(), m.pool.GetMetricsSnapshot(), backups, m.articleStats), nil
+}
+
+// buildPoolStats combines the provider info and metrics reported by the pool into PoolStats
+func buildPoolStats(providersInfo []nntppool.ProviderInfo, metrics nntppool.PoolMetricsSnapshot, backups map[string]bool, articleStats *ArticleStats) PoolStats {
+	stats := PoolStats{
+		Providers:   make([]ProviderStats, 0, len(providersInfo)),
+		TotalErrors: metrics.TotalErrors,
+		Timestamp:   metrics.Timestamp,
+	}
+
+	for _, info := range providersInfo {
+		providerMetrics := metrics.ProviderMetrics[info.Host]
+
+		// The pool reports open connections in UsedConnections and the ones checked out in ActiveConnections
+		total := info.UsedConnections
+		active := min(providerMetrics.ActiveConnections, total)
+
+		provider := ProviderStats{
+			ID:                info.ID(),
+			Host:              info.Host,
+			Username:          info.Username,
+			IsBackup:          backups[info.ID()],
+			State:             info.State.String(),
+			ActiveConnections: active,
+			IdleConnections:   total - active,
+			TotalConnections:  total,
+			MaxConnections:    info.MaxConnections,
+			Errors:            metrics.ProviderErrors[info.Host],
+			FailureReason:     info.FailureReason,
+		}
+		provider.Articles = articleStats.Get(provider.ID)
+
+		stats.Providers = append(stats.Providers, provider)
+		stats.ActiveConnections += provider.ActiveConnections
+		stats.IdleConnections += provider.IdleConnections
+		stats.TotalConnections += provider.TotalConnections
+		stats.MaxConnections += provider.MaxConnections
+	}
+
+	return stats
+}
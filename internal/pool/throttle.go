@@ -0,0 +1,227 @@
+package pool
+
+import (
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/javi11/altmount/internal/config"
+	"github.com/javi11/nntppool/v2/pkg/nntpcli"
+)
+
+// SpeedLimits maps a provider address (host:port) to its download cap in bytes per second
+type SpeedLimits map[string]int64
+
+// SpeedLimitsFromConfig returns the download caps of the enabled providers that have one.
+// Connections are dialed by address, so providers sharing a host and port share the lowest cap.
+func SpeedLimitsFromConfig(cfg *config.Config) SpeedLimits {
+	limits := make(SpeedLimits)
+	for _, p := range cfg.Providers {
+		if (p.Enabled != nil && !*p.Enabled) || p.SpeedLimitKBps <= 0 {
+			continue
+		}
+
+		addr := providerAddress(p.Host, p.Port)
+		limit := int64(p.SpeedLimitKBps) * 1024
+		if current, ok := limits[addr]; !ok || limit < current {
+			limits[addr] = limit
+		}
+	}
+
+	return limits
+}
+
+// providerAddress returns the address a provider is dialed at
+func providerAddress(host string, port int) string {
+	return net.JoinHostPort(host, strconv.Itoa(port))
+}
+
+// rateLimiter is a token bucket shared by all connections to a provider
+type rateLimiter struct {
+	mu          sync.Mutex
+	bytesPerSec float64
+	burst       float64
+	tokens      float64
+	last        time.Time
+}
+
+func newRateLimiter(bytesPerSec int64) *rateLimiter {
+	return &rateLimiter{
+		bytesPerSec: float64(bytesPerSec),
+		burst:       float64(bytesPerSec), // Allow up to one second of traffic at once
+		tokens:      float64(bytesPerSec),
+		last:        time.Now(),
+	}
+}
+
+// setRate changes the limit, keeping the bytes already taken
+func (l *rateLimiter) setRate(bytesPerSec int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.bytesPerSec)
+	l.last = now
+
+	l.bytesPerSec = float64(bytesPerSec)
+	l.burst = float64(bytesPerSec)
+	l.tokens = min(l.tokens, l.burst)
+}
+
+// updateRateLimiters returns the rate limiters for limits, reusing the limiters in current so
+// a draining pool and the pool replacing it share the provider's speed cap
+func updateRateLimiters(current map[string]*rateLimiter, limits SpeedLimits) map[string]*rateLimiter {
+	limiters := make(map[string]*rateLimiter, len(limits))
+	for addr, limit := range limits {
+		if limiter, ok := current[addr]; ok {
+			limiter.setRate(limit)
+			limiters[addr] = limiter
+		} else {
+			limiters[addr] = newRateLimiter(limit)
+		}
+	}
+	return limiters
+}
+
+// wait takes n bytes from the bucket, sleeping until the bucket has refilled enough to cover
+// them. Callers queue behind each other's debt, so the combined rate stays within the limit.
+// It returns early with the context's error when ctx is done, the bytes stay taken.
+func (l *rateLimiter) wait(ctx context.Context, n int) error {
+	if n <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.bytesPerSec)
+	l.last = now
+	l.tokens -= float64(n)
+
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.bytesPerSec * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// throttledClient dials NNTP connections whose article downloads are limited to the
+// speed cap of the provider they connect to
+type throttledClient struct {
+	nntpcli.Client
+	ctx      context.Context
+	limiters map[string]*rateLimiter
+}
+
+// newThrottledClient wraps client with the given rate limiters by provider address. Reads
+// waiting for the limit are abandoned once ctx is done or their connection is closed.
+func newThrottledClient(ctx context.Context, client nntpcli.Client, limiters map[string]*rateLimiter) *throttledClient {
+	return &throttledClient{
+		Client:   client,
+		ctx:      ctx,
+		limiters: limiters,
+	}
+}
+
+// Dial connects to a provider without TLS
+func (c *throttledClient) Dial(ctx context.Context, host string, port int, dialConfig ...nntpcli.DialConfig) (nntpcli.Connection, error) {
+	conn, err := c.Client.Dial(ctx, host, port, dialConfig...)
+	if err != nil {
+		return nil, err
+	}
+	return c.wrap(conn, host, port), nil
+}
+
+// DialTLS connects to a provider over TLS
+func (c *throttledClient) DialTLS(ctx context.Context, host string, port int, insecureSSL bool, dialConfig ...nntpcli.DialConfig) (nntpcli.Connection, error) {
+	conn, err := c.Client.DialTLS(ctx, host, port, insecureSSL, dialConfig...)
+	if err != nil {
+		return nil, err
+	}
+	return c.wrap(conn, host, port), nil
+}
+
+// wrap limits conn if its provider has a speed cap
+func (c *throttledClient) wrap(conn nntpcli.Connection, host string, port int) nntpcli.Connection {
+	limiter, ok := c.limiters[providerAddress(host, port)]
+	if !ok {
+		return conn
+	}
+
+	ctx, cancel := context.WithCancel(c.ctx)
+	return &throttledConnection{Connection: conn, limiter: limiter, ctx: ctx, cancel: cancel}
+}
+
+// throttledConnection limits the article bodies read through a connection
+type throttledConnection struct {
+	nntpcli.Connection
+	limiter *rateLimiter
+	ctx     context.Context // Done once the connection is closed
+	cancel  context.CancelFunc
+}
+
+// Close stops waiting for the limiter and closes the connection
+func (c *throttledConnection) Close() error {
+	c.cancel()
+	return c.Connection.Close()
+}
+
+// BodyDecoded writes the decoded article body to w at the provider's speed cap
+func (c *throttledConnection) BodyDecoded(msgID string, w io.Writer, discard int64) (int64, error) {
+	return c.Connection.BodyDecoded(msgID, &throttledWriter{ctx: c.ctx, w: w, limiter: c.limiter}, discard)
+}
+
+// BodyReader returns a reader for the article body that reads at the provider's speed cap
+func (c *throttledConnection) BodyReader(msgID string) (nntpcli.ArticleBodyReader, error) {
+	reader, err := c.Connection.BodyReader(msgID)
+	if err != nil {
+		return nil, err
+	}
+	return &throttledBodyReader{ArticleBodyReader: reader, ctx: c.ctx, limiter: c.limiter}, nil
+}
+
+// throttledWriter waits for the limiter after every write
+type throttledWriter struct {
+	ctx     context.Context
+	w       io.Writer
+	limiter *rateLimiter
+}
+
+func (tw *throttledWriter) Write(p []byte) (int, error) {
+	n, err := tw.w.Write(p)
+	if waitErr := tw.limiter.wait(tw.ctx, n); err == nil {
+		err = waitErr
+	}
+	return n, err
+}
+
+// throttledBodyReader waits for the limiter after every read
+type throttledBodyReader struct {
+	nntpcli.ArticleBodyReader
+	ctx     context.Context
+	limiter *rateLimiter
+}
+
+func (r *throttledBodyReader) Read(p []byte) (int, error) {
+	n, err := r.ArticleBodyReader.Read(p)
+	if waitErr := r.limiter.wait(r.ctx, n); err == nil {
+		err = waitErr
+	}
+	return n, err
+}
@@ -0,0 +1,239 @@
+package pool
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/javi11/altmount/internal/config"
+	"github.com/javi11/nntppool/v2/pkg/nntpcli"
+)
+
+func TestSpeedLimitsFromConfig(t *testing.T) {
+	enabled, disabled := true, false
+	cfg := &config.Config{Providers: []config.ProviderConfig{
+		{Host: "a.example.com", Port: 563, SpeedLimitKBps: 200},
+		{Host: "a.example.com", Port: 563, SpeedLimitKBps: 100, Enabled: &enabled},
+		{Host: "a.example.com", Port: 119, SpeedLimitKBps: 300},
+		{Host: "b.example.com", Port: 563},
+		{Host: "c.example.com", Port: 563, SpeedLimitKBps: 50, Enabled: &disabled},
+	}}
+
+	got := SpeedLimitsFromConfig(cfg)
+	want := SpeedLimits{
+		"a.example.com:563": 100 * 1024,
+		"a.example.com:119": 300 * 1024,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for addr, limit := range want {
+		if got[addr] != limit {
+			t.Errorf("%s: got %d, want %d", addr, got[addr], limit)
+		}
+	}
+}
+
+func TestUpdateRateLimiters(t *testing.T) {
+	current := updateRateLimiters(nil, SpeedLimits{"a.example.com:563": 1000, "b.example.com:563": 1000})
+	limiter := current["a.example.com:563"]
+
+	limiters := updateRateLimiters(current, SpeedLimits{"a.example.com:563": 5000})
+	if len(limiters) != 1 {
+		t.Fatalf("got %d limiters, want 1", len(limiters))
+	}
+	if limiters["a.example.com:563"] != limiter {
+		t.Error("limiter of an address still limited was replaced, the old pool would not share it")
+	}
+	if limiter.bytesPerSec != 5000 {
+		t.Errorf("rate: got %v, want 5000", limiter.bytesPerSec)
+	}
+}
+
+func TestRateLimiterWait(t *testing.T) {
+	l := newRateLimiter(10000)
+
+	start := time.Now()
+	if err := l.wait(context.Background(), 10000); err != nil {
+		t.Fatalf("wait: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 20*time.Millisecond {
+		t.Errorf("burst waited %v, want no wait", elapsed)
+	}
+
+	start = time.Now()
+	if err := l.wait(context.Background(), 500); err != nil {
+		t.Fatalf("wait: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("waited %v past the burst, want about 50ms", elapsed)
+	}
+}
+
+func TestRateLimiterWaitCancelled(t *testing.T) {
+	l := newRateLimiter(1000)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	// Ten seconds of traffic past the burst
+	err := l.wait(ctx, 11000)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("wait: got %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("wait returned after %v, want right after the context was done", elapsed)
+	}
+}
+
+// fakeClient dials fakeConnections
+type fakeClient struct {
+	nntpcli.Client
+}
+
+func (fakeClient) Dial(_ context.Context, _ string, _ int, _ ...nntpcli.DialConfig) (nntpcli.Connection, error) {
+	return &fakeConnection{}, nil
+}
+
+func (fakeClient) DialTLS(_ context.Context, _ string, _ int, _ bool, _ ...nntpcli.DialConfig) (nntpcli.Connection, error) {
+	return &fakeConnection{}, nil
+}
+
+// fakeConnection serves article bodies of bodySize zero bytes
+type fakeConnection struct {
+	nntpcli.Connection
+	closed atomic.Bool
+}
+
+const bodySize = 2000
+
+func (c *fakeConnection) Close() error {
+	c.closed.Store(true)
+	return nil
+}
+
+func (c *fakeConnection) Authenticate(_, password string) error {
+	if password != "secret" {
+		return errors.New("481 authentication failed")
+	}
+	return nil
+}
+
+func (c *fakeConnection) BodyDecoded(_ string, w io.Writer, _ int64) (int64, error) {
+	return io.Copy(w, bytes.NewReader(make([]byte, bodySize)))
+}
+
+func (c *fakeConnection) BodyReader(_ string) (nntpcli.ArticleBodyReader, error) {
+	return &fakeBodyReader{Reader: bytes.NewReader(make([]byte, bodySize))}, nil
+}
+
+type fakeBodyReader struct {
+	nntpcli.ArticleBodyReader
+	*bytes.Reader
+}
+
+func (r *fakeBodyReader) Read(p []byte) (int, error) {
+	return r.Reader.Read(p)
+}
+
+func (r *fakeBodyReader) Close() error {
+	return nil
+}
+
+func TestThrottledClientWrapsLimitedProviders(t *testing.T) {
+	client := newThrottledClient(context.Background(), fakeClient{}, updateRateLimiters(nil, SpeedLimits{"limited.example.com:563": 1000}))
+
+	limited, err := client.DialTLS(context.Background(), "limited.example.com", 563, false)
+	if err != nil {
+		t.Fatalf("DialTLS: %v", err)
+	}
+	if _, ok := limited.(*throttledConnection); !ok {
+		t.Errorf("connection to a limited provider is %T, want it throttled", limited)
+	}
+
+	for _, addr := range []struct {
+		host string
+		port int
+	}{{"limited.example.com", 119}, {"other.example.com", 563}} {
+		conn, err := client.Dial(context.Background(), addr.host, addr.port)
+		if err != nil {
+			t.Fatalf("Dial: %v", err)
+		}
+		if _, ok := conn.(*fakeConnection); !ok {
+			t.Errorf("connection to %s:%d is %T, want it unthrottled", addr.host, addr.port, conn)
+		}
+	}
+}
+
+func TestThrottledConnectionLimitsBodies(t *testing.T) {
+	// 20000 B/s: the first body fits the burst, the next ones take 100ms each
+	client := newThrottledClient(context.Background(), fakeClient{}, updateRateLimiters(nil, SpeedLimits{"news.example.com:563": 20000}))
+	conn, err := client.Dial(context.Background(), "news.example.com", 563)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	for range 10 {
+		if _, err := conn.BodyDecoded("burst@example", io.Discard, 0); err != nil {
+			t.Fatalf("BodyDecoded: %v", err)
+		}
+	}
+
+	start := time.Now()
+	n, err := conn.BodyDecoded("decoded@example", io.Discard, 0)
+	if err != nil || n != bodySize {
+		t.Fatalf("BodyDecoded: got %d, %v, want %d bytes", n, err, bodySize)
+	}
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("BodyDecoded took %v, want about 100ms", elapsed)
+	}
+
+	reader, err := conn.BodyReader("reader@example")
+	if err != nil {
+		t.Fatalf("BodyReader: %v", err)
+	}
+	start = time.Now()
+	data, err := io.ReadAll(reader)
+	if err != nil || len(data) != bodySize {
+		t.Fatalf("ReadAll: got %d bytes, %v, want %d bytes", len(data), err, bodySize)
+	}
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("reading the body took %v, want about 100ms", elapsed)
+	}
+}
+
+func TestThrottledConnectionCloseStopsWaiting(t *testing.T) {
+	// The first body fits the burst, the next one takes a second
+	client := newThrottledClient(context.Background(), fakeClient{}, updateRateLimiters(nil, SpeedLimits{"news.example.com:563": bodySize}))
+	conn, err := client.Dial(context.Background(), "news.example.com", 563)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	if _, err := conn.BodyDecoded("burst@example", io.Discard, 0); err != nil {
+		t.Fatalf("BodyDecoded: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := conn.BodyDecoded("waiting@example", io.Discard, 0)
+		done <- err
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	if err := conn.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("BodyDecoded: got %v, want %v", err, context.Canceled)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("BodyDecoded still waiting for the limit after Close")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"sort"
 	"sync"
 	"time"
 )
@@ -15,11 +16,23 @@ type ProgressUpdate struct {
 	Timestamp  time.Time `json:"timestamp"`
 }
 
+const (
+	// replayEventsPerJob is how many recent updates of each queue item are replayed to new subscribers
+	replayEventsPerJob = 16
+	// maxReplayJobs bounds the queue items with recent updates kept, the least recently
+	// updated item is dropped first
+	maxReplayJobs = 64
+)
+
 // ProgressBroadcaster manages progress tracking for queue items
 type ProgressBroadcaster struct {
 	// Map of queue item ID to current progress percentage
 	progress map[int]int
-	// Mutex for thread-safe access
+	// Recent updates per queue item, replayed to new subscribers so they can show the
+	// progress of imports that started before they connected
+	recent map[int]*updateRing
+	// Mutex for thread-safe access, held while broadcasting so subscribers get the
+	// replay and the live updates without gaps or duplicates
 	mu sync.RWMutex
 	// Logger
 	log *slog.Logger
@@ -32,6 +45,7 @@ type ProgressBroadcaster struct {
 func NewProgressBroadcaster() *ProgressBroadcaster {
 	pb := &ProgressBroadcaster{
 		progress:    make(map[int]int),
+		recent:      make(map[int]*updateRing),
 		subscribers: make(map[string]chan ProgressUpdate),
 		log:         slog.Default().With("component", "progress-broadcaster"),
 	}
@@ -60,22 +74,24 @@ func (pb *ProgressBroadcaster) UpdateProgress(queueID int, percentage int) {
 		percentage = 100
 	}
 
+	update := ProgressUpdate{
+		QueueID:    queueID,
+		Percentage: percentage,
+		Timestamp:  time.Now(),
+	}
+
 	pb.mu.Lock()
+	defer pb.mu.Unlock()
+
 	if percentage >= 100 {
 		// Remove progress when complete (100%)
 		delete(pb.progress, queueID)
 	} else {
 		pb.progress[queueID] = percentage
 	}
-	pb.mu.Unlock()
+	pb.recordLocked(update)
 
 	// Broadcast update to all SSE subscribers
-	update := ProgressUpdate{
-		QueueID:    queueID,
-		Percentage: percentage,
-		Timestamp:  time.Now(),
-	}
-
 	pb.subMu.RLock()
 	for subID, ch := range pb.subscribers {
 		select {
@@ -89,10 +105,33 @@ func (pb *ProgressBroadcaster) UpdateProgress(queueID int, percentage int) {
 	pb.subMu.RUnlock()
 }
 
+// recordLocked keeps update for replay, dropping the least recently updated queue item
+// when too many are tracked
+func (pb *ProgressBroadcaster) recordLocked(update ProgressUpdate) {
+	ring, ok := pb.recent[update.QueueID]
+	if !ok {
+		if len(pb.recent) >= maxReplayJobs {
+			oldestID, oldest := 0, time.Time{}
+			for id, r := range pb.recent {
+				if last := r.last().Timestamp; oldest.IsZero() || last.Before(oldest) {
+					oldestID, oldest = id, last
+				}
+			}
+			delete(pb.recent, oldestID)
+		}
+
+		ring = &updateRing{}
+		pb.recent[update.QueueID] = ring
+	}
+
+	ring.add(update)
+}
+
 // ClearProgress removes progress tracking for a completed or failed queue item
 func (pb *ProgressBroadcaster) ClearProgress(queueID int) {
 	pb.mu.Lock()
 	delete(pb.progress, queueID)
+	delete(pb.recent, queueID)
 	pb.mu.Unlock()
 }
 
@@ -123,6 +162,26 @@ func (pb *ProgressBroadcaster) CreateTracker(queueID, minPercent, maxPercent int
 
 // Subscribe creates a new SSE subscriber and returns a subscription ID and update channel
 func (pb *ProgressBroadcaster) Subscribe() (string, <-chan ProgressUpdate) {
+	subID, ch, _ := pb.SubscribeWithReplay()
+	return subID, ch
+}
+
+// SubscribeWithReplay creates a new SSE subscriber like Subscribe, and also returns the
+// recent updates of the queue items being imported, oldest first. The channel delivers
+// every update after them, so a client that joins while an import is running can rebuild
+// its progress right away.
+func (pb *ProgressBroadcaster) SubscribeWithReplay() (string, <-chan ProgressUpdate, []ProgressUpdate) {
+	pb.mu.RLock()
+	defer pb.mu.RUnlock()
+
+	var replay []ProgressUpdate
+	for _, ring := range pb.recent {
+		replay = ring.appendTo(replay)
+	}
+	sort.SliceStable(replay, func(i, j int) bool {
+		return replay[i].Timestamp.Before(replay[j].Timestamp)
+	})
+
 	pb.subMu.Lock()
 	defer pb.subMu.Unlock()
 
@@ -133,7 +192,7 @@ func (pb *ProgressBroadcaster) Subscribe() (string, <-chan ProgressUpdate) {
 	ch := make(chan ProgressUpdate, 10)
 	pb.subscribers[subID] = ch
 
-	return subID, ch
+	return subID, ch, replay
 }
 
 // Unsubscribe removes an SSE subscriber and closes its channel
@@ -146,3 +205,32 @@ func (pb *ProgressBroadcaster) Unsubscribe(subID string) {
 		delete(pb.subscribers, subID)
 	}
 }
+
+// updateRing holds the last replayEventsPerJob updates of a queue item
+type updateRing struct {
+	updates [replayEventsPerJob]ProgressUpdate
+	next    int // Index the next update is written to
+	count   int
+}
+
+func (r *updateRing) add(update ProgressUpdate) {
+	r.updates[r.next] = update
+	r.next = (r.next + 1) % len(r.updates)
+	if r.count < len(r.updates) {
+		r.count++
+	}
+}
+
+// last returns the most recent update
+func (r *updateRing) last() ProgressUpdate {
+	return r.updates[(r.next-1+len(r.updates))%len(r.updates)]
+}
+
+// appendTo appends the updates to dst, oldest first
+func (r *updateRing) appendTo(dst []ProgressUpdate) []ProgressUpdate {
+	start := (r.next - r.count + len(r.updates)) % len(r.updates)
+	for i := range r.count {
+		dst = append(dst, r.updates[(start+i)%len(r.updates)])
+	}
+	return dst
+}
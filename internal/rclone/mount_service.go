@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
 	"sync"
 	"time"
 
@@ -11,24 +14,103 @@ import (
 	"github.com/javi11/altmount/pkg/rclonecli"
 )
 
+const (
+	// mountCheckInterval is how often the mount is verified to still be alive
+	mountCheckInterval = 30 * time.Second
+	// mountCheckTimeout bounds how long accessing the mount point may take, as a dead FUSE mount can hang
+	mountCheckTimeout = 10 * time.Second
+	// maxRemountBackoff caps the wait between failed remount attempts
+	maxRemountBackoff = 5 * time.Minute
+)
+
+// rcManager is the part of the rclone RC manager used by the mount service
+type rcManager interface {
+	Start(ctx context.Context) error
+	Stop() error
+	Ping() error
+	IsReady() bool
+	WaitForReady(timeout time.Duration) error
+	ListMountPoints(ctx context.Context) ([]string, error)
+	GetVFSStats(ctx context.Context, provider string) (*rclonecli.VFSStats, error)
+}
+
+// mounter is a mount created through the RC server
+type mounter interface {
+	Mount(ctx context.Context) error
+	Unmount(ctx context.Context) error
+	IsMounted() bool
+	RefreshDir(ctx context.Context, dirs []string) error
+	GetMountInfo() (*rclonecli.MountInfo, bool)
+}
+
 // MountService handles rclone mount operations using RC server
 type MountService struct {
-	cfm     *config.Manager
-	mu      sync.RWMutex
-	manager *rclonecli.Manager
-	mount   *rclonecli.Mount
+	cfm           *config.Manager
+	mu            sync.RWMutex
+	client        *rclonecli.Manager
+	manager       rcManager
+	newMount      func(mountPath, webdavURL string) mounter
+	mount         mounter
+	mountPath     string // Where mount is mounted
+	wanted        bool   // Set while the mount should be up, the monitor mounts it again when it is not
+	remountCount  int
+	lastRemountAt time.Time
+
+	monitorOnce   sync.Once
+	checkInterval time.Duration
+	checkTimeout  time.Duration
+
+	// At most one stat of the mount point runs at a time, see statMountPoint
+	stat        func(path string) error
+	statMu      sync.Mutex
+	pendingStat *pendingStat
+
+	// Result of the last health check, served to readiness probes
+	healthMu   sync.Mutex
+	lastHealth *MountHealth
+}
+
+// pendingStat is a stat of the mount point that may still be running
+type pendingStat struct {
+	done chan struct{}
+	err  error
+}
+
+// MountStatus is the mount information together with the automatic remounts performed
+type MountStatus struct {
+	rclonecli.MountInfo
+	RemountCount  int        `json:"remount_count"`
+	LastRemountAt *time.Time `json:"last_remount_at,omitempty"`
 }
 
 // NewMountService creates a new mount service
 func NewMountService(cfm *config.Manager) *MountService {
+	manager := rclonecli.NewManager(cfm)
+
 	return &MountService{
 		cfm:     cfm,
-		manager: rclonecli.NewManager(cfm),
+		client:  manager,
+		manager: manager,
+		newMount: func(mountPath, webdavURL string) mounter {
+			return rclonecli.NewMount(config.MountProvider, mountPath, webdavURL, manager)
+		},
+		checkInterval: mountCheckInterval,
+		checkTimeout:  mountCheckTimeout,
+		stat: func(path string) error {
+			_, err := os.Stat(path)
+			return err
+		},
 	}
 }
 
-// Start starts the mount if enabled in configuration
+// Start starts the mount if enabled in configuration, and the monitor keeping it up. The
+// monitor also watches mounts started later through the API, and retries a first mount
+// that failed.
 func (s *MountService) Start(ctx context.Context) error {
+	s.monitorOnce.Do(func() {
+		go s.monitorMount(ctx)
+	})
+
 	cfg := s.cfm.GetConfig()
 
 	// Only start if mount is enabled
@@ -37,6 +119,11 @@ func (s *MountService) Start(ctx context.Context) error {
 		return nil
 	}
 
+	// From here on the monitor keeps the mount up, even when starting it fails now
+	s.mu.Lock()
+	s.wanted = true
+	s.mu.Unlock()
+
 	// Start RC server
 	if err := s.manager.Start(ctx); err != nil {
 		return fmt.Errorf("failed to start rclone RC server: %w", err)
@@ -46,6 +133,174 @@ func (s *MountService) Start(ctx context.Context) error {
 	return s.Mount(ctx)
 }
 
+// monitorMount periodically verifies the mount is alive and mounts it again when it
+// dropped or never came up, backing off between failed attempts
+func (s *MountService) monitorMount(ctx context.Context) {
+	ticker := time.NewTicker(s.checkInterval)
+	defer ticker.Stop()
+
+	var backoff time.Duration
+	var nextAttempt time.Time
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		s.checkHealth(ctx)
+
+		// Nothing to watch while the mount is stopped on purpose
+		s.mu.RLock()
+		wanted, mounted, mountPath := s.wanted, s.mount != nil, s.mountPath
+		s.mu.RUnlock()
+		if !wanted {
+			backoff = 0
+			continue
+		}
+
+		err := fmt.Errorf("not mounted")
+		if mounted {
+			err = s.checkMount(ctx, mountPath)
+		} else {
+			mountPath = s.cfm.GetConfig().MountPath
+		}
+		if err == nil {
+			backoff = 0
+			continue
+		}
+
+		if time.Now().Before(nextAttempt) {
+			continue
+		}
+
+		slog.WarnContext(ctx, "RClone mount is not available, remounting",
+			"mount_point", mountPath,
+			"error", err)
+
+		if err := s.remount(ctx); err != nil {
+			backoff = min(max(2*backoff, s.checkInterval), maxRemountBackoff)
+			nextAttempt = time.Now().Add(backoff)
+
+			slog.ErrorContext(ctx, "Failed to remount RClone mount",
+				"mount_point", mountPath,
+				"error", err,
+				"retry_in", backoff)
+			continue
+		}
+
+		backoff = 0
+		s.checkHealth(ctx)
+	}
+}
+
+// checkMount returns an error when mountPath is no longer mounted by rclone or cannot be accessed
+func (s *MountService) checkMount(ctx context.Context, mountPath string) error {
+	mountPoints, err := s.manager.ListMountPoints(ctx)
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(mountPoints, func(mp string) bool {
+		return filepath.Clean(mp) == filepath.Clean(mountPath)
+	}) {
+		return fmt.Errorf("%s is not mounted", mountPath)
+	}
+
+	return s.statMountPoint(mountPath)
+}
+
+// statMountPoint checks that mountPath can be accessed within the check timeout. A stat
+// of a dead FUSE mount can hang forever, so while one is still running no other is
+// started: later checks wait for the same one instead of leaking a goroutine each.
+func (s *MountService) statMountPoint(mountPath string) error {
+	s.statMu.Lock()
+	pending := s.pendingStat
+	if pending == nil {
+		pending = &pendingStat{done: make(chan struct{})}
+		s.pendingStat = pending
+
+		go func() {
+			pending.err = s.stat(mountPath)
+			close(pending.done)
+
+			s.statMu.Lock()
+			if s.pendingStat == pending {
+				s.pendingStat = nil
+			}
+			s.statMu.Unlock()
+		}()
+	}
+	s.statMu.Unlock()
+
+	select {
+	case <-pending.done:
+		if pending.err != nil {
+			return fmt.Errorf("mount point is not accessible: %w", pending.err)
+		}
+		return nil
+	case <-time.After(s.checkTimeout):
+		return fmt.Errorf("timed out accessing mount point %s", mountPath)
+	}
+}
+
+// remount recreates a mount that dropped unexpectedly or failed to start
+func (s *MountService) remount(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// The mount may have been stopped while it was being checked
+	if !s.wanted {
+		return nil
+	}
+
+	// The RC server is not running yet when the mount was enabled after startup
+	if err := s.manager.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start rclone RC server: %w", err)
+	}
+
+	if s.mount != nil {
+		if err := s.mount.Unmount(ctx); err != nil {
+			slog.WarnContext(ctx, "Failed to clean up dropped mount", "error", err)
+		}
+		s.mount = nil
+	}
+
+	if err := s.mountLocked(ctx); err != nil {
+		return err
+	}
+
+	s.remountCount++
+	s.lastRemountAt = time.Now()
+
+	slog.InfoContext(ctx, "RClone mount recovered",
+		"mount_point", s.mountPath,
+		"remount_count", s.remountCount)
+
+	return nil
+}
+
+// mountLocked mounts at the configured mount path, creating the mount when there is none.
+// A previous mount must have been cleared when mount_path changed. s.mu must be held.
+func (s *MountService) mountLocked(ctx context.Context) error {
+	cfg := s.cfm.GetConfig()
+	if cfg.MountPath == "" {
+		return fmt.Errorf("mount point not configured")
+	}
+
+	if s.mount == nil {
+		webdavURL := fmt.Sprintf("http://localhost:%d%s", cfg.WebDAV.Port, cfg.WebDAV.Prefix)
+		s.mount = s.newMount(cfg.MountPath, webdavURL)
+		s.mountPath = cfg.MountPath
+	}
+
+	if err := s.mount.Mount(ctx); err != nil {
+		return fmt.Errorf("failed to mount: %w", err)
+	}
+
+	return nil
+}
+
 // Mount creates the rclone mount
 func (s *MountService) Mount(ctx context.Context) error {
 	s.mu.Lock()
@@ -54,26 +309,27 @@ func (s *MountService) Mount(ctx context.Context) error {
 	cfg := s.cfm.GetConfig()
 
 	if s.mount != nil && s.mount.IsMounted() {
-		return fmt.Errorf("already mounted at %s", cfg.MountPath)
+		return fmt.Errorf("already mounted at %s", s.mountPath)
 	}
 	if cfg.MountPath == "" {
 		return fmt.Errorf("mount point not configured")
 	}
 
-	// Create WebDAV URL
-	webdavURL := fmt.Sprintf("http://localhost:%d/webdav", cfg.WebDAV.Port)
+	// Keep the mount up from now on, the monitor retries when this attempt fails
+	s.wanted = true
 
-	// Create mount instance
+	// Clean up a previous mount that dropped. It is created again, as mount_path may have
+	// changed since.
 	if s.mount != nil {
 		s.mount.Unmount(ctx)
-	} else {
-		s.mount = rclonecli.NewMount(config.MountProvider, cfg.MountPath, webdavURL, s.manager)
+		s.mount = nil
 	}
 
-	if err := s.mount.Mount(ctx); err != nil {
-		return fmt.Errorf("failed to mount: %w", err)
+	if err := s.mountLocked(ctx); err != nil {
+		return err
 	}
 
+	s.resetHealth()
 	slog.InfoContext(ctx, "RClone mount started", "mount_point", cfg.MountPath)
 
 	return nil
@@ -84,6 +340,9 @@ func (s *MountService) Unmount(ctx context.Context) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	// Stopped on purpose, the monitor must not bring it back
+	s.wanted = false
+
 	if s.mount == nil || !s.mount.IsMounted() {
 		return nil
 	}
@@ -96,30 +355,178 @@ func (s *MountService) Unmount(ctx context.Context) error {
 	}
 
 	s.mount = nil
+	s.mountPath = ""
+	s.resetHealth()
 
 	slog.InfoContext(ctx, "RClone mount stopped")
 	return nil
 }
 
+// RegisterConfigHandlers moves an active mount when mount_path changes, so the mount stays
+// where symlinks, STRM files and the ARRs expect it
+func (s *MountService) RegisterConfigHandlers(ctx context.Context) {
+	s.cfm.OnConfigChange(func(oldConfig, newConfig *config.Config) {
+		if oldConfig.MountPath == newConfig.MountPath {
+			return
+		}
+
+		// Remounting can take a while, don't hold up the config update
+		go func() {
+			if err := s.moveMount(ctx); err != nil {
+				slog.ErrorContext(ctx, "Failed to move RClone mount to the new mount path",
+					"old_mount_point", oldConfig.MountPath,
+					"new_mount_point", newConfig.MountPath,
+					"error", err)
+			}
+		}()
+	})
+}
+
+// moveMount recreates an active mount at the configured mount path. A mount that is not
+// active is left alone, it uses the new path the next time it is started.
+func (s *MountService) moveMount(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.mount == nil {
+		return nil
+	}
+
+	cfg := s.cfm.GetConfig()
+	if filepath.Clean(s.mountPath) == filepath.Clean(cfg.MountPath) {
+		return nil
+	}
+
+	if err := s.mount.Unmount(ctx); err != nil {
+		slog.WarnContext(ctx, "Failed to unmount previous mount point", "mount_point", s.mountPath, "error", err)
+	}
+	s.mount = nil
+	s.mountPath = ""
+	s.resetHealth()
+
+	if cfg.MountPath == "" {
+		s.wanted = false
+		slog.InfoContext(ctx, "RClone mount stopped, mount path was cleared")
+		return nil
+	}
+
+	if err := s.mountLocked(ctx); err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "RClone mount moved", "mount_point", cfg.MountPath)
+
+	return nil
+}
+
+// MountHealth reports whether the mount is functional
+type MountHealth struct {
+	Healthy     bool                `json:"healthy"`
+	Enabled     bool                `json:"enabled"`
+	Mounted     bool                `json:"mounted"`
+	RCReachable bool                `json:"rc_reachable"`
+	MountPoint  string              `json:"mount_point,omitempty"`
+	VFS         *rclonecli.VFSStats `json:"vfs,omitempty"`
+	Error       string              `json:"error,omitempty"`
+	CheckedAt   time.Time           `json:"checked_at"`
+}
+
+// Health returns the result of the last check made by the mount monitor, so frequent
+// readiness probes never touch the mount point themselves. Until the monitor has run, and
+// after the mount was started or stopped, the mount is checked on the spot. A disabled
+// mount is reported healthy so it never fails readiness checks.
+func (s *MountService) Health(ctx context.Context) MountHealth {
+	cfg := s.cfm.GetConfig()
+	if cfg.RClone.MountEnabled == nil || !*cfg.RClone.MountEnabled {
+		return MountHealth{Healthy: true, MountPoint: cfg.MountPath, CheckedAt: time.Now()}
+	}
+
+	s.healthMu.Lock()
+	last := s.lastHealth
+	s.healthMu.Unlock()
+	if last != nil {
+		return *last
+	}
+
+	return s.checkHealth(ctx)
+}
+
+// resetHealth drops the cached health, which no longer holds after the mount changed
+func (s *MountService) resetHealth() {
+	s.healthMu.Lock()
+	s.lastHealth = nil
+	s.healthMu.Unlock()
+}
+
+// checkHealth checks that the RC server responds and the mount point is mounted and
+// accessible, and caches the result for Health
+func (s *MountService) checkHealth(ctx context.Context) MountHealth {
+	health := s.probeHealth(ctx)
+
+	s.healthMu.Lock()
+	s.lastHealth = &health
+	s.healthMu.Unlock()
+
+	return health
+}
+
+func (s *MountService) probeHealth(ctx context.Context) MountHealth {
+	cfg := s.cfm.GetConfig()
+
+	health := MountHealth{
+		Enabled:    cfg.RClone.MountEnabled != nil && *cfg.RClone.MountEnabled,
+		MountPoint: cfg.MountPath,
+		CheckedAt:  time.Now(),
+	}
+	if !health.Enabled {
+		health.Healthy = true
+		return health
+	}
+
+	if err := s.manager.Ping(); err != nil {
+		health.Error = err.Error()
+		return health
+	}
+	health.RCReachable = true
+
+	if err := s.checkMount(ctx, cfg.MountPath); err != nil {
+		health.Error = err.Error()
+		return health
+	}
+	health.Mounted = true
+	health.Healthy = true
+
+	// Cache usage is informational, a failure to read it does not make the mount unhealthy
+	if vfs, err := s.manager.GetVFSStats(ctx, config.MountProvider); err == nil {
+		health.VFS = vfs
+	}
+
+	return health
+}
+
 // GetStatus returns the current mount status
-func (s *MountService) GetStatus() rclonecli.MountInfo {
+func (s *MountService) GetStatus() MountStatus {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
+	status := MountStatus{
+		RemountCount: s.remountCount,
+	}
+	if !s.lastRemountAt.IsZero() {
+		lastRemountAt := s.lastRemountAt
+		status.LastRemountAt = &lastRemountAt
+	}
+
 	if s.mount == nil {
-		return rclonecli.MountInfo{
-			Mounted: false,
-		}
+		return status
 	}
 
-	status, _ := s.mount.GetMountInfo()
-	if status == nil {
-		return rclonecli.MountInfo{
-			Mounted: false,
-		}
+	info, _ := s.mount.GetMountInfo()
+	if info != nil {
+		status.MountInfo = *info
 	}
 
-	return *status
+	return status
 }
 
 // Stop gracefully stops the mount service
@@ -177,5 +584,5 @@ func (s *MountService) StartRCServer(ctx context.Context) error {
 
 // GetManager returns the underlying rclone manager for RC operations
 func (s *MountService) GetManager() *rclonecli.Manager {
-	return s.manager
+	return s.client
 }
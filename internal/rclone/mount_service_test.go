@@ -0,0 +1,300 @@
+package rclone
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/javi11/altmount/internal/config"
+	"github.com/javi11/altmount/pkg/rclonecli"
+)
+
+// fakeRC stands in for the rclone RC server, tracking the mounted paths
+type fakeRC struct {
+	mu         sync.Mutex
+	mounted    map[string]bool
+	failMounts int // Mount attempts left to fail
+}
+
+func (rc *fakeRC) Start(context.Context) error      { return nil }
+func (rc *fakeRC) Stop() error                      { return nil }
+func (rc *fakeRC) Ping() error                      { return nil }
+func (rc *fakeRC) IsReady() bool                    { return true }
+func (rc *fakeRC) WaitForReady(time.Duration) error { return nil }
+func (rc *fakeRC) GetVFSStats(context.Context, string) (*rclonecli.VFSStats, error) {
+	return nil, errors.New("not supported")
+}
+
+func (rc *fakeRC) ListMountPoints(context.Context) ([]string, error) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return slices.Collect(maps.Keys(rc.mounted)), nil
+}
+
+func (rc *fakeRC) isMounted(path string) bool {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.mounted[path]
+}
+
+// drop simulates a mount that died
+func (rc *fakeRC) drop(path string) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	delete(rc.mounted, path)
+}
+
+type fakeMount struct {
+	rc   *fakeRC
+	path string
+}
+
+func (m *fakeMount) Mount(context.Context) error {
+	m.rc.mu.Lock()
+	defer m.rc.mu.Unlock()
+	if m.rc.failMounts > 0 {
+		m.rc.failMounts--
+		return errors.New("mount failed")
+	}
+	m.rc.mounted[m.path] = true
+	return nil
+}
+
+func (m *fakeMount) Unmount(context.Context) error {
+	m.rc.drop(m.path)
+	return nil
+}
+
+func (m *fakeMount) IsMounted() bool                            { return m.rc.isMounted(m.path) }
+func (m *fakeMount) RefreshDir(context.Context, []string) error { return nil }
+func (m *fakeMount) GetMountInfo() (*rclonecli.MountInfo, bool) { return nil, false }
+
+// newTestMountService returns a mount service backed by rc, checking the mount every few
+// milliseconds
+func newTestMountService(t *testing.T, rc *fakeRC, mountEnabled bool) (*MountService, *config.Manager) {
+	t.Helper()
+
+	cfg := config.DefaultConfig(t.TempDir())
+	cfg.MountPath = "/mnt/altmount"
+	cfg.RClone.MountEnabled = &mountEnabled
+	cfm := config.NewManager(cfg, "")
+
+	return &MountService{
+		cfm:     cfm,
+		manager: rc,
+		newMount: func(mountPath, _ string) mounter {
+			return &fakeMount{rc: rc, path: mountPath}
+		},
+		checkInterval: 5 * time.Millisecond,
+		checkTimeout:  50 * time.Millisecond,
+		stat:          func(string) error { return nil },
+	}, cfm
+}
+
+// waitFor fails the test when cond does not become true within a second
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting until %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestStartRetriesFailedMount(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	rc := &fakeRC{mounted: map[string]bool{}, failMounts: 1}
+	s, _ := newTestMountService(t, rc, true)
+
+	if err := s.Start(ctx); err == nil {
+		t.Fatal("Start: expected the first mount to fail")
+	}
+
+	waitFor(t, "the mount is retried", func() bool { return rc.isMounted("/mnt/altmount") })
+}
+
+func TestMonitorWatchesMountStartedThroughAPI(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	rc := &fakeRC{mounted: map[string]bool{}}
+	s, _ := newTestMountService(t, rc, false)
+
+	// Mount disabled at boot, started later through the API
+	if err := s.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if err := s.Mount(ctx); err != nil {
+		t.Fatalf("Mount: %v", err)
+	}
+
+	rc.drop("/mnt/altmount")
+	waitFor(t, "the dropped mount is remounted", func() bool { return rc.isMounted("/mnt/altmount") })
+
+	if got := s.GetStatus().RemountCount; got != 1 {
+		t.Errorf("RemountCount: got %d, want 1", got)
+	}
+}
+
+func TestUnmountStopsRemounting(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	rc := &fakeRC{mounted: map[string]bool{}}
+	s, _ := newTestMountService(t, rc, true)
+
+	if err := s.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if err := s.Unmount(ctx); err != nil {
+		t.Fatalf("Unmount: %v", err)
+	}
+
+	time.Sleep(20 * s.checkInterval)
+	if rc.isMounted("/mnt/altmount") {
+		t.Error("mount stopped on purpose was mounted again")
+	}
+}
+
+func TestCheckMountBoundsHangingStat(t *testing.T) {
+	rc := &fakeRC{mounted: map[string]bool{"/mnt/altmount": true}}
+	s, _ := newTestMountService(t, rc, true)
+	s.checkTimeout = 5 * time.Millisecond
+
+	release := make(chan struct{})
+	var stats atomic.Int32
+	s.stat = func(string) error {
+		stats.Add(1)
+		<-release
+		return nil
+	}
+
+	for range 3 {
+		if err := s.checkMount(context.Background(), "/mnt/altmount"); err == nil {
+			t.Fatal("checkMount: expected a timeout while the mount point hangs")
+		}
+	}
+	if got := stats.Load(); got != 1 {
+		t.Errorf("started %d stats of a hanging mount point, want 1", got)
+	}
+
+	close(release)
+	waitFor(t, "the mount point is accessible again", func() bool {
+		return s.checkMount(context.Background(), "/mnt/altmount") == nil
+	})
+}
+
+func TestHealthServesLastCheck(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	rc := &fakeRC{mounted: map[string]bool{}}
+	s, _ := newTestMountService(t, rc, true)
+	s.checkInterval = time.Hour // Only the checks made by the test
+
+	var stats atomic.Int32
+	s.stat = func(string) error {
+		stats.Add(1)
+		return nil
+	}
+
+	if err := s.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	for range 5 {
+		if health := s.Health(ctx); !health.Healthy {
+			t.Fatalf("Health: got unhealthy (%s), want healthy", health.Error)
+		}
+	}
+	if got := stats.Load(); got != 1 {
+		t.Errorf("Health accessed the mount point %d times, want 1", got)
+	}
+
+	// A drop is reported once the monitor checked again
+	rc.drop("/mnt/altmount")
+	if !s.Health(ctx).Healthy {
+		t.Error("Health: expected the cached result until the next check")
+	}
+	s.checkHealth(ctx)
+	if health := s.Health(ctx); health.Healthy || health.Mounted {
+		t.Errorf("Health after check: got %+v, want the dropped mount reported", health)
+	}
+}
+
+// setMountPath changes mount_path through the config manager
+func setMountPath(t *testing.T, cfm *config.Manager, mountPath string) {
+	t.Helper()
+
+	cfg := cfm.GetConfig().DeepCopy()
+	cfg.MountPath = mountPath
+	if err := cfm.UpdateConfig(cfg); err != nil {
+		t.Fatalf("UpdateConfig: %v", err)
+	}
+}
+
+func TestConfigChangeMovesMount(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	rc := &fakeRC{mounted: map[string]bool{}}
+	s, cfm := newTestMountService(t, rc, true)
+	s.checkInterval = time.Hour
+	s.RegisterConfigHandlers(ctx)
+
+	if err := s.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	setMountPath(t, cfm, "/mnt/moved")
+	waitFor(t, "the mount is moved", func() bool { return rc.isMounted("/mnt/moved") })
+	if rc.isMounted("/mnt/altmount") {
+		t.Error("previous mount point is still mounted")
+	}
+
+	// Clearing mount_path stops the mount for good
+	setMountPath(t, cfm, "")
+	waitFor(t, "the mount is stopped", func() bool { return !rc.isMounted("/mnt/moved") })
+
+	s.mu.RLock()
+	wanted := s.wanted
+	s.mu.RUnlock()
+	if wanted {
+		t.Error("mount is still kept up after mount_path was cleared")
+	}
+}
+
+func TestMountUsesCurrentMountPath(t *testing.T) {
+	ctx := context.Background()
+
+	rc := &fakeRC{mounted: map[string]bool{}}
+	s, cfm := newTestMountService(t, rc, false)
+
+	if err := s.Mount(ctx); err != nil {
+		t.Fatalf("Mount: %v", err)
+	}
+
+	// The mount drops, then mount_path changes without a config handler moving it
+	rc.drop("/mnt/altmount")
+	setMountPath(t, cfm, "/mnt/moved")
+
+	if err := s.Mount(ctx); err != nil {
+		t.Fatalf("Mount: %v", err)
+	}
+	if !rc.isMounted("/mnt/moved") {
+		t.Error("mount was not created at the current mount_path")
+	}
+	if rc.isMounted("/mnt/altmount") {
+		t.Error("mount was created again at the previous mount_path")
+	}
+}
@@ -0,0 +1,154 @@
+package sabnzbd
+
+import (
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned when the fallback host has failed repeatedly and requests
+// are being skipped until the cooldown expires
+var ErrCircuitOpen = errors.New("SABnzbd fallback circuit breaker is open")
+
+// CircuitState is the state of the fallback circuit breaker
+type CircuitState string
+
+const (
+	CircuitClosed   CircuitState = "closed"    // Requests flow normally
+	CircuitOpen     CircuitState = "open"      // Requests are rejected until the cooldown expires
+	CircuitHalfOpen CircuitState = "half_open" // A single trial request is allowed through
+)
+
+const (
+	// defaultFailureThreshold is the number of consecutive failed transfers that opens the breaker
+	defaultFailureThreshold = 5
+	// defaultOpenDuration is how long the breaker stays open before allowing a trial request
+	defaultOpenDuration = 5 * time.Minute
+)
+
+// BreakerStatus is a snapshot of the circuit breaker state
+type BreakerStatus struct {
+	State               CircuitState `json:"state"`
+	ConsecutiveFailures int          `json:"consecutive_failures"`
+	OpenedAt            *time.Time   `json:"opened_at,omitempty"`
+	RetryAt             *time.Time   `json:"retry_at,omitempty"`
+}
+
+// circuitBreaker stops calls to a failing host after repeated failures
+type circuitBreaker struct {
+	mu               sync.Mutex
+	state            CircuitState
+	failures         int
+	openedAt         time.Time
+	failureThreshold int
+	openDuration     time.Duration
+	trialInFlight    bool
+}
+
+func newCircuitBreaker(failureThreshold int, openDuration time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		state:            CircuitClosed,
+		failureThreshold: failureThreshold,
+		openDuration:     openDuration,
+	}
+}
+
+// allow reports whether a request may be sent, moving an expired open breaker to half-open
+func (b *circuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitOpen:
+		if time.Since(b.openedAt) < b.openDuration {
+			return ErrCircuitOpen
+		}
+		b.setStateLocked(CircuitHalfOpen)
+		b.trialInFlight = true
+		return nil
+	case CircuitHalfOpen:
+		// Only one trial request at a time while probing the host
+		if b.trialInFlight {
+			return ErrCircuitOpen
+		}
+		b.trialInFlight = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// recordSuccess closes the breaker and resets the failure count
+func (b *circuitBreaker) recordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.trialInFlight = false
+	if b.state != CircuitClosed {
+		b.setStateLocked(CircuitClosed)
+	}
+}
+
+// recordNeutral ends a request whose outcome says nothing about the host's health, such as a
+// cancelled transfer or an error the host answered with. A trial request leaves the breaker
+// half-open for the next one.
+func (b *circuitBreaker) recordNeutral() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trialInFlight = false
+}
+
+// recordFailure counts a failed request and opens the breaker once the threshold is reached.
+// A failed trial request reopens it immediately.
+func (b *circuitBreaker) recordFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.trialInFlight = false
+
+	if b.state == CircuitHalfOpen || b.failures >= b.failureThreshold {
+		b.openedAt = time.Now()
+		b.setStateLocked(CircuitOpen)
+	}
+}
+
+// status returns a snapshot of the breaker state
+func (b *circuitBreaker) status() BreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	status := BreakerStatus{
+		State:               b.state,
+		ConsecutiveFailures: b.failures,
+	}
+	if b.state != CircuitClosed {
+		openedAt := b.openedAt
+		retryAt := b.openedAt.Add(b.openDuration)
+		status.OpenedAt = &openedAt
+		status.RetryAt = &retryAt
+	}
+
+	return status
+}
+
+// setStateLocked changes the breaker state and logs the transition. Caller must hold b.mu.
+func (b *circuitBreaker) setStateLocked(state CircuitState) {
+	previous := b.state
+	b.state = state
+
+	switch state {
+	case CircuitOpen:
+		slog.Warn("SABnzbd fallback circuit breaker opened",
+			"previous_state", previous,
+			"consecutive_failures", b.failures,
+			"retry_after", b.openDuration)
+	default:
+		slog.Info("SABnzbd fallback circuit breaker state changed",
+			"previous_state", previous,
+			"state", state)
+	}
+}
@@ -0,0 +1,76 @@
+package sabnzbd
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerOpensAfterFailures(t *testing.T) {
+	b := newCircuitBreaker(3, time.Hour)
+
+	for range 3 {
+		if err := b.allow(); err != nil {
+			t.Fatalf("allow: %v", err)
+		}
+		b.recordFailure()
+	}
+
+	if err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("allow: got %v, want %v", err, ErrCircuitOpen)
+	}
+	if got := b.status(); got.State != CircuitOpen || got.ConsecutiveFailures != 3 || got.RetryAt == nil {
+		t.Errorf("status: got %+v, want open after 3 failures", got)
+	}
+}
+
+func TestCircuitBreakerNeutralOutcomes(t *testing.T) {
+	b := newCircuitBreaker(2, time.Hour)
+
+	// A cancelled or rejected request neither resets nor adds to the failures
+	b.recordFailure()
+	b.recordNeutral()
+	b.recordFailure()
+
+	if got := b.status().State; got != CircuitOpen {
+		t.Errorf("state: got %s, want %s", got, CircuitOpen)
+	}
+}
+
+func TestCircuitBreakerHalfOpen(t *testing.T) {
+	tests := []struct {
+		name   string
+		record func(b *circuitBreaker)
+		want   CircuitState
+	}{
+		{name: "trial succeeds", record: (*circuitBreaker).recordSuccess, want: CircuitClosed},
+		{name: "trial fails", record: (*circuitBreaker).recordFailure, want: CircuitOpen},
+		{name: "trial is neutral", record: (*circuitBreaker).recordNeutral, want: CircuitHalfOpen},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newCircuitBreaker(1, time.Millisecond)
+			b.recordFailure()
+			time.Sleep(2 * time.Millisecond)
+
+			if err := b.allow(); err != nil {
+				t.Fatalf("allow after the cooldown: %v", err)
+			}
+			// Only one trial request at a time
+			if err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+				t.Fatalf("second allow during the trial: got %v, want %v", err, ErrCircuitOpen)
+			}
+
+			tt.record(b)
+			if got := b.status().State; got != tt.want {
+				t.Errorf("state: got %s, want %s", got, tt.want)
+			}
+			if tt.want == CircuitHalfOpen {
+				if err := b.allow(); err != nil {
+					t.Errorf("allow after a neutral trial: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -4,19 +4,31 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"mime/multipart"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
 	"time"
+
+	"github.com/avast/retry-go/v4"
+)
+
+// Retry settings for transfers to the fallback host
+const (
+	sendAttempts      = 3
+	sendRetryDelay    = 1 * time.Second
+	sendRetryMaxDelay = 10 * time.Second
 )
 
 // SABnzbdClient handles communication with external SABnzbd instances
 type SABnzbdClient struct {
 	httpClient *http.Client
+	breaker    *circuitBreaker
 }
 
 // NewSABnzbdClient creates a new SABnzbd client with reasonable timeouts
@@ -25,6 +37,7 @@ func NewSABnzbdClient() *SABnzbdClient {
 		httpClient: &http.Client{
 			Timeout: 60 * time.Second, // 60 second timeout for file uploads
 		},
+		breaker: newCircuitBreaker(defaultFailureThreshold, defaultOpenDuration),
 	}
 }
 
@@ -48,10 +61,9 @@ const (
 // SendNZBFile sends an NZB file to an external SABnzbd instance
 // Returns the NZO ID assigned by SABnzbd, or an error
 // Priority values: "-100" (default), "-2" (paused), "-1" (low), "0" (normal), "1" (high), "2" (force)
+// Network errors, timeouts and 5xx responses are retried with backoff. After repeated failures
+// the circuit breaker opens and ErrCircuitOpen is returned without contacting the host.
 func (c *SABnzbdClient) SendNZBFile(ctx context.Context, host, apiKey, nzbPath string, category *string, priority *string) (string, error) {
-	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
-	defer cancel()
-
 	// Validate inputs
 	if host == "" {
 		return "", fmt.Errorf("SABnzbd host cannot be empty")
@@ -119,30 +131,85 @@ func (c *SABnzbdClient) SendNZBFile(ctx context.Context, host, apiKey, nzbPath s
 		return "", fmt.Errorf("failed to build request URL: %w", err)
 	}
 
+	// Skip the transfer entirely while the fallback host is considered down
+	if err := c.breaker.allow(); err != nil {
+		return "", err
+	}
+
+	var nzoID string
+	err = retry.Do(
+		func() error {
+			id, err := c.postNZB(ctx, requestURL, body.Bytes(), writer.FormDataContentType())
+			if err != nil {
+				return err
+			}
+			nzoID = id
+			return nil
+		},
+		retry.Attempts(sendAttempts),
+		retry.Delay(sendRetryDelay),
+		retry.MaxDelay(sendRetryMaxDelay),
+		retry.DelayType(retry.BackOffDelay),
+		retry.LastErrorOnly(true),
+		retry.RetryIf(isRetryableError),
+		retry.OnRetry(func(n uint, err error) {
+			slog.WarnContext(ctx, "Retrying transfer to SABnzbd fallback host",
+				"attempt", n+1,
+				"host", host,
+				"error", err)
+		}),
+		retry.Context(ctx),
+	)
+	if err != nil {
+		// Only failures that point at an unreachable or broken host count towards the breaker,
+		// cancellations and rejected requests neither open nor close it
+		if isRetryableError(err) {
+			c.breaker.recordFailure()
+		} else {
+			c.breaker.recordNeutral()
+		}
+		return "", err
+	}
+
+	c.breaker.recordSuccess()
+
+	return nzoID, nil
+}
+
+// BreakerStatus returns the state of the fallback circuit breaker
+func (c *SABnzbdClient) BreakerStatus() BreakerStatus {
+	return c.breaker.status()
+}
+
+// postNZB performs a single addfile request and returns the NZO ID assigned by SABnzbd
+func (c *SABnzbdClient) postNZB(ctx context.Context, requestURL string, body []byte, contentType string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
 	// Create the HTTP request
-	req, err := http.NewRequestWithContext(ctx, "POST", requestURL, body)
+	req, err := http.NewRequestWithContext(ctx, "POST", requestURL, bytes.NewReader(body))
 	if err != nil {
 		return "", fmt.Errorf("failed to create HTTP request: %w", err)
 	}
 
-	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Content-Type", contentType)
 
 	// Send the request
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to send request to SABnzbd: %w", err)
+		return "", &transportError{err: fmt.Errorf("failed to send request to SABnzbd: %w", err)}
 	}
 	defer resp.Body.Close()
 
 	// Read the response body
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("failed to read response body: %w", err)
+		return "", &transportError{err: fmt.Errorf("failed to read response body: %w", err)}
 	}
 
 	// Check HTTP status
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("SABnzbd returned HTTP %d: %s", resp.StatusCode, string(respBody))
+		return "", &httpStatusError{StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
 	// Parse the JSON response
@@ -168,6 +235,44 @@ func (c *SABnzbdClient) SendNZBFile(ctx context.Context, host, apiKey, nzbPath s
 	return apiResp.NzoIds[0], nil
 }
 
+// transportError wraps network-level failures such as timeouts and refused connections
+type transportError struct {
+	err error
+}
+
+func (e *transportError) Error() string { return e.err.Error() }
+func (e *transportError) Unwrap() error { return e.err }
+
+// httpStatusError is returned when SABnzbd answers with a non-200 status
+type httpStatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("SABnzbd returned HTTP %d: %s", e.StatusCode, e.Body)
+}
+
+// isRetryableError reports whether a failed transfer is worth retrying: network errors,
+// timeouts and 5xx responses. Cancellation of the caller's context is never retried.
+func isRetryableError(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var transportErr *transportError
+	if errors.As(err, &transportErr) {
+		return true
+	}
+
+	var statusErr *httpStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= 500
+	}
+
+	return false
+}
+
 // buildAddFileURL constructs the SABnzbd API URL for adding files
 func (c *SABnzbdClient) buildAddFileURL(host, apiKey string) (string, error) {
 	// Parse the host URL to ensure it's valid
@@ -0,0 +1,115 @@
+package slogutil
+
+import (
+	"context"
+	"log/slog"
+)
+
+// ComponentKey is the attribute naming the subsystem that logged a record
+const ComponentKey = "component"
+
+// componentLevelHandler drops records below the level set for their component, or below
+// the global level when their component has no level of its own. The component is taken
+// from the logger attributes, then the record attributes and finally the context.
+type componentLevelHandler struct {
+	handler   slog.Handler
+	level     slog.Leveler
+	levels    map[string]slog.Level
+	component string
+}
+
+// withComponentLevels wraps handler to filter records by level per component. The wrapped
+// handler must accept every level, filtering is left to the returned handler.
+func withComponentLevels(handler slog.Handler, level slog.Leveler, levels map[string]slog.Level) slog.Handler {
+	return &componentLevelHandler{
+		handler: handler,
+		level:   level,
+		levels:  levels,
+	}
+}
+
+func (h *componentLevelHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	if component := h.knownComponent(ctx); component != "" {
+		return l >= h.levelFor(component)
+	}
+
+	// The record may still name its component, let Handle decide
+	return l >= h.minLevel()
+}
+
+func (h *componentLevelHandler) Handle(ctx context.Context, r slog.Record) error {
+	component := h.component
+	if component == "" {
+		r.Attrs(func(a slog.Attr) bool {
+			if a.Key == ComponentKey {
+				component = a.Value.String()
+				return false
+			}
+			return true
+		})
+	}
+	if component == "" {
+		component = h.knownComponent(ctx)
+	}
+
+	if r.Level < h.levelFor(component) {
+		return nil
+	}
+
+	return h.handler.Handle(ctx, r)
+}
+
+func (h *componentLevelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.handler = h.handler.WithAttrs(attrs)
+	for _, a := range attrs {
+		if a.Key == ComponentKey {
+			clone.component = a.Value.String()
+		}
+	}
+
+	return &clone
+}
+
+func (h *componentLevelHandler) WithGroup(name string) slog.Handler {
+	clone := *h
+	clone.handler = h.handler.WithGroup(name)
+
+	return &clone
+}
+
+// knownComponent returns the component set on the logger or in the context
+func (h *componentLevelHandler) knownComponent(ctx context.Context) string {
+	if h.component != "" {
+		return h.component
+	}
+
+	if ctx != nil {
+		if d, ok := ctx.Value(dataKey{}).(data); ok {
+			if a, ok := d[ComponentKey]; ok {
+				return a.Value.String()
+			}
+		}
+	}
+
+	return ""
+}
+
+// levelFor returns the level records of component must reach to be logged
+func (h *componentLevelHandler) levelFor(component string) slog.Level {
+	if l, ok := h.levels[component]; ok {
+		return l
+	}
+
+	return h.level.Level()
+}
+
+// minLevel returns the lowest level any record can be logged at
+func (h *componentLevelHandler) minLevel() slog.Level {
+	level := h.level.Level()
+	for _, l := range h.levels {
+		level = min(level, l)
+	}
+
+	return level
+}
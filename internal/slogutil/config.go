@@ -10,8 +10,14 @@ import (
 	"gopkg.in/natefinch/lumberjack.v2"
 )
 
+// Format is the output format of log records
 type Format string
 
+const (
+	FormatText Format = "text"
+	FormatJSON Format = "json"
+)
+
 type ReplaceAttrFunc func(groups []string, a slog.Attr) slog.Attr
 
 type Config struct {
@@ -22,6 +28,20 @@ type Config struct {
 	LogPath     string
 }
 
+// activeLevel is the global level of the logger created by SetupLogRotation
+var activeLevel DynamicLeveler
+
+// SetLevel changes the global level of the logger created by SetupLogRotation at runtime.
+// Components with their own level in log.component_levels keep it.
+func SetLevel(level string) {
+	activeLevel.SetLevel(parseLevel(level).Level())
+}
+
+// Level returns the current global log level
+func Level() slog.Level {
+	return activeLevel.Level()
+}
+
 var defaultConfig = Config{
 	Level:   defaultLevel(),
 	LogPath: "activity.log",
@@ -69,6 +89,8 @@ func parseLevel(level string) slog.Leveler {
 // SetupLogRotation configures slog with log rotation using lumberjack
 // If logConfig.File is empty, it logs to console only
 // If logConfig.File is configured, it logs to both console and file
+// Records are written as text unless logConfig.Format is json
+// Components listed in logConfig.ComponentLevels are logged at their own level
 // Returns the configured logger
 func SetupLogRotation(logConfig config.LogConfig) *slog.Logger {
 	var writer io.Writer = os.Stdout
@@ -92,10 +114,33 @@ func SetupLogRotation(logConfig config.LogConfig) *slog.Logger {
 		level = "info" // fallback default
 	}
 
-	// Create handler with the writer and level
-	handler := slog.NewTextHandler(writer, &slog.HandlerOptions{
-		Level: parseLevel(level),
-	})
+	SetLevel(level)
+	opts := &slog.HandlerOptions{
+		Level: &activeLevel,
+	}
+
+	// Components with their own level are filtered by withComponentLevels, the format
+	// handler has to let every record through
+	var componentLevels map[string]slog.Level
+	if len(logConfig.ComponentLevels) > 0 {
+		componentLevels = make(map[string]slog.Level, len(logConfig.ComponentLevels))
+		for component, componentLevel := range logConfig.ComponentLevels {
+			componentLevels[component] = parseLevel(componentLevel).Level()
+		}
+		opts.Level = slog.LevelDebug
+	}
+
+	// Create handler with the writer and level in the configured format
+	var handler slog.Handler
+	if Format(logConfig.Format) == FormatJSON {
+		handler = slog.NewJSONHandler(writer, opts)
+	} else {
+		handler = slog.NewTextHandler(writer, opts)
+	}
+
+	if componentLevels != nil {
+		handler = withComponentLevels(handler, &activeLevel, componentLevels)
+	}
 
 	// Wrap handler to support context data extraction
 	wrappedHandler := WrapHandler(handler)
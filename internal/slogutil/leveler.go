@@ -9,9 +9,13 @@ type DynamicLeveler struct {
 	level atomic.Value
 }
 
-// Level returns the current logging level.
+// Level returns the current logging level, info until a level is set.
 func (dl *DynamicLeveler) Level() slog.Level {
-	return dl.level.Load().(slog.Level)
+	if level, ok := dl.level.Load().(slog.Level); ok {
+		return level
+	}
+
+	return slog.LevelInfo
 }
 
 // SetLevel updates the logging level.
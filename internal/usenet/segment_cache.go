@@ -0,0 +1,232 @@
+package usenet
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tempFilePrefix marks segment files that are still being written
+const tempFilePrefix = ".tmp-"
+
+// SegmentCache keeps decoded segments on disk so files that are read again, even after a
+// restart, are not downloaded from the providers again. The least recently used segments
+// are evicted once the cache grows over its size limit.
+type SegmentCache struct {
+	dir string
+
+	mu      sync.Mutex
+	maxSize int64
+	size    int64
+	entries map[string]*list.Element
+	lru     *list.List // Most recently used at the front
+}
+
+type segmentCacheEntry struct {
+	key  string
+	size int64
+}
+
+// NewSegmentCache opens the segment cache in dir, creating the directory if needed, and
+// indexes the segments cached by previous runs
+func NewSegmentCache(dir string, maxSizeMB int) (*SegmentCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create segment cache directory: %w", err)
+	}
+
+	c := &SegmentCache{
+		dir:     dir,
+		maxSize: int64(maxSizeMB) * 1024 * 1024,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// load indexes the cached segments, using their modification time as the last use
+func (c *SegmentCache) load() error {
+	dirEntries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read segment cache directory: %w", err)
+	}
+
+	type cachedFile struct {
+		key     string
+		size    int64
+		modTime time.Time
+	}
+
+	files := make([]cachedFile, 0, len(dirEntries))
+	for _, entry := range dirEntries {
+		if entry.IsDir() {
+			continue
+		}
+
+		// Writes interrupted by a crash are never completed
+		if strings.HasPrefix(entry.Name(), tempFilePrefix) {
+			_ = os.Remove(filepath.Join(c.dir, entry.Name()))
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, cachedFile{key: entry.Name(), size: info.Size(), modTime: info.ModTime()})
+	}
+
+	slices.SortFunc(files, func(a, b cachedFile) int {
+		return a.modTime.Compare(b.modTime)
+	})
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, f := range files {
+		c.entries[f.key] = c.lru.PushFront(&segmentCacheEntry{key: f.key, size: f.size})
+		c.size += f.size
+	}
+	c.evictLocked()
+
+	slog.Info("Segment disk cache loaded",
+		"dir", c.dir,
+		"segments", len(c.entries),
+		"size_mb", c.size/1024/1024,
+		"max_size_mb", c.maxSize/1024/1024)
+
+	return nil
+}
+
+// SetMaxSize changes the size limit, evicting segments if the cache is now over it
+func (c *SegmentCache) SetMaxSize(maxSizeMB int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.maxSize = int64(maxSizeMB) * 1024 * 1024
+	c.evictLocked()
+}
+
+// Get returns the cached data of a segment
+func (c *SegmentCache) Get(segmentID string) ([]byte, bool) {
+	key := segmentCacheKey(segmentID)
+
+	c.mu.Lock()
+	elem, ok := c.entries[key]
+	if ok {
+		c.lru.MoveToFront(elem)
+	}
+	c.mu.Unlock()
+
+	if !ok {
+		return nil, false
+	}
+
+	path := filepath.Join(c.dir, key)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		c.mu.Lock()
+		if current, ok := c.entries[key]; ok && current == elem {
+			c.removeLocked(elem)
+		}
+		c.mu.Unlock()
+		return nil, false
+	}
+
+	// Record the use so the eviction order survives restarts
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+
+	return data, true
+}
+
+// Put stores the data of a segment, evicting the least recently used segments to make room
+func (c *SegmentCache) Put(segmentID string, data []byte) {
+	key := segmentCacheKey(segmentID)
+	size := int64(len(data))
+
+	c.mu.Lock()
+	_, exists := c.entries[key]
+	tooLarge := size == 0 || size > c.maxSize
+	c.mu.Unlock()
+
+	if exists || tooLarge {
+		return
+	}
+
+	// Write to a temporary file first so readers never see a partial segment
+	tmp, err := os.CreateTemp(c.dir, tempFilePrefix+"*")
+	if err != nil {
+		slog.Warn("Failed to cache segment on disk", "segment_id", segmentID, "error", err)
+		return
+	}
+
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), filepath.Join(c.dir, key))
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		slog.Warn("Failed to cache segment on disk", "segment_id", segmentID, "error", err)
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Another reader may have cached the same segment meanwhile
+	if elem, ok := c.entries[key]; ok {
+		c.lru.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.lru.PushFront(&segmentCacheEntry{key: key, size: size})
+	c.size += size
+	c.evictLocked()
+}
+
+// evictLocked removes the least recently used segments until the cache fits its limit
+func (c *SegmentCache) evictLocked() {
+	for c.size > c.maxSize {
+		elem := c.lru.Back()
+		if elem == nil {
+			return
+		}
+
+		entry := elem.Value.(*segmentCacheEntry)
+		if err := os.Remove(filepath.Join(c.dir, entry.key)); err != nil && !os.IsNotExist(err) {
+			slog.Warn("Failed to evict cached segment", "file", entry.key, "error", err)
+		}
+		c.removeLocked(elem)
+	}
+}
+
+// removeLocked drops an entry from the index
+func (c *SegmentCache) removeLocked(elem *list.Element) {
+	entry := elem.Value.(*segmentCacheEntry)
+	c.lru.Remove(elem)
+	delete(c.entries, entry.key)
+	c.size -= entry.size
+}
+
+// segmentCacheKey returns the file name a segment is cached under. Message IDs can contain
+// characters that are not valid in file names, so they are hashed.
+func segmentCacheKey(segmentID string) string {
+	hash := sha256.Sum256([]byte(segmentID))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,61 @@
+package usenet
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestSegmentCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	cache, err := NewSegmentCache(t.TempDir(), 1)
+	if err != nil {
+		t.Fatalf("NewSegmentCache() error = %v", err)
+	}
+
+	segment := bytes.Repeat([]byte("x"), 400*1024)
+	cache.Put("a@example", segment)
+	cache.Put("b@example", segment)
+
+	// Reading a makes b the least recently used segment
+	if _, ok := cache.Get("a@example"); !ok {
+		t.Fatalf("expected segment a to be cached")
+	}
+	cache.Put("c@example", segment)
+
+	if _, ok := cache.Get("b@example"); ok {
+		t.Errorf("expected segment b to be evicted")
+	}
+	for _, id := range []string{"a@example", "c@example"} {
+		if data, ok := cache.Get(id); !ok || !bytes.Equal(data, segment) {
+			t.Errorf("expected segment %s to be cached", id)
+		}
+	}
+}
+
+func TestSegmentCache_SurvivesReopen(t *testing.T) {
+	dir := t.TempDir()
+
+	cache, err := NewSegmentCache(dir, 1)
+	if err != nil {
+		t.Fatalf("NewSegmentCache() error = %v", err)
+	}
+	cache.Put("a@example", []byte("segment data"))
+
+	// Leftovers of an interrupted write are removed on open
+	if err := os.WriteFile(dir+"/"+tempFilePrefix+"partial", []byte("partial"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := NewSegmentCache(dir, 1)
+	if err != nil {
+		t.Fatalf("NewSegmentCache() error = %v", err)
+	}
+
+	data, ok := reopened.Get("a@example")
+	if !ok || string(data) != "segment data" {
+		t.Fatalf("Get() = %q, %v, want cached segment", data, ok)
+	}
+	if _, err := os.Stat(dir + "/" + tempFilePrefix + "partial"); !os.IsNotExist(err) {
+		t.Errorf("expected temporary file to be removed, stat error = %v", err)
+	}
+}
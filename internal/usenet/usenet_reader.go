@@ -1,6 +1,7 @@
 package usenet
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"io"
@@ -18,6 +19,8 @@ import (
 const (
 	defaultMaxCacheSize    = 32 * 1024 * 1024 // Default to 32MB
 	defaultDownloadWorkers = 15
+	// Number of segments that must be consumed in order before the read-ahead window is used
+	sequentialReadThreshold = 2
 )
 
 var (
@@ -45,10 +48,12 @@ type usenetReader struct {
 	rg                 *segmentRange
 	maxDownloadWorkers int
 	maxCacheSize       int64 // Maximum cache size in bytes
+	readAheadSize      int64 // Prefetch window in bytes once reads are sequential, 0 disables
 	init               chan any
 	initDownload       sync.Once
 	totalBytesRead     int64
 	poolGetter         func() (nntppool.UsenetConnectionPool, error) // Dynamic pool getter
+	diskCache          *SegmentCache                                 // Optional on-disk segment cache
 
 	// Dynamic download tracking
 	nextToDownload      int          // Index of next segment to download
@@ -64,6 +69,8 @@ func NewUsenetReader(
 	rg *segmentRange,
 	maxDownloadWorkers int,
 	maxCacheSizeMB int,
+	readAheadMB int,
+	diskCache *SegmentCache,
 ) (io.ReadCloser, error) {
 	log := slog.Default().With("component", "usenet-reader")
 	ctx, cancel := context.WithCancel(ctx)
@@ -81,7 +88,9 @@ func NewUsenetReader(
 		init:                make(chan any, 1),
 		maxDownloadWorkers:  maxDownloadWorkers,
 		maxCacheSize:        maxCacheSize,
+		readAheadSize:       max(int64(readAheadMB), 0) * 1024 * 1024,
 		poolGetter:          poolGetter,
+		diskCache:           diskCache,
 		nextToDownload:      0,
 		downloadingSegments: make(map[int]bool),
 	}
@@ -236,7 +245,15 @@ func (b *usenetReader) isPoolUnavailableError(err error) bool {
 }
 
 // downloadSegmentWithRetry attempts to download a segment with retry logic for pool unavailability
+// Segments found in the disk cache are not downloaded, downloaded ones are added to it.
 func (b *usenetReader) downloadSegmentWithRetry(ctx context.Context, segment *segment) error {
+	if b.diskCache != nil {
+		if data, ok := b.diskCache.Get(segment.Id); ok {
+			_, err := segment.Writer().Write(data)
+			return err
+		}
+	}
+
 	return retry.Do(
 		func() error {
 			// Get current pool
@@ -245,8 +262,16 @@ func (b *usenetReader) downloadSegmentWithRetry(ctx context.Context, segment *se
 				return err
 			}
 
+			// Keep a copy of the segment for the disk cache
+			w := segment.Writer()
+			var cached *bytes.Buffer
+			if b.diskCache != nil {
+				cached = bytes.NewBuffer(make([]byte, 0, segment.SegmentSize))
+				w = io.MultiWriter(w, cached)
+			}
+
 			// Attempt download
-			bytesWritten, err := cp.Body(ctx, segment.Id, segment.Writer(), segment.groups)
+			bytesWritten, err := cp.Body(ctx, segment.Id, w, segment.groups)
 			if err != nil {
 				if strings.Contains(err.Error(), "data corruption detected") {
 					return &DataCorruptionError{
@@ -258,6 +283,10 @@ func (b *usenetReader) downloadSegmentWithRetry(ctx context.Context, segment *se
 				return err
 			}
 
+			if cached != nil {
+				b.diskCache.Put(segment.Id, cached.Bytes())
+			}
+
 			return nil
 		},
 		retry.Attempts(10),
@@ -309,9 +338,12 @@ func (b *usenetReader) downloadManager(
 			maxSegmentsAhead = len(b.rg.segments)
 		}
 
+		// Once reads are sequential, prefetch up to the read-ahead window if it is larger
+		readAheadSegments := min(int(b.readAheadSize/avgSegmentSize), len(b.rg.segments))
+
 		// Limit concurrent downloads to prevent cache overflow
-		if downloadWorkers > maxSegmentsAhead {
-			downloadWorkers = maxSegmentsAhead
+		if downloadWorkers > max(maxSegmentsAhead, readAheadSegments) {
+			downloadWorkers = max(maxSegmentsAhead, readAheadSegments)
 		}
 
 		pool := pool.New().
@@ -324,7 +356,11 @@ func (b *usenetReader) downloadManager(
 			currentIndex := b.rg.GetCurrentIndex()
 
 			// Calculate how many segments we should have downloaded
-			targetDownload := currentIndex + maxSegmentsAhead
+			segmentsAhead := maxSegmentsAhead
+			if currentIndex >= sequentialReadThreshold && readAheadSegments > segmentsAhead {
+				segmentsAhead = readAheadSegments
+			}
+			targetDownload := currentIndex + segmentsAhead
 			if targetDownload > len(b.rg.segments) {
 				targetDownload = len(b.rg.segments)
 			}
@@ -7,6 +7,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/javi11/altmount/internal/config"
 	metapb "github.com/javi11/altmount/internal/metadata/proto"
 	"github.com/javi11/altmount/internal/pool"
 	"github.com/javi11/altmount/internal/progress"
@@ -14,13 +15,8 @@ import (
 )
 
 // ValidateSegmentAvailability validates that segments are available on Usenet servers.
-// It uses a strategic sampling approach for efficiency when fullValidation is false:
-// - Validates first 3 segments (DMCA/takedown detection)
-// - Validates last 2 segments (incomplete upload detection)
-// - Validates random middle segments based on samplePercentage (general integrity check)
-// The samplePercentage parameter controls how many segments to check (1-100%).
-//
-// For fullValidation=true, all segments are validated regardless of samplePercentage.
+// The samplePercentage parameter controls how many segments to check (1-100%) and the
+// strategy which ones (see selectSegmentsForValidation). At 100% every segment is validated.
 // A minimum of 5 segments are always validated for statistical validity when sampling.
 //
 // The optional progressTracker updates progress after each segment validation completes,
@@ -33,6 +29,7 @@ func ValidateSegmentAvailability(
 	poolManager pool.Manager,
 	maxConnections int,
 	samplePercentage int,
+	strategy config.SampleStrategy,
 	progressTracker progress.ProgressTracker,
 ) error {
 	if len(segments) == 0 {
@@ -50,7 +47,7 @@ func ValidateSegmentAvailability(
 	}
 
 	// Select which segments to validate
-	segmentsToValidate := selectSegmentsForValidation(segments, samplePercentage)
+	segmentsToValidate := selectSegmentsForValidation(segments, samplePercentage, strategy)
 	totalToValidate := len(segmentsToValidate)
 
 	// Atomic counter for progress tracking (thread-safe for concurrent validation)
@@ -86,32 +83,55 @@ func ValidateSegmentAvailability(
 	return nil
 }
 
-// selectSegmentsForValidation determines which segments to validate based on validation mode and sample percentage.
-// For full validation, returns all segments. For sampling, uses a strategic approach that:
-// - Validates first 3 segments (DMCA/takedown detection)
-// - Validates last 2 segments (incomplete upload detection)
-// - Validates random middle segments based on samplePercentage (general integrity check)
-// A minimum of 5 segments are always validated for statistical validity when sampling.
-func selectSegmentsForValidation(segments []*metapb.SegmentData, samplePercentage int) []*metapb.SegmentData {
+// SegmentsToValidate returns how many of segmentCount segments are validated at the given
+// sample percentage. Every strategy selects the same number of segments.
+func SegmentsToValidate(segmentCount int, samplePercentage int) int {
 	if samplePercentage == 100 {
-		return segments
+		return segmentCount
 	}
 
-	totalSegments := len(segments)
-
 	// Calculate target number of segments based on percentage
-	targetSamples := (totalSegments * samplePercentage) / 100
+	targetSamples := (segmentCount * samplePercentage) / 100
 
 	// Enforce minimum of 5 segments for statistical validity
 	if targetSamples < 5 {
 		targetSamples = 5
 	}
 
+	return min(targetSamples, segmentCount)
+}
+
+// selectSegmentsForValidation determines which segments to validate based on sample percentage
+// and strategy. At 100% all segments are returned; otherwise SegmentsToValidate segments are
+// picked by the strategy:
+//   - PERCENTAGE (default): first 3 (DMCA/takedown detection), last 2 (incomplete upload
+//     detection) and random middle segments (general integrity check)
+//   - HEAD_TAIL: contiguous runs at the start, middle and end of the file
+//   - DISTRIBUTED: the first and last segments plus one random segment from each evenly
+//     sized slice in between, spreading checks over the whole file
+func selectSegmentsForValidation(segments []*metapb.SegmentData, samplePercentage int, strategy config.SampleStrategy) []*metapb.SegmentData {
+	targetSamples := SegmentsToValidate(len(segments), samplePercentage)
+
 	// If target samples equals or exceeds total segments, validate all
-	if targetSamples >= totalSegments {
+	if targetSamples >= len(segments) {
 		return segments
 	}
 
+	switch strategy {
+	case config.SampleStrategyHeadTail:
+		return selectHeadMiddleTail(segments, targetSamples)
+	case config.SampleStrategyDistributed:
+		return selectDistributed(segments, targetSamples)
+	default:
+		return selectFirstLastRandom(segments, targetSamples)
+	}
+}
+
+// selectFirstLastRandom returns the first 3 and last 2 segments plus random middle segments
+// up to targetSamples
+func selectFirstLastRandom(segments []*metapb.SegmentData, targetSamples int) []*metapb.SegmentData {
+	totalSegments := len(segments)
+
 	var toValidate []*metapb.SegmentData
 
 	// 1. First 3 segments (DMCA/takedown detection)
@@ -159,3 +179,50 @@ func selectSegmentsForValidation(segments []*metapb.SegmentData, samplePercentag
 
 	return toValidate
 }
+
+// selectHeadMiddleTail returns targetSamples segments split into contiguous runs at the
+// start, middle and end of the file, where missing articles tend to cluster.
+// targetSamples must be less than len(segments).
+func selectHeadMiddleTail(segments []*metapb.SegmentData, targetSamples int) []*metapb.SegmentData {
+	totalSegments := len(segments)
+
+	tailCount := targetSamples / 3
+	middleCount := targetSamples / 3
+	headCount := targetSamples - tailCount - middleCount
+
+	// Center the middle run, keeping it clear of the head and tail runs
+	middleStart := (totalSegments - middleCount) / 2
+	middleStart = max(middleStart, headCount)
+	middleStart = min(middleStart, totalSegments-tailCount-middleCount)
+
+	toValidate := make([]*metapb.SegmentData, 0, targetSamples)
+	toValidate = append(toValidate, segments[:headCount]...)
+	toValidate = append(toValidate, segments[middleStart:middleStart+middleCount]...)
+	toValidate = append(toValidate, segments[totalSegments-tailCount:]...)
+
+	return toValidate
+}
+
+// selectDistributed splits the segments into targetSamples evenly sized slices and returns
+// the first segment, the last segment and a random segment from every slice in between.
+// targetSamples must be less than len(segments).
+func selectDistributed(segments []*metapb.SegmentData, targetSamples int) []*metapb.SegmentData {
+	totalSegments := len(segments)
+
+	toValidate := make([]*metapb.SegmentData, 0, targetSamples)
+	for i := 0; i < targetSamples; i++ {
+		start := i * totalSegments / targetSamples
+		end := (i + 1) * totalSegments / targetSamples
+
+		switch i {
+		case 0:
+			toValidate = append(toValidate, segments[0])
+		case targetSamples - 1:
+			toValidate = append(toValidate, segments[totalSegments-1])
+		default:
+			toValidate = append(toValidate, segments[start+rand.Intn(end-start)])
+		}
+	}
+
+	return toValidate
+}
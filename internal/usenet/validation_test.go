@@ -0,0 +1,82 @@
+package usenet
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/javi11/altmount/internal/config"
+	metapb "github.com/javi11/altmount/internal/metadata/proto"
+)
+
+func testSegments(n int) []*metapb.SegmentData {
+	segments := make([]*metapb.SegmentData, n)
+	for i := range segments {
+		segments[i] = &metapb.SegmentData{Id: fmt.Sprintf("seg-%d", i)}
+	}
+	return segments
+}
+
+func TestSelectSegmentsForValidation(t *testing.T) {
+	strategies := []config.SampleStrategy{
+		config.SampleStrategyPercentage,
+		config.SampleStrategyHeadTail,
+		config.SampleStrategyDistributed,
+	}
+
+	tests := []struct {
+		segments   int
+		percentage int
+		want       int
+	}{
+		{segments: 3, percentage: 1, want: 3},
+		{segments: 6, percentage: 1, want: 5},
+		{segments: 1000, percentage: 1, want: 10},
+		{segments: 1000, percentage: 10, want: 100},
+		{segments: 1000, percentage: 100, want: 1000},
+	}
+
+	for _, strategy := range strategies {
+		for _, tt := range tests {
+			t.Run(fmt.Sprintf("%s/%d_at_%d%%", strategy, tt.segments, tt.percentage), func(t *testing.T) {
+				segments := testSegments(tt.segments)
+				got := selectSegmentsForValidation(segments, tt.percentage, strategy)
+
+				if len(got) != tt.want {
+					t.Fatalf("selected %d segments, want %d", len(got), tt.want)
+				}
+				if want := SegmentsToValidate(tt.segments, tt.percentage); len(got) != want {
+					t.Errorf("SegmentsToValidate() = %d, but %d segments were selected", want, len(got))
+				}
+
+				seen := make(map[string]bool, len(got))
+				for _, segment := range got {
+					if seen[segment.Id] {
+						t.Fatalf("segment %s selected more than once", segment.Id)
+					}
+					seen[segment.Id] = true
+				}
+
+				// Every strategy checks both ends of the file
+				if !seen[segments[0].Id] || !seen[segments[len(segments)-1].Id] {
+					t.Errorf("first and last segments must always be selected")
+				}
+			})
+		}
+	}
+}
+
+func TestSelectHeadMiddleTail_IncludesMiddle(t *testing.T) {
+	segments := testSegments(1000)
+	got := selectSegmentsForValidation(segments, 3, config.SampleStrategyHeadTail)
+
+	found := false
+	for _, segment := range got {
+		if segment.Id == "seg-500" {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Errorf("expected the middle segment to be selected, got %d segments", len(got))
+	}
+}
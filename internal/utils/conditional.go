@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// HasRevalidationHeaders reports whether a GET or HEAD request carries the headers
+// NotModified evaluates, so callers only look up the file when they can answer 304
+func HasRevalidationHeaders(r *http.Request) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+	// If-Match and If-Unmodified-Since may fail with 412, leave those to the full handler
+	if r.Header.Get("If-Match") != "" || r.Header.Get("If-Unmodified-Since") != "" {
+		return false
+	}
+
+	return r.Header.Get("If-None-Match") != "" || r.Header.Get("If-Modified-Since") != ""
+}
+
+// NotModified reports whether a request with revalidation headers can be answered with
+// 304 Not Modified for a file with the given ETag and modification time. Like
+// http.ServeContent, If-None-Match takes precedence over If-Modified-Since and entity
+// tags are compared weakly. etag may be empty when the file has none.
+func NotModified(r *http.Request, etag string, modTime time.Time) bool {
+	if !HasRevalidationHeaders(r) {
+		return false
+	}
+
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		if etag == "" {
+			return false
+		}
+		for _, candidate := range strings.Split(inm, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || weakETagMatch(candidate, etag) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if modTime.IsZero() || modTime.Equal(time.Unix(0, 0)) {
+		return false
+	}
+	t, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+
+	// The Last-Modified header has a one second resolution
+	return !modTime.Truncate(time.Second).After(t)
+}
+
+// weakETagMatch compares two entity tags ignoring their weak indicators
+func weakETagMatch(a, b string) bool {
+	return strings.TrimPrefix(a, "W/") == strings.TrimPrefix(b, "W/")
+}
+
+// WriteNotModified answers a request with 304 Not Modified, keeping the caching headers
+// a 200 response would have had
+func WriteNotModified(w http.ResponseWriter, etag string, modTime time.Time) {
+	h := w.Header()
+	// A 304 has no body, drop the headers describing one
+	h.Del("Content-Type")
+	h.Del("Content-Length")
+	h.Del("Content-Encoding")
+	if etag != "" {
+		h.Set("ETag", etag)
+	}
+	if !modTime.IsZero() && !modTime.Equal(time.Unix(0, 0)) {
+		h.Set("Last-Modified", modTime.UTC().Format(http.TimeFormat))
+	}
+	w.WriteHeader(http.StatusNotModified)
+}
@@ -3,16 +3,22 @@ package webdav
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"mime"
 	"net/http"
+	"os"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 
 	"github.com/go-pkgz/auth/v2/token"
+	"github.com/javi11/altmount/internal/auth"
 	"github.com/javi11/altmount/internal/config"
 	"github.com/javi11/altmount/internal/database"
+	"github.com/javi11/altmount/internal/maintenance"
 	"github.com/javi11/altmount/internal/nzbfilesystem"
+	"github.com/javi11/altmount/internal/slogutil"
 	"github.com/javi11/altmount/internal/utils"
 	"github.com/javi11/altmount/internal/webdav/propfind"
 	"golang.org/x/net/webdav"
@@ -23,6 +29,11 @@ type Handler struct {
 	handler      http.Handler
 	authCreds    *AuthCredentials
 	configGetter config.ConfigGetter
+	networks     *auth.NetworkGuard // Rejects clients outside auth.allowed_networks
+	readOnly     atomic.Bool        // Reject mutating methods when set
+	maintenance  *maintenance.State // Reject mutating methods while maintenance mode is on
+
+	maxPropfindDepth atomic.Int64 // Deepest PROPFIND allowed, 0 allows infinity
 }
 
 // NewHandler creates a new WebDAV handler that can be used with Fiber adaptor
@@ -33,16 +44,41 @@ func NewHandler(
 	userRepo *database.UserRepository, // Optional user repository for JWT auth
 	configGetter config.ConfigGetter, // Dynamic config access
 ) (*Handler, error) {
-	// Create dynamic auth credentials with initial values
-	authCreds := NewAuthCredentials(config.User, config.Pass)
-
 	// Create custom error handler that maps our errors to proper HTTP status codes
 	errorHandler := &customErrorHandler{
 		fileSystem: nzbToWebdavFS(fs),
 	}
 
+	return newHandler(config, errorHandler, tokenService, userRepo, configGetter), nil
+}
+
+// newHandler creates the WebDAV handler serving fileSystem
+func newHandler(
+	config *Config,
+	fileSystem webdav.FileSystem,
+	tokenService *token.Service,
+	userRepo *database.UserRepository,
+	configGetter config.ConfigGetter,
+) *Handler {
+	// Create dynamic auth credentials with initial values
+	authCreds := NewAuthCredentials(config.User, config.Pass)
+
+	wh := &Handler{
+		authCreds:    authCreds,
+		configGetter: configGetter,
+	}
+	wh.networks = auth.NewNetworkGuard(func() ([]string, []string) {
+		if wh.configGetter == nil {
+			return nil, nil
+		}
+		cfg := wh.configGetter()
+		return cfg.Auth.AllowedNetworks, cfg.Auth.TrustedProxies
+	})
+	wh.readOnly.Store(config.ReadOnly)
+	wh.maxPropfindDepth.Store(int64(config.MaxPropfindDepth))
+
 	webdavHandler := &webdav.Handler{
-		FileSystem: errorHandler,
+		FileSystem: fileSystem,
 		LockSystem: webdav.NewMemLS(),
 		Prefix:     config.Prefix,
 		Logger: func(r *http.Request, err error) {
@@ -54,10 +90,19 @@ func NewHandler(
 
 	// Create the main handler with authentication
 	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = r.WithContext(slogutil.With(r.Context(), slogutil.ComponentKey, "webdav"))
+
+		// Reject clients outside auth.allowed_networks before looking at their credentials
+		if !wh.networks.Allow(r) {
+			http.Error(w, "403 Forbidden: client network not allowed", http.StatusForbidden)
+			return
+		}
+
 		// Fallback to basic authentication if JWT failed
 		username, password, hasBasicAuth := r.BasicAuth()
 
 		var authenticated bool
+		var basePath string
 		if !hasBasicAuth {
 			// Try JWT token authentication first (if services are available)
 			if tokenService != nil && userRepo != nil {
@@ -73,6 +118,9 @@ func NewHandler(
 						user, err := userRepo.GetUserByID(r.Context(), userID)
 						if err == nil && user != nil {
 							authenticated = true
+							if user.BasePath != nil {
+								basePath = *user.BasePath
+							}
 						}
 					}
 				}
@@ -95,6 +143,31 @@ func NewHandler(
 			return
 		}
 
+		// Restrict users with a base path to their subtree of the library
+		if basePath != "" {
+			r = r.WithContext(withBasePath(r.Context(), basePath))
+		}
+
+		// In read-only mode only allow methods that cannot change the library
+		if wh.readOnly.Load() && isMutatingMethod(r.Method) && !allowReadOnlyLock(r, webdavHandler) {
+			slog.WarnContext(r.Context(), "Rejected WebDAV write in read-only mode",
+				"method", r.Method,
+				"path", r.URL.Path,
+				"user_agent", r.Header.Get("User-Agent"))
+			http.Error(w, "403 Forbidden: WebDAV is read-only", http.StatusForbidden)
+			return
+		}
+
+		// Maintenance mode makes WebDAV read-only as well
+		if wh.maintenance.Enabled() && isMutatingMethod(r.Method) && !allowReadOnlyLock(r, webdavHandler) {
+			slog.WarnContext(r.Context(), "Rejected WebDAV write in maintenance mode",
+				"method", r.Method,
+				"path", r.URL.Path,
+				"user_agent", r.Header.Get("User-Agent"))
+			http.Error(w, "403 Forbidden: server is in maintenance mode", http.StatusForbidden)
+			return
+		}
+
 		// This will prevent webdav internal seeks which is not supported by usenet reader
 		ext := filepath.Ext(r.URL.Path)
 		if ext != "" {
@@ -131,8 +204,13 @@ func NewHandler(
 				"user_agent", r.Header.Get("User-Agent"))
 		}
 
+		// Revalidations of an unchanged file are answered from metadata, without opening it
+		if utils.HasRevalidationHeaders(r) && serveNotModified(webdavHandler, w, r) {
+			return
+		}
+
 		if r.Method == "PROPFIND" {
-			status, err := propfind.HandlePropfind(webdavHandler.FileSystem, webdavHandler.LockSystem, w, r, config.Prefix)
+			status, err := propfind.HandlePropfind(webdavHandler.FileSystem, webdavHandler.LockSystem, w, r, config.Prefix, int(wh.maxPropfindDepth.Load()))
 			if status != 0 {
 				w.WriteHeader(status)
 				if status != http.StatusNoContent {
@@ -183,11 +261,71 @@ func NewHandler(
 		mux.Handle(base+"/", h)
 	}
 
-	return &Handler{
-		handler:      mux,
-		authCreds:    authCreds,
-		configGetter: configGetter,
-	}, nil
+	wh.handler = mux
+
+	return wh
+}
+
+// isMutatingMethod reports whether a WebDAV method can modify resources
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, "PROPFIND":
+		return false
+	default:
+		return true
+	}
+}
+
+// allowReadOnlyLock reports whether a LOCK or UNLOCK request can be served in read-only
+// mode. Clients like macOS Finder and the Windows mini-redirector lock files while browsing
+// and fail when locking is refused. A lock cannot change the library, except a LOCK on a
+// missing resource which creates it.
+func allowReadOnlyLock(r *http.Request, h *webdav.Handler) bool {
+	switch r.Method {
+	case "UNLOCK":
+		return true
+	case "LOCK":
+		name := strings.TrimPrefix(r.URL.Path, h.Prefix)
+		_, err := h.FileSystem.Stat(r.Context(), name)
+		return err == nil
+	default:
+		return false
+	}
+}
+
+// serveNotModified answers 304 Not Modified when the conditional headers of a GET or HEAD
+// request match the metadata of the file, using the ETag the WebDAV handler would send.
+// It reports false, without writing anything, when the request has to be served.
+func serveNotModified(h *webdav.Handler, w http.ResponseWriter, r *http.Request) bool {
+	name := strings.TrimPrefix(r.URL.Path, h.Prefix)
+	fi, err := h.FileSystem.Stat(r.Context(), name)
+	if err != nil || fi.IsDir() {
+		return false
+	}
+
+	etag, ok := fileETag(r.Context(), fi)
+	if !ok || !utils.NotModified(r, etag, fi.ModTime()) {
+		return false
+	}
+
+	utils.WriteNotModified(w, etag, fi.ModTime())
+	return true
+}
+
+// fileETag returns the ETag golang.org/x/net/webdav sends for a file: its own one, or
+// the modification time and size heuristic for files without one
+func fileETag(ctx context.Context, fi os.FileInfo) (string, bool) {
+	if etager, ok := fi.(webdav.ETager); ok {
+		etag, err := etager.ETag(ctx)
+		if err == nil {
+			return etag, true
+		}
+		if !errors.Is(err, webdav.ErrNotImplemented) {
+			return "", false
+		}
+	}
+
+	return fmt.Sprintf(`"%x%x"`, fi.ModTime().UnixNano(), fi.Size()), true
 }
 
 // GetHTTPHandler returns the HTTP handler for use with Fiber adaptor
@@ -200,6 +338,25 @@ func (h *Handler) GetAuthCredentials() *AuthCredentials {
 	return h.authCreds
 }
 
+// SetMaintenanceState makes the handler reject writes while maintenance mode is on
+func (h *Handler) SetMaintenanceState(state *maintenance.State) {
+	h.maintenance = state
+}
+
+// SyncReadOnly updates the read-only mode from current config
+func (h *Handler) SyncReadOnly() {
+	if h.configGetter != nil {
+		h.readOnly.Store(h.configGetter().WebDAV.ReadOnly)
+	}
+}
+
+// SyncMaxPropfindDepth updates the PROPFIND depth limit from current config
+func (h *Handler) SyncMaxPropfindDepth() {
+	if h.configGetter != nil {
+		h.maxPropfindDepth.Store(int64(h.configGetter().WebDAV.MaxPropfindDepth))
+	}
+}
+
 // SyncAuthCredentials updates auth credentials from current config
 func (h *Handler) SyncAuthCredentials() {
 	if h.configGetter != nil {
@@ -0,0 +1,333 @@
+package webdav
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/javi11/altmount/internal/config"
+	"github.com/javi11/altmount/internal/maintenance"
+	"golang.org/x/net/webdav"
+)
+
+const testLockBody = `<?xml version="1.0" encoding="utf-8"?>
+<D:lockinfo xmlns:D="DAV:">
+  <D:lockscope><D:exclusive/></D:lockscope>
+  <D:locktype><D:write/></D:locktype>
+  <D:owner>test</D:owner>
+</D:lockinfo>`
+
+// newTestHandler serves an in-memory filesystem holding /movie.mkv with the given settings
+func newTestHandler(t *testing.T, cfg Config) http.Handler {
+	t.Helper()
+
+	return newTestWebDAVHandler(t, cfg).GetHTTPHandler()
+}
+
+// newTestWebDAVHandler is newTestHandler returning the Handler itself
+func newTestWebDAVHandler(t *testing.T, cfg Config) *Handler {
+	t.Helper()
+
+	fs := webdav.NewMemFS()
+	f, err := fs.OpenFile(context.Background(), "/movie.mkv", os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	if _, err := f.Write([]byte("content")); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+	_ = f.Close()
+
+	cfg.User, cfg.Pass, cfg.Prefix = "user", "pass", "/webdav/"
+	return newHandler(&cfg, fs, nil, nil, nil)
+}
+
+func doRequest(h http.Handler, method, path, body string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.SetBasicAuth("user", "pass")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func lockFile(t *testing.T, h http.Handler, path string) string {
+	t.Helper()
+
+	rec := doRequest(h, "LOCK", path, testLockBody, map[string]string{"Depth": "0", "Timeout": "Second-60"})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("LOCK %s: got status %d, want %d: %s", path, rec.Code, http.StatusOK, rec.Body.String())
+	}
+
+	token := rec.Header().Get("Lock-Token")
+	if !strings.HasPrefix(token, "<") || !strings.HasSuffix(token, ">") {
+		t.Fatalf("LOCK %s: invalid Lock-Token header %q", path, token)
+	}
+	if !strings.Contains(rec.Body.String(), strings.Trim(token, "<>")) {
+		t.Errorf("LOCK %s: response does not include the lock token: %s", path, rec.Body.String())
+	}
+
+	return token
+}
+
+func TestLockLifecycle(t *testing.T) {
+	for _, readOnly := range []bool{false, true} {
+		name := "read-write"
+		if readOnly {
+			name = "read-only"
+		}
+
+		t.Run(name, func(t *testing.T) {
+			h := newTestHandler(t, Config{ReadOnly: readOnly})
+
+			token := lockFile(t, h, "/webdav/movie.mkv")
+
+			// A second exclusive lock must be refused while the first is held
+			rec := doRequest(h, "LOCK", "/webdav/movie.mkv", testLockBody, map[string]string{"Depth": "0"})
+			if rec.Code != webdav.StatusLocked {
+				t.Errorf("second LOCK: got status %d, want %d", rec.Code, webdav.StatusLocked)
+			}
+
+			// Refresh the lock with its token
+			rec = doRequest(h, "LOCK", "/webdav/movie.mkv", "", map[string]string{"If": "(" + token + ")", "Timeout": "Second-120"})
+			if rec.Code != http.StatusOK {
+				t.Errorf("refresh LOCK: got status %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+			}
+
+			// Refreshing an unknown lock fails
+			rec = doRequest(h, "LOCK", "/webdav/movie.mkv", "", map[string]string{"If": "(<opaquelocktoken:unknown>)"})
+			if rec.Code != http.StatusPreconditionFailed {
+				t.Errorf("refresh unknown LOCK: got status %d, want %d", rec.Code, http.StatusPreconditionFailed)
+			}
+
+			rec = doRequest(h, "UNLOCK", "/webdav/movie.mkv", "", map[string]string{"Lock-Token": token})
+			if rec.Code != http.StatusNoContent {
+				t.Errorf("UNLOCK: got status %d, want %d", rec.Code, http.StatusNoContent)
+			}
+
+			// The token is gone once unlocked
+			rec = doRequest(h, "UNLOCK", "/webdav/movie.mkv", "", map[string]string{"Lock-Token": token})
+			if rec.Code != http.StatusConflict {
+				t.Errorf("second UNLOCK: got status %d, want %d", rec.Code, http.StatusConflict)
+			}
+
+			// The resource can be locked again
+			lockFile(t, h, "/webdav/movie.mkv")
+		})
+	}
+}
+
+func TestLockRequiresTokenForWrites(t *testing.T) {
+	h := newTestHandler(t, Config{})
+
+	token := lockFile(t, h, "/webdav/movie.mkv")
+
+	rec := doRequest(h, http.MethodPut, "/webdav/movie.mkv", "new content", nil)
+	if rec.Code != webdav.StatusLocked {
+		t.Errorf("PUT without lock token: got status %d, want %d", rec.Code, webdav.StatusLocked)
+	}
+
+	rec = doRequest(h, http.MethodPut, "/webdav/movie.mkv", "new content", map[string]string{"If": "(" + token + ")"})
+	if rec.Code != http.StatusCreated {
+		t.Errorf("PUT with lock token: got status %d, want %d", rec.Code, http.StatusCreated)
+	}
+}
+
+func TestReadOnlyLock(t *testing.T) {
+	h := newTestHandler(t, Config{ReadOnly: true})
+
+	// Locking a missing resource would create it
+	rec := doRequest(h, "LOCK", "/webdav/missing.mkv", testLockBody, map[string]string{"Depth": "0"})
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("LOCK missing resource: got status %d, want %d", rec.Code, http.StatusForbidden)
+	}
+
+	// Holding a lock does not allow writes
+	token := lockFile(t, h, "/webdav/movie.mkv")
+	rec = doRequest(h, http.MethodPut, "/webdav/movie.mkv", "new content", map[string]string{"If": "(" + token + ")"})
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("PUT with lock token: got status %d, want %d", rec.Code, http.StatusForbidden)
+	}
+}
+
+func TestMaintenanceRejectsWrites(t *testing.T) {
+	wh := newTestWebDAVHandler(t, Config{})
+	state := maintenance.NewState()
+	wh.SetMaintenanceState(state)
+	h := wh.GetHTTPHandler()
+
+	state.Enable("backup")
+
+	rec := doRequest(h, http.MethodPut, "/webdav/movie.mkv", "new content", nil)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("PUT in maintenance mode: got status %d, want %d", rec.Code, http.StatusForbidden)
+	}
+	rec = doRequest(h, http.MethodGet, "/webdav/movie.mkv", "", nil)
+	if rec.Code != http.StatusOK || rec.Body.String() != "content" {
+		t.Errorf("GET in maintenance mode: got status %d with %q, want %d with %q", rec.Code, rec.Body.String(), http.StatusOK, "content")
+	}
+	// Clients lock files while browsing
+	lockFile(t, h, "/webdav/movie.mkv")
+
+	state.Disable()
+
+	rec = doRequest(h, "MKCOL", "/webdav/shows", "", nil)
+	if rec.Code != http.StatusCreated {
+		t.Errorf("MKCOL after maintenance mode: got status %d, want %d", rec.Code, http.StatusCreated)
+	}
+}
+
+func TestPropfindDepthLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxDepth int
+		depth    string
+		want     int
+	}{
+		{name: "depth 0", maxDepth: 1, depth: "0", want: webdav.StatusMulti},
+		{name: "depth 1", maxDepth: 1, depth: "1", want: webdav.StatusMulti},
+		{name: "infinity", maxDepth: 1, depth: "infinity", want: http.StatusForbidden},
+		{name: "missing depth is served as depth 1", maxDepth: 1, depth: "", want: webdav.StatusMulti},
+		{name: "unlimited depth 1", maxDepth: 0, depth: "1", want: webdav.StatusMulti},
+		{name: "unlimited infinity", maxDepth: 0, depth: "infinity", want: webdav.StatusMulti},
+		{name: "unlimited missing depth", maxDepth: 0, depth: "", want: webdav.StatusMulti},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler(t, Config{MaxPropfindDepth: tt.maxDepth})
+
+			headers := map[string]string{}
+			if tt.depth != "" {
+				headers["Depth"] = tt.depth
+			}
+
+			rec := doRequest(h, "PROPFIND", "/webdav/", "", headers)
+			if rec.Code != tt.want {
+				t.Fatalf("PROPFIND Depth %q: got status %d, want %d: %s", tt.depth, rec.Code, tt.want, rec.Body.String())
+			}
+			if tt.want == http.StatusForbidden && !strings.Contains(rec.Body.String(), "propfind-finite-depth") {
+				t.Errorf("PROPFIND Depth %q: response is missing the propfind-finite-depth precondition: %s", tt.depth, rec.Body.String())
+			}
+		})
+	}
+}
+
+// openCountingFS counts the files opened through it
+type openCountingFS struct {
+	webdav.FileSystem
+	opens int
+}
+
+func (fs *openCountingFS) OpenFile(ctx context.Context, name string, flag int, perm os.FileMode) (webdav.File, error) {
+	fs.opens++
+	return fs.FileSystem.OpenFile(ctx, name, flag, perm)
+}
+
+func TestRevalidationDoesNotOpenFile(t *testing.T) {
+	mem := webdav.NewMemFS()
+	f, err := mem.OpenFile(context.Background(), "/movie.mkv", os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	if _, err := f.Write([]byte("content")); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+	_ = f.Close()
+
+	fs := &openCountingFS{FileSystem: mem}
+	h := newHandler(&Config{User: "user", Pass: "pass", Prefix: "/webdav/"}, fs, nil, nil, nil).GetHTTPHandler()
+
+	rec := doRequest(h, http.MethodGet, "/webdav/movie.mkv", "", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET: got status %d, want %d", rec.Code, http.StatusOK)
+	}
+	etag, lastModified := rec.Header().Get("ETag"), rec.Header().Get("Last-Modified")
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    int
+	}{
+		{name: "matching etag", headers: map[string]string{"If-None-Match": etag}, want: http.StatusNotModified},
+		{name: "weak etag in list", headers: map[string]string{"If-None-Match": `"other", W/` + etag}, want: http.StatusNotModified},
+		{name: "not modified since", headers: map[string]string{"If-Modified-Since": lastModified}, want: http.StatusNotModified},
+		{name: "stale etag", headers: map[string]string{"If-None-Match": `"other"`}, want: http.StatusOK},
+		{name: "modified since", headers: map[string]string{"If-Modified-Since": "Mon, 02 Jan 2006 15:04:05 GMT"}, want: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs.opens = 0
+			rec := doRequest(h, http.MethodGet, "/webdav/movie.mkv", "", tt.headers)
+			if rec.Code != tt.want {
+				t.Fatalf("GET: got status %d, want %d", rec.Code, tt.want)
+			}
+			if tt.want == http.StatusNotModified {
+				if fs.opens != 0 {
+					t.Errorf("GET: file opened %d times for a 304", fs.opens)
+				}
+				if got := rec.Header().Get("ETag"); got != etag {
+					t.Errorf("GET: got ETag %q, want %q", got, etag)
+				}
+			}
+		})
+	}
+}
+
+func TestAllowedNetworks(t *testing.T) {
+	wh := newTestWebDAVHandler(t, Config{})
+	wh.configGetter = func() *config.Config {
+		return &config.Config{Auth: config.AuthConfig{
+			AllowedNetworks: []string{"10.0.0.0/8", "fd00::/8"},
+			TrustedProxies:  []string{"192.0.2.1"},
+		}}
+	}
+	h := wh.GetHTTPHandler()
+
+	tests := []struct {
+		name          string
+		remoteAddr    string
+		forwardedFor  string
+		wrongPassword bool
+		want          int
+	}{
+		{name: "allowed IPv4", remoteAddr: "10.1.2.3:5000", want: http.StatusOK},
+		{name: "allowed IPv6", remoteAddr: "[fd00::1]:5000", want: http.StatusOK},
+		{name: "outside the allow-list", remoteAddr: "203.0.113.5:5000", want: http.StatusForbidden},
+		{name: "loopback IPv4", remoteAddr: "127.0.0.1:5000", want: http.StatusOK},
+		{name: "loopback IPv6", remoteAddr: "[::1]:5000", want: http.StatusOK},
+		{name: "rejected before credentials", remoteAddr: "203.0.113.5:5000", wrongPassword: true, want: http.StatusForbidden},
+		{name: "allowed credentials still checked", remoteAddr: "10.1.2.3:5000", wrongPassword: true, want: http.StatusUnauthorized},
+		{name: "client behind trusted proxy", remoteAddr: "192.0.2.1:5000", forwardedFor: "10.0.0.5", want: http.StatusOK},
+		{name: "forwarded header from untrusted client", remoteAddr: "203.0.113.5:5000", forwardedFor: "10.0.0.5", want: http.StatusForbidden},
+		{name: "spoofed hop before proxy", remoteAddr: "192.0.2.1:5000", forwardedFor: "10.0.0.5, 203.0.113.9", want: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/webdav/movie.mkv", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.forwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			}
+			password := "pass"
+			if tt.wrongPassword {
+				password = "wrong"
+			}
+			req.SetBasicAuth("user", password)
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("got status %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
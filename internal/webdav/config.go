@@ -16,6 +16,10 @@ type Config struct {
 	Pass string `yaml:"password" default:"usenet" json:"-" mapstructure:"password"`
 	// Prefix is the URL path prefix for the WebDAV server
 	Prefix string `yaml:"prefix" default:"/webdav/" mapstructure:"prefix"`
+	// ReadOnly rejects all methods that can modify the library
+	ReadOnly bool `yaml:"read_only" mapstructure:"read_only"`
+	// MaxPropfindDepth is the deepest PROPFIND allowed, 0 allows Depth: infinity
+	MaxPropfindDepth int `yaml:"max_propfind_depth" mapstructure:"max_propfind_depth"`
 }
 
 // RegisterConfigHandlers registers handlers for WebDAV-related configuration changes
@@ -28,5 +32,15 @@ func RegisterConfigHandlers(ctx context.Context, configManager *config.Manager,
 				"old_user", oldConfig.WebDAV.User,
 				"new_user", newConfig.WebDAV.User)
 		}
+
+		if oldConfig.WebDAV.ReadOnly != newConfig.WebDAV.ReadOnly {
+			handler.SyncReadOnly()
+			slog.InfoContext(ctx, "WebDAV read-only mode updated", "read_only", newConfig.WebDAV.ReadOnly)
+		}
+
+		if oldConfig.WebDAV.MaxPropfindDepth != newConfig.WebDAV.MaxPropfindDepth {
+			handler.SyncMaxPropfindDepth()
+			slog.InfoContext(ctx, "WebDAV PROPFIND depth limit updated", "max_propfind_depth", newConfig.WebDAV.MaxPropfindDepth)
+		}
 	})
 }
@@ -5,10 +5,19 @@ import (
 	"log/slog"
 	"os"
 
+	"github.com/javi11/altmount/internal/auth"
 	"github.com/javi11/altmount/internal/nzbfilesystem"
 	"golang.org/x/net/webdav"
 )
 
+// basePathKey holds the subtree the authenticated user is restricted to
+type basePathKey struct{}
+
+// withBasePath restricts all filesystem operations made with ctx to basePath
+func withBasePath(ctx context.Context, basePath string) context.Context {
+	return context.WithValue(ctx, basePathKey{}, basePath)
+}
+
 type fileSystem struct {
 	nzbFs *nzbfilesystem.NzbFilesystem
 }
@@ -19,21 +28,30 @@ func nzbToWebdavFS(vfs *nzbfilesystem.NzbFilesystem) webdav.FileSystem {
 	}
 }
 
+// scopedName maps a client path into the user's base path. The name is cleaned as an
+// absolute path first, so it can never resolve outside the base path.
+func scopedName(ctx context.Context, name string) string {
+	basePath, _ := ctx.Value(basePathKey{}).(string)
+	return auth.ScopedPath(basePath, name)
+}
+
 func (fs *fileSystem) Mkdir(ctx context.Context, name string, perm os.FileMode) error {
-	return fs.nzbFs.Mkdir(ctx, name, perm)
+	return fs.nzbFs.Mkdir(ctx, scopedName(ctx, name), perm)
 }
 
 func (fs *fileSystem) OpenFile(ctx context.Context, name string, flag int, perm os.FileMode) (webdav.File, error) {
 	// Context values are now passed directly through the context
 	// No need to encode them into the path string
-	return fs.nzbFs.OpenFile(ctx, name, flag, perm)
+	return fs.nzbFs.OpenFile(ctx, scopedName(ctx, name), flag, perm)
 }
 
 func (fs *fileSystem) RemoveAll(ctx context.Context, name string) error {
-	return fs.nzbFs.RemoveAll(ctx, name)
+	return fs.nzbFs.RemoveAll(ctx, scopedName(ctx, name))
 }
 
 func (fs *fileSystem) Rename(ctx context.Context, oldName, newName string) error {
+	oldName, newName = scopedName(ctx, oldName), scopedName(ctx, newName)
+
 	// Add logging to understand when MOVE operations trigger renames
 	slog.InfoContext(ctx, "WebDAV filesystem Rename called",
 		"oldName", oldName,
@@ -42,5 +60,5 @@ func (fs *fileSystem) Rename(ctx context.Context, oldName, newName string) error
 }
 
 func (fs *fileSystem) Stat(ctx context.Context, name string) (os.FileInfo, error) {
-	return fs.nzbFs.Stat(ctx, name)
+	return fs.nzbFs.Stat(ctx, scopedName(ctx, name))
 }
@@ -0,0 +1,32 @@
+package webdav
+
+import (
+	"context"
+	"testing"
+)
+
+func TestScopedName(t *testing.T) {
+	scoped := withBasePath(context.Background(), "/movies")
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		path string
+		want string
+	}{
+		{name: "no base path", ctx: context.Background(), path: "/tv/show.mkv", want: "/tv/show.mkv"},
+		{name: "root", ctx: scoped, path: "/", want: "/movies"},
+		{name: "file", ctx: scoped, path: "/film/film.mkv", want: "/movies/film/film.mkv"},
+		{name: "relative", ctx: scoped, path: "film.mkv", want: "/movies/film.mkv"},
+		{name: "parent traversal", ctx: scoped, path: "/../tv/show.mkv", want: "/movies/tv/show.mkv"},
+		{name: "nested traversal", ctx: scoped, path: "/film/../../../etc/passwd", want: "/movies/etc/passwd"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scopedName(tt.ctx, tt.path); got != tt.want {
+				t.Errorf("scopedName(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
@@ -28,12 +28,30 @@ var (
 	errPrefixMismatch  = errors.New("webdav: prefix mismatch")
 )
 
-func HandlePropfind(fs webdav.FileSystem, ls webdav.LockSystem, w http.ResponseWriter, r *http.Request, prefix string) (status int, err error) {
+// HandlePropfind handles a PROPFIND request. Requests deeper than maxDepth are refused
+// with 403 Forbidden before anything is read, 0 allows any depth including infinity.
+// With a limit, a request without a Depth header is answered as Depth: 1 instead of
+// infinity, since many clients omit the header when listing a directory.
+func HandlePropfind(fs webdav.FileSystem, ls webdav.LockSystem, w http.ResponseWriter, r *http.Request, prefix string, maxDepth int) (status int, err error) {
 	reqPath, status, err := stripPrefix(r.URL.Path, prefix)
 	if err != nil {
 		return status, err
 	}
 
+	depth := infiniteDepth
+	if maxDepth > 0 {
+		depth = 1
+	}
+	if hdr := r.Header.Get("Depth"); hdr != "" {
+		depth = parseDepth(hdr)
+		if depth == invalidDepth {
+			return http.StatusBadRequest, errInvalidDepth
+		}
+	}
+	if maxDepth > 0 && (depth == infiniteDepth || depth > maxDepth) {
+		return writeFiniteDepthError(w)
+	}
+
 	ctx := r.Context()
 	fi, err := fs.Stat(ctx, reqPath)
 	if err != nil {
@@ -42,13 +60,6 @@ func HandlePropfind(fs webdav.FileSystem, ls webdav.LockSystem, w http.ResponseW
 		}
 		return http.StatusMethodNotAllowed, err
 	}
-	depth := infiniteDepth
-	if hdr := r.Header.Get("Depth"); hdr != "" {
-		depth = parseDepth(hdr)
-		if depth == invalidDepth {
-			return http.StatusBadRequest, errInvalidDepth
-		}
-	}
 	pf, status, err := readPropfind(r.Body)
 	if err != nil {
 		return status, err
@@ -99,6 +110,16 @@ func HandlePropfind(fs webdav.FileSystem, ls webdav.LockSystem, w http.ResponseW
 	return 0, nil
 }
 
+// writeFiniteDepthError refuses a PROPFIND that is too deep with the propfind-finite-depth
+// precondition of section 9.1, so clients know to retry with a lower depth
+func writeFiniteDepthError(w http.ResponseWriter) (int, error) {
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(http.StatusForbidden)
+	_, err := fmt.Fprint(w, `<?xml version="1.0" encoding="utf-8"?>`+
+		`<D:error xmlns:D="DAV:"><D:propfind-finite-depth/></D:error>`)
+	return 0, err
+}
+
 // parseDepth maps the strings "0", "1" and "infinity" to 0, 1 and
 // infiniteDepth. Parsing any other string returns invalidDepth.
 //
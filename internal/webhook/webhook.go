@@ -0,0 +1,160 @@
+// Package webhook delivers JSON event notifications to user configured URLs
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+)
+
+// SignatureHeader carries the HMAC-SHA256 of the request body when a secret is configured,
+// formatted as "sha256=<hex digest>"
+const SignatureHeader = "X-AltMount-Signature"
+
+// Retry settings for webhook deliveries
+const (
+	sendAttempts      = 3
+	sendRetryDelay    = 2 * time.Second
+	sendRetryMaxDelay = 10 * time.Second
+	requestTimeout    = 10 * time.Second
+)
+
+// Client sends webhook notifications
+type Client struct {
+	httpClient *http.Client
+}
+
+// NewClient creates a new webhook client
+func NewClient() *Client {
+	return &Client{
+		httpClient: &http.Client{
+			Timeout: requestTimeout,
+		},
+	}
+}
+
+// Send posts payload as JSON to url. When secret is set the body is signed with it, see
+// SignatureHeader. Network errors, timeouts, 429 and 5xx responses are retried with backoff.
+func (c *Client) Send(ctx context.Context, url, secret string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	return retry.Do(
+		func() error {
+			return c.post(ctx, url, secret, body)
+		},
+		retry.Attempts(sendAttempts),
+		retry.Delay(sendRetryDelay),
+		retry.MaxDelay(sendRetryMaxDelay),
+		retry.DelayType(retry.BackOffDelay),
+		retry.LastErrorOnly(true),
+		retry.RetryIf(isRetryableError),
+		retry.OnRetry(func(n uint, err error) {
+			slog.WarnContext(ctx, "Retrying webhook delivery",
+				"attempt", n+1,
+				"url", url,
+				"error", err)
+		}),
+		retry.Context(ctx),
+	)
+}
+
+// SendAsync sends payload in the background so the caller is never blocked. The delivery
+// outlives the cancellation of ctx, failures are only logged.
+func (c *Client) SendAsync(ctx context.Context, url, secret string, payload any) {
+	ctx = context.WithoutCancel(ctx)
+
+	go func() {
+		if err := c.Send(ctx, url, secret, payload); err != nil {
+			slog.WarnContext(ctx, "Failed to deliver webhook", "url", url, "error", err)
+		}
+	}()
+}
+
+// post performs a single delivery attempt
+func (c *Client) post(ctx context.Context, url, secret string, body []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if secret != "" {
+		req.Header.Set(SignatureHeader, Sign(secret, body))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return &transportError{err: fmt.Errorf("failed to send webhook: %w", err)}
+	}
+	defer resp.Body.Close()
+
+	// The response is not used, drain a bounded amount so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &httpStatusError{StatusCode: resp.StatusCode}
+	}
+
+	return nil
+}
+
+// Sign returns the SignatureHeader value for body signed with secret
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// transportError wraps network-level failures such as timeouts and refused connections
+type transportError struct {
+	err error
+}
+
+func (e *transportError) Error() string { return e.err.Error() }
+func (e *transportError) Unwrap() error { return e.err }
+
+// httpStatusError is returned when the receiver answers with a non-2xx status
+type httpStatusError struct {
+	StatusCode int
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("webhook returned HTTP %d", e.StatusCode)
+}
+
+// isRetryableError reports whether a failed delivery is worth retrying: network errors,
+// timeouts, 429 and 5xx responses. Cancellation of the caller's context is never retried.
+func isRetryableError(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var transportErr *transportError
+	if errors.As(err, &transportErr) {
+		return true
+	}
+
+	var statusErr *httpStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= 500 || statusErr.StatusCode == http.StatusTooManyRequests
+	}
+
+	return false
+}
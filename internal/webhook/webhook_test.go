@@ -0,0 +1,86 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSendSignsPayload(t *testing.T) {
+	var gotBody []byte
+	var gotSignature, gotContentType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		gotSignature = r.Header.Get(SignatureHeader)
+		gotContentType = r.Header.Get("Content-Type")
+	}))
+	defer srv.Close()
+
+	payload := map[string]string{"event": "test"}
+	if err := NewClient().Send(context.Background(), srv.URL, "secret", payload); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	var decoded map[string]string
+	if err := json.Unmarshal(gotBody, &decoded); err != nil || decoded["event"] != "test" {
+		t.Errorf("unexpected body %q", gotBody)
+	}
+	if gotContentType != "application/json" {
+		t.Errorf("Content-Type: got %q, want application/json", gotContentType)
+	}
+	if want := Sign("secret", gotBody); gotSignature != want {
+		t.Errorf("signature: got %q, want %q", gotSignature, want)
+	}
+}
+
+func TestSendWithoutSecret(t *testing.T) {
+	var gotSignature string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSignature = r.Header.Get(SignatureHeader)
+	}))
+	defer srv.Close()
+
+	if err := NewClient().Send(context.Background(), srv.URL, "", struct{}{}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if gotSignature != "" {
+		t.Errorf("unexpected signature header %q", gotSignature)
+	}
+}
+
+func TestSendRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		wantErr  bool
+		wantCall int32
+	}{
+		{name: "server error is retried", status: http.StatusBadGateway, wantErr: false, wantCall: 2},
+		{name: "client error is not retried", status: http.StatusBadRequest, wantErr: true, wantCall: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// Fail the first attempt only
+				if calls.Add(1) == 1 {
+					w.WriteHeader(tt.status)
+				}
+			}))
+			defer srv.Close()
+
+			err := NewClient().Send(context.Background(), srv.URL, "", struct{}{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Send: got error %v, want error %v", err, tt.wantErr)
+			}
+			if got := calls.Load(); got != tt.wantCall {
+				t.Errorf("got %d attempts, want %d", got, tt.wantCall)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package rclonecli
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"os"
@@ -94,10 +95,6 @@ func (m *Manager) performMount(ctx context.Context, provider, mountPath, webdavU
 		configOpts["BufferSize"] = cfg.RClone.BufferSize
 	}
 
-	if len(configOpts) > 0 {
-		// Only add _config if there are options to set
-		mountArgs["_config"] = configOpts
-	}
 	vfsOpt := map[string]interface{}{
 		"CacheMode": cfg.RClone.VFSCacheMode,
 	}
@@ -143,6 +140,17 @@ func (m *Manager) performMount(ctx context.Context, provider, mountPath, webdavU
 		}
 	}
 
+	// Custom mount options override the values above
+	applyMountOptions(ctx, m.logger, cfg.RClone.MountOptions, map[string]map[string]any{
+		mountOptGroup:  mountOpt,
+		vfsOptGroup:    vfsOpt,
+		configOptGroup: configOpts,
+	})
+
+	if len(configOpts) > 0 {
+		// Only add _config if there are options to set
+		mountArgs["_config"] = configOpts
+	}
 	mountArgs["vfsOpt"] = vfsOpt
 	mountArgs["mountOpt"] = mountOpt
 	// Make the mount request
@@ -288,6 +296,40 @@ func (m *Manager) IsMounted(provider string) bool {
 	return exists && info.Mounted
 }
 
+// ListMountPoints returns the local paths the RC server currently has mounted
+func (m *Manager) ListMountPoints(ctx context.Context) ([]string, error) {
+	if !m.IsReady() {
+		return nil, fmt.Errorf("rclone RC server not ready")
+	}
+
+	req := RCRequest{
+		Command: "mount/listmounts",
+		Args:    map[string]interface{}{},
+	}
+
+	resp, err := m.makeRequest(req, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list mounts: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var listResp struct {
+		MountPoints []struct {
+			Fs         string `json:"Fs"`
+			MountPoint string `json:"MountPoint"`
+		} `json:"mountPoints"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&listResp); err != nil {
+		return nil, fmt.Errorf("failed to decode list mounts response: %w", err)
+	}
+
+	mountPoints := make([]string, 0, len(listResp.MountPoints))
+	for _, mp := range listResp.MountPoints {
+		mountPoints = append(mountPoints, mp.MountPoint)
+	}
+	return mountPoints, nil
+}
+
 // RefreshDir refreshes directories in the VFS cache
 func (m *Manager) RefreshDir(ctx context.Context, provider string, dirs []string) error {
 	if !m.IsReady() {
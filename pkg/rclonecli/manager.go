@@ -12,6 +12,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"slices"
+	"strings"
 	"sync"
 	"time"
 
@@ -135,7 +136,10 @@ func (m *Manager) Start(ctx context.Context) error {
 		}
 	}
 
-	m.logger.InfoContext(ctx, "Starting rclone RC server", "args", args)
+	// Custom RC server options come last so they override the defaults above
+	args = append(args, rcOptionArgs(ctx, m.logger, cfg.RClone.RCOptions)...)
+
+	m.logger.InfoContext(ctx, "Starting rclone RC server", "args", cfg.RedactText(strings.Join(args, " ")))
 
 	m.cmd = exec.CommandContext(ctx, "rclone", args...)
 
@@ -327,6 +331,17 @@ func (m *Manager) waitForServer() {
 	m.logger.ErrorContext(m.ctx, "Rclone RC server not responding - mount operations will be disabled")
 }
 
+// Ping returns an error if the RC server is not ready or not responding
+func (m *Manager) Ping() error {
+	if !m.IsReady() {
+		return fmt.Errorf("rclone RC server not ready")
+	}
+	if !m.pingServer() {
+		return fmt.Errorf("rclone RC server is not responding")
+	}
+	return nil
+}
+
 // pingServer checks if the RC server is responding
 func (m *Manager) pingServer() bool {
 	req := RCRequest{
@@ -0,0 +1,177 @@
+package rclonecli
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// optionKind is how the value of an rclone flag is sent to the RC API
+type optionKind int
+
+const (
+	optionString   optionKind = iota // Sent as is, e.g. sizes such as "128M"
+	optionBool                       // "true" or "false"
+	optionInt                        // Whole number
+	optionDuration                   // Go duration such as "10m", sent in nanoseconds
+	optionList                       // Comma separated values
+)
+
+// Groups of options in an RC mount/mount request
+const (
+	mountOptGroup  = "mountOpt"
+	vfsOptGroup    = "vfsOpt"
+	configOptGroup = "_config"
+)
+
+// rcMountOption locates an rclone mount flag in the mount/mount request
+type rcMountOption struct {
+	group string
+	field string
+	kind  optionKind
+}
+
+// rcMountOptions maps the rclone.mount_options keys, in normalized form, to the fields of
+// the mount/mount request. Flags of the rclone process itself, such as cache-dir, log-level
+// and syslog, cannot be set per mount and have their own settings.
+var rcMountOptions = map[string]rcMountOption{
+	"allow-non-empty":        {mountOptGroup, "AllowNonEmpty", optionBool},
+	"allow-other":            {mountOptGroup, "AllowOther", optionBool},
+	"allow-root":             {mountOptGroup, "AllowRoot", optionBool},
+	"async-read":             {mountOptGroup, "AsyncRead", optionBool},
+	"attr-timeout":           {mountOptGroup, "AttrTimeout", optionDuration},
+	"daemon-timeout":         {mountOptGroup, "DaemonTimeout", optionDuration},
+	"debug-fuse":             {mountOptGroup, "DebugFUSE", optionBool},
+	"default-permissions":    {mountOptGroup, "DefaultPermissions", optionBool},
+	"devname":                {mountOptGroup, "DeviceName", optionString},
+	"direct-io":              {mountOptGroup, "DirectIO", optionBool},
+	"fuse-flag":              {mountOptGroup, "ExtraFlags", optionList},
+	"max-read-ahead":         {mountOptGroup, "MaxReadAhead", optionString},
+	"mount-case-insensitive": {mountOptGroup, "CaseInsensitive", optionBool},
+	"network-mode":           {mountOptGroup, "NetworkMode", optionBool},
+	"noappledouble":          {mountOptGroup, "NoAppleDouble", optionBool},
+	"noapplexattr":           {mountOptGroup, "NoAppleXattr", optionBool},
+	"option":                 {mountOptGroup, "ExtraOptions", optionList},
+	"volname":                {mountOptGroup, "VolumeName", optionString},
+	"write-back-cache":       {mountOptGroup, "WritebackCache", optionBool},
+
+	"dir-cache-time":            {vfsOptGroup, "DirCacheTime", optionDuration},
+	"dir-perms":                 {vfsOptGroup, "DirPerms", optionString},
+	"file-perms":                {vfsOptGroup, "FilePerms", optionString},
+	"gid":                       {vfsOptGroup, "GID", optionInt},
+	"links":                     {vfsOptGroup, "Links", optionBool},
+	"no-checksum":               {vfsOptGroup, "NoChecksum", optionBool},
+	"no-modtime":                {vfsOptGroup, "NoModTime", optionBool},
+	"no-seek":                   {vfsOptGroup, "NoSeek", optionBool},
+	"poll-interval":             {vfsOptGroup, "PollInterval", optionDuration},
+	"read-only":                 {vfsOptGroup, "ReadOnly", optionBool},
+	"uid":                       {vfsOptGroup, "UID", optionInt},
+	"umask":                     {vfsOptGroup, "Umask", optionString},
+	"vfs-cache-max-age":         {vfsOptGroup, "CacheMaxAge", optionDuration},
+	"vfs-cache-max-size":        {vfsOptGroup, "CacheMaxSize", optionString},
+	"vfs-cache-min-free-space":  {vfsOptGroup, "CacheMinFreeSpace", optionString},
+	"vfs-cache-mode":            {vfsOptGroup, "CacheMode", optionString},
+	"vfs-cache-poll-interval":   {vfsOptGroup, "CachePollInterval", optionDuration},
+	"vfs-case-insensitive":      {vfsOptGroup, "CaseInsensitive", optionBool},
+	"vfs-disk-space-total-size": {vfsOptGroup, "DiskSpaceTotalSize", optionString},
+	"vfs-fast-fingerprint":      {vfsOptGroup, "FastFingerprint", optionBool},
+	"vfs-read-ahead":            {vfsOptGroup, "ReadAhead", optionString},
+	"vfs-read-chunk-size":       {vfsOptGroup, "ChunkSize", optionString},
+	"vfs-read-chunk-size-limit": {vfsOptGroup, "ChunkSizeLimit", optionString},
+	"vfs-read-chunk-streams":    {vfsOptGroup, "ChunkStreams", optionInt},
+	"vfs-read-wait":             {vfsOptGroup, "ReadWait", optionDuration},
+	"vfs-refresh":               {vfsOptGroup, "Refresh", optionBool},
+	"vfs-used-is-size":          {vfsOptGroup, "UsedIsSize", optionBool},
+	"vfs-write-back":            {vfsOptGroup, "WriteBack", optionDuration},
+	"vfs-write-wait":            {vfsOptGroup, "WriteWait", optionDuration},
+
+	"buffer-size": {configOptGroup, "BufferSize", optionString},
+	"timeout":     {configOptGroup, "Timeout", optionDuration},
+	"transfers":   {configOptGroup, "Transfers", optionInt},
+	"use-mmap":    {configOptGroup, "UseMmap", optionBool},
+}
+
+// applyMountOptions sets the rclone.mount_options in the option groups of a mount/mount
+// request, overriding the values derived from the other settings. Keys must already be
+// normalized. Options that cannot be set per mount or have an invalid value are logged
+// and skipped.
+func applyMountOptions(ctx context.Context, logger *slog.Logger, options map[string]string, groups map[string]map[string]any) {
+	for _, key := range slices.Sorted(maps.Keys(options)) {
+		option, ok := rcMountOptions[key]
+		if !ok {
+			logger.WarnContext(ctx, "Mount option cannot be applied to an rclone RC mount, ignoring it", "option", key)
+			continue
+		}
+
+		value, err := parseMountOption(option.kind, options[key])
+		if err != nil {
+			logger.WarnContext(ctx, "Invalid mount option value, ignoring it", "option", key, "value", options[key], "err", err)
+			continue
+		}
+
+		groups[option.group][option.field] = value
+	}
+}
+
+// parseMountOption converts a flag value to the type expected by the RC API
+func parseMountOption(kind optionKind, value string) (any, error) {
+	value = strings.TrimSpace(value)
+
+	switch kind {
+	case optionBool:
+		// A flag given without a value is switched on
+		if value == "" {
+			return true, nil
+		}
+		return strconv.ParseBool(value)
+	case optionInt:
+		return strconv.Atoi(value)
+	case optionDuration:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, err
+		}
+		return d.Nanoseconds(), nil
+	case optionList:
+		var items []string
+		for item := range strings.SplitSeq(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		return items, nil
+	default:
+		if value == "" {
+			return nil, fmt.Errorf("value cannot be empty")
+		}
+		return value, nil
+	}
+}
+
+// reservedRCOptions are the RC server flags set by the manager, which rclone.rc_options
+// cannot override without cutting AltMount off from its own RC server
+var reservedRCOptions = []string{"rc-addr", "rc-no-auth"}
+
+// rcOptionArgs returns the command line flags of rclone.rc_options for the RC server.
+// Keys must already be normalized. An empty value passes the flag on its own.
+func rcOptionArgs(ctx context.Context, logger *slog.Logger, options map[string]string) []string {
+	var args []string
+	for _, key := range slices.Sorted(maps.Keys(options)) {
+		if slices.Contains(reservedRCOptions, key) {
+			logger.WarnContext(ctx, "RC option is managed by AltMount, ignoring it", "option", key)
+			continue
+		}
+
+		if value := strings.TrimSpace(options[key]); value != "" {
+			args = append(args, "--"+key+"="+value)
+		} else {
+			args = append(args, "--"+key)
+		}
+	}
+	return args
+}
@@ -0,0 +1,87 @@
+package rclonecli
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestApplyMountOptions(t *testing.T) {
+	mountOpt := map[string]any{"AllowOther": true}
+	vfsOpt := map[string]any{"CacheMode": "full"}
+	configOpt := map[string]any{}
+
+	applyMountOptions(context.Background(), slog.Default(), map[string]string{
+		"allow-other":         "false",
+		"vfs-cache-mode":      "writes",
+		"vfs-read-chunk-size": "64M",
+		"dir-cache-time":      "5m",
+		"uid":                 "1000",
+		"fuse-flag":           "sync_read, auto_cache",
+		"no-seek":             "",
+		"transfers":           "8",
+		"syslog":              "true", // Flag of the rclone process, not of a mount
+		"attr-timeout":        "soon", // Invalid duration
+	}, map[string]map[string]any{
+		mountOptGroup:  mountOpt,
+		vfsOptGroup:    vfsOpt,
+		configOptGroup: configOpt,
+	})
+
+	wantMount := map[string]any{
+		"AllowOther": false,
+		"ExtraFlags": []string{"sync_read", "auto_cache"},
+	}
+	wantVFS := map[string]any{
+		"CacheMode":    "writes",
+		"ChunkSize":    "64M",
+		"DirCacheTime": (5 * time.Minute).Nanoseconds(),
+		"UID":          1000,
+		"NoSeek":       true,
+	}
+	wantConfig := map[string]any{"Transfers": 8}
+
+	if !reflect.DeepEqual(mountOpt, wantMount) {
+		t.Errorf("mountOpt: got %v, want %v", mountOpt, wantMount)
+	}
+	if !reflect.DeepEqual(vfsOpt, wantVFS) {
+		t.Errorf("vfsOpt: got %v, want %v", vfsOpt, wantVFS)
+	}
+	if !reflect.DeepEqual(configOpt, wantConfig) {
+		t.Errorf("_config: got %v, want %v", configOpt, wantConfig)
+	}
+}
+
+func TestParseMountOptionErrors(t *testing.T) {
+	tests := []struct {
+		kind  optionKind
+		value string
+	}{
+		{optionBool, "maybe"},
+		{optionInt, "ten"},
+		{optionDuration, "10"},
+		{optionString, ""},
+	}
+
+	for _, tt := range tests {
+		if _, err := parseMountOption(tt.kind, tt.value); err == nil {
+			t.Errorf("parseMountOption(%d, %q): expected an error", tt.kind, tt.value)
+		}
+	}
+}
+
+func TestRCOptionArgs(t *testing.T) {
+	args := rcOptionArgs(context.Background(), slog.Default(), map[string]string{
+		"rc-web-gui":                 "true",
+		"rc-web-gui-no-open-browser": "",
+		"rc-addr":                    ":1234", // Managed by AltMount
+		"rc-no-auth":                 "false",
+	})
+
+	want := []string{"--rc-web-gui=true", "--rc-web-gui-no-open-browser"}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("got %v, want %v", args, want)
+	}
+}
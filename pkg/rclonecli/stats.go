@@ -59,6 +59,23 @@ type Stats struct {
 	Version   VersionResponse       `json:"version"`
 }
 
+// VFSDiskCacheStats describes the on-disk VFS cache of a mount
+type VFSDiskCacheStats struct {
+	BytesUsed         int64  `json:"bytesUsed"`
+	Files             int    `json:"files"`
+	ErroredFiles      int    `json:"erroredFiles"`
+	OutOfSpace        bool   `json:"outOfSpace"`
+	UploadsInProgress int    `json:"uploadsInProgress"`
+	UploadsQueued     int    `json:"uploadsQueued"`
+	Path              string `json:"path"`
+}
+
+// VFSStats represents the VFS statistics of a mount
+type VFSStats struct {
+	InUse     int                `json:"inUse"`
+	DiskCache *VFSDiskCacheStats `json:"diskCache,omitempty"`
+}
+
 // GetStats retrieves statistics from the rclone RC server
 func (m *Manager) GetStats(ctx context.Context) (*Stats, error) {
 	stats := &Stats{}
@@ -187,3 +204,28 @@ func (m *Manager) GetVersion(ctx context.Context) (*VersionResponse, error) {
 	}
 	return &versionResp, nil
 }
+
+// GetVFSStats returns the VFS statistics of a provider's mount
+func (m *Manager) GetVFSStats(ctx context.Context, provider string) (*VFSStats, error) {
+	if !m.IsReady() {
+		return nil, fmt.Errorf("rclone RC server not ready")
+	}
+
+	req := RCRequest{
+		Command: "vfs/stats",
+		Args: map[string]interface{}{
+			"fs": fmt.Sprintf("%s:", provider),
+		},
+	}
+
+	resp, err := m.makeRequest(req, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vfs stats: %w", err)
+	}
+	defer resp.Body.Close()
+	var vfsStats VFSStats
+	if err := json.NewDecoder(resp.Body).Decode(&vfsStats); err != nil {
+		return nil, fmt.Errorf("failed to decode vfs stats response: %w", err)
+	}
+	return &vfsStats, nil
+}